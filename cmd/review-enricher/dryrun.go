@@ -0,0 +1,124 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+)
+
+// enrichOptions describes a single enrichment run
+type enrichOptions struct {
+	InputPaths        []string
+	OutputPath        string
+	PerFile           bool // Write each input's enriched copy next to it instead of merging into OutputPath
+	Offline           bool // Use local analysis instead of the AI APIs
+	DryRun            bool // Analyze offline and print a summary without writing OutputPath
+	Concurrency       int
+	RequestsPerMinute int
+	IDs               string // idsSequential or idsStable; empty means idsSequential
+}
+
+// EnrichmentSummary counts enriched reviews by each analysis outcome
+type EnrichmentSummary struct {
+	Reviews      int
+	Sentiments   map[string]int
+	Departments  map[string]int
+	Products     map[string]int
+	NeedsActions int
+}
+
+// newEnrichmentSummary creates an empty summary
+func newEnrichmentSummary() EnrichmentSummary {
+	return EnrichmentSummary{
+		Sentiments:  make(map[string]int),
+		Departments: make(map[string]int),
+		Products:    make(map[string]int),
+	}
+}
+
+// add counts review in the summary
+func (s *EnrichmentSummary) add(review EnrichedReview) {
+	s.Reviews++
+	s.Sentiments[review.Sentiment]++
+	s.Departments[review.Department]++
+	s.Products[review.Product]++
+	if review.NeedsAction {
+		s.NeedsActions++
+	}
+}
+
+// runEnrichment enriches opts.InputPaths and writes the result to opts.OutputPath.
+// A dry run always analyzes offline, so it never calls a paid API, and prints a
+// summary to stdout instead of writing the output file.
+func runEnrichment(ctx context.Context, opts enrichOptions, stdout io.Writer) error {
+	// Offline analysis is local, so it uses the whole pool with no rate cap
+	analyze := newOnlineAnalyzer(newRequestPacer(opts.RequestsPerMinute))
+	if opts.Offline || opts.DryRun {
+		analyze = analyzeOffline
+	}
+
+	if opts.DryRun {
+		summary := newEnrichmentSummary()
+		err := enrichInputs(ctx, opts.InputPaths, analyze, opts.Concurrency, func(review EnrichedReview) error {
+			summary.add(review)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		printEnrichmentSummary(stdout, summary)
+		return nil
+	}
+
+	if opts.PerFile {
+		for _, inputPath := range opts.InputPaths {
+			outputPath := perFileOutputPath(inputPath)
+			processed, err := enrichFile(ctx, []string{inputPath}, outputPath, analyze, opts.Concurrency, opts.IDs)
+			if err != nil {
+				return err
+			}
+			log.Printf("Enriched %d reviews from %s into %s", processed, inputPath, outputPath)
+		}
+		return nil
+	}
+
+	processed, err := enrichFile(ctx, opts.InputPaths, opts.OutputPath, analyze, opts.Concurrency, opts.IDs)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Processed %d reviews successfully", processed)
+	log.Printf("Enriched reviews saved to %s", opts.OutputPath)
+	return nil
+}
+
+// printEnrichmentSummary writes a human-readable dry run summary
+func printEnrichmentSummary(w io.Writer, summary EnrichmentSummary) {
+	fmt.Fprintln(w, "Enrichment dry run (offline analysis, no output written)")
+	fmt.Fprintf(w, "  Reviews:      %d\n", summary.Reviews)
+	fmt.Fprintf(w, "  Needs action: %d\n", summary.NeedsActions)
+	printCounts(w, "Sentiment", summary.Sentiments)
+	printCounts(w, "Department", summary.Departments)
+	printCounts(w, "Product", summary.Products)
+}
+
+// printCounts writes counts under a heading, most frequent first and then by name
+func printCounts(w io.Writer, heading string, counts map[string]int) {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+
+	fmt.Fprintf(w, "  %s:\n", heading)
+	for _, name := range names {
+		fmt.Fprintf(w, "    %-34s %d\n", name, counts[name])
+	}
+}
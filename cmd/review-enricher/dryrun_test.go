@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunEnrichmentDryRunWritesNoOutput(t *testing.T) {
+	// An API key must not matter: dry runs never call the API
+	t.Setenv("OPENAI_API_KEY", "sk-test")
+
+	dir := t.TempDir()
+	inputPath := writeInputFile(t, dir, sampleInputReviews(10))
+	outputPath := filepath.Join(dir, "enriched_reviews.json")
+
+	var stdout bytes.Buffer
+	err := runEnrichment(context.Background(), enrichOptions{
+		InputPaths:  []string{inputPath},
+		OutputPath:  outputPath,
+		DryRun:      true,
+		Concurrency: 4,
+	}, &stdout)
+	assert.NoError(t, err)
+
+	_, err = os.Stat(outputPath)
+	assert.True(t, os.IsNotExist(err))
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	out := stdout.String()
+	assert.Contains(t, out, "Enrichment dry run")
+	assert.Contains(t, out, "Reviews:      10")
+	assert.Contains(t, out, "Sentiment:")
+	assert.Contains(t, out, "Department:")
+	assert.Contains(t, out, "Product:")
+}
+
+func TestRunEnrichmentWritesOutput(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := writeInputFile(t, dir, sampleInputReviews(3))
+	outputPath := filepath.Join(dir, "enriched_reviews.json")
+
+	var stdout bytes.Buffer
+	err := runEnrichment(context.Background(), enrichOptions{
+		InputPaths:  []string{inputPath},
+		OutputPath:  outputPath,
+		Offline:     true,
+		Concurrency: 2,
+	}, &stdout)
+	assert.NoError(t, err)
+
+	_, err = os.Stat(outputPath)
+	assert.NoError(t, err)
+	assert.Empty(t, stdout.String())
+}
+
+func TestEnrichmentSummaryCounts(t *testing.T) {
+	summary := newEnrichmentSummary()
+	summary.add(EnrichedReview{Sentiment: "Negative", Department: "Engineering", Product: "NIOS", NeedsAction: true})
+	summary.add(EnrichedReview{Sentiment: "Negative", Department: "Support", Product: "NIOS"})
+	summary.add(EnrichedReview{Sentiment: "Positive", Department: "General", Product: "BloxOne DDI"})
+
+	assert.Equal(t, 3, summary.Reviews)
+	assert.Equal(t, 1, summary.NeedsActions)
+	assert.Equal(t, map[string]int{"Negative": 2, "Positive": 1}, summary.Sentiments)
+	assert.Equal(t, 2, summary.Products["NIOS"])
+
+	var buf bytes.Buffer
+	printEnrichmentSummary(&buf, summary)
+	// Most frequent values are listed first
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("Negative")), bytes.Index(buf.Bytes(), []byte("Positive")))
+}
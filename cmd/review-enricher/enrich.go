@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"sync"
+)
+
+// defaultConcurrency is the number of reviews analyzed in parallel by default
+const defaultConcurrency = 4
+
+// analyzeFunc produces the analysis of a single review
+type analyzeFunc func(review InputReview) AIAnalysisResult
+
+// enrichReviews analyzes reviews with a pool of concurrency workers and returns
+// the enriched reviews in input order. Reviews not yet started when ctx is
+// done are left out of the result.
+func enrichReviews(ctx context.Context, reviews []InputReview, analyze analyzeFunc, concurrency int) []EnrichedReview {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	results := make([]EnrichedReview, len(reviews))
+	done := make([]bool, len(reviews))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				analysis := analyze(reviews[i])
+				results[i] = newEnrichedReview(reviews[i], analysis)
+				done[i] = true
+				log.Printf("Processed review %d: Sentiment=%s, Department=%s, Product=%s, NeedsAction=%v",
+					reviews[i].ID, analysis.Sentiment, analysis.Department, analysis.Product, analysis.NeedsAction)
+			}
+		}()
+	}
+
+feed:
+	for i := range reviews {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	enriched := make([]EnrichedReview, 0, len(reviews))
+	for i, ok := range done {
+		if ok {
+			enriched = append(enriched, results[i])
+		}
+	}
+	return enriched
+}
+
+// newEnrichedReview combines an input review with its analysis
+func newEnrichedReview(review InputReview, analysis AIAnalysisResult) EnrichedReview {
+	return EnrichedReview{
+		ID:            review.ID,
+		ReviewID:      review.ReviewID,
+		Author:        review.Author,
+		Platform:      review.Platform,
+		Title:         review.Title,
+		Postcontent:   review.Postcontent,
+		ReplyContents: review.ReplyContents,
+		Timestamp:     review.Timestamp,
+		Tags:          review.Tags,
+		Rating:        review.Rating,
+		Sentiment:     analysis.Sentiment,
+		Department:    analysis.Department,
+		Product:       analysis.Product,
+		NeedsAction:   analysis.NeedsAction,
+	}
+}
+
+// newOnlineAnalyzer returns an analyzeFunc using Azure OpenAI when configured,
+// falling back to OpenAI and finally to offline analysis if the APIs fail.
+// Every API call goes through pacer.
+func newOnlineAnalyzer(pacer *requestPacer) analyzeFunc {
+	return func(review InputReview) AIAnalysisResult {
+		return analyzeOnline(pacer, review)
+	}
+}
+
+// analyzeOnline analyzes a review with the AI APIs, pacing calls with pacer
+func analyzeOnline(pacer *requestPacer, review InputReview) AIAnalysisResult {
+	var analysisResult AIAnalysisResult
+	var err error
+
+	azureApiKey := os.Getenv("AZURE_OPENAI_API_KEY")
+	azureEndpoint := os.Getenv("AZURE_OPENAI_ENDPOINT")
+	azureDeployment := os.Getenv("AZURE_OPENAI_DEPLOYMENT")
+	openaiApiKey := os.Getenv("OPENAI_API_KEY")
+
+	if azureDeployment == "" {
+		azureDeployment = "gpt-4.1-mini" // Use default if not set
+	}
+
+	if azureApiKey != "" && azureEndpoint != "" {
+		// Try to analyze with Azure OpenAI
+		analysisResult, err = analyzeWithAzureOpenAI(pacer, azureApiKey, azureEndpoint, azureDeployment, review)
+		if err != nil {
+			log.Printf("Error analyzing review %d with Azure OpenAI: %v", review.ID, err)
+
+			// Fall back to standard OpenAI if available
+			if openaiApiKey != "" {
+				log.Println("Falling back to standard OpenAI API")
+				analysisResult, err = analyzeWithOpenAI(pacer, openaiApiKey, review)
+			}
+		}
+	} else if openaiApiKey != "" {
+		analysisResult, err = analyzeWithOpenAI(pacer, openaiApiKey, review)
+	}
+
+	// If we still have an error, fall back to offline mode
+	if err != nil {
+		log.Printf("Error analyzing review %d: %v", review.ID, err)
+		log.Println("Switching to offline analysis mode for this review")
+		return analyzeOffline(review)
+	}
+
+	// Models don't always stick to the allowed values
+	return normalizeAnalysis(review, analysisResult)
+}
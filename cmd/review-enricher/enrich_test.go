@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnrichReviewsPreservesOrder(t *testing.T) {
+	reviews := sampleInputReviews(50)
+
+	var inFlight, peak int32
+	analyze := func(review InputReview) AIAnalysisResult {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			max := atomic.LoadInt32(&peak)
+			if current <= max || atomic.CompareAndSwapInt32(&peak, max, current) {
+				break
+			}
+		}
+		// Later reviews finish first so ordering cannot come from completion order
+		time.Sleep(time.Duration(50-review.ID) * 100 * time.Microsecond)
+		atomic.AddInt32(&inFlight, -1)
+		return analyzeOffline(review)
+	}
+
+	enriched := enrichReviews(context.Background(), reviews, analyze, 8)
+
+	assert.Len(t, enriched, len(reviews))
+	for i, review := range reviews {
+		assert.Equal(t, review.ID, enriched[i].ID)
+		assert.Equal(t, review.Postcontent, enriched[i].Postcontent)
+		assert.NotEmpty(t, enriched[i].Sentiment)
+	}
+	assert.Greater(t, atomic.LoadInt32(&peak), int32(1))
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(8))
+}
+
+func TestEnrichReviewsStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	enriched := enrichReviews(ctx, sampleInputReviews(10), analyzeOffline, 2)
+
+	assert.Empty(t, enriched)
+}
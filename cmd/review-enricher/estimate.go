@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io"
+)
+
+// charsPerToken is the rough characters-per-token ratio for English text with OpenAI tokenizers
+const charsPerToken = 4
+
+// completionTokensPerReview approximates the size of the four-field JSON answer for one review
+const completionTokensPerReview = 40
+
+// CostEstimateOptions controls how an enrichment run is priced
+type CostEstimateOptions struct {
+	BatchSize            int     // Reviews sent per API call
+	PromptPricePer1K     float64 // USD per 1K prompt tokens
+	CompletionPricePer1K float64 // USD per 1K completion tokens
+}
+
+// CostEstimate summarizes the expected API usage of an enrichment run
+type CostEstimate struct {
+	Reviews          int
+	APICalls         int
+	PromptTokens     int
+	CompletionTokens int
+	EstimatedCostUSD float64
+}
+
+// estimateTokens approximates the token count of a piece of text
+func estimateTokens(text string) int {
+	if text == "" {
+		return 0
+	}
+	return (len(text) + charsPerToken - 1) / charsPerToken
+}
+
+// estimateEnrichmentCost computes the API calls, token usage and cost of enriching reviews
+// without contacting any provider. The system prompt and instructions are counted once per
+// call, while the review-specific part of the prompt is counted once per review.
+func estimateEnrichmentCost(reviews []InputReview, opts CostEstimateOptions) CostEstimate {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1
+	}
+
+	estimate := CostEstimate{
+		Reviews:  len(reviews),
+		APICalls: (len(reviews) + batchSize - 1) / batchSize,
+	}
+
+	// Fixed per-call overhead: system message plus the instruction template
+	overheadTokens := estimateTokens(analysisSystemPrompt) + estimateTokens(buildAnalysisPrompt(InputReview{}))
+	estimate.PromptTokens = estimate.APICalls * overheadTokens
+
+	// Variable per-review content
+	for _, review := range reviews {
+		reviewTokens := estimateTokens(buildAnalysisPrompt(review)) - estimateTokens(buildAnalysisPrompt(InputReview{}))
+		if reviewTokens > 0 {
+			estimate.PromptTokens += reviewTokens
+		}
+	}
+	estimate.CompletionTokens = len(reviews) * completionTokensPerReview
+
+	estimate.EstimatedCostUSD = float64(estimate.PromptTokens)/1000*opts.PromptPricePer1K +
+		float64(estimate.CompletionTokens)/1000*opts.CompletionPricePer1K
+
+	return estimate
+}
+
+// printCostEstimate writes a human-readable cost estimate
+func printCostEstimate(w io.Writer, estimate CostEstimate, opts CostEstimateOptions) {
+	fmt.Fprintln(w, "Enrichment cost estimate (no API calls made)")
+	fmt.Fprintf(w, "  Reviews:           %d\n", estimate.Reviews)
+	fmt.Fprintf(w, "  API calls:         %d (batch size %d)\n", estimate.APICalls, opts.BatchSize)
+	fmt.Fprintf(w, "  Prompt tokens:     ~%d ($%.4f per 1K)\n", estimate.PromptTokens, opts.PromptPricePer1K)
+	fmt.Fprintf(w, "  Completion tokens: ~%d ($%.4f per 1K)\n", estimate.CompletionTokens, opts.CompletionPricePer1K)
+	fmt.Fprintf(w, "  Estimated cost:    $%.4f\n", estimate.EstimatedCostUSD)
+}
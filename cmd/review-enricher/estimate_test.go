@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sampleInputReviews generates n reviews with identical shape for estimation tests
+func sampleInputReviews(n int) []InputReview {
+	reviews := make([]InputReview, 0, n)
+	for i := 0; i < n; i++ {
+		reviews = append(reviews, InputReview{
+			ID:          i + 1,
+			ReviewID:    1000 + i,
+			Platform:    "G2",
+			Title:       "Solid DDI platform",
+			Postcontent: fmt.Sprintf("Review %04d: NIOS grid has been reliable but upgrades are slow.", i),
+			Tags:        []string{"DNS", "DHCP"},
+			Rating:      4,
+		})
+	}
+	return reviews
+}
+
+func TestEstimateEnrichmentCostScalesWithInput(t *testing.T) {
+	opts := CostEstimateOptions{BatchSize: 1, PromptPricePer1K: 0.001, CompletionPricePer1K: 0.002}
+
+	small := estimateEnrichmentCost(sampleInputReviews(10), opts)
+	large := estimateEnrichmentCost(sampleInputReviews(100), opts)
+
+	assert.Equal(t, 10, small.APICalls)
+	assert.Equal(t, 100, large.APICalls)
+	assert.Equal(t, 10*small.PromptTokens, large.PromptTokens)
+	assert.Equal(t, 10*small.CompletionTokens, large.CompletionTokens)
+	assert.InDelta(t, 10*small.EstimatedCostUSD, large.EstimatedCostUSD, 1e-9)
+	assert.Greater(t, small.EstimatedCostUSD, 0.0)
+}
+
+func TestEstimateEnrichmentCostRespectsBatchSize(t *testing.T) {
+	reviews := sampleInputReviews(25)
+
+	single := estimateEnrichmentCost(reviews, CostEstimateOptions{BatchSize: 1, PromptPricePer1K: 0.001})
+	batched := estimateEnrichmentCost(reviews, CostEstimateOptions{BatchSize: 10, PromptPricePer1K: 0.001})
+
+	assert.Equal(t, 25, single.APICalls)
+	assert.Equal(t, 3, batched.APICalls)
+
+	// Batching shares the instruction overhead, so it needs fewer prompt tokens
+	assert.Less(t, batched.PromptTokens, single.PromptTokens)
+	assert.Equal(t, single.CompletionTokens, batched.CompletionTokens)
+	assert.Less(t, batched.EstimatedCostUSD, single.EstimatedCostUSD)
+}
+
+func TestEstimateEnrichmentCostEmptyInput(t *testing.T) {
+	estimate := estimateEnrichmentCost(nil, CostEstimateOptions{BatchSize: 5, PromptPricePer1K: 0.001})
+
+	assert.Equal(t, 0, estimate.APICalls)
+	assert.Equal(t, 0, estimate.PromptTokens)
+	assert.Equal(t, 0.0, estimate.EstimatedCostUSD)
+}
+
+func TestPrintCostEstimate(t *testing.T) {
+	opts := CostEstimateOptions{BatchSize: 1, PromptPricePer1K: 0.0005, CompletionPricePer1K: 0.0015}
+	var buf bytes.Buffer
+
+	printCostEstimate(&buf, estimateEnrichmentCost(sampleInputReviews(3), opts), opts)
+
+	assert.Contains(t, buf.String(), "API calls:         3")
+	assert.Contains(t, buf.String(), "Estimated cost:")
+}
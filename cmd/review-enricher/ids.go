@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+)
+
+// ID modes for enriched output, chosen with the -ids flag
+const (
+	// idsSequential keeps input IDs, renumbering from 1 when files are merged
+	idsSequential = "sequential"
+	// idsStable derives each ID from the review's platform and review ID
+	idsStable = "stable"
+)
+
+// maxStableID keeps stable IDs within the integers a JSON consumer using
+// float64, such as JavaScript, reads exactly
+const maxStableID = 1<<53 - 1
+
+// validateIDMode rejects an unknown -ids value
+func validateIDMode(mode string) error {
+	switch mode {
+	case idsSequential, idsStable:
+		return nil
+	default:
+		return fmt.Errorf("unknown id mode %q, expected %q or %q", mode, idsSequential, idsStable)
+	}
+}
+
+// stableReviewID hashes the review's platform and review ID, so the same
+// review gets the same ID in every run and in every file it is merged from.
+// Reviews without a review ID fall back to their author, timestamp and content.
+func stableReviewID(review EnrichedReview) int {
+	h := fnv.New64a()
+	platform := strings.ToLower(strings.TrimSpace(review.Platform))
+	if review.ReviewID != 0 {
+		fmt.Fprintf(h, "%s\x00%d", platform, review.ReviewID)
+	} else {
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s", platform, review.Author, review.Timestamp, review.Postcontent)
+	}
+	return int(h.Sum64() & maxStableID)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStableIDsAreIdempotentAcrossRuns(t *testing.T) {
+	inputDir := t.TempDir()
+	writeReviewsFile(t, inputDir, "first.json", sampleInputReviews(3))
+	second := sampleInputReviews(2)
+	for i := range second {
+		second[i].ReviewID += 100
+	}
+	writeReviewsFile(t, inputDir, "second.json", second)
+
+	inputPaths, err := resolveInputs(inputDir)
+	assert.NoError(t, err)
+
+	run := func() []EnrichedReview {
+		outputPath := filepath.Join(t.TempDir(), "enriched_reviews.json")
+		err := runEnrichment(context.Background(), enrichOptions{
+			InputPaths:  inputPaths,
+			OutputPath:  outputPath,
+			Offline:     true,
+			Concurrency: 2,
+			IDs:         idsStable,
+		}, &bytes.Buffer{})
+		assert.NoError(t, err)
+		return readEnrichedFile(t, outputPath)
+	}
+
+	first, rerun := run(), run()
+	assert.Len(t, first, 5)
+	seen := make(map[int]bool)
+	for i, review := range first {
+		assert.Equal(t, review.ID, rerun[i].ID)
+		assert.Equal(t, stableReviewID(review), review.ID)
+		assert.False(t, seen[review.ID], "ids are unique across merged files")
+		seen[review.ID] = true
+	}
+}
+
+func TestStableReviewID(t *testing.T) {
+	review := EnrichedReview{ID: 1, ReviewID: 1000, Platform: "G2"}
+	renumbered := EnrichedReview{ID: 7, ReviewID: 1000, Platform: " g2 ", Title: "edited"}
+	otherPlatform := EnrichedReview{ID: 1, ReviewID: 1000, Platform: "Reddit"}
+
+	assert.Equal(t, stableReviewID(review), stableReviewID(renumbered), "depends only on platform and review ID")
+	assert.NotEqual(t, stableReviewID(review), stableReviewID(otherPlatform))
+	assert.LessOrEqual(t, stableReviewID(review), maxStableID)
+
+	// Without a review ID the content identifies the review
+	a := EnrichedReview{Platform: "G2", Author: "pat", Postcontent: "DNS is slow"}
+	b := EnrichedReview{Platform: "G2", Author: "pat", Postcontent: "DHCP is slow"}
+	assert.NotEqual(t, stableReviewID(a), stableReviewID(b))
+}
+
+func TestValidateIDMode(t *testing.T) {
+	assert.NoError(t, validateIDMode(idsSequential))
+	assert.NoError(t, validateIDMode(idsStable))
+	assert.EqualError(t, validateIDMode("random"), `unknown id mode "random", expected "sequential" or "stable"`)
+}
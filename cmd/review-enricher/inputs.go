@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// enrichedSuffix names the per-file outputs written next to each input
+const enrichedSuffix = ".enriched.json"
+
+// resolveInputs expands the -input value into the JSON files to enrich. The
+// value may be a single file, a directory, whose *.json files are all used,
+// or a glob pattern. Files are returned in name order and earlier per-file
+// outputs are skipped so rerunning over a directory does not enrich them again.
+func resolveInputs(input string) ([]string, error) {
+	info, err := os.Stat(input)
+	switch {
+	case err == nil && !info.IsDir():
+		return []string{input}, nil
+	case err == nil:
+		input = filepath.Join(input, "*.json")
+	case !strings.ContainsAny(input, "*?["):
+		return nil, err
+	}
+
+	matches, err := filepath.Glob(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid input pattern %q: %w", input, err)
+	}
+
+	var paths []string
+	for _, path := range matches {
+		if strings.HasSuffix(path, enrichedSuffix) {
+			continue
+		}
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			paths = append(paths, path)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no JSON files match %q", input)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// perFileOutputPath returns where the enriched copy of inputPath is written
+// when inputs are not merged
+func perFileOutputPath(inputPath string) string {
+	return strings.TrimSuffix(inputPath, filepath.Ext(inputPath)) + enrichedSuffix
+}
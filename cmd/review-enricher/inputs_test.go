@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeReviewsFile writes reviews as a JSON array to dir/name
+func writeReviewsFile(t *testing.T, dir, name string, reviews []InputReview) string {
+	data, err := json.Marshal(reviews)
+	assert.NoError(t, err)
+	path := filepath.Join(dir, name)
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+// readEnrichedFile decodes an enriched output file
+func readEnrichedFile(t *testing.T, path string) []EnrichedReview {
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var enriched []EnrichedReview
+	assert.NoError(t, json.Unmarshal(data, &enriched))
+	return enriched
+}
+
+func TestRunEnrichmentMergesDirectory(t *testing.T) {
+	inputDir := t.TempDir()
+	// Both files number their reviews from 1, as per-product scrapes do
+	nios := sampleInputReviews(3)
+	bloxone := sampleInputReviews(2)
+	writeReviewsFile(t, inputDir, "nios_reviews.json", nios)
+	writeReviewsFile(t, inputDir, "bloxone_reviews.json", bloxone)
+	assert.NoError(t, os.WriteFile(filepath.Join(inputDir, "notes.txt"), []byte("not reviews"), 0644))
+
+	inputPaths, err := resolveInputs(inputDir)
+	assert.NoError(t, err)
+	outputPath := filepath.Join(t.TempDir(), "enriched_reviews.json")
+
+	err = runEnrichment(context.Background(), enrichOptions{
+		InputPaths:  inputPaths,
+		OutputPath:  outputPath,
+		Offline:     true,
+		Concurrency: 2,
+	}, &bytes.Buffer{})
+	assert.NoError(t, err)
+
+	enriched := readEnrichedFile(t, outputPath)
+	assert.Len(t, enriched, 5)
+	// Files are merged in name order and IDs renumbered to stay unique
+	expected := append(append([]InputReview{}, bloxone...), nios...)
+	for i, review := range enriched {
+		assert.Equal(t, i+1, review.ID)
+		assert.Equal(t, expected[i].ReviewID, review.ReviewID)
+		assert.NotEmpty(t, review.Sentiment)
+	}
+}
+
+func TestRunEnrichmentPerFile(t *testing.T) {
+	inputDir := t.TempDir()
+	first := writeReviewsFile(t, inputDir, "first.json", sampleInputReviews(3))
+	second := writeReviewsFile(t, inputDir, "second.json", sampleInputReviews(2))
+
+	inputPaths, err := resolveInputs(filepath.Join(inputDir, "*.json"))
+	assert.NoError(t, err)
+
+	err = runEnrichment(context.Background(), enrichOptions{
+		InputPaths:  inputPaths,
+		PerFile:     true,
+		Offline:     true,
+		Concurrency: 2,
+	}, &bytes.Buffer{})
+	assert.NoError(t, err)
+
+	firstOut := readEnrichedFile(t, perFileOutputPath(first))
+	secondOut := readEnrichedFile(t, perFileOutputPath(second))
+	assert.Len(t, firstOut, 3)
+	assert.Len(t, secondOut, 2)
+	// Per-file outputs keep the original IDs
+	assert.Equal(t, 1, secondOut[0].ID)
+
+	// A rerun over the directory ignores the outputs written next to the inputs
+	inputPaths, err = resolveInputs(inputDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{first, second}, inputPaths)
+}
+
+func TestResolveInputs(t *testing.T) {
+	dir := t.TempDir()
+	file := writeReviewsFile(t, dir, "reviews.json", sampleInputReviews(1))
+
+	paths, err := resolveInputs(file)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{file}, paths)
+
+	_, err = resolveInputs(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+
+	_, err = resolveInputs(filepath.Join(dir, "*.csv"))
+	assert.Error(t, err)
+
+	_, err = resolveInputs(t.TempDir())
+	assert.Error(t, err)
+}
+
+func TestPerFileOutputPath(t *testing.T) {
+	assert.Equal(t, filepath.Join("output", "nios_reviews.enriched.json"), perFileOutputPath(filepath.Join("output", "nios_reviews.json")))
+}
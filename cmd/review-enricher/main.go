@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -13,7 +14,9 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
 
+	"github.com/Infoblox-CTO/review-scraper/pkg/taxonomy"
 	"github.com/joho/godotenv"
 )
 
@@ -88,104 +91,6 @@ type AIAnalysisResult struct {
 	NeedsAction bool   `json:"needsAction"`
 }
 
-// InfobloxCategories contains mappings of keywords to their categories for analysis
-type InfobloxCategories struct {
-	defaultCategories map[string]string
-	infobloxTerms     map[string]string
-	productKeywords   map[string][]string
-}
-
-// Initialize Infoblox-specific knowledge base for enhanced AI analysis
-var infobloxCategories = InfobloxCategories{
-	defaultCategories: map[string]string{
-		"bug":        "bug_report",
-		"crash":      "bug_report",
-		"error":      "bug_report",
-		"broken":     "bug_report",
-		"freeze":     "bug_report",
-		"slow":       "performance",
-		"laggy":      "performance",
-		"hang":       "performance",
-		"feature":    "feature_request",
-		"missing":    "feature_request",
-		"wish":       "feature_request",
-		"delivery":   "logistics",
-		"shipping":   "logistics",
-		"payment":    "billing",
-		"charge":     "billing",
-		"refund":     "billing",
-		"support":    "customer_service",
-		"service":    "customer_service",
-		"rude":       "customer_service",
-		"interface":  "ui_ux",
-		"confusing":  "ui_ux",
-		"difficult":  "ui_ux",
-		"dns":        "network_services",
-		"dhcp":       "network_services",
-		"ipam":       "network_services",
-		"ddi":        "network_services",
-		"nios":       "nios_platform",
-		"bloxone":    "bloxone_platform",
-		"threat":     "security",
-		"secure":     "security",
-		"protection": "security",
-		"ddos":       "security",
-		"cloud":      "cloud_services",
-		"automation": "network_automation",
-		"netmri":     "network_automation",
-	},
-	infobloxTerms: map[string]string{
-		"ddi":            "core_product",
-		"dns":            "core_product",
-		"dhcp":           "core_product",
-		"ipam":           "core_product",
-		"bloxone":        "cloud_product",
-		"nios":           "on_prem_product",
-		"universal ddi":  "core_product",
-		"threat defense": "security_product",
-		"advanced dns":   "security_product",
-		"grid":           "infrastructure",
-		"netmri":         "automation",
-		"cloud network":  "cloud_product",
-		"ip address":     "core_product",
-		"outbound dns":   "security_product",
-		"dns firewall":   "security_product",
-		"lookup":         "core_product",
-		"zone":           "core_product",
-		"record":         "core_product",
-		"domain":         "core_product",
-		"bind":           "core_product",
-		"lease":          "core_product",
-		"subnet":         "core_product",
-		"network":        "core_product",
-		"vista":          "company",
-		"warburg":        "company",
-	},
-	productKeywords: map[string][]string{
-		"bloxone": {
-			"bloxone", "cloud ddi", "cloud-native", "saas", "branch", "distributed",
-		},
-		"nios": {
-			"nios", "grid", "on-premise", "on-prem", "appliance", "virtual appliance", "vm",
-		},
-		"threat_defense": {
-			"threat defense", "security", "protect", "threat", "malware", "ransomware",
-			"exfiltration", "dns security", "secure dns", "threat intelligence",
-		},
-		"dns": {
-			"dns", "domain", "lookup", "zone", "record", "cname", "mx", "soa", "ns", "ptr",
-			"a record", "aaaa", "bind", "recursive", "resolver",
-		},
-		"dhcp": {
-			"dhcp", "lease", "ip assignment", "dynamic", "scope", "ip address allocation",
-		},
-		"ipam": {
-			"ipam", "ip address management", "subnet", "allocation", "address space",
-			"network management", "ip management",
-		},
-	},
-}
-
 // Department mappings for consistent department assignment
 var departmentMappings = map[string]string{
 	"bug_report":         "Engineering",
@@ -232,10 +137,23 @@ func main() {
 
 	// Add command-line flag for offline mode
 	offlinePtr := flag.Bool("offline", false, "Use offline analysis mode instead of AI API")
-	inputFilePtr := flag.String("input", "scraped_data.json", "Path to input JSON file")
-	outputFilePtr := flag.String("output", "enriched_reviews.json", "Path to output JSON file")
+	inputFilePtr := flag.String("input", "scraped_data.json", "Path to an input JSON file, a directory of them, or a glob such as 'output/*.json'")
+	outputFilePtr := flag.String("output", "enriched_reviews.json", "Path to the output JSON file that all inputs are merged into")
+	perFilePtr := flag.Bool("per-file", false, "Write each input's enriched reviews next to it as <name>.enriched.json instead of merging")
+	estimatePtr := flag.Bool("estimate", false, "Estimate API calls, tokens and cost without calling any API")
+	batchSizePtr := flag.Int("batch-size", 1, "Reviews per API call assumed when estimating cost")
+	promptPricePtr := flag.Float64("prompt-price", 0.0005, "USD per 1K prompt tokens used when estimating cost")
+	completionPricePtr := flag.Float64("completion-price", 0.0015, "USD per 1K completion tokens used when estimating cost")
+	dryRunPtr := flag.Bool("dry-run", false, "Analyze offline and print a summary without writing the output file")
+	concurrencyPtr := flag.Int("concurrency", defaultConcurrency, "Number of reviews analyzed in parallel")
+	rpmPtr := flag.Int("rpm", defaultRequestsPerMinute, "Maximum API requests per minute across all workers (0 for no limit)")
+	idsPtr := flag.String("ids", idsSequential, "Output ids: 'sequential' keeps input ids (renumbered when merging), 'stable' hashes platform and reviewID so reruns and merges produce the same ids")
 	flag.Parse()
 
+	if err := validateIDMode(*idsPtr); err != nil {
+		log.Fatalf("Invalid -ids: %v", err)
+	}
+
 	// Define file paths
 	inputFilePath := *inputFilePtr
 	outputFilePath := *outputFilePtr
@@ -252,21 +170,30 @@ func main() {
 		}
 	}
 
-	// Read the scraped data
-	inputData, err := os.ReadFile(inputFilePath)
+	inputFilePaths, err := resolveInputs(inputFilePath)
 	if err != nil {
-		log.Fatalf("Error reading input file: %v", err)
+		log.Fatalf("Error finding input files: %v", err)
 	}
 
-	var inputReviews []InputReview
-	if err := json.Unmarshal(inputData, &inputReviews); err != nil {
-		log.Fatalf("Error parsing input JSON: %v", err)
+	// Estimate the cost of the run and exit without calling any API
+	if *estimatePtr {
+		inputReviews, err := readReviews(inputFilePaths)
+		if err != nil {
+			log.Fatalf("Error reading input file: %v", err)
+		}
+		opts := CostEstimateOptions{
+			BatchSize:            *batchSizePtr,
+			PromptPricePer1K:     *promptPricePtr,
+			CompletionPricePer1K: *completionPricePtr,
+		}
+		printCostEstimate(os.Stdout, estimateEnrichmentCost(inputReviews, opts), opts)
+		return
 	}
 
-	log.Printf("Processing %d reviews...", len(inputReviews))
+	log.Printf("Processing reviews from %s...", strings.Join(inputFilePaths, ", "))
 
-	// Check if we're using offline mode
-	useOfflineMode := *offlinePtr
+	// Check if we're using offline mode; dry runs never call a paid API
+	useOfflineMode := *offlinePtr || *dryRunPtr
 
 	// If not explicitly offline, check for API keys in order of preference
 	if !useOfflineMode {
@@ -290,145 +217,43 @@ func main() {
 			log.Println("No API keys found. Using offline analysis mode.")
 			useOfflineMode = true
 		}
+	} else if *dryRunPtr {
+		log.Println("Dry run selected. Using offline analysis and skipping the output file.")
 	} else {
 		log.Println("Offline analysis mode selected. Using local analysis algorithms.")
 	}
 
-	enrichedReviews := make([]EnrichedReview, 0, len(inputReviews))
-
-	// Process each review
-	for i, inputReview := range inputReviews {
-		var analysisResult AIAnalysisResult
-		var err error
-
-		if !useOfflineMode {
-			// First try Azure OpenAI if credentials are available
-			azureApiKey := os.Getenv("AZURE_OPENAI_API_KEY")
-			azureEndpoint := os.Getenv("AZURE_OPENAI_ENDPOINT")
-			azureDeployment := os.Getenv("AZURE_OPENAI_DEPLOYMENT")
-
-			if azureDeployment == "" {
-				azureDeployment = "gpt-4.1-mini" // Use default if not set
-			}
-
-			if azureApiKey != "" && azureEndpoint != "" {
-				// Try to analyze with Azure OpenAI
-				analysisResult, err = analyzeWithAzureOpenAI(azureApiKey, azureEndpoint, azureDeployment, inputReview)
-				if err != nil {
-					log.Printf("Error analyzing review %d with Azure OpenAI: %v", inputReview.ID, err)
-
-					// Fall back to standard OpenAI if available
-					openaiApiKey := os.Getenv("OPENAI_API_KEY")
-					if openaiApiKey != "" {
-						log.Println("Falling back to standard OpenAI API")
-						analysisResult, err = analyzeWithOpenAI(openaiApiKey, inputReview)
-					}
-				}
-			} else {
-				// Try standard OpenAI
-				openaiApiKey := os.Getenv("OPENAI_API_KEY")
-				if openaiApiKey != "" {
-					analysisResult, err = analyzeWithOpenAI(openaiApiKey, inputReview)
-				}
-			}
-
-			// If we still have an error, fall back to offline mode
-			if err != nil {
-				log.Printf("Error analyzing review %d: %v", inputReview.ID, err)
-				log.Println("Switching to offline analysis mode for this review")
-				analysisResult = analyzeOffline(inputReview)
-			}
-		} else {
-			// Use offline analysis
-			analysisResult = analyzeOffline(inputReview)
-		}
-
-		// Create enriched review
-		enrichedReview := EnrichedReview{
-			ID:            inputReview.ID,
-			ReviewID:      inputReview.ReviewID,
-			Author:        inputReview.Author,
-			Platform:      inputReview.Platform,
-			Title:         inputReview.Title,
-			Postcontent:   inputReview.Postcontent,
-			ReplyContents: inputReview.ReplyContents,
-			Timestamp:     inputReview.Timestamp,
-			Tags:          inputReview.Tags,
-			Rating:        inputReview.Rating,
-			Sentiment:     analysisResult.Sentiment,
-			Department:    analysisResult.Department,
-			Product:       analysisResult.Product,
-			NeedsAction:   analysisResult.NeedsAction,
-		}
-
-		enrichedReviews = append(enrichedReviews, enrichedReview)
-		log.Printf("Processed review %d: Sentiment=%s, Department=%s, Product=%s, NeedsAction=%v",
-			inputReview.ID, analysisResult.Sentiment, analysisResult.Department, analysisResult.Product, analysisResult.NeedsAction)
-
-		// Add small delay to avoid rate limiting if using online mode
-		if !useOfflineMode && i < len(inputReviews)-1 {
-			time.Sleep(200 * time.Millisecond)
-		}
+	opts := enrichOptions{
+		InputPaths:        inputFilePaths,
+		OutputPath:        outputFilePath,
+		PerFile:           *perFilePtr,
+		Offline:           useOfflineMode,
+		DryRun:            *dryRunPtr,
+		Concurrency:       *concurrencyPtr,
+		RequestsPerMinute: *rpmPtr,
+		IDs:               *idsPtr,
 	}
-
-	log.Printf("Processed %d reviews successfully", len(enrichedReviews))
-
-	// Write enriched reviews to output file
-	outputData, err := json.MarshalIndent(enrichedReviews, "", "  ")
-	if err != nil {
-		log.Fatalf("Error creating output JSON: %v", err)
+	if err := runEnrichment(context.Background(), opts, os.Stdout); err != nil {
+		log.Fatalf("Error enriching reviews: %v", err)
 	}
+}
 
-	if err := os.WriteFile(outputFilePath, outputData, 0644); err != nil {
-		log.Fatalf("Error writing output file: %v", err)
-	}
+// analysisSystemPrompt is the system message sent with every analysis request
+const analysisSystemPrompt = "You are an expert Infoblox product review analyzer. You classify reviews by sentiment, department, product, and prioritize actions needed."
 
-	log.Printf("Enriched reviews saved to %s", outputFilePath)
+// analyzeWithOpenAI uses the OpenAI API to analyze a review
+func analyzeWithOpenAI(pacer *requestPacer, apiKey string, review InputReview) (AIAnalysisResult, error) {
+	return analyzeWithRetry(func(systemPrompt string) (string, error) {
+		return requestOpenAIAnalysis(pacer, apiKey, review, systemPrompt)
+	})
 }
 
-// analyzeWithOpenAI uses the OpenAI API to analyze a review
-func analyzeWithOpenAI(apiKey string, review InputReview) (AIAnalysisResult, error) {
+// requestOpenAIAnalysis asks the OpenAI API to analyze a review and returns the raw reply
+func requestOpenAIAnalysis(pacer *requestPacer, apiKey string, review InputReview, systemPrompt string) (string, error) {
 	url := "https://api.openai.com/v1/chat/completions"
 
 	// Construct the prompt for OpenAI with Infoblox-specific knowledge
-	prompt := fmt.Sprintf(`
-As an Infoblox product review analyzer, analyze the following review and provide a structured response.
-
-INFOBLOX PRODUCT CONTEXT:
-- Core Products: DDI (DNS, DHCP, IPAM)
-- Cloud products: BloxOne Platform (cloud-native DDI solutions)
-- On-prem products: NIOS (traditional appliance-based DDI)
-- Security products: BloxOne Threat Defense, DNS firewall, Advanced DNS Protection
-- Network Automation: NetMRI, Cloud Network Automation
-
-REVIEW INFORMATION:
-Title: %s
-Content: %s
-Platform: %s
-Rating: %d (out of 5)
-Tags: %s
-
-DEPARTMENT MAPPING:
-- Product team: Handles feature requests, UI/UX issues
-- Engineering: Handles bugs, performance issues, technical problems
-- Support: Handles customer service issues, documentation
-- Sales: Handles billing, pricing, licensing issues
-- General: General feedback that doesn't fit elsewhere
-
-ANALYSIS TASK:
-1. Sentiment: Classify as exactly one of ["Positive", "Neutral", "Negative"] based on review content and rating if there is good review must map to positive
-2. Department: Assign to exactly one of ["Product", "Engineering", "Support", "Sales", "General"] based on review content
-3. Product: Identify which specific Infoblox product is being discussed (BloxOne Platform, NIOS, BloxOne Threat Defense, BloxOne DNS, BloxOne DHCP, BloxOne IPAM, etc.)
-4. NeedsAction: Set to true if this is a high-priority issue that needs immediate attention (e.g., negative review with serious issues, security concern, etc.), otherwise false
-
-Respond with a JSON object containing ONLY these four fields:
-{
-  "sentiment": "Positive/Neutral/Negative",
-  "department": "Department name",
-  "product": "Product name",
-  "needsAction": true/false
-}
-`, review.Title, review.Postcontent, review.Platform, review.Rating, strings.Join(review.Tags, ", "))
+	prompt := buildAnalysisPrompt(review)
 
 	// Create the request body
 	requestBody := OpenAIRequest{
@@ -436,7 +261,7 @@ Respond with a JSON object containing ONLY these four fields:
 		Messages: []ChatMessage{
 			{
 				Role:    "system",
-				Content: "You are an expert Infoblox product review analyzer. You classify reviews by sentiment, department, product, and prioritize actions needed.",
+				Content: systemPrompt,
 			},
 			{
 				Role:    "user",
@@ -449,63 +274,104 @@ Respond with a JSON object containing ONLY these four fields:
 	// Convert the request to JSON
 	requestJSON, err := json.Marshal(requestBody)
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error marshalling request: %v", err)
+		return "", fmt.Errorf("error marshalling request: %v", err)
 	}
 
 	// Create HTTP request
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(requestJSON))
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error creating request: %v", err)
+		return "", fmt.Errorf("error creating request: %v", err)
 	}
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+apiKey)
 
-	// Send the request
+	// Send the request within the provider's rate limit
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := pacer.Do(client, req)
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error sending request to OpenAI: %v", err)
+		return "", fmt.Errorf("error sending request to OpenAI: %v", err)
 	}
 	defer resp.Body.Close()
 
 	// Read the response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error reading response: %v", err)
+		return "", fmt.Errorf("error reading response: %v", err)
 	}
 
 	// Check if the response status is not OK
 	if resp.StatusCode != http.StatusOK {
-		return AIAnalysisResult{}, fmt.Errorf("OpenAI API error: %s", string(respBody))
+		return "", fmt.Errorf("OpenAI API error: %s", string(respBody))
 	}
 
 	// Parse the response
 	var openAIResp OpenAIResponse
 	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error parsing OpenAI response: %v", err)
+		return "", fmt.Errorf("error parsing OpenAI response: %v", err)
 	}
 
 	// Check if there's content in the response
 	if len(openAIResp.Choices) == 0 {
-		return AIAnalysisResult{}, fmt.Errorf("no content in OpenAI response")
+		return "", fmt.Errorf("no content in OpenAI response")
 	}
 
-	// Extract the AI's analysis from the response content
-	analysisJSON := openAIResp.Choices[0].Message.Content
+	// Return the AI's analysis for the caller to parse
+	return openAIResp.Choices[0].Message.Content, nil
+}
 
-	// Parse the AI's analysis
-	var analysisResult AIAnalysisResult
-	if err := json.Unmarshal([]byte(analysisJSON), &analysisResult); err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error parsing AI analysis: %v\nResponse was: %s", err, analysisJSON)
-	}
+// buildAnalysisPrompt constructs the OpenAI prompt for a review with Infoblox-specific knowledge
+func buildAnalysisPrompt(review InputReview) string {
+	return fmt.Sprintf(`
+As an Infoblox product review analyzer, analyze the following review and provide a structured response.
+
+INFOBLOX PRODUCT CONTEXT:
+- Core Products: DDI (DNS, DHCP, IPAM)
+- Cloud products: BloxOne Platform (cloud-native DDI solutions)
+- On-prem products: NIOS (traditional appliance-based DDI)
+- Security products: BloxOne Threat Defense, DNS firewall, Advanced DNS Protection
+- Network Automation: NetMRI, Cloud Network Automation
+
+REVIEW INFORMATION:
+Title: %s
+Content: %s
+Platform: %s
+Rating: %d (out of 5)
+Tags: %s
+
+DEPARTMENT MAPPING:
+- Product team: Handles feature requests, UI/UX issues
+- Engineering: Handles bugs, performance issues, technical problems
+- Support: Handles customer service issues, documentation
+- Sales: Handles billing, pricing, licensing issues
+- General: General feedback that doesn't fit elsewhere
+
+ANALYSIS TASK:
+1. Sentiment: Classify as exactly one of ["Positive", "Neutral", "Negative"] based on review content and rating if there is good review must map to positive
+2. Department: Assign to exactly one of ["Product", "Engineering", "Support", "Sales", "General"] based on review content
+3. Product: Identify which specific Infoblox product is being discussed (BloxOne Platform, NIOS, BloxOne Threat Defense, BloxOne DNS, BloxOne DHCP, BloxOne IPAM, etc.)
+4. NeedsAction: Set to true if this is a high-priority issue that needs immediate attention (e.g., negative review with serious issues, security concern, etc.), otherwise false
 
-	return analysisResult, nil
+Respond with a JSON object containing ONLY these four fields:
+{
+  "sentiment": "Positive/Neutral/Negative",
+  "department": "Department name",
+  "product": "Product name",
+  "needsAction": true/false
+}
+`, review.Title, review.Postcontent, review.Platform, review.Rating, strings.Join(review.Tags, ", "))
 }
 
 // analyzeWithAzureOpenAI uses the Azure OpenAI API to analyze a review
-func analyzeWithAzureOpenAI(apiKey string, endpoint string, deploymentName string, review InputReview) (AIAnalysisResult, error) {
+func analyzeWithAzureOpenAI(pacer *requestPacer, apiKey string, endpoint string, deploymentName string, review InputReview) (AIAnalysisResult, error) {
+	return analyzeWithRetry(func(systemPrompt string) (string, error) {
+		return requestAzureOpenAIAnalysis(pacer, apiKey, endpoint, deploymentName, review, systemPrompt)
+	})
+}
+
+// requestAzureOpenAIAnalysis asks the Azure OpenAI API to analyze a review and returns the raw reply
+func requestAzureOpenAIAnalysis(pacer *requestPacer, apiKey string, endpoint string, deploymentName string, review InputReview, systemPrompt string) (string, error) {
 	// Construct the URL for Azure OpenAI API
 	url := fmt.Sprintf("%s/openai/deployments/%s/chat/completions?api-version=2025-01-01-preview", endpoint, deploymentName)
 
@@ -554,7 +420,7 @@ Respond with a JSON object containing ONLY these four fields:
 		Messages: []ChatMessage{
 			{
 				Role:    "system",
-				Content: "You are an expert Infoblox product review analyzer. You classify reviews by sentiment, department, product, and prioritize actions needed.",
+				Content: systemPrompt,
 			},
 			{
 				Role:    "user",
@@ -567,59 +433,51 @@ Respond with a JSON object containing ONLY these four fields:
 	// Convert the request to JSON
 	requestJSON, err := json.Marshal(requestBody)
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error marshalling request: %v", err)
+		return "", fmt.Errorf("error marshalling request: %v", err)
 	}
 
 	// Create HTTP request
 	req, err := http.NewRequest("POST", url, bytes.NewBuffer(requestJSON))
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error creating request: %v", err)
+		return "", fmt.Errorf("error creating request: %v", err)
 	}
 
 	// Set headers for Azure OpenAI
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("api-key", apiKey)
 
-	// Send the request
+	// Send the request within the provider's rate limit
 	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := pacer.Do(client, req)
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error sending request to Azure OpenAI: %v", err)
+		return "", fmt.Errorf("error sending request to Azure OpenAI: %v", err)
 	}
 	defer resp.Body.Close()
 
 	// Read the response
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error reading response: %v", err)
+		return "", fmt.Errorf("error reading response: %v", err)
 	}
 
 	// Check if the response status is not OK
 	if resp.StatusCode != http.StatusOK {
-		return AIAnalysisResult{}, fmt.Errorf("Azure OpenAI API error: %s", string(respBody))
+		return "", fmt.Errorf("Azure OpenAI API error: %s", string(respBody))
 	}
 
 	// Parse the response (same structure as OpenAI response)
 	var openAIResp OpenAIResponse
 	if err := json.Unmarshal(respBody, &openAIResp); err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error parsing Azure OpenAI response: %v", err)
+		return "", fmt.Errorf("error parsing Azure OpenAI response: %v", err)
 	}
 
 	// Check if there's content in the response
 	if len(openAIResp.Choices) == 0 {
-		return AIAnalysisResult{}, fmt.Errorf("no content in Azure OpenAI response")
-	}
-
-	// Extract the AI's analysis from the response content
-	analysisJSON := openAIResp.Choices[0].Message.Content
-
-	// Parse the AI's analysis
-	var analysisResult AIAnalysisResult
-	if err := json.Unmarshal([]byte(analysisJSON), &analysisResult); err != nil {
-		return AIAnalysisResult{}, fmt.Errorf("error parsing AI analysis: %v\nResponse was: %s", err, analysisJSON)
+		return "", fmt.Errorf("no content in Azure OpenAI response")
 	}
 
-	return analysisResult, nil
+	// Return the AI's analysis for the caller to parse
+	return openAIResp.Choices[0].Message.Content, nil
 }
 
 // analyzeOffline performs a local analysis of the review without using external APIs
@@ -742,6 +600,15 @@ func determineDepartment(review InputReview) string {
 		}
 	}
 
+	// Words with a default intent category count toward its department
+	for _, word := range strings.FieldsFunc(text, func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if category, found := taxonomy.CategoryForKeyword(word); found {
+			if dept, ok := departmentMappings[category]; ok && dept != "General" {
+				departmentCounts[dept]++
+			}
+		}
+	}
+
 	// Find department with highest count
 	maxCount := 0
 	maxDept := "General" // default
@@ -774,65 +641,14 @@ func determineProduct(review InputReview) string {
 		tags = append(tags, strings.ToLower(tag))
 	}
 
-	// Check for specific product mentions
-	productMatches := map[string]int{
-		"BloxOne Platform":                 0,
-		"NIOS":                             0,
-		"BloxOne Threat Defense":           0,
-		"BloxOne DNS":                      0,
-		"BloxOne DHCP":                     0,
-		"BloxOne IPAM":                     0,
-		"BloxOne Cloud Network Automation": 0,
-		"BloxOne DDI":                      0,
-	}
-
-	// Match product keywords to specific products
-	for product, keywords := range infobloxCategories.productKeywords {
-		for _, keyword := range keywords {
-			count := strings.Count(text, keyword)
-
-			// Check tags too
-			for _, tag := range tags {
-				if strings.Contains(tag, keyword) {
-					count++
-				}
-			}
-
-			// Assign counts to proper product names
-			switch product {
-			case "bloxone":
-				productMatches["BloxOne Platform"] += count
-			case "nios":
-				productMatches["NIOS"] += count
-			case "threat_defense":
-				productMatches["BloxOne Threat Defense"] += count
-			case "dns":
-				productMatches["BloxOne DNS"] += count
-				productMatches["BloxOne DDI"] += count / 2 // Partial match for DDI
-			case "dhcp":
-				productMatches["BloxOne DHCP"] += count
-				productMatches["BloxOne DDI"] += count / 2 // Partial match for DDI
-			case "ipam":
-				productMatches["BloxOne IPAM"] += count
-				productMatches["BloxOne DDI"] += count / 2 // Partial match for DDI
-			}
-		}
-	}
+	// Match product keywords, as whole words, to specific products
+	taxonomyText := review.Title + "\n" + review.Postcontent + "\n" + strings.Join(review.Tags, "\n")
+	mentions := taxonomy.ProductMentions(taxonomyText)
 
-	// Find product with most mentions
-	maxCount := 0
-	maxProduct := "BloxOne Platform" // Default product if nothing specific mentioned
-
-	for product, count := range productMatches {
-		if count > maxCount {
-			maxCount = count
-			maxProduct = product
-		}
-	}
-
-	// If DDI components are mentioned together, prioritize the overall DDI product
-	ddiComponents := productMatches["BloxOne DNS"] + productMatches["BloxOne DHCP"] + productMatches["BloxOne IPAM"]
-	if ddiComponents >= 2 && productMatches["BloxOne DDI"] > 0 {
+	// If DDI components are mentioned together, prioritize the overall DDI
+	// product, as long as one of them is mentioned more than once
+	ddiComponents := mentions["dns"] + mentions["dhcp"] + mentions["ipam"]
+	if ddiComponents >= 2 && max(mentions["dns"], mentions["dhcp"], mentions["ipam"]) >= 2 {
 		return "BloxOne DDI"
 	}
 
@@ -853,7 +669,12 @@ func determineProduct(review InputReview) string {
 		return "BloxOne Threat Defense"
 	}
 
-	return maxProduct
+	// Otherwise the product mentioned most, defaulting to the platform
+	product, found := taxonomy.ProductForText(taxonomyText)
+	if !found {
+		return "BloxOne Platform"
+	}
+	return productNameMappings[product]
 }
 
 // determineNeedsAction flags high-priority issues that need attention
@@ -0,0 +1,19 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetermineProduct(t *testing.T) {
+	assert.Equal(t, "NIOS", determineProduct(InputReview{Postcontent: "The grid appliance rebooted overnight"}))
+	assert.Equal(t, "BloxOne DDI", determineProduct(InputReview{Postcontent: "DHCP leases and DHCP scopes fill up, and DNS is slow"}))
+	assert.Equal(t, "BloxOne Threat Defense", determineProduct(InputReview{Postcontent: "Malware blocked before it reached DNS"}))
+	assert.Equal(t, "BloxOne Platform", determineProduct(InputReview{Postcontent: "Nothing relevant here"}))
+}
+
+func TestDetermineDepartmentCountsIntentCategories(t *testing.T) {
+	// "crash" and "freeze" are bug reports, which go to Engineering
+	assert.Equal(t, "Engineering", determineDepartment(InputReview{Postcontent: "Crash, then a freeze. Support was fine.", Rating: 3}))
+}
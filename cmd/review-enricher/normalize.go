@@ -0,0 +1,157 @@
+package main
+
+import (
+	"log"
+	"strings"
+	"unicode"
+)
+
+// Canonical values of the enriched enum fields
+var (
+	canonicalSentiments  = []string{"Positive", "Neutral", "Negative"}
+	canonicalDepartments = []string{"Product", "Engineering", "Support", "Sales", "General"}
+	canonicalProducts    = []string{
+		"BloxOne Platform",
+		"NIOS",
+		"BloxOne Threat Defense",
+		"BloxOne DNS",
+		"BloxOne DHCP",
+		"BloxOne IPAM",
+		"BloxOne Cloud Network Automation",
+		"BloxOne DDI",
+	}
+)
+
+// Fallbacks for values that cannot be mapped to a canonical one
+const (
+	defaultSentiment  = "Neutral"
+	defaultDepartment = "General"
+)
+
+// departmentAliases maps common model spellings to canonical departments
+var departmentAliases = map[string]string{
+	"dev":               "Engineering",
+	"development":       "Engineering",
+	"rd":                "Engineering",
+	"engineeringteam":   "Engineering",
+	"pm":                "Product",
+	"productteam":       "Product",
+	"productmanagement": "Product",
+	"customersupport":   "Support",
+	"customerservice":   "Support",
+	"technicalsupport":  "Support",
+	"supportteam":       "Support",
+	"cs":                "Support",
+	"billing":           "Sales",
+	"salesteam":         "Sales",
+	"other":             "General",
+	"none":              "General",
+}
+
+// sentimentAliases maps common model spellings to canonical sentiments
+var sentimentAliases = map[string]string{
+	"mixed":   "Neutral",
+	"neither": "Neutral",
+}
+
+// normalizeKey lowercases value and drops everything but letters and digits,
+// so "Positive." and " positive" compare equal
+func normalizeKey(value string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(value) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// matchCanonical maps value to one of canonical by normalized equality, an
+// alias, or an unambiguous prefix of at least three characters
+func matchCanonical(value string, canonical []string, aliases map[string]string) (string, bool) {
+	key := normalizeKey(value)
+	if key == "" {
+		return "", false
+	}
+	for _, c := range canonical {
+		if normalizeKey(c) == key {
+			return c, true
+		}
+	}
+	if alias, found := aliases[key]; found {
+		return alias, true
+	}
+
+	if len(key) >= 3 {
+		var match string
+		for _, c := range canonical {
+			if strings.HasPrefix(normalizeKey(c), key) {
+				if match != "" {
+					return "", false
+				}
+				match = c
+			}
+		}
+		if match != "" {
+			return match, true
+		}
+	}
+	return "", false
+}
+
+// productAliases maps product keywords, as used in productNameMappings, to product names
+func productAliases() map[string]string {
+	aliases := map[string]string{
+		"bloxone":       "BloxOne Platform",
+		"ddi":           "BloxOne DDI",
+		"universalddi":  "BloxOne DDI",
+		"threatdefense": "BloxOne Threat Defense",
+	}
+	for keyword, name := range productNameMappings {
+		aliases[normalizeKey(keyword)] = name
+	}
+	return aliases
+}
+
+// normalizeProduct maps a model's product answer to a known product name. The
+// "Infoblox" prefix models like to add is ignored.
+func normalizeProduct(value string) (string, bool) {
+	if product, ok := matchCanonical(value, canonicalProducts, productAliases()); ok {
+		return product, true
+	}
+	key := normalizeKey(value)
+	if trimmed := strings.TrimPrefix(key, "infoblox"); trimmed != key {
+		return matchCanonical(trimmed, canonicalProducts, productAliases())
+	}
+	return "", false
+}
+
+// normalizeAnalysis maps the enum fields of an AI analysis onto their canonical
+// values. Unmappable sentiments and departments become Neutral and General,
+// and unknown products are replaced by the offline product detection, each
+// with a logged warning.
+func normalizeAnalysis(review InputReview, result AIAnalysisResult) AIAnalysisResult {
+	if sentiment, ok := matchCanonical(result.Sentiment, canonicalSentiments, sentimentAliases); ok {
+		result.Sentiment = sentiment
+	} else {
+		log.Printf("Warning: review %d has unknown sentiment %q, using %s", review.ID, result.Sentiment, defaultSentiment)
+		result.Sentiment = defaultSentiment
+	}
+
+	if department, ok := matchCanonical(result.Department, canonicalDepartments, departmentAliases); ok {
+		result.Department = department
+	} else {
+		log.Printf("Warning: review %d has unknown department %q, using %s", review.ID, result.Department, defaultDepartment)
+		result.Department = defaultDepartment
+	}
+
+	if product, ok := normalizeProduct(result.Product); ok {
+		result.Product = product
+	} else {
+		detected := determineProduct(review)
+		log.Printf("Warning: review %d has unknown product %q, using %s", review.ID, result.Product, detected)
+		result.Product = detected
+	}
+
+	return result
+}
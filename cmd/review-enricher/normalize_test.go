@@ -0,0 +1,55 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAnalysisMessyValues(t *testing.T) {
+	review := InputReview{ID: 1, Title: "Grid upgrade", Postcontent: "The NIOS grid appliance keeps failing"}
+
+	tests := []struct {
+		in   AIAnalysisResult
+		want AIAnalysisResult
+	}{
+		{
+			in:   AIAnalysisResult{Sentiment: "positive.", Department: "Eng", Product: "nios"},
+			want: AIAnalysisResult{Sentiment: "Positive", Department: "Engineering", Product: "NIOS"},
+		},
+		{
+			in:   AIAnalysisResult{Sentiment: " NEGATIVE ", Department: "customer service", Product: "Infoblox Threat Defense"},
+			want: AIAnalysisResult{Sentiment: "Negative", Department: "Support", Product: "BloxOne Threat Defense"},
+		},
+		{
+			in:   AIAnalysisResult{Sentiment: "Mixed", Department: "product team", Product: "bloxone ddi"},
+			want: AIAnalysisResult{Sentiment: "Neutral", Department: "Product", Product: "BloxOne DDI"},
+		},
+		{
+			in:   AIAnalysisResult{Sentiment: "neg", Department: "Billing", Product: "DNS"},
+			want: AIAnalysisResult{Sentiment: "Negative", Department: "Sales", Product: "BloxOne DNS"},
+		},
+		{
+			// Unmappable values fall back to the defaults and offline product detection
+			in:   AIAnalysisResult{Sentiment: "angry", Department: "Legal", Product: "Acme Router 3000", NeedsAction: true},
+			want: AIAnalysisResult{Sentiment: "Neutral", Department: "General", Product: "NIOS", NeedsAction: true},
+		},
+		{
+			in:   AIAnalysisResult{},
+			want: AIAnalysisResult{Sentiment: "Neutral", Department: "General", Product: "NIOS"},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, normalizeAnalysis(review, tt.in), "normalizing %+v", tt.in)
+	}
+}
+
+func TestNormalizeProductRejectsAmbiguousPrefix(t *testing.T) {
+	_, ok := normalizeProduct("BloxOne D")
+	assert.False(t, ok)
+
+	product, ok := normalizeProduct("BloxOne DH")
+	assert.True(t, ok)
+	assert.Equal(t, "BloxOne DHCP", product)
+}
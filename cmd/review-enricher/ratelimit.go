@@ -0,0 +1,109 @@
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// defaultRequestsPerMinute keeps online runs at the pace of the old fixed 200ms delay
+const defaultRequestsPerMinute = 300
+
+// maxRateLimitRetries is how many times a request answered with 429 is retried
+const maxRateLimitRetries = 3
+
+// initialBackoff is the wait before the first 429 retry when the provider sends
+// no Retry-After header; it doubles on every further retry
+const initialBackoff = time.Second
+
+// requestPacer spaces API calls to stay within the provider's requests per
+// minute and backs off when the provider still answers 429 Too Many Requests.
+// One pacer is shared by every worker, so the budget covers the whole run.
+type requestPacer struct {
+	limiter    *rate.Limiter // Nil means no request budget
+	maxRetries int
+	backoff    time.Duration
+	sleep      func(time.Duration)
+}
+
+// newRequestPacer creates a pacer allowing rpm requests per minute; a
+// non-positive rpm disables the budget but keeps the 429 backoff
+func newRequestPacer(rpm int) *requestPacer {
+	return &requestPacer{
+		limiter:    newRequestLimiter(rpm),
+		maxRetries: maxRateLimitRetries,
+		backoff:    initialBackoff,
+		sleep:      time.Sleep,
+	}
+}
+
+// newRequestLimiter returns a limiter allowing rpm requests per minute, or nil
+// when rpm is not positive
+func newRequestLimiter(rpm int) *rate.Limiter {
+	if rpm <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Every(time.Minute/time.Duration(rpm)), 1)
+}
+
+// Do sends req with client once the request budget allows, retrying with
+// exponential backoff while the provider answers 429. The Retry-After header
+// takes precedence over the computed backoff. A nil pacer sends req directly.
+func (p *requestPacer) Do(client *http.Client, req *http.Request) (*http.Response, error) {
+	if p == nil {
+		return client.Do(req)
+	}
+
+	backoff := p.backoff
+	for attempt := 0; ; attempt++ {
+		if p.limiter != nil {
+			if err := p.limiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := client.Do(req)
+		if err != nil || resp.StatusCode != http.StatusTooManyRequests || attempt == p.maxRetries {
+			return resp, err
+		}
+
+		delay := retryAfter(resp, backoff)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		log.Printf("Rate limited by %s, retrying in %s", req.URL.Host, delay)
+		p.sleep(delay)
+		backoff *= 2
+
+		// The body was consumed by the previous attempt
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+	}
+}
+
+// retryAfter returns the wait requested by resp's Retry-After header, given in
+// seconds or as an HTTP date, or fallback when the header is absent or invalid
+func retryAfter(resp *http.Response, fallback time.Duration) time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return fallback
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if delay := time.Until(at); delay > 0 {
+			return delay
+		}
+		return 0
+	}
+	return fallback
+}
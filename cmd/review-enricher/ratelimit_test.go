@@ -0,0 +1,120 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestRequestPacerSpacesCallsAtRPM(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		times []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	pacer := newRequestPacer(60)
+	for i := 0; i < 3; i++ {
+		req, err := http.NewRequest("POST", server.URL, strings.NewReader("{}"))
+		assert.NoError(t, err)
+		resp, err := pacer.Do(server.Client(), req)
+		assert.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	assert.Len(t, times, 3)
+	for i := 1; i < len(times); i++ {
+		gap := times[i].Sub(times[i-1])
+		assert.InDelta(t, time.Second.Seconds(), gap.Seconds(), 0.15, "gap between call %d and %d", i, i+1)
+	}
+}
+
+func TestRequestPacerBacksOffOnTooManyRequests(t *testing.T) {
+	var (
+		calls  int
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		buf := new(strings.Builder)
+		_, _ = io.Copy(buf, r.Body)
+		bodies = append(bodies, buf.String())
+		switch calls {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	var slept []time.Duration
+	pacer := newRequestPacer(0)
+	pacer.sleep = func(d time.Duration) { slept = append(slept, d) }
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader(`{"review": 1}`))
+	assert.NoError(t, err)
+	resp, err := pacer.Do(server.Client(), req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 3, calls)
+	// Retry-After wins over the backoff, which has doubled by the second retry
+	assert.Equal(t, []time.Duration{7 * time.Second, 2 * initialBackoff}, slept)
+	// Every attempt resends the full body
+	assert.Equal(t, []string{`{"review": 1}`, `{"review": 1}`, `{"review": 1}`}, bodies)
+}
+
+func TestRequestPacerGivesUpAfterMaxRetries(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	pacer := newRequestPacer(0)
+	pacer.sleep = func(time.Duration) {}
+
+	req, err := http.NewRequest("POST", server.URL, strings.NewReader("{}"))
+	assert.NoError(t, err)
+	resp, err := pacer.Do(server.Client(), req)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+	assert.Equal(t, maxRateLimitRetries+1, calls)
+}
+
+func TestRetryAfter(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+	assert.Equal(t, 3*time.Second, retryAfter(resp, 3*time.Second))
+
+	resp.Header.Set("Retry-After", "12")
+	assert.Equal(t, 12*time.Second, retryAfter(resp, time.Second))
+
+	resp.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.Equal(t, time.Duration(0), retryAfter(resp, time.Second))
+
+	resp.Header.Set("Retry-After", "soon")
+	assert.Equal(t, time.Second, retryAfter(resp, time.Second))
+}
+
+func TestNewRequestLimiter(t *testing.T) {
+	assert.Nil(t, newRequestLimiter(0))
+	assert.Equal(t, rate.Limit(5), newRequestLimiter(300).Limit())
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+)
+
+// strictSystemPrompt is sent when retrying a request whose reply could not be parsed
+const strictSystemPrompt = analysisSystemPrompt + " Reply with a single raw JSON object only: no markdown code fences, no explanations, no text before or after the object."
+
+// extractJSON isolates the JSON object in a model reply. Models often wrap the
+// object in ```json fences or surround it with prose, so this strips fences and
+// returns the first balanced {...} object.
+func extractJSON(content string) (string, error) {
+	content = strings.TrimSpace(content)
+
+	// Keep only the body of the first fenced block, if any
+	if start := strings.Index(content, "```"); start >= 0 {
+		body := content[start+3:]
+		// Skip the language tag, e.g. ```json
+		if newline := strings.IndexByte(body, '\n'); newline >= 0 {
+			body = body[newline+1:]
+		}
+		if end := strings.Index(body, "```"); end >= 0 {
+			body = body[:end]
+		}
+		content = body
+	}
+
+	start := strings.IndexByte(content, '{')
+	if start < 0 {
+		return "", fmt.Errorf("no JSON object in response")
+	}
+
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case escaped:
+			escaped = false
+		case inString && c == '\\':
+			escaped = true
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '{':
+			depth++
+		case c == '}':
+			depth--
+			if depth == 0 {
+				return content[start : i+1], nil
+			}
+		}
+	}
+	return "", fmt.Errorf("unterminated JSON object in response")
+}
+
+// parseAnalysis extracts and decodes the analysis in a model reply
+func parseAnalysis(content string) (AIAnalysisResult, error) {
+	analysisJSON, err := extractJSON(content)
+	if err != nil {
+		return AIAnalysisResult{}, fmt.Errorf("error parsing AI analysis: %v\nResponse was: %s", err, content)
+	}
+
+	var analysisResult AIAnalysisResult
+	if err := json.Unmarshal([]byte(analysisJSON), &analysisResult); err != nil {
+		return AIAnalysisResult{}, fmt.Errorf("error parsing AI analysis: %v\nResponse was: %s", err, content)
+	}
+	return analysisResult, nil
+}
+
+// analyzeWithRetry requests an analysis with the normal system prompt and, if
+// the reply cannot be parsed, retries once with a stricter prompt. Request
+// errors are returned without retrying.
+func analyzeWithRetry(request func(systemPrompt string) (string, error)) (AIAnalysisResult, error) {
+	content, err := request(analysisSystemPrompt)
+	if err != nil {
+		return AIAnalysisResult{}, err
+	}
+
+	analysisResult, err := parseAnalysis(content)
+	if err == nil {
+		return analysisResult, nil
+	}
+	log.Printf("Could not parse AI analysis, retrying with a stricter prompt: %v", err)
+
+	content, err = request(strictSystemPrompt)
+	if err != nil {
+		return AIAnalysisResult{}, err
+	}
+	return parseAnalysis(content)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const analysisReply = `{"sentiment": "Negative", "department": "Engineering", "product": "NIOS", "needsAction": true}`
+
+func TestExtractJSON(t *testing.T) {
+	tests := map[string]string{
+		"raw":                  analysisReply,
+		"fenced":               "```json\n" + analysisReply + "\n```",
+		"fenced without tag":   "```\n" + analysisReply + "\n```",
+		"prose before":         "Here is the analysis you asked for:\n" + analysisReply,
+		"prose after":          analysisReply + "\n\nLet me know if you need anything else.",
+		"fenced inside prose":  "Sure! ```json\n" + analysisReply + "\n``` Hope this helps {:",
+		"braces inside string": `Result: {"sentiment": "Negative", "department": "Engineering", "product": "NIOS {beta}", "needsAction": true} done`,
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, err := parseAnalysis(content)
+			assert.NoError(t, err)
+			assert.Equal(t, "Negative", result.Sentiment)
+			assert.Equal(t, "Engineering", result.Department)
+			assert.True(t, result.NeedsAction)
+		})
+	}
+}
+
+func TestExtractJSONRejectsMissingObject(t *testing.T) {
+	_, err := extractJSON("I could not analyze this review.")
+	assert.Error(t, err)
+
+	_, err = extractJSON(`{"sentiment": "Negative"`)
+	assert.Error(t, err)
+}
+
+func TestAnalyzeWithRetryUsesStricterPrompt(t *testing.T) {
+	var prompts []string
+	result, err := analyzeWithRetry(func(systemPrompt string) (string, error) {
+		prompts = append(prompts, systemPrompt)
+		if len(prompts) == 1 {
+			return "Sentiment: negative, department: engineering", nil
+		}
+		return analysisReply, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "NIOS", result.Product)
+	assert.Equal(t, []string{analysisSystemPrompt, strictSystemPrompt}, prompts)
+}
+
+func TestAnalyzeWithRetryGivesUpAfterOneRetry(t *testing.T) {
+	calls := 0
+	_, err := analyzeWithRetry(func(string) (string, error) {
+		calls++
+		return "not json", nil
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestAnalyzeWithRetryDoesNotRetryRequestErrors(t *testing.T) {
+	calls := 0
+	_, err := analyzeWithRetry(func(string) (string, error) {
+		calls++
+		return "", errors.New("connection refused")
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// streamBatchSize is how many reviews are decoded and enriched at a time, which
+// bounds memory use regardless of the input file size
+const streamBatchSize = 256
+
+// streamReviews decodes a JSON array of reviews from r one element at a time,
+// calling fn with consecutive batches of up to batchSize reviews
+func streamReviews(r io.Reader, batchSize int, fn func([]InputReview) error) error {
+	if batchSize <= 0 {
+		batchSize = streamBatchSize
+	}
+
+	dec := json.NewDecoder(r)
+	token, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("reading review array: %w", err)
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("expected a JSON array of reviews, found %v", token)
+	}
+
+	batch := make([]InputReview, 0, batchSize)
+	for decoded := 0; dec.More(); decoded++ {
+		var review InputReview
+		if err := dec.Decode(&review); err != nil {
+			return fmt.Errorf("decoding review %d: %w", decoded+1, err)
+		}
+		batch = append(batch, review)
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]InputReview, 0, batchSize)
+		}
+	}
+
+	if _, err := dec.Token(); err != nil {
+		return fmt.Errorf("reading end of review array: %w", err)
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+// readReviews loads every review in the JSON array files at paths
+func readReviews(paths []string) ([]InputReview, error) {
+	var reviews []InputReview
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = streamReviews(file, streamBatchSize, func(batch []InputReview) error {
+			reviews = append(reviews, batch...)
+			return nil
+		})
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return reviews, nil
+}
+
+// reviewArrayWriter writes enriched reviews as an indented JSON array one
+// element at a time. The output matches json.MarshalIndent with a two-space
+// indent.
+type reviewArrayWriter struct {
+	w     io.Writer
+	buf   bytes.Buffer
+	enc   *json.Encoder
+	count int
+}
+
+// newReviewArrayWriter creates a writer emitting the array to w
+func newReviewArrayWriter(w io.Writer) *reviewArrayWriter {
+	aw := &reviewArrayWriter{w: w}
+	aw.enc = json.NewEncoder(&aw.buf)
+	aw.enc.SetIndent("  ", "  ")
+	return aw
+}
+
+// Write appends review to the array
+func (aw *reviewArrayWriter) Write(review EnrichedReview) error {
+	aw.buf.Reset()
+	if aw.count == 0 {
+		aw.buf.WriteString("[\n  ")
+	} else {
+		aw.buf.WriteString(",\n  ")
+	}
+	if err := aw.enc.Encode(review); err != nil {
+		return err
+	}
+	// Encode terminates each value with a newline; the separator adds its own
+	aw.buf.Truncate(aw.buf.Len() - 1)
+
+	if _, err := aw.w.Write(aw.buf.Bytes()); err != nil {
+		return err
+	}
+	aw.count++
+	return nil
+}
+
+// Close terminates the array. It does not close the underlying writer.
+func (aw *reviewArrayWriter) Close() error {
+	closing := "\n]"
+	if aw.count == 0 {
+		closing = "[]"
+	}
+	_, err := io.WriteString(aw.w, closing)
+	return err
+}
+
+// enrichStream enriches the JSON array of reviews read from r batch by batch,
+// passing each enriched review to emit in input order
+func enrichStream(ctx context.Context, r io.Reader, analyze analyzeFunc, concurrency int, emit func(EnrichedReview) error) error {
+	return streamReviews(r, streamBatchSize, func(batch []InputReview) error {
+		for _, review := range enrichReviews(ctx, batch, analyze, concurrency) {
+			if err := emit(review); err != nil {
+				return err
+			}
+		}
+		return ctx.Err()
+	})
+}
+
+// enrichInputs streams every file in inputPaths through enrichStream in turn
+func enrichInputs(ctx context.Context, inputPaths []string, analyze analyzeFunc, concurrency int, emit func(EnrichedReview) error) error {
+	for _, inputPath := range inputPaths {
+		input, err := os.Open(inputPath)
+		if err != nil {
+			return fmt.Errorf("opening input file: %w", err)
+		}
+		err = enrichStream(ctx, input, analyze, concurrency, emit)
+		input.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", inputPath, err)
+		}
+	}
+	return nil
+}
+
+// enrichFile streams the reviews in inputPaths through analyze and writes the
+// enriched reviews to outputPath as one array, returning how many were
+// written. With idsStable every ID is derived from the review itself;
+// otherwise, when several files are merged, IDs are renumbered from 1 so they
+// stay unique across files. Output goes to a temporary file that is renamed
+// into place, so a failed run never leaves a partial file behind.
+func enrichFile(ctx context.Context, inputPaths []string, outputPath string, analyze analyzeFunc, concurrency int, ids string) (int, error) {
+	output, err := os.CreateTemp(filepath.Dir(outputPath), ".enriched-*.json")
+	if err != nil {
+		return 0, fmt.Errorf("creating output file: %w", err)
+	}
+	defer os.Remove(output.Name()) // No-op once renamed
+	defer output.Close()
+
+	writer := newReviewArrayWriter(output)
+	err = enrichInputs(ctx, inputPaths, analyze, concurrency, func(review EnrichedReview) error {
+		switch {
+		case ids == idsStable:
+			review.ID = stableReviewID(review)
+		case len(inputPaths) > 1:
+			review.ID = writer.count + 1
+		}
+		if err := writer.Write(review); err != nil {
+			return fmt.Errorf("writing output file: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return writer.count, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return writer.count, fmt.Errorf("writing output file: %w", err)
+	}
+	if err := output.Chmod(0644); err != nil {
+		return writer.count, fmt.Errorf("writing output file: %w", err)
+	}
+	if err := output.Close(); err != nil {
+		return writer.count, fmt.Errorf("writing output file: %w", err)
+	}
+	if err := os.Rename(output.Name(), outputPath); err != nil {
+		return writer.count, fmt.Errorf("writing output file: %w", err)
+	}
+	return writer.count, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeInputFile writes reviews as a JSON array to a file in dir
+func writeInputFile(t *testing.T, dir string, reviews []InputReview) string {
+	return writeReviewsFile(t, dir, "scraped_data.json", reviews)
+}
+
+func TestEnrichFileStreamsLargeInput(t *testing.T) {
+	dir := t.TempDir()
+	reviews := sampleInputReviews(5000)
+	inputPath := writeInputFile(t, dir, reviews)
+	outputPath := filepath.Join(dir, "enriched_reviews.json")
+
+	processed, err := enrichFile(context.Background(), []string{inputPath}, outputPath, analyzeOffline, 4, idsSequential)
+	assert.NoError(t, err)
+	assert.Equal(t, len(reviews), processed)
+
+	data, err := os.ReadFile(outputPath)
+	assert.NoError(t, err)
+	var enriched []EnrichedReview
+	assert.NoError(t, json.Unmarshal(data, &enriched))
+	assert.Len(t, enriched, len(reviews))
+	for i, review := range reviews {
+		assert.Equal(t, review.ID, enriched[i].ID)
+	}
+
+	// Only the output file remains; the temporary file was renamed into place
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+}
+
+func TestReviewArrayWriterMatchesMarshalIndent(t *testing.T) {
+	for _, n := range []int{0, 1, 3} {
+		var enriched []EnrichedReview
+		for _, review := range sampleInputReviews(n) {
+			enriched = append(enriched, newEnrichedReview(review, analyzeOffline(review)))
+		}
+
+		var buf bytes.Buffer
+		writer := newReviewArrayWriter(&buf)
+		for _, review := range enriched {
+			assert.NoError(t, writer.Write(review))
+		}
+		assert.NoError(t, writer.Close())
+
+		if enriched == nil {
+			enriched = []EnrichedReview{}
+		}
+		expected, err := json.MarshalIndent(enriched, "", "  ")
+		assert.NoError(t, err)
+		assert.Equal(t, string(expected), buf.String())
+	}
+}
+
+func TestStreamReviewsBatches(t *testing.T) {
+	data, err := json.Marshal(sampleInputReviews(7))
+	assert.NoError(t, err)
+
+	var sizes []int
+	err = streamReviews(bytes.NewReader(data), 3, func(batch []InputReview) error {
+		sizes = append(sizes, len(batch))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 3, 1}, sizes)
+}
+
+func TestStreamReviewsRejectsInvalidInput(t *testing.T) {
+	noop := func([]InputReview) error { return nil }
+
+	assert.Error(t, streamReviews(strings.NewReader(`{"id": 1}`), 10, noop))
+	assert.Error(t, streamReviews(strings.NewReader(`[{"id": "one"}]`), 10, noop))
+	assert.Error(t, streamReviews(strings.NewReader(`[{"id": 1}`), 10, noop))
+}
+
+func TestEnrichFileLeavesNoOutputOnError(t *testing.T) {
+	dir := t.TempDir()
+	inputPath := filepath.Join(dir, "scraped_data.json")
+	assert.NoError(t, os.WriteFile(inputPath, []byte(`[{"id": 1}, {"id": "two"}]`), 0644))
+	outputPath := filepath.Join(dir, "enriched_reviews.json")
+
+	_, err := enrichFile(context.Background(), []string{inputPath}, outputPath, analyzeOffline, 2, idsSequential)
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
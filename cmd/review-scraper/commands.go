@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
+	"github.com/Infoblox-CTO/review-scraper/internal/scraper"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// defaultCommand runs when no subcommand is given
+const defaultCommand = "serve"
+
+// command runs a subcommand with the arguments that follow its name
+type command func(args []string) error
+
+// commands are the subcommands of review-scraper
+var commands = map[string]command{
+	"serve":       serve,
+	"scrape-once": scrapeOnce,
+	"analyze":     analyzeFile,
+}
+
+// dispatch runs the subcommand named by args[0]. Without a subcommand, or when
+// the first argument is a flag, the default serve command runs.
+func dispatch(args []string, commands map[string]command) error {
+	name := defaultCommand
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	run, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q, expected one of: %s", name, strings.Join(commandNames(commands), ", "))
+	}
+	return run(args)
+}
+
+// commandNames returns the names of commands in alphabetical order
+func commandNames(commands map[string]command) []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scrapeOnce runs every enabled scraper once and writes the reviews as JSON,
+// so scraping can be scheduled from cron or CI without the server
+func scrapeOnce(args []string) error {
+	flags := flag.NewFlagSet("scrape-once", flag.ContinueOnError)
+	output := flags.String("output", "", "File to write the reviews to (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, logger, err := setup()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	scraperManager, err := scraper.NewManager(cfg.Scrapers)
+	if err != nil {
+		return err
+	}
+	scraperManager.SetLogger(logger)
+	reviews, err := scraperManager.ScrapeAll(ctx)
+	if err != nil {
+		return fmt.Errorf("scraping failed: %w", err)
+	}
+
+	log.Printf("Scraped %d reviews", len(reviews))
+	return writeJSON(*output, reviews)
+}
+
+// analyzeFile analyzes a JSON file of reviews, such as one written by
+// scrape-once, and writes each review with its analysis as JSON
+func analyzeFile(args []string) error {
+	flags := flag.NewFlagSet("analyze", flag.ContinueOnError)
+	output := flags.String("output", "", "File to write the analyzed reviews to (default stdout)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("analyze requires exactly one input file")
+	}
+
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return fmt.Errorf("failed to read reviews: %w", err)
+	}
+	var reviews []models.Review
+	if err := json.Unmarshal(data, &reviews); err != nil {
+		return fmt.Errorf("failed to parse reviews from %s: %w", flags.Arg(0), err)
+	}
+
+	cfg, logger, err := setup()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	reviewAnalyzer := analyzer.New(cfg.Analyzer)
+	reviewAnalyzer.SetLogger(logger)
+	analyzed := make([]models.AnalyzedReview, 0, len(reviews))
+	for _, review := range reviews {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		result, err := reviewAnalyzer.Analyze(ctx, review)
+		if err != nil {
+			log.Printf("Error analyzing review %s: %v", review.ID, err)
+			analyzed = append(analyzed, models.AnalyzedReview{Review: review})
+			continue
+		}
+		reviewAnalyzer.AutoTag(&review, result)
+		analyzed = append(analyzed, models.AnalyzedReview{Review: review, Analysis: &result})
+	}
+
+	log.Printf("Analyzed %d reviews", len(analyzed))
+	return writeJSON(*output, analyzed)
+}
+
+// writeJSON writes v as indented JSON to path, or to stdout when path is empty
+func writeJSON(path string, v interface{}) error {
+	var w io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(v); err != nil {
+		return fmt.Errorf("failed to write JSON: %w", err)
+	}
+	return nil
+}
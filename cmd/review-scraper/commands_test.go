@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingCommands returns commands that record which one ran and with what arguments
+func recordingCommands(ran *string, gotArgs *[]string) map[string]command {
+	record := func(name string) command {
+		return func(args []string) error {
+			*ran = name
+			*gotArgs = args
+			return nil
+		}
+	}
+	return map[string]command{
+		"serve":       record("serve"),
+		"scrape-once": record("scrape-once"),
+		"analyze":     record("analyze"),
+	}
+}
+
+func TestDispatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		wantCmd  string
+		wantArgs []string
+	}{
+		{name: "no arguments serves", args: nil, wantCmd: "serve", wantArgs: nil},
+		{name: "leading flag serves", args: []string{"-h"}, wantCmd: "serve", wantArgs: []string{"-h"}},
+		{name: "explicit serve", args: []string{"serve"}, wantCmd: "serve", wantArgs: []string{}},
+		{name: "scrape once", args: []string{"scrape-once", "-output", "out.json"}, wantCmd: "scrape-once", wantArgs: []string{"-output", "out.json"}},
+		{name: "analyze file", args: []string{"analyze", "reviews.json"}, wantCmd: "analyze", wantArgs: []string{"reviews.json"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran string
+			var gotArgs []string
+			assert.NoError(t, dispatch(tt.args, recordingCommands(&ran, &gotArgs)))
+			assert.Equal(t, tt.wantCmd, ran)
+			assert.Equal(t, tt.wantArgs, gotArgs)
+		})
+	}
+}
+
+func TestDispatchUnknownCommand(t *testing.T) {
+	var ran string
+	var gotArgs []string
+	err := dispatch([]string{"scrape"}, recordingCommands(&ran, &gotArgs))
+
+	assert.EqualError(t, err, `unknown command "scrape", expected one of: analyze, scrape-once, serve`)
+	assert.Empty(t, ran)
+}
+
+func TestCommandsRegistered(t *testing.T) {
+	assert.Equal(t, []string{"analyze", "scrape-once", "serve"}, commandNames(commands))
+}
+
+func TestAnalyzeRequiresOneFile(t *testing.T) {
+	assert.EqualError(t, analyzeFile(nil), "analyze requires exactly one input file")
+	assert.EqualError(t, analyzeFile([]string{"a.json", "b.json"}), "analyze requires exactly one input file")
+}
+
+func TestWriteJSONToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reviews.json")
+	reviews := []models.Review{{ID: "r1", Source: "g2", Content: "DNS keeps failing"}}
+
+	assert.NoError(t, writeJSON(path, reviews))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var got []models.Review
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, "r1", got[0].ID)
+	assert.Equal(t, "DNS keeps failing", got[0].Content)
+}
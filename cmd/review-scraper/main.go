@@ -2,43 +2,169 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/signal"
+	"sync"
 	"syscall"
 	"time"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
 	"github.com/Infoblox-CTO/review-scraper/internal/api"
+	"github.com/Infoblox-CTO/review-scraper/internal/buildinfo"
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/internal/metrics"
 	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
 	"github.com/Infoblox-CTO/review-scraper/internal/router"
 	"github.com/Infoblox-CTO/review-scraper/internal/scraper"
+	"github.com/Infoblox-CTO/review-scraper/internal/sink"
+	"github.com/Infoblox-CTO/review-scraper/internal/storage"
+	"github.com/Infoblox-CTO/review-scraper/internal/tenant"
+	"github.com/Infoblox-CTO/review-scraper/internal/tracing"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	_ "github.com/lib/pq" // Registers the "postgres" driver for the warehouse export (Postgres, Redshift) and the review store
 )
 
+// defaultPipelineWorkers is how many reviews are processed at once when
+// pipelineWorkers is not set
+const defaultPipelineWorkers = 4
+
 func main() {
-	log.Println("Starting Review Scraper System...")
+	err := dispatch(os.Args[1:], commands)
+	if errors.Is(err, flag.ErrHelp) {
+		return // Usage was already printed
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
 
-	// Load configuration
+// setup loads the configuration and installs the structured logger
+func setup() (*config.Config, *slog.Logger, error) {
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatalf("Failed to load configuration: %v", err)
+		return nil, nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+
+	// Emit structured JSON logs; the standard log package is routed through it too
+	logger, err := logging.New(os.Stderr, cfg.LogLevel)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to configure logging: %w", err)
+	}
+	slog.SetDefault(logger)
+	return cfg, logger, nil
+}
+
+// serve runs the long-lived pipeline and API server until it is signalled to stop
+func serve(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, logger, err := setup()
+	if err != nil {
+		return err
 	}
 
-	// Create context with cancellation
+	build := buildinfo.Get()
+	logger.Info("Starting Review Scraper System", "version", build.Version, "commit", build.Commit, "build_time", build.BuildTime)
+
+	// Create context with cancellation; cancelling it stops scheduling pipeline runs
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Runs get their own context so shutdown can let the one in progress finish
+	runCtx, cancelRuns := context.WithCancel(context.Background())
+	defer cancelRuns()
+
+	// Export pipeline spans, if configured
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing)
+	if err != nil {
+		return err
+	}
+
 	// Initialize components
-	scraperManager := scraper.NewManager(cfg.Scrapers)
+	scraperManager, err := scraper.NewManager(cfg.Scrapers)
+	if err != nil {
+		return err
+	}
 	analyzer := analyzer.New(cfg.Analyzer)
 	router := router.New(cfg.Router)
 	notifier := notifier.New(cfg.Notifier)
+	tenants := tenant.NewResolver(cfg.Tenants, &tenant.Pipeline{
+		Name:     tenant.DefaultTenant,
+		Analyzer: analyzer,
+		Router:   router,
+		Notifier: notifier,
+	})
+
+	// Tag each component's logs, and each tenant's, so they can be filtered
+	scraperManager.SetLogger(logger)
+	for _, pipeline := range tenants.Pipelines() {
+		tenantLogger := logger.With("tenant", pipeline.Name)
+		pipeline.Analyzer.SetLogger(tenantLogger)
+		pipeline.Router.SetLogger(tenantLogger)
+		pipeline.Notifier.SetLogger(tenantLogger)
+	}
+
+	// Export analysis results to the warehouse, if configured
+	analysisSink, err := sink.New(cfg.Warehouse)
+	if err != nil {
+		log.Printf("Warehouse export disabled: %v", err)
+		analysisSink = sink.NoopSink{}
+	}
+
+	// Keep reviews and notifications, and remember notified reviews across
+	// restarts, when a database is configured
+	store, err := storage.New(ctx, cfg.Storage)
+	if err != nil {
+		return fmt.Errorf("failed to open review store: %w", err)
+	}
+	for _, pipeline := range tenants.Pipelines() {
+		pipeline.Notifier.SetStore(store)
+	}
+
+	// Check notification channels before relying on them
+	if cfg.Notifier.ProbeOnStartup {
+		if err := notifier.Probe(ctx); err != nil {
+			if cfg.Notifier.FailOnProbeError {
+				return fmt.Errorf("notifier startup check failed: %w", err)
+			}
+			log.Printf("Notifier is degraded: %v", err)
+		} else {
+			log.Println("Notifier startup check passed")
+		}
+	}
 
 	// Start the API server
-	apiServer := api.NewServer(cfg.API, scraperManager, analyzer, router, notifier)
+	apiServer := api.NewServer(cfg, scraperManager, analyzer, router, notifier)
+	for _, pipeline := range tenants.Pipelines() {
+		if pipeline.Notifier != notifier {
+			apiServer.AddTenantNotifier(pipeline.Notifier)
+		}
+	}
+	// Runs started through the API go through the same pipeline as scheduled ones
+	process := newPipeline(logger, cfg.PipelineWorkers, tenants, analysisSink, store, apiServer.AddRecentReview)
+	apiServer.SetPipeline(process)
+	apiServer.AddReadinessCheck("storage", analysisSink.Ping)
+	apiServer.AddReadinessCheck("reviewStore", store.Ping)
+
+	// Instrument the pipeline when metrics are exposed
+	if cfg.API.EnableMetrics {
+		pipelineMetrics := metrics.New()
+		scraperManager.SetMetrics(pipelineMetrics)
+		for _, pipeline := range tenants.Pipelines() {
+			pipeline.Analyzer.SetMetrics(pipelineMetrics)
+			pipeline.Notifier.SetMetrics(pipelineMetrics)
+		}
+		apiServer.SetMetrics(pipelineMetrics)
+	}
 	go func() {
 		if err := apiServer.Start(); err != nil {
 			log.Printf("API server error: %v", err)
@@ -46,18 +172,25 @@ func main() {
 		}
 	}()
 
-	// Start the scraping pipeline
+	// Start the scraping pipeline, tracked so shutdown can wait for it
+	var pipelineWG sync.WaitGroup
+	pipelineWG.Add(1)
 	go func() {
-		ticker := time.NewTicker(cfg.ScrapingInterval)
+		defer pipelineWG.Done()
+		ticker := time.NewTicker(cfg.ScrapingInterval.Duration())
 		defer ticker.Stop()
 
 		// Run immediately upon startup
-		runPipeline(ctx, scraperManager, analyzer, router, notifier)
+		runPipeline(runCtx, logger, scraperManager, process, store)
 
 		for {
 			select {
 			case <-ticker.C:
-				runPipeline(ctx, scraperManager, analyzer, router, notifier)
+				// Don't start a new run once shutdown has begun
+				if ctx.Err() != nil {
+					continue
+				}
+				runPipeline(runCtx, logger, scraperManager, process, store)
 			case <-ctx.Done():
 				log.Println("Scraping pipeline stopped")
 				return
@@ -65,117 +198,304 @@ func main() {
 		}
 	}()
 
-	// Parse command line flags
-	apiKey := flag.String("apikey", "", "RapidAPI key (required)")
-	product := flag.String("product", "", "Product name (bloxone-ddi, infoblox-nios, or bloxone-threat-defense)")
-	rating := flag.Int("rating", 0, "Filter by star rating (0-5, 0 means all ratings)")
-	page := flag.Int("page", 1, "Page number")
-	flag.Parse()
-
-	// Validate API key
-	if *apiKey == "" {
-		apiKeyEnv := os.Getenv("RAPID_API_KEY")
-		if apiKeyEnv == "" {
-			fmt.Println("Error: API key is required. Set it with -apikey flag or RAPID_API_KEY environment variable")
-			os.Exit(1)
+	// Wait for termination signal, reloading the configuration on SIGHUP
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+	// The API server comes last, so it records only the sections the other
+	// components accepted. Only the default pipeline's analyzer and router are
+	// reloaded; tenant pipelines keep the configuration they started with.
+	reloadables := []reloadTarget{
+		{scraperManager, func(next, current *config.Config) { next.Scrapers = current.Scrapers }},
+		{analyzer, func(next, current *config.Config) { next.Analyzer = current.Analyzer }},
+		{router, func(next, current *config.Config) { next.Router = current.Router }},
+		{apiServer, func(next, current *config.Config) { next.API.APIKeys = current.API.APIKeys }},
+	}
+	current := cfg
+	for sig := range sigCh {
+		if sig != syscall.SIGHUP {
+			break
 		}
-		*apiKey = apiKeyEnv
+		current = reloadConfig(current, reloadables)
 	}
 
-	// Validate product name
-	validProducts := map[string]bool{
-		"bloxone-ddi":            true,
-		"infoblox-nios":          true,
-		"bloxone-threat-defense": true,
+	log.Println("Received shutdown signal. Stopping services...")
+	cancel()
+
+	// Allow graceful shutdown (e.g., finish current scraping jobs)
+	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer shutdownCancel()
+
+	// Let a pipeline run in progress finish, abandoning it only at the timeout
+	if err := waitGroupDone(shutdownCtx, &pipelineWG); err != nil {
+		log.Printf("Pipeline run did not finish before shutdown: %v", err)
 	}
+	cancelRuns()
 
-	if *product == "" {
-		fmt.Println("Error: Product name is required. Choose from: bloxone-ddi, infoblox-nios, bloxone-threat-defense")
-		os.Exit(1)
+	// Stop the API server and the scraping runs it started, before their
+	// notifications are drained
+	if err := apiServer.Stop(shutdownCtx); err != nil {
+		log.Printf("Error stopping API server: %v", err)
 	}
 
-	if !validProducts[*product] {
-		fmt.Printf("Error: Invalid product name. Choose from: bloxone-ddi, infoblox-nios, bloxone-threat-defense\n")
-		os.Exit(1)
+	// Finish notifications still being sent, including ones started through the API
+	for _, pipeline := range tenants.Pipelines() {
+		if err := pipeline.Notifier.Drain(shutdownCtx); err != nil {
+			log.Printf("Error draining %s notifications: %v", pipeline.Name, err)
+		}
+		if err := pipeline.Notifier.Close(); err != nil {
+			log.Printf("Error closing %s notifier: %v", pipeline.Name, err)
+		}
 	}
 
-	// Create G2 client
-	client := scraper.NewG2Client(*apiKey)
+	// Export any analyses still buffered
+	if err := analysisSink.Close(shutdownCtx); err != nil {
+		log.Printf("Error closing warehouse export: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		log.Printf("Error closing review store: %v", err)
+	}
 
-	// Fetch reviews
-	fmt.Printf("Fetching reviews for %s (page %d, rating %d)...\n", *product, *page, *rating)
-	reviews, err := client.FetchReviews(*product, *rating, *page)
-	if err != nil {
-		fmt.Printf("Error fetching reviews: %v\n", err)
-		os.Exit(1)
+	// Send any spans still buffered
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Printf("Error flushing traces: %v", err)
 	}
 
-	fmt.Printf("Successfully fetched %d reviews\n", len(reviews))
+	log.Println("Review Scraper System stopped")
+	return nil
+}
+
+// waitGroupDone waits for wg, giving up when ctx is done
+func waitGroupDone(ctx context.Context, wg *sync.WaitGroup) error {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
 
-	// Save reviews to file
-	filePath, err := scraper.SaveReviewsToFile(reviews, *product)
-	if err != nil {
-		fmt.Printf("Error saving reviews: %v\n", err)
-		os.Exit(1)
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
 	}
+}
 
-	fmt.Printf("Reviews saved to %s\n", filePath)
+// reloadTarget is a component reloaded on SIGHUP, with a function restoring
+// the section of the configuration it runs with
+type reloadTarget struct {
+	component config.Reloadable
+	keep      func(next, current *config.Config)
+}
 
-	// Wait for termination signal
-	sigCh := make(chan os.Signal, 1)
-	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
-	<-sigCh
+// reloadConfig re-reads the configuration file and pushes it to each component.
+// The current configuration is kept if the file cannot be loaded.
+func reloadConfig(current *config.Config, targets []reloadTarget) *config.Config {
+	log.Println("Received SIGHUP. Reloading configuration...")
 
-	log.Println("Received shutdown signal. Stopping services...")
+	next, warnings, err := config.Reload(current)
+	if err != nil {
+		log.Printf("Configuration reload failed, keeping current settings: %v", err)
+		return current
+	}
+	for _, warning := range warnings {
+		log.Printf("Warning: %s", warning)
+	}
 
-	// Allow graceful shutdown (e.g., finish current scraping jobs)
-	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer shutdownCancel()
+	applyReload(next, current, targets)
 
-	// Stop API server
-	if err := apiServer.Stop(shutdownCtx); err != nil {
-		log.Printf("Error stopping API server: %v", err)
-	}
+	log.Println("Configuration reloaded")
+	return next
+}
 
-	log.Println("Review Scraper System stopped")
+// applyReload pushes next to each target in turn. A component that rejects
+// its section keeps running with the current one, so that section of next is
+// restored from current before the remaining targets see it.
+func applyReload(next, current *config.Config, targets []reloadTarget) {
+	for _, target := range targets {
+		if err := target.component.Reload(next); err != nil {
+			log.Printf("Error reloading %T, keeping its current settings: %v", target.component, err)
+			target.keep(next, current)
+		}
+	}
 }
 
-// runPipeline executes the complete data processing pipeline
-func runPipeline(ctx context.Context, scraperManager *scraper.Manager, analyzer *analyzer.Analyzer,
-	router *router.Router, notifier *notifier.Notifier) {
+// runPipeline executes the complete data processing pipeline, passing the
+// reviews of a scraping run to process. Reviews process returns for a retry
+// are scraped again on the next run.
+func runPipeline(ctx context.Context, logger *slog.Logger, scraperManager *scraper.Manager, process api.Pipeline, store storage.Store) {
+	logger = logging.Component(logger, "pipeline")
+	logger.Info("starting scraping pipeline")
 
-	log.Println("Starting scraping pipeline...")
+	ctx, span := tracing.Start(ctx, "pipeline.run")
+	var runErr error
+	defer func() { tracing.End(span, runErr) }()
 
 	// Gather reviews from all sources
-	reviews, err := scraperManager.ScrapeAll(ctx)
+	run, err := scraperManager.StartRun()
+	if errors.Is(err, scraper.ErrRunInProgress) {
+		logger.Info("skipping scheduled run, a scraping run is already in progress")
+		return
+	}
+	batch, err := run(ctx)
 	if err != nil {
-		log.Printf("Error during scraping: %v", err)
+		logger.Error("scraping failed", "error", err)
+		runErr = err
 		return
 	}
 
-	log.Printf("Scraped %d reviews", len(reviews))
+	logger.Info("scraped reviews", "count", len(batch.Reviews))
+
+	retry := process(ctx, batch.Reviews)
+	batch.Commit(retry)
+	if len(retry) > 0 {
+		logger.Info("reviews will be retried on the next run", "count", len(retry))
+	}
+
+	// Forget notified reviews once they are past the store's TTL
+	if removed, err := store.PurgeExpired(ctx); err != nil {
+		logger.Error("failed to purge expired seen reviews", "error", err)
+	} else if removed > 0 {
+		logger.Info("purged expired seen reviews", "count", removed)
+	}
+
+	logger.Info("scraping pipeline completed")
+}
+
+// newPipeline returns the pipeline every scraping run goes through, scheduled
+// or started through the API: processReviews, then a flush of the analysis export
+func newPipeline(logger *slog.Logger, workers int, tenants *tenant.Resolver, analysisSink sink.AnalysisSink,
+	store storage.Store, record func(models.AnalyzedReview)) api.Pipeline {
+	logger = logging.Component(logger, "pipeline")
+	return func(ctx context.Context, reviews []models.Review) []models.Review {
+		retry := processReviews(ctx, logger, reviews, workers, tenants, analysisSink, store, record)
+		if err := analysisSink.Flush(ctx); err != nil {
+			logger.Error("analysis export failed", "error", err)
+		}
+		return retry
+	}
+}
+
+// processReviews analyzes, exports, routes and notifies reviews on a pool of
+// workers goroutines (defaultPipelineWorkers when not positive), passing each
+// processed review to record. Reviews finish in no particular order. It returns
+// the reviews whose notifications failed, to be retried.
+func processReviews(ctx context.Context, logger *slog.Logger, reviews []models.Review, workers int,
+	tenants *tenant.Resolver, analysisSink sink.AnalysisSink, store storage.Store, record func(models.AnalyzedReview)) []models.Review {
+	if workers <= 0 {
+		workers = defaultPipelineWorkers
+	}
+	if workers > len(reviews) {
+		workers = len(reviews)
+	}
+
+	var (
+		queue   = make(chan models.Review)
+		wg      sync.WaitGroup
+		retryMu sync.Mutex
+		retry   []models.Review
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for review := range queue {
+				entry, failed := processReview(ctx, logger, review, tenants, analysisSink, store)
+				record(entry)
+				if failed {
+					retryMu.Lock()
+					retry = append(retry, review)
+					retryMu.Unlock()
+				}
+			}
+		}()
+	}
 
 	for _, review := range reviews {
-		// Analyze sentiment and intent
-		analysisResult, err := analyzer.Analyze(ctx, review)
+		queue <- review
+	}
+	close(queue)
+	wg.Wait()
+	return retry
+}
+
+// processReview saves one review to store and runs it through its tenant's
+// analyzer, the analysis export and, when it is negative and relevant, routing
+// and notification. Reviews store has seen are not notified again, and a
+// review is marked seen once a notification about it was sent and none failed.
+// Failures are logged against the review rather than returned; failed reports
+// whether a notification failed, so the review should be retried.
+func processReview(ctx context.Context, logger *slog.Logger, review models.Review,
+	tenants *tenant.Resolver, analysisSink sink.AnalysisSink, store storage.Store) (_ models.AnalyzedReview, failed bool) {
+	ctx, span := tracing.Start(ctx, "pipeline.processReview", tracing.ReviewAttributes(review)...)
+	defer span.End()
+
+	// Process the review with its tenant's configuration
+	pipeline := tenants.Tag(&review)
+	reviewLogger := logging.WithReview(logger, review).With("tenant", pipeline.Name)
+
+	// Analyze sentiment and intent
+	analysisResult, err := pipeline.Analyzer.Analyze(ctx, review)
+	if err != nil {
+		reviewLogger.Error("analysis failed", "error", err)
+		saveReview(ctx, reviewLogger, store, review)
+		return models.AnalyzedReview{Review: review}, false
+	}
+	pipeline.Analyzer.AutoTag(&review, analysisResult)
+	saveReview(ctx, reviewLogger, store, review)
+	entry := models.AnalyzedReview{Review: review, Analysis: &analysisResult}
+
+	// Export every analysis, whether or not it leads to a notification
+	if err := analysisSink.Write(ctx, review, analysisResult); err != nil {
+		reviewLogger.Error("analysis export failed", "error", err)
+	}
+
+	// Route and notify negative, relevant reviews
+	if analysisResult.IsNegative && analysisResult.IsRelevant {
+		_, routeSpan := tracing.Start(ctx, "router.Route", tracing.ReviewAttributes(review)...)
+		department := pipeline.Router.Route(analysisResult)
+		escalations := pipeline.Router.Escalate(analysisResult, department)
+		routeSpan.SetAttributes(tracing.KeyDepartment.String(department.ID))
+		routeSpan.End()
+		entry.Department = department.ID
+
+		// A review notified before a restart is not notified again. If the
+		// store cannot tell, notify anyway rather than risk losing the alert.
+		seen, err := store.HasSeen(ctx, review)
 		if err != nil {
-			log.Printf("Error analyzing review: %v", err)
-			continue
+			reviewLogger.Error("failed to check for an earlier notification", "error", err)
 		}
-
-		// Skip if not negative or not relevant
-		if !analysisResult.IsNegative || !analysisResult.IsRelevant {
-			continue
+		if seen {
+			reviewLogger.Debug("skipping notification, review was already notified")
+			return entry, false
 		}
 
-		// Route to appropriate department
-		department := router.Route(analysisResult)
+		// Severe reviews also go to the escalation departments
+		sent := false
+		for _, target := range append([]models.Department{department}, escalations...) {
+			delivered, err := pipeline.Notifier.Deliver(ctx, target, review, analysisResult)
+			if err != nil {
+				reviewLogger.Error("notification failed", "department", target.ID, "error", err)
+				failed = true
+			}
+			sent = sent || delivered
+		}
 
-		// Send notification
-		if err := notifier.Notify(ctx, department, review, analysisResult); err != nil {
-			log.Printf("Error sending notification: %v", err)
+		// Only a review notified without a failure counts as seen. One that
+		// was skipped, or only logged by a dry run, is notified when it next
+		// qualifies, and a failed one is scraped again on the next run.
+		if sent && !failed {
+			if err := store.MarkSeen(ctx, review); err != nil {
+				reviewLogger.Error("failed to mark review seen", "error", err)
+			}
 		}
 	}
 
-	log.Println("Scraping pipeline completed")
+	return entry, failed
+}
+
+// saveReview stores a review, logging rather than returning a failure
+func saveReview(ctx context.Context, reviewLogger *slog.Logger, store storage.Store, review models.Review) {
+	if err := store.SaveReview(ctx, review); err != nil {
+		reviewLogger.Error("failed to save review", "error", err)
+	}
 }
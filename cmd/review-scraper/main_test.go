@@ -0,0 +1,457 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
+	"github.com/Infoblox-CTO/review-scraper/internal/api"
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/Infoblox-CTO/review-scraper/internal/router"
+	"github.com/Infoblox-CTO/review-scraper/internal/scraper"
+	"github.com/Infoblox-CTO/review-scraper/internal/sink"
+	"github.com/Infoblox-CTO/review-scraper/internal/storage"
+	"github.com/Infoblox-CTO/review-scraper/internal/tenant"
+	"github.com/Infoblox-CTO/review-scraper/internal/tracing"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"golang.org/x/time/rate"
+)
+
+func TestCommandsBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping build of ./cmd/... in short mode")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go toolchain not found")
+	}
+
+	// Building several main packages at once only checks that they compile
+	cmd := exec.Command(goTool, "build", "./cmd/...")
+	cmd.Dir = "../.."
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, "go build ./cmd/... failed:\n%s", output)
+}
+
+func TestWaitGroupDoneWaitsForInFlightRun(t *testing.T) {
+	release := make(chan struct{})
+	var finished bool
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		<-release
+		finished = true
+	}()
+
+	// Shutdown that times out mid-run reports the run is still going
+	shortCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, waitGroupDone(shortCtx, &wg), context.DeadlineExceeded)
+
+	// With time left, shutdown waits for the run to finish
+	close(release)
+	ctx, cancelWait := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelWait()
+	assert.NoError(t, waitGroupDone(ctx, &wg))
+	assert.True(t, finished)
+}
+
+// countingSink counts the analyses written to it
+type countingSink struct {
+	sink.NoopSink
+	mu      sync.Mutex
+	written int
+}
+
+func (s *countingSink) Write(ctx context.Context, review models.Review, result models.AnalysisResult) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.written++
+	return nil
+}
+
+func TestProcessReviewsHandlesEveryReview(t *testing.T) {
+	tenants := tenant.NewResolver(nil, &tenant.Pipeline{
+		Name:     tenant.DefaultTenant,
+		Analyzer: analyzer.New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.3}),
+		Router:   router.New(config.RouterConfig{}),
+		Notifier: notifier.New(config.NotifierConfig{}),
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// Repeated contents exercise the shared analyzer cache from several workers
+	reviews := make([]models.Review, 250)
+	for i := range reviews {
+		reviews[i] = models.Review{
+			ID:      fmt.Sprintf("review-%d", i),
+			Source:  "G2",
+			Content: fmt.Sprintf("NIOS upgrade %d was great", i%10),
+		}
+	}
+
+	analysisSink := &countingSink{}
+	var mu sync.Mutex
+	processed := make(map[string]models.AnalyzedReview)
+	processReviews(context.Background(), logger, reviews, 8, tenants, analysisSink, storage.NewMemoryStore(time.Hour), func(entry models.AnalyzedReview) {
+		mu.Lock()
+		defer mu.Unlock()
+		processed[entry.Review.ID] = entry
+	})
+
+	assert.Len(t, processed, len(reviews))
+	assert.Equal(t, len(reviews), analysisSink.written)
+	for _, review := range reviews {
+		entry := processed[review.ID]
+		if assert.NotNil(t, entry.Analysis, review.ID) {
+			assert.Equal(t, review.ID, entry.Analysis.ReviewID)
+		}
+		assert.Equal(t, tenant.DefaultTenant, entry.Review.Metadata["tenant"])
+	}
+}
+
+func TestProcessReviewsWithoutReviews(t *testing.T) {
+	tenants := tenant.NewResolver(nil, &tenant.Pipeline{Name: tenant.DefaultTenant})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	processReviews(context.Background(), logger, nil, 0, tenants, sink.NoopSink{}, storage.NewMemoryStore(time.Hour), func(models.AnalyzedReview) {
+		t.Error("no review should be recorded")
+	})
+}
+
+func TestProcessReviewNotifiesEscalationDepartments(t *testing.T) {
+	notifier := notifier.New(config.NotifierConfig{DryRun: true})
+	tenants := tenant.NewResolver(nil, &tenant.Pipeline{
+		Name:     tenant.DefaultTenant,
+		Analyzer: analyzer.New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.1}),
+		Router: router.New(config.RouterConfig{
+			Escalation: config.EscalationConfig{SeverityThreshold: models.SeverityHigh, Departments: []string{"management"}},
+		}),
+		Notifier: notifier,
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	review := models.Review{
+		ID:      "severe-1",
+		Source:  "G2",
+		Content: "Security breach: the DNS firewall is broken and the vulnerability is still not fixed, terrible",
+	}
+	entry, _ := processReview(context.Background(), logger, review, tenants, sink.NoopSink{}, storage.NewMemoryStore(time.Hour))
+	require.NotNil(t, entry.Analysis)
+	require.Equal(t, models.SeverityHigh, entry.Analysis.Severity)
+
+	var notified []string
+	for _, notification := range notifier.GetNotifications(review.ID) {
+		notified = append(notified, notification.Department.ID)
+	}
+	assert.ElementsMatch(t, []string{entry.Department, "management"}, notified)
+	assert.NotEqual(t, "management", entry.Department)
+}
+
+// negativeReview returns a review the local analyzer rates negative and relevant
+func negativeReview(id string) models.Review {
+	return models.Review{
+		ID:       id,
+		Source:   "G2",
+		SourceID: id,
+		Content:  "Security breach: the DNS firewall is broken and the vulnerability is still not fixed, terrible",
+	}
+}
+
+// newNotifyingTenants returns a default pipeline notifying through n
+func newNotifyingTenants(n *notifier.Notifier) *tenant.Resolver {
+	return tenant.NewResolver(nil, &tenant.Pipeline{
+		Name:     tenant.DefaultTenant,
+		Analyzer: analyzer.New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.1}),
+		Router:   router.New(config.RouterConfig{}),
+		Notifier: n,
+	})
+}
+
+// newWebhook starts a webhook answering every notification with status,
+// counting the notifications it receives
+func newWebhook(t *testing.T, status int) (*httptest.Server, *atomic.Int32) {
+	var received atomic.Int32
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Add(1)
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(webhook.Close)
+	return webhook, &received
+}
+
+// newWebhookNotifier returns a notifier sending to the webhook at url
+func newWebhookNotifier(url string) *notifier.Notifier {
+	return notifier.New(config.NotifierConfig{Webhook: config.WebhookConfig{Enabled: true, URL: url}})
+}
+
+func TestProcessReviewsSkipsReviewsNotifiedBeforeRestart(t *testing.T) {
+	webhook, received := newWebhook(t, http.StatusOK)
+	store := storage.NewMemoryStore(time.Hour)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	record := func(models.AnalyzedReview) {}
+
+	first := newWebhookNotifier(webhook.URL)
+	processReviews(context.Background(), logger, []models.Review{negativeReview("g2-1")}, 1,
+		newNotifyingTenants(first), sink.NoopSink{}, store, record)
+	assert.Len(t, first.GetNotifications("g2-1"), 1)
+
+	// After a restart, components start afresh but the store remembers
+	restarted := newWebhookNotifier(webhook.URL)
+	processReviews(context.Background(), logger, []models.Review{negativeReview("g2-1"), negativeReview("g2-2")}, 1,
+		newNotifyingTenants(restarted), sink.NoopSink{}, store, record)
+	assert.Empty(t, restarted.GetNotifications("g2-1"))
+	assert.Len(t, restarted.GetNotifications("g2-2"), 1)
+	assert.Equal(t, int32(2), received.Load())
+}
+
+func TestProcessReviewsNotifiesAfterDryRun(t *testing.T) {
+	webhook, received := newWebhook(t, http.StatusOK)
+	store := storage.NewMemoryStore(time.Hour)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	record := func(models.AnalyzedReview) {}
+
+	dryRun := notifier.New(config.NotifierConfig{DryRun: true, Webhook: config.WebhookConfig{Enabled: true, URL: webhook.URL}})
+	retry := processReviews(context.Background(), logger, []models.Review{negativeReview("g2-1")}, 1,
+		newNotifyingTenants(dryRun), sink.NoopSink{}, store, record)
+	assert.Empty(t, retry)
+	assert.Zero(t, received.Load())
+
+	seen, err := store.HasSeen(context.Background(), negativeReview("g2-1"))
+	assert.NoError(t, err)
+	assert.False(t, seen, "a dry run does not count as a notification")
+
+	// Restarted without dry run, the review is notified for real
+	live := newWebhookNotifier(webhook.URL)
+	processReviews(context.Background(), logger, []models.Review{negativeReview("g2-1")}, 1,
+		newNotifyingTenants(live), sink.NoopSink{}, store, record)
+	assert.Equal(t, int32(1), received.Load())
+	seen, err = store.HasSeen(context.Background(), negativeReview("g2-1"))
+	assert.NoError(t, err)
+	assert.True(t, seen)
+}
+
+func TestProcessReviewLeavesFailedNotificationsUnseen(t *testing.T) {
+	webhook, _ := newWebhook(t, http.StatusInternalServerError)
+	store := storage.NewMemoryStore(time.Hour)
+	failing := newWebhookNotifier(webhook.URL)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	review := negativeReview("g2-1")
+
+	entry, failed := processReview(context.Background(), logger, review, newNotifyingTenants(failing), sink.NoopSink{}, store)
+	require.NotEmpty(t, entry.Department)
+	assert.True(t, failed)
+
+	seen, err := store.HasSeen(context.Background(), review)
+	assert.NoError(t, err)
+	assert.False(t, seen, "a review whose notification failed is retried on the next run")
+}
+
+// savingStore is a MemoryStore that keeps the reviews saved to it
+type savingStore struct {
+	*storage.MemoryStore
+
+	mu    sync.Mutex
+	saved []string
+}
+
+func (s *savingStore) SaveReview(ctx context.Context, review models.Review) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = append(s.saved, review.ID)
+	return nil
+}
+
+func TestProcessReviewsSavesEveryReview(t *testing.T) {
+	store := &savingStore{MemoryStore: storage.NewMemoryStore(time.Hour)}
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	reviews := []models.Review{
+		negativeReview("g2-1"),
+		{ID: "g2-2", Source: "G2", SourceID: "g2-2", Content: "Great DNS management, works well"},
+	}
+
+	processReviews(context.Background(), logger, reviews, 2,
+		newNotifyingTenants(notifier.New(config.NotifierConfig{DryRun: true})), sink.NoopSink{}, store, func(models.AnalyzedReview) {})
+
+	assert.ElementsMatch(t, []string{"g2-1", "g2-2"}, store.saved)
+}
+
+// recordingReloadable remembers the configuration it was reloaded with
+type recordingReloadable struct {
+	cfg config.Config
+}
+
+func (r *recordingReloadable) Reload(cfg *config.Config) error {
+	r.cfg = *cfg
+	return nil
+}
+
+func TestApplyReloadKeepsSectionsComponentsRejected(t *testing.T) {
+	current := &config.Config{
+		Analyzer: config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.5},
+		Router:   config.RouterConfig{DefaultDepartment: "support"},
+	}
+	next := &config.Config{
+		Analyzer: config.AnalyzerConfig{Mode: "local", NegativeThreshold: -2},
+		Router:   config.RouterConfig{DefaultDepartment: "product"},
+	}
+	analyzer := analyzer.New(current.Analyzer)
+	router := router.New(current.Router)
+	server := &recordingReloadable{}
+
+	applyReload(next, current, []reloadTarget{
+		{analyzer, func(next, current *config.Config) { next.Analyzer = current.Analyzer }},
+		{router, func(next, current *config.Config) { next.Router = current.Router }},
+		{server, func(next, current *config.Config) {}},
+	})
+
+	assert.Equal(t, -0.5, analyzer.Config().NegativeThreshold, "an invalid analyzer section is not applied")
+	assert.Equal(t, current.Analyzer, next.Analyzer, "the rejected section is restored")
+	assert.Equal(t, "product", next.Router.DefaultDepartment, "accepted sections are kept")
+	assert.Equal(t, current.Analyzer, server.cfg.Analyzer, "later components see the settings still in use")
+	assert.Equal(t, "product", server.cfg.Router.DefaultDepartment)
+}
+
+// tracedScraper returns a fixed set of reviews
+type tracedScraper struct {
+	reviews []models.Review
+}
+
+func (s *tracedScraper) Name() string    { return "Traced" }
+func (s *tracedScraper) IsEnabled() bool { return true }
+func (s *tracedScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	return s.reviews, nil
+}
+
+// tracedSourceEnabled switches on the "traced" source; registrations cannot
+// be undone from outside the scraper package, so it stays off for other tests
+var tracedSourceEnabled bool
+
+// tracedSource is the scraper behind the "traced" source
+var tracedSource = &tracedScraper{}
+
+func init() {
+	scraper.Register("traced", func(config.ScrapersConfig, config.RateLimitConfig, *rate.Limiter) ([]scraper.Scraper, error) {
+		if !tracedSourceEnabled {
+			return nil, nil
+		}
+		return []scraper.Scraper{tracedSource}, nil
+	})
+}
+
+func TestRunPipelineTracesEachStage(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	tracedSource.reviews = []models.Review{{
+		ID:      "traced-1",
+		Source:  "traced",
+		Content: "NIOS upgrade is terrible, broken and awful",
+	}}
+	tracedSourceEnabled = true
+	manager, err := scraper.NewManager(config.ScrapersConfig{})
+	tracedSourceEnabled = false
+	require.NoError(t, err)
+
+	analyzer := analyzer.New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.1})
+	router := router.New(config.RouterConfig{})
+	notifier := notifier.New(config.NotifierConfig{DryRun: true})
+	tenants := tenant.NewResolver(nil, &tenant.Pipeline{
+		Name:     tenant.DefaultTenant,
+		Analyzer: analyzer,
+		Router:   router,
+		Notifier: notifier,
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	apiServer := api.NewServer(&config.Config{}, manager, analyzer, router, notifier)
+	store := storage.NewMemoryStore(time.Hour)
+
+	runPipeline(context.Background(), logger, manager, newPipeline(logger, 1, tenants, sink.NoopSink{}, store, apiServer.AddRecentReview), store)
+
+	spans := make(map[string]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = span
+	}
+	parentOf := func(child, parent string) {
+		t.Helper()
+		require.Contains(t, spans, child)
+		require.Contains(t, spans, parent)
+		assert.Equal(t, spans[parent].SpanContext.SpanID(), spans[child].Parent.SpanID(),
+			"%s should be a child of %s", child, parent)
+	}
+
+	require.Contains(t, spans, "pipeline.run")
+	assert.False(t, spans["pipeline.run"].Parent.IsValid())
+	parentOf("scraper.ScrapeAll", "pipeline.run")
+	parentOf("scraper.Scrape", "scraper.ScrapeAll")
+	parentOf("pipeline.processReview", "pipeline.run")
+	parentOf("analyzer.Analyze", "pipeline.processReview")
+	parentOf("router.Route", "pipeline.processReview")
+	parentOf("notifier.Notify", "pipeline.processReview")
+
+	assert.Contains(t, spans["scraper.Scrape"].Attributes, tracing.KeyScraper.String("Traced"))
+	for _, name := range []string{"pipeline.processReview", "analyzer.Analyze", "notifier.Notify"} {
+		assert.Contains(t, spans[name].Attributes, tracing.KeyReviewID.String("traced-1"), name)
+		assert.Contains(t, spans[name].Attributes, tracing.KeyReviewSource.String("traced"), name)
+	}
+}
+
+func TestRunPipelineScrapesFailedNotificationsAgain(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var received atomic.Int32
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received.Add(1)
+	}))
+	defer webhook.Close()
+
+	review := negativeReview("traced-1")
+	review.Source = "traced"
+	review.CreatedAt = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracedSource.reviews = []models.Review{review}
+	tracedSourceEnabled = true
+	manager, err := scraper.NewManager(config.ScrapersConfig{})
+	tracedSourceEnabled = false
+	require.NoError(t, err)
+
+	store := storage.NewMemoryStore(time.Hour)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	process := newPipeline(logger, 1, newNotifyingTenants(newWebhookNotifier(webhook.URL)), sink.NoopSink{}, store,
+		func(models.AnalyzedReview) {})
+
+	runPipeline(context.Background(), logger, manager, process, store)
+	assert.Zero(t, received.Load())
+
+	// The next run scrapes the review again and notifies it once the webhook recovers
+	failing.Store(false)
+	runPipeline(context.Background(), logger, manager, process, store)
+	assert.Equal(t, int32(1), received.Load())
+	seen, err := store.HasSeen(context.Background(), review)
+	assert.NoError(t, err)
+	assert.True(t, seen)
+
+	// Once notified, the review is behind the watermark
+	runPipeline(context.Background(), logger, manager, process, store)
+	assert.Equal(t, int32(1), received.Load())
+}
@@ -3,169 +3,127 @@ package analyzer
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
-	"regexp"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/internal/metrics"
+	"github.com/Infoblox-CTO/review-scraper/internal/tracing"
 	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/Infoblox-CTO/review-scraper/pkg/taxonomy"
 )
 
 // Analyzer processes review text to determine sentiment and intent
 type Analyzer struct {
-	config          config.AnalyzerConfig
-	httpClient      *http.Client
-	keywordMap      map[string]bool
-	infobloxTerms   map[string]string   // Maps Infoblox terms to their categories
-	productKeywords map[string][]string // Maps product categories to relevant keywords
-	cache           map[string]models.AnalysisResult
-	cacheMutex      sync.RWMutex
-	categoryMap     map[string]string // Maps keywords to categories
+	config           config.AnalyzerConfig
+	configMutex      sync.RWMutex // Guards config, keyword and category maps, the lexicon and remoteSlots, which can be swapped at runtime
+	httpClient       *http.Client
+	keywordMap       map[string]bool
+	cache            *resultCache
+	categoryMap      map[string]string // Maps keywords to categories; guarded by configMutex
+	lexicon          sentimentLexicon  // Local sentiment words; guarded by configMutex
+	customCategories map[string]string // The configured subset of categoryMap; guarded by configMutex
+	metrics          *metrics.Metrics
+	remoteSlots      chan struct{} // Limits in-flight remote requests; nil means unlimited
+	logger           *slog.Logger
 }
 
 // New creates a new analyzer with the provided configuration
 func New(cfg config.AnalyzerConfig) *Analyzer {
 	// Create a map for faster keyword lookups
-	keywordMap := make(map[string]bool, len(cfg.Keywords))
-	for _, keyword := range cfg.Keywords {
-		keywordMap[strings.ToLower(keyword)] = true
+	keywordMap := buildKeywordMap(cfg.Keywords)
+
+	return &Analyzer{
+		config:           cfg,
+		httpClient:       &http.Client{Timeout: 30 * time.Second},
+		keywordMap:       keywordMap,
+		cache:            newResultCache(cfg.CacheSize),
+		categoryMap:      buildCategoryMap(cfg),
+		customCategories: buildCustomCategories(cfg),
+		lexicon:          buildSentimentLexicon(cfg),
+		remoteSlots:      newRemoteSlots(cfg.MaxConcurrentRequests),
+		logger:           logging.Component(nil, "analyzer"),
 	}
+}
 
-	// Define some default category mappings if needed
-	defaultCategories := map[string]string{
-		"bug":        "bug_report",
-		"crash":      "bug_report",
-		"error":      "bug_report",
-		"broken":     "bug_report",
-		"freeze":     "bug_report",
-		"slow":       "performance",
-		"laggy":      "performance",
-		"hang":       "performance",
-		"feature":    "feature_request",
-		"missing":    "feature_request",
-		"wish":       "feature_request",
-		"delivery":   "logistics",
-		"shipping":   "logistics",
-		"payment":    "billing",
-		"charge":     "billing",
-		"refund":     "billing",
-		"support":    "customer_service",
-		"service":    "customer_service",
-		"rude":       "customer_service",
-		"interface":  "ui_ux",
-		"confusing":  "ui_ux",
-		"difficult":  "ui_ux",
-		"dns":        "network_services",
-		"dhcp":       "network_services",
-		"ipam":       "network_services",
-		"ddi":        "network_services",
-		"nios":       "nios_platform",
-		"bloxone":    "bloxone_platform",
-		"threat":     "security",
-		"secure":     "security",
-		"protection": "security",
-		"ddos":       "security",
-		"cloud":      "cloud_services",
-		"automation": "network_automation",
-		"netmri":     "network_automation",
-	}
-
-	// Create Infoblox-specific term mappings
-	infobloxTerms := map[string]string{
-		"ddi":            "core_product",
-		"dns":            "core_product",
-		"dhcp":           "core_product",
-		"ipam":           "core_product",
-		"bloxone":        "cloud_product",
-		"nios":           "on_prem_product",
-		"universal ddi":  "core_product",
-		"threat defense": "security_product",
-		"advanced dns":   "security_product",
-		"grid":           "infrastructure",
-		"netmri":         "automation",
-		"cloud network":  "cloud_product",
-		"ip address":     "core_product",
-		"outbound dns":   "security_product",
-		"dns firewall":   "security_product",
-		"lookup":         "core_product",
-		"zone":           "core_product",
-		"record":         "core_product",
-		"domain":         "core_product",
-		"bind":           "core_product",
-		"lease":          "core_product",
-		"subnet":         "core_product",
-		"network":        "core_product",
-		"vista":          "company",
-		"warburg":        "company",
-	}
-
-	// Define product keywords for better categorization
-	productKeywords := map[string][]string{
-		"bloxone": {
-			"bloxone", "cloud ddi", "cloud-native", "saas", "branch", "distributed",
-		},
-		"nios": {
-			"nios", "grid", "on-premise", "on-prem", "appliance", "virtual appliance", "vm",
-		},
-		"threat_defense": {
-			"threat defense", "security", "protect", "threat", "malware", "ransomware",
-			"exfiltration", "dns security", "secure dns", "threat intelligence",
-		},
-		"dns": {
-			"dns", "domain", "lookup", "zone", "record", "cname", "mx", "soa", "ns", "ptr",
-			"a record", "aaaa", "bind", "recursive", "resolver",
-		},
-		"dhcp": {
-			"dhcp", "lease", "ip assignment", "dynamic", "scope", "ip address allocation",
-		},
-		"ipam": {
-			"ipam", "ip address management", "subnet", "allocation", "address space",
-			"network management", "ip management",
-		},
+// newRemoteSlots returns a semaphore admitting max remote requests, or nil when unlimited
+func newRemoteSlots(max int) chan struct{} {
+	if max <= 0 {
+		return nil
 	}
+	return make(chan struct{}, max)
+}
 
-	return &Analyzer{
-		config:          cfg,
-		httpClient:      &http.Client{Timeout: 30 * time.Second},
-		keywordMap:      keywordMap,
-		infobloxTerms:   infobloxTerms,
-		productKeywords: productKeywords,
-		cache:           make(map[string]models.AnalysisResult),
-		cacheMutex:      sync.RWMutex{},
-		categoryMap:     defaultCategories,
+// acquireRemote waits for a free remote request slot and returns a function
+// releasing it. Callers beyond MaxConcurrentRequests queue here until a slot
+// frees up or ctx is done.
+func (a *Analyzer) acquireRemote(ctx context.Context) (func(), error) {
+	a.configMutex.RLock()
+	slots := a.remoteSlots
+	a.configMutex.RUnlock()
+
+	if slots == nil {
+		return func() {}, nil
+	}
+
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("waiting for analyzer request slot: %w", ctx.Err())
 	}
 }
 
 // Analyze processes a review to extract sentiment and intent
 func (a *Analyzer) Analyze(ctx context.Context, review models.Review) (models.AnalysisResult, error) {
-	// Generate a cache key based on the review content
-	cacheKey := fmt.Sprintf("%x", review.Content)
+	return a.analyze(ctx, review, true)
+}
 
-	// Check if we already analyzed this review
-	a.cacheMutex.RLock()
-	if cachedResult, found := a.cache[cacheKey]; found {
-		a.cacheMutex.RUnlock()
-		return cachedResult, nil
+// Reanalyze analyzes a review afresh with the current configuration, ignoring
+// any cached result, and replaces the cached result with the new one
+func (a *Analyzer) Reanalyze(ctx context.Context, review models.Review) (models.AnalysisResult, error) {
+	return a.analyze(ctx, review, false)
+}
+
+// analyze performs the analysis behind Analyze and Reanalyze, consulting the
+// result cache only when useCache is set
+func (a *Analyzer) analyze(ctx context.Context, review models.Review, useCache bool) (_ models.AnalysisResult, err error) {
+	ctx, span := tracing.Start(ctx, "analyzer.Analyze", tracing.ReviewAttributes(review)...)
+	defer func() { tracing.End(span, err) }()
+
+	// Generate a cache key based on the review source and content
+	key := cacheKey(review.Source, review.Content)
+
+	// Check if we already analyzed this content, unless a fresh analysis is wanted
+	if useCache {
+		if cachedResult, found := a.cache.Get(key); found {
+			cachedResult.ReviewID = review.ID
+			if review.Language != "" {
+				cachedResult.Language = review.Language
+			}
+			// Severity also depends on the review's rating, title and replies,
+			// which can differ between reviews with the same content
+			cachedResult.NeedsAction, cachedResult.Severity = assessSeverity(review, cachedResult, a.Config().DiscountVendorReplies)
+			return cachedResult, nil
+		}
 	}
-	a.cacheMutex.RUnlock()
 
 	// Analyze based on the configured mode
 	var result models.AnalysisResult
-	var err error
+	var degraded bool
 
-	switch a.config.Mode {
-	case "openai":
-		result, err = a.analyzeWithOpenAI(ctx, review)
-	case "google":
-		result, err = a.analyzeWithGoogle(ctx, review)
-	case "aws":
-		result, err = a.analyzeWithAWS(ctx, review)
-	case "azure":
-		result, err = a.analyzeWithAzure(ctx, review)
+	cfg := a.Config()
+	start := time.Now()
+
+	switch cfg.Mode {
+	case "openai", "google", "aws", "azure":
+		result, degraded, err = a.analyzeRemoteWithRecovery(ctx, cfg.Mode, review, cfg.FallbackToLocal)
 	case "local":
 		result, err = a.analyzeLocal(review)
 	default:
@@ -176,113 +134,123 @@ func (a *Analyzer) Analyze(ctx context.Context, review models.Review) (models.An
 		return models.AnalysisResult{}, err
 	}
 
-	// Add the review ID to the result
+	// Add the review ID and language to the result
 	result.ReviewID = review.ID
+	if review.Language != "" {
+		result.Language = review.Language
+	}
 
 	// Check if the result meets the thresholds for negativity and relevance
-	result.IsNegative = result.SentimentScore <= a.config.NegativeThreshold
-	result.IsRelevant = result.Confidence >= a.config.RelevanceThreshold
-
-	// Cache the result for future queries
-	a.cacheMutex.Lock()
-	a.cache[cacheKey] = result
-	a.cacheMutex.Unlock()
-
-	return result, nil
-}
+	result.IsNegative = result.SentimentScore <= cfg.NegativeThreshold
+	result.IsRelevant = result.Confidence >= cfg.RelevanceThreshold
+	result.NeedsAction, result.Severity = assessSeverity(review, result, cfg.DiscountVendorReplies)
 
-// analyzeLocal performs a basic sentiment and intent analysis without external APIs
-func (a *Analyzer) analyzeLocal(review models.Review) (models.AnalysisResult, error) {
-	content := strings.ToLower(review.Content)
-
-	// Basic sentiment analysis using predefined word lists
-	positiveWords := []string{
-		"good", "great", "awesome", "excellent", "amazing", "love", "best", "fantastic",
-		"perfect", "happy", "pleased", "satisfied", "wonderful", "helpful", "thank", "thanks",
-		"reliable", "secure", "efficient", "intuitive",
+	mode := cfg.Mode
+	if mode == "" {
+		mode = "local"
 	}
+	a.metrics.ObserveAnalysis(mode, result.IsNegative, time.Since(start))
+	logging.WithReview(a.logger, review).Debug("review analyzed",
+		"mode", mode,
+		"sentiment_score", result.SentimentScore,
+		"intent_category", result.IntentCategory,
+		"negative", result.IsNegative,
+		"relevant", result.IsRelevant,
+		"severity", result.Severity)
 
-	negativeWords := []string{
-		"bad", "poor", "terrible", "awful", "horrible", "worst", "hate", "disappointed",
-		"frustrating", "useless", "broken", "annoying", "slow", "expensive", "waste",
-		"difficult", "confusing", "crash", "bug", "error", "problem", "issue", "fail", "fails",
-		"failed", "failing", "failure", "cannot", "can't", "won't", "doesn't", "didn't",
-		"insecure", "vulnerability", "breach", "outage", "downtime",
+	// Cache the result for future queries; a fallback result is not kept, so
+	// the backend is asked again once it recovers
+	if !degraded {
+		a.cache.Add(key, result)
 	}
 
-	// Count word occurrences
-	var positiveCount, negativeCount int
+	return result, nil
+}
 
-	for _, word := range positiveWords {
-		positiveCount += countOccurrences(content, word)
+// analyzeRemote calls the remote analysis service for mode, holding a request
+// slot for the duration of the call
+func (a *Analyzer) analyzeRemote(ctx context.Context, mode string, review models.Review) (models.AnalysisResult, error) {
+	release, err := a.acquireRemote(ctx)
+	if err != nil {
+		return models.AnalysisResult{}, err
 	}
+	defer release()
 
-	for _, word := range negativeWords {
-		negativeCount += countOccurrences(content, word)
+	switch mode {
+	case "google":
+		return a.analyzeWithGoogle(ctx, review)
+	case "aws":
+		return a.analyzeWithAWS(ctx, review)
+	case "azure":
+		return a.analyzeWithAzure(ctx, review)
+	default:
+		return a.analyzeWithOpenAI(ctx, review)
 	}
+}
 
-	// Calculate sentiment score between -1 (very negative) and 1 (very positive)
-	var sentimentScore float64
-	totalWords := positiveCount + negativeCount
-	if totalWords > 0 {
-		sentimentScore = float64(positiveCount-negativeCount) / float64(totalWords)
-	}
+// QuickSentiment returns only the local sentiment score of content, between
+// -1 (very negative) and 1 (very positive), skipping keyword, entity and
+// category analysis and the result cache. It suits high-volume pre-filtering,
+// and matches the score local analysis gives the same content.
+func (a *Analyzer) QuickSentiment(content string) float64 {
+	a.configMutex.RLock()
+	lexicon := a.lexicon
+	a.configMutex.RUnlock()
+	return lexicon.sentiment(content)
+}
 
-	// Check for exclamation marks and ALL CAPS, which might indicate stronger sentiment
-	exclamationCount := strings.Count(review.Content, "!")
-	capsPattern := regexp.MustCompile(`[A-Z]{3,}`)
-	capsCount := len(capsPattern.FindAllString(review.Content, -1))
+// analyzeLocal performs a basic sentiment and intent analysis without external APIs
+func (a *Analyzer) analyzeLocal(review models.Review) (models.AnalysisResult, error) {
+	content := strings.ToLower(review.Content)
 
-	// Adjust sentiment score based on these indicators
-	if exclamationCount > 2 || capsCount > 2 {
-		if sentimentScore < 0 {
-			// Make more negative if already negative
-			sentimentScore *= 1.2
-			if sentimentScore < -1 {
-				sentimentScore = -1
-			}
-		} else if sentimentScore > 0 {
-			// Make more positive if already positive
-			sentimentScore *= 1.2
-			if sentimentScore > 1 {
-				sentimentScore = 1
-			}
-		}
-	}
+	// Basic sentiment analysis using the configured word lists
+	a.configMutex.RLock()
+	lexicon := a.lexicon
+	a.configMutex.RUnlock()
+	sentimentScore := lexicon.sentiment(review.Content)
 
 	// Determine if the review contains relevant keywords
 	var keywords []string
-	for keyword := range a.keywordMap {
+	a.configMutex.RLock()
+	keywordMap := a.keywordMap
+	a.configMutex.RUnlock()
+
+	for keyword := range keywordMap {
 		if strings.Contains(content, keyword) {
 			keywords = append(keywords, keyword)
 		}
 	}
 
-	// Infoblox product specific detection
-	infobloxProducts := []string{
-		"infoblox", "bloxone", "nios", "ddi", "dns firewall", "threat defense",
-		"netmri", "ip address management", "ipam", "dhcp", "advanced dns protection",
-	}
-
-	for _, product := range infobloxProducts {
-		if strings.Contains(content, product) && !contains(keywords, product) {
-			keywords = append(keywords, product)
+	// Infoblox products mentioned, matched as whole words with the shared taxonomy
+	var products []string
+	for product := range taxonomy.ProductMentions(review.Content) {
+		if !contains(keywords, product) {
+			products = append(products, product)
 		}
 	}
+	sort.Strings(products)
+	keywords = append(keywords, products...)
+
+	// Determine intent category from the relevant keywords found, plus any
+	// configured category keywords that appear anywhere in the content
+	a.configMutex.RLock()
+	categoryMap := a.categoryMap
+	customCategories := a.customCategories
+	a.configMutex.RUnlock()
 
-	// Determine intent category based on keywords
 	categoryScores := make(map[string]float64)
-	for _, keyword := range keywords {
-		if category, exists := a.categoryMap[keyword]; exists {
+	for keyword, category := range categoryMap {
+		_, custom := customCategories[keyword]
+		if contains(keywords, keyword) || (custom && strings.Contains(content, keyword)) {
 			categoryScores[category]++
 		}
 	}
 
-	// Choose the category with the highest score
+	// Choose the category with the highest score, breaking ties by name
 	var topCategory string
 	var topScore float64
 	for category, score := range categoryScores {
-		if score > topScore {
+		if score > topScore || (score == topScore && category < topCategory) {
 			topCategory = category
 			topScore = score
 		}
@@ -293,36 +261,8 @@ func (a *Analyzer) analyzeLocal(review models.Review) (models.AnalysisResult, er
 		topCategory = "general_complaint"
 	}
 
-	// Extract simple entities (products, features)
-	var entities []models.Entity
-
-	// Infoblox specific products and features to look for
-	productPatterns := []string{
-		"infoblox", "bloxone", "nios", "ddi", "dhcp", "dns", "ipam", "netmri",
-		"threat defense", "dns firewall", "cloud network automation",
-	}
-
-	for _, pattern := range productPatterns {
-		if idx := strings.Index(content, pattern); idx >= 0 {
-			entities = append(entities, models.Entity{
-				Text:     pattern,
-				Type:     "PRODUCT",
-				Position: idx,
-			})
-		}
-	}
-
-	// Add generic product/feature detection as fallback
-	genericPatterns := []string{"app", "website", "service", "product", "interface", "platform", "system"}
-	for _, pattern := range genericPatterns {
-		if idx := strings.Index(content, pattern); idx >= 0 && !containsEntityWithText(entities, pattern) {
-			entities = append(entities, models.Entity{
-				Text:     pattern,
-				Type:     "PRODUCT",
-				Position: idx,
-			})
-		}
-	}
+	// Extract simple entities (products, features), with offsets into the original text
+	entities := extractEntities(review.Content)
 
 	// Set confidence proportional to the number of keywords found
 	confidence := 0.5
@@ -335,15 +275,8 @@ func (a *Analyzer) analyzeLocal(review models.Review) (models.AnalysisResult, er
 		}
 	}
 
-	// If the content has a star rating, use that to adjust sentiment
-	if review.Rating != nil {
-		// Convert 5-star scale to -1 to 1 scale
-		// 1 star = -1, 5 stars = 1
-		ratingScore := (*review.Rating - 3) / 2
-
-		// Blend the lexical and rating-based scores (60% lexical, 40% rating)
-		sentimentScore = sentimentScore*0.6 + ratingScore*0.4
-	}
+	// If the review has a rating, blend it into the sentiment
+	sentimentScore = blendRating(a.Config(), review, sentimentScore)
 
 	return models.AnalysisResult{
 		SentimentScore: sentimentScore,
@@ -367,21 +300,6 @@ func contains(slice []string, s string) bool {
 	return false
 }
 
-// containsEntityWithText checks if an entity with the given text exists in the slice
-func containsEntityWithText(entities []models.Entity, text string) bool {
-	for _, entity := range entities {
-		if entity.Text == text {
-			return true
-		}
-	}
-	return false
-}
-
-// countOccurrences counts how many times a word appears in text
-func countOccurrences(text, word string) int {
-	return len(strings.Split(text, word)) - 1
-}
-
 // OpenAIRequest represents the structure of a request to the OpenAI API
 type OpenAIRequest struct {
 	Model     string    `json:"model"`
@@ -407,14 +325,35 @@ type Choice struct {
 	FinishReason string  `json:"finish_reason"`
 }
 
-// analyzeWithOpenAI uses OpenAI API for sentiment and intent analysis
-func (a *Analyzer) analyzeWithOpenAI(ctx context.Context, review models.Review) (models.AnalysisResult, error) {
-	if a.config.APIKey == "" {
-		return models.AnalysisResult{}, errors.New("OpenAI API key is not configured")
+// defaultOpenAIEndpoint is used when AnalyzerConfig.ModelEndpoint is empty
+const defaultOpenAIEndpoint = "https://api.openai.com/v1/chat/completions"
+
+// buildPrompt creates the analysis prompt for a review, adding whichever review
+// metadata fields are listed in AnalyzerConfig.PromptMetadata
+func (a *Analyzer) buildPrompt(review models.Review) string {
+	var metadata strings.Builder
+	for _, field := range a.Config().PromptMetadata {
+		switch strings.ToLower(field) {
+		case "title":
+			if review.Title != "" {
+				metadata.WriteString(fmt.Sprintf("Title: %s\n", review.Title))
+			}
+		case "rating":
+			if review.Rating != nil {
+				metadata.WriteString(fmt.Sprintf("Rating: %.1f (out of 5)\n", *review.Rating))
+			}
+		case "source":
+			if review.Source != "" {
+				metadata.WriteString(fmt.Sprintf("Source: %s\n", review.Source))
+			}
+		case "tags":
+			if tags := reviewTags(review); len(tags) > 0 {
+				metadata.WriteString(fmt.Sprintf("Tags: %s\n", strings.Join(tags, ", ")))
+			}
+		}
 	}
 
-	// Create a prompt that asks for sentiment analysis and intent classification
-	prompt := fmt.Sprintf(`
+	return fmt.Sprintf(`
 Analyze the following product review/comment for sentiment and intent.
 Return the analysis as a JSON object with the following fields:
 - sentimentScore: a number between -1 (very negative) and 1 (very positive)
@@ -424,8 +363,45 @@ Return the analysis as a JSON object with the following fields:
 - entities: an array of detected entities like product names, features, etc.
 - categoryScores: a dictionary mapping each category to a relevance score
 
-Review: "%s"
-`, review.Content)
+%sReview: "%s"
+`, metadata.String(), review.Content)
+}
+
+// reviewTags returns the review tags, or the hashtags or tags a scraper stored in its metadata
+func reviewTags(review models.Review) []string {
+	if len(review.Tags) > 0 {
+		return review.Tags
+	}
+	for _, key := range []string{"tags", "hashtags"} {
+		switch tags := review.Metadata[key].(type) {
+		case []string:
+			if len(tags) > 0 {
+				return tags
+			}
+		case []interface{}:
+			var result []string
+			for _, tag := range tags {
+				if str, ok := tag.(string); ok {
+					result = append(result, str)
+				}
+			}
+			if len(result) > 0 {
+				return result
+			}
+		}
+	}
+	return nil
+}
+
+// analyzeWithOpenAI uses OpenAI API for sentiment and intent analysis
+func (a *Analyzer) analyzeWithOpenAI(ctx context.Context, review models.Review) (models.AnalysisResult, error) {
+	cfg := a.Config()
+	if cfg.APIKey == "" {
+		return models.AnalysisResult{}, fmt.Errorf("%w: OpenAI API key is not configured", ErrNotConfigured)
+	}
+
+	// Create a prompt that asks for sentiment analysis and intent classification
+	prompt := a.buildPrompt(review)
 
 	// Create the request
 	openaiReq := OpenAIRequest{
@@ -448,11 +424,17 @@ Review: "%s"
 		return models.AnalysisResult{}, fmt.Errorf("error marshaling OpenAI request: %w", err)
 	}
 
+	// Use the configured endpoint if one is set
+	endpoint := cfg.ModelEndpoint
+	if endpoint == "" {
+		endpoint = defaultOpenAIEndpoint
+	}
+
 	// Create the HTTP request
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
-		"https://api.openai.com/v1/chat/completions",
+		endpoint,
 		strings.NewReader(string(reqBody)),
 	)
 	if err != nil {
@@ -461,36 +443,36 @@ Review: "%s"
 
 	// Set headers
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", "Bearer "+a.config.APIKey)
+	req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
 
 	// Send the request
 	resp, err := a.httpClient.Do(req)
 	if err != nil {
-		return models.AnalysisResult{}, fmt.Errorf("error sending request to OpenAI: %w", err)
+		return models.AnalysisResult{}, fmt.Errorf("%w: error sending request to OpenAI: %w", ErrUpstream, err)
 	}
 	defer resp.Body.Close()
 
 	// Check for API errors
 	if resp.StatusCode != http.StatusOK {
-		return models.AnalysisResult{}, fmt.Errorf("OpenAI API returned status code %d", resp.StatusCode)
+		return models.AnalysisResult{}, statusError("OpenAI API", resp)
 	}
 
 	// Parse the response
 	var openaiResp OpenAIResponse
 	if err := json.NewDecoder(resp.Body).Decode(&openaiResp); err != nil {
-		return models.AnalysisResult{}, fmt.Errorf("error parsing OpenAI response: %w", err)
+		return models.AnalysisResult{}, fmt.Errorf("%w: error parsing OpenAI response: %w", ErrParse, err)
 	}
 
 	// Ensure we have at least one choice
 	if len(openaiResp.Choices) == 0 {
-		return models.AnalysisResult{}, errors.New("no choices returned from OpenAI")
+		return models.AnalysisResult{}, fmt.Errorf("%w: no choices returned from OpenAI", ErrParse)
 	}
 
 	// Parse the JSON content from the response
 	var analysisResult models.AnalysisResult
 	err = json.Unmarshal([]byte(openaiResp.Choices[0].Message.Content), &analysisResult)
 	if err != nil {
-		return models.AnalysisResult{}, fmt.Errorf("error unmarshaling analysis result: %w", err)
+		return models.AnalysisResult{}, fmt.Errorf("%w: error unmarshaling analysis result: %w", ErrParse, err)
 	}
 
 	return analysisResult, nil
@@ -499,32 +481,123 @@ Review: "%s"
 // analyzeWithGoogle uses Google Cloud Natural Language API for analysis
 func (a *Analyzer) analyzeWithGoogle(ctx context.Context, review models.Review) (models.AnalysisResult, error) {
 	// This is a placeholder - in a real implementation, this would use the Google Cloud Natural Language API
-	return models.AnalysisResult{}, errors.New("Google Cloud analysis not implemented")
+	return models.AnalysisResult{}, fmt.Errorf("%w: Google Cloud analysis not implemented", ErrNotConfigured)
 }
 
 // analyzeWithAWS uses AWS Comprehend for analysis
 func (a *Analyzer) analyzeWithAWS(ctx context.Context, review models.Review) (models.AnalysisResult, error) {
 	// This is a placeholder - in a real implementation, this would use AWS Comprehend
-	return models.AnalysisResult{}, errors.New("AWS Comprehend analysis not implemented")
+	return models.AnalysisResult{}, fmt.Errorf("%w: AWS Comprehend analysis not implemented", ErrNotConfigured)
 }
 
 // analyzeWithAzure uses Azure Text Analytics for analysis
 func (a *Analyzer) analyzeWithAzure(ctx context.Context, review models.Review) (models.AnalysisResult, error) {
 	// This is a placeholder - in a real implementation, this would use Azure Text Analytics
-	return models.AnalysisResult{}, errors.New("Azure Text Analytics analysis not implemented")
+	return models.AnalysisResult{}, fmt.Errorf("%w: Azure Text Analytics analysis not implemented", ErrNotConfigured)
+}
+
+// Config returns the analyzer's current configuration
+func (a *Analyzer) Config() config.AnalyzerConfig {
+	a.configMutex.RLock()
+	defer a.configMutex.RUnlock()
+	return a.config
+}
+
+// SetMetrics enables instrumentation of analyses
+func (a *Analyzer) SetMetrics(m *metrics.Metrics) {
+	a.metrics = m
+}
+
+// SetLogger sets the structured logger used for analysis events
+func (a *Analyzer) SetLogger(logger *slog.Logger) {
+	a.logger = logging.Component(logger, "analyzer")
+}
+
+// UpdateConfig swaps the analyzer configuration at runtime. Subsequent Analyze
+// calls use the new mode, thresholds and keywords; cached results are dropped
+// because they were computed with the old settings. A changed request limit
+// applies to new requests while those in flight finish under the old one.
+func (a *Analyzer) UpdateConfig(cfg config.AnalyzerConfig) {
+	a.configMutex.Lock()
+	if cfg.MaxConcurrentRequests != a.config.MaxConcurrentRequests {
+		a.remoteSlots = newRemoteSlots(cfg.MaxConcurrentRequests)
+	}
+	a.config = cfg
+	a.keywordMap = buildKeywordMap(cfg.Keywords)
+	a.categoryMap = buildCategoryMap(cfg)
+	a.customCategories = buildCustomCategories(cfg)
+	a.lexicon = buildSentimentLexicon(cfg)
+	a.configMutex.Unlock()
+
+	a.cache.Reset(cfg.CacheSize)
+}
+
+// Reload applies the analyzer section of a reloaded configuration
+func (a *Analyzer) Reload(cfg *config.Config) error {
+	if err := cfg.Analyzer.Validate(); err != nil {
+		return err
+	}
+	a.UpdateConfig(cfg.Analyzer)
+	return nil
+}
+
+// buildKeywordMap creates a lowercase lookup set of keywords
+func buildKeywordMap(keywords []string) map[string]bool {
+	keywordMap := make(map[string]bool, len(keywords))
+	for _, keyword := range keywords {
+		keywordMap[strings.ToLower(keyword)] = true
+	}
+	return keywordMap
+}
+
+// buildCategoryMap maps lowercase keywords to intent categories. Categories from
+// the config are merged over the defaults, so a configured keyword takes
+// precedence, unless ReplaceDefaultCategories drops the defaults entirely.
+func buildCategoryMap(cfg config.AnalyzerConfig) map[string]string {
+	categoryMap := make(map[string]string)
+	if !cfg.ReplaceDefaultCategories {
+		for keyword, category := range taxonomy.DefaultCategories {
+			categoryMap[keyword] = category
+		}
+	}
+	for keyword, category := range buildCustomCategories(cfg) {
+		categoryMap[keyword] = category
+	}
+	return categoryMap
+}
+
+// buildCustomCategories maps the configured lowercase category keywords to their category
+func buildCustomCategories(cfg config.AnalyzerConfig) map[string]string {
+	customCategories := make(map[string]string)
+	for category, keywords := range cfg.CategoryKeywords {
+		for _, keyword := range keywords {
+			customCategories[strings.ToLower(keyword)] = category
+		}
+	}
+	return customCategories
 }
 
 // GetStats returns statistics about the analyzer
 func (a *Analyzer) GetStats() map[string]interface{} {
-	a.cacheMutex.RLock()
-	defer a.cacheMutex.RUnlock()
+	cfg := a.Config()
+
+	a.configMutex.RLock()
+	keywordCount := len(a.keywordMap)
+	categoryCount := len(a.categoryMap)
+	a.configMutex.RUnlock()
+
+	cache := a.cache.Stats()
 
 	return map[string]interface{}{
-		"cache_size":          len(a.cache),
-		"mode":                a.config.Mode,
-		"negative_threshold":  a.config.NegativeThreshold,
-		"relevance_threshold": a.config.RelevanceThreshold,
-		"keyword_count":       len(a.keywordMap),
-		"category_count":      len(a.categoryMap),
+		"cache_size":          cache.Size,
+		"cache_capacity":      cache.Capacity,
+		"cache_hits":          cache.Hits,
+		"cache_misses":        cache.Misses,
+		"cache_evictions":     cache.Evictions,
+		"mode":                cfg.Mode,
+		"negative_threshold":  cfg.NegativeThreshold,
+		"relevance_threshold": cfg.RelevanceThreshold,
+		"keyword_count":       keywordCount,
+		"category_count":      categoryCount,
 	}
 }
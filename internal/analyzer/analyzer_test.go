@@ -0,0 +1,244 @@
+package analyzer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/router"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockOpenAIServer returns a fixed analysis and records the user prompt of each request
+func mockOpenAIServer(t *testing.T, prompts *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req OpenAIRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("invalid OpenAI request body: %v", err)
+		}
+		for _, msg := range req.Messages {
+			if msg.Role == "user" {
+				*prompts = append(*prompts, msg.Content)
+			}
+		}
+
+		content := `{"sentimentScore": -0.6, "intentCategory": "bug_report", "confidence": 0.9, "keywords": ["nios"]}`
+		resp := OpenAIResponse{
+			ID:      "chatcmpl-test",
+			Choices: []Choice{{Message: Message{Role: "assistant", Content: content}, FinishReason: "stop"}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+}
+
+// sampleReview returns a review carrying every metadata field the prompt can include
+func sampleReview() models.Review {
+	rating := 2.0
+	return models.Review{
+		ID:       "twitter-1",
+		Source:   "twitter",
+		Title:    "Upgrade broke our grid",
+		Content:  "NIOS 9.0 upgrade left half the grid members offline",
+		Rating:   &rating,
+		Metadata: map[string]interface{}{"hashtags": []string{"infoblox", "outage"}},
+	}
+}
+
+func TestOpenAIPromptIncludesConfiguredMetadata(t *testing.T) {
+	var prompts []string
+	server := mockOpenAIServer(t, &prompts)
+	defer server.Close()
+
+	a := New(config.AnalyzerConfig{
+		Mode:           "openai",
+		APIKey:         "test-key",
+		ModelEndpoint:  server.URL,
+		PromptMetadata: []string{"title", "rating", "source", "tags"},
+	})
+
+	result, err := a.Analyze(context.Background(), sampleReview())
+	assert.NoError(t, err)
+	assert.Equal(t, "bug_report", result.IntentCategory)
+
+	assert.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "Title: Upgrade broke our grid")
+	assert.Contains(t, prompts[0], "Rating: 2.0 (out of 5)")
+	assert.Contains(t, prompts[0], "Source: twitter")
+	assert.Contains(t, prompts[0], "Tags: infoblox, outage")
+	assert.Contains(t, prompts[0], `Review: "NIOS 9.0 upgrade left half the grid members offline"`)
+}
+
+func TestOpenAIPromptOmitsUnconfiguredMetadata(t *testing.T) {
+	var prompts []string
+	server := mockOpenAIServer(t, &prompts)
+	defer server.Close()
+
+	a := New(config.AnalyzerConfig{
+		Mode:           "openai",
+		APIKey:         "test-key",
+		ModelEndpoint:  server.URL,
+		PromptMetadata: []string{"source"},
+	})
+
+	_, err := a.Analyze(context.Background(), sampleReview())
+	assert.NoError(t, err)
+
+	assert.Len(t, prompts, 1)
+	assert.Contains(t, prompts[0], "Source: twitter")
+	assert.NotContains(t, prompts[0], "Title:")
+	assert.NotContains(t, prompts[0], "Rating:")
+	assert.NotContains(t, prompts[0], "Tags:")
+}
+
+func TestMaxConcurrentRequestsLimitsInFlightCalls(t *testing.T) {
+	const maxConcurrent = 3
+	var inFlight, peak int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			seen := atomic.LoadInt32(&peak)
+			if current <= seen || atomic.CompareAndSwapInt32(&peak, seen, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		content := `{"sentimentScore": -0.6, "intentCategory": "bug_report", "confidence": 0.9, "keywords": ["nios"]}`
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(OpenAIResponse{
+			Choices: []Choice{{Message: Message{Role: "assistant", Content: content}, FinishReason: "stop"}},
+		})
+	}))
+	defer server.Close()
+
+	a := New(config.AnalyzerConfig{
+		Mode:                  "openai",
+		APIKey:                "test-key",
+		ModelEndpoint:         server.URL,
+		MaxConcurrentRequests: maxConcurrent,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			review := sampleReview()
+			review.Content = fmt.Sprintf("NIOS upgrade %d left the grid offline", i)
+			_, err := a.Analyze(context.Background(), review)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(maxConcurrent))
+	assert.Equal(t, int32(maxConcurrent), atomic.LoadInt32(&peak), "requests beyond the limit should queue, not serialize")
+}
+
+func TestMaxConcurrentRequestsHonorsContext(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", MaxConcurrentRequests: 1})
+	release, err := a.acquireRemote(context.Background())
+	assert.NoError(t, err)
+	defer release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = a.Analyze(ctx, sampleReview())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestReloadSwapsThresholds(t *testing.T) {
+	var reloadable config.Reloadable = New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.5})
+	a := reloadable.(*Analyzer)
+	review := models.Review{ID: "r1", Content: "Great DNS features but the upgrade is slow and has a bug"}
+
+	before, err := a.Analyze(context.Background(), review)
+	assert.NoError(t, err)
+	assert.False(t, before.IsNegative)
+
+	err = reloadable.Reload(&config.Config{Analyzer: config.AnalyzerConfig{Mode: "local", NegativeThreshold: 0.0}})
+	assert.NoError(t, err)
+
+	// The cached result from the old thresholds is discarded
+	after, err := a.Analyze(context.Background(), review)
+	assert.NoError(t, err)
+	assert.True(t, after.IsNegative)
+	assert.Equal(t, 0.0, a.Config().NegativeThreshold)
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.5})
+
+	err := a.Reload(&config.Config{Analyzer: config.AnalyzerConfig{Mode: "openai", NegativeThreshold: -2}})
+
+	assert.Error(t, err)
+	assert.Equal(t, "local", a.Config().Mode, "the previous config is kept")
+	assert.Equal(t, -0.5, a.Config().NegativeThreshold)
+}
+
+func TestCustomCategoryIsDetectedAndRouted(t *testing.T) {
+	a := New(config.AnalyzerConfig{
+		Mode:     "local",
+		Keywords: []string{"nios"},
+		CategoryKeywords: map[string][]string{
+			"documentation": {"docs", "Admin Guide"},
+		},
+	})
+	r := router.New(config.RouterConfig{
+		Mappings: []config.DepartmentMapping{
+			{Category: "documentation", Department: "documentation", Priority: 5},
+		},
+		DefaultDepartment: "support",
+	})
+
+	result, err := a.Analyze(context.Background(), models.Review{
+		ID:      "docs-1",
+		Content: "The NIOS admin guide is outdated and the docs contradict each other",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "documentation", result.IntentCategory)
+	assert.Equal(t, 2.0, result.CategoryScores["documentation"])
+	assert.Equal(t, "documentation", r.Route(result).ID)
+}
+
+func TestCategoryKeywordsMergeWithOrReplaceDefaults(t *testing.T) {
+	custom := map[string][]string{"deployment": {"rollout"}, "capacity": {"slow"}}
+	review := models.Review{Content: "The rollout was slow"}
+
+	merged := New(config.AnalyzerConfig{Mode: "local", Keywords: []string{"crash"}, CategoryKeywords: custom})
+	assert.Equal(t, "capacity", merged.categoryMap["slow"], "configured keywords override the defaults")
+	assert.Equal(t, "bug_report", merged.categoryMap["crash"], "other defaults are kept")
+
+	replaced := New(config.AnalyzerConfig{Mode: "local", CategoryKeywords: custom, ReplaceDefaultCategories: true})
+	assert.NotContains(t, replaced.categoryMap, "crash")
+
+	result, err := replaced.Analyze(context.Background(), review)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]float64{"capacity": 1, "deployment": 1}, result.CategoryScores)
+	assert.Equal(t, "capacity", result.IntentCategory, "ties are broken by category name")
+}
+
+func TestLocalAnalysisDetectsProductsFromTaxonomy(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+
+	result, err := a.Analyze(context.Background(), models.Review{
+		ID:      "grid-1",
+		Content: "Our grid appliance rebooted twice and DHCP leases were lost",
+	})
+	assert.NoError(t, err)
+	assert.True(t, result.IsRelevant)
+	assert.Subset(t, result.Keywords, []string{"dhcp", "nios"})
+	assert.NotContains(t, result.Keywords, "dns", `"ns" is matched as a whole word only`)
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// DefaultCacheSize is the number of analyses kept when CacheSize is unset
+const DefaultCacheSize = 10000
+
+// cacheKey identifies a review's content in the cache. The source is part of
+// the key so identical text posted about different products is analyzed
+// separately. Differences in spacing share an entry, but case does not: the
+// local analyzer scores shouting and remote backends see the text as written.
+// The content is hashed to keep keys short.
+func cacheKey(source, content string) string {
+	sum := sha256.Sum256([]byte(collapseSpacing(content)))
+	return source + ":" + hex.EncodeToString(sum[:])
+}
+
+// cacheEntry is an element of the cache's recency list
+type cacheEntry struct {
+	key    string
+	result models.AnalysisResult
+}
+
+// CacheStats reports the analysis cache's size and effectiveness
+type CacheStats struct {
+	Size      int    `json:"size"`
+	Capacity  int    `json:"capacity"`
+	Hits      uint64 `json:"hits"`
+	Misses    uint64 `json:"misses"`
+	Evictions uint64 `json:"evictions"`
+}
+
+// resultCache is a fixed-capacity LRU cache of analysis results. Counters
+// survive Reset so they describe the whole life of the process.
+type resultCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List // Front is the most recently used
+	entries  map[string]*list.Element
+
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// newResultCache creates a cache holding up to capacity results; a
+// non-positive capacity means DefaultCacheSize
+func newResultCache(capacity int) *resultCache {
+	c := &resultCache{}
+	c.Reset(capacity)
+	return c
+}
+
+// Get returns the cached result for key and marks it as recently used
+func (c *resultCache) Get(key string) (models.AnalysisResult, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.entries[key]
+	if !found {
+		c.misses++
+		return models.AnalysisResult{}, false
+	}
+	c.hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).result, true
+}
+
+// Add stores result under key, evicting the least recently used result when full
+func (c *resultCache) Add(key string, result models.AnalysisResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, found := c.entries[key]; found {
+		elem.Value.(*cacheEntry).result = result
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, result: result})
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+		c.evictions++
+	}
+}
+
+// Reset drops every cached result and sets a new capacity
+func (c *resultCache) Reset(capacity int) {
+	if capacity <= 0 {
+		capacity = DefaultCacheSize
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.capacity = capacity
+	c.order = list.New()
+	c.entries = make(map[string]*list.Element)
+}
+
+// Stats returns the current size and counters
+func (c *resultCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return CacheStats{
+		Size:      c.order.Len(),
+		Capacity:  c.capacity,
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+	}
+}
@@ -0,0 +1,163 @@
+package analyzer
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResultCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := newResultCache(2)
+	c.Add("a", models.AnalysisResult{ReviewID: "a"})
+	c.Add("b", models.AnalysisResult{ReviewID: "b"})
+
+	// Reading "a" makes "b" the least recently used
+	_, found := c.Get("a")
+	assert.True(t, found)
+	c.Add("c", models.AnalysisResult{ReviewID: "c"})
+
+	_, found = c.Get("b")
+	assert.False(t, found, "b should have been evicted")
+	result, found := c.Get("a")
+	assert.True(t, found)
+	assert.Equal(t, "a", result.ReviewID)
+	_, found = c.Get("c")
+	assert.True(t, found)
+
+	assert.Equal(t, CacheStats{Size: 2, Capacity: 2, Hits: 3, Misses: 1, Evictions: 1}, c.Stats())
+}
+
+func TestResultCacheUpdateDoesNotEvict(t *testing.T) {
+	c := newResultCache(2)
+	c.Add("a", models.AnalysisResult{SentimentScore: 0.1})
+	c.Add("b", models.AnalysisResult{})
+	c.Add("a", models.AnalysisResult{SentimentScore: 0.9})
+
+	result, _ := c.Get("a")
+	assert.Equal(t, 0.9, result.SentimentScore)
+	assert.Zero(t, c.Stats().Evictions)
+}
+
+func TestResultCacheResetKeepsCounters(t *testing.T) {
+	c := newResultCache(0)
+	assert.Equal(t, DefaultCacheSize, c.Stats().Capacity)
+
+	c.Add("a", models.AnalysisResult{})
+	c.Get("a")
+	c.Reset(5)
+
+	_, found := c.Get("a")
+	assert.False(t, found)
+	assert.Equal(t, CacheStats{Size: 0, Capacity: 5, Hits: 1, Misses: 1}, c.Stats())
+}
+
+func TestCacheKeyHashesContent(t *testing.T) {
+	long := cacheKey("twitter", strings.Repeat("NIOS outage ", 1000))
+
+	assert.Len(t, long, len("twitter:")+64)
+	assert.Equal(t, long, cacheKey("twitter", strings.Repeat("NIOS outage ", 1000)))
+	assert.NotEqual(t, long, cacheKey("twitter", "NIOS outage"))
+}
+
+func TestShoutedContentIsNotServedFromCache(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	ctx := context.Background()
+
+	plain, err := a.Analyze(ctx, models.Review{ID: "r1", Content: "great support but the upgrade is slow and broken"})
+	assert.NoError(t, err)
+	shouted, err := a.Analyze(ctx, models.Review{ID: "r2", Content: "great support BUT THE UPGRADE IS  slow and broken"})
+	assert.NoError(t, err)
+	fresh, err := New(config.AnalyzerConfig{Mode: "local"}).Analyze(ctx, models.Review{ID: "r3", Content: "great support BUT THE UPGRADE IS  slow and broken"})
+	assert.NoError(t, err)
+
+	assert.Zero(t, a.cache.Stats().Hits, "a change of case is analyzed again")
+	assert.Equal(t, fresh.SentimentScore, shouted.SentimentScore)
+	assert.Less(t, shouted.SentimentScore, plain.SentimentScore, "shouting strengthens the negative score")
+
+	_, err = a.Analyze(ctx, models.Review{ID: "r4", Content: "great support BUT THE UPGRADE IS slow and broken"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), a.cache.Stats().Hits, "differences in spacing still share an entry")
+}
+
+func TestIdenticalContentFromDifferentSourcesIsCachedSeparately(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	ctx := context.Background()
+	content := "The upgrade broke our grid again"
+
+	_, err := a.Analyze(ctx, models.Review{ID: "t1", Source: "twitter", Content: content})
+	assert.NoError(t, err)
+	_, err = a.Analyze(ctx, models.Review{ID: "g1", Source: "g2", Content: content})
+	assert.NoError(t, err)
+
+	stats := a.cache.Stats()
+	assert.Equal(t, 2, stats.Size, "each source gets its own entry")
+	assert.Zero(t, stats.Hits)
+
+	_, found := a.cache.Get(cacheKey("twitter", content))
+	assert.True(t, found)
+	_, found = a.cache.Get(cacheKey("g2", content))
+	assert.True(t, found)
+}
+
+func TestAnalyzerReportsCacheStats(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local", CacheSize: 2})
+	ctx := context.Background()
+
+	for _, content := range []string{"first review", "second review", "first review", "third review"} {
+		_, err := a.Analyze(ctx, models.Review{ID: content, Content: content})
+		assert.NoError(t, err)
+	}
+
+	stats := a.GetStats()
+	assert.Equal(t, 2, stats["cache_size"])
+	assert.Equal(t, 2, stats["cache_capacity"])
+	assert.Equal(t, uint64(1), stats["cache_hits"])
+	assert.Equal(t, uint64(3), stats["cache_misses"])
+	assert.Equal(t, uint64(1), stats["cache_evictions"])
+}
+
+func TestCachedResultCarriesRequestingReviewID(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	ctx := context.Background()
+
+	_, err := a.Analyze(ctx, models.Review{ID: "r1", Content: "same text"})
+	assert.NoError(t, err)
+	result, err := a.Analyze(ctx, models.Review{ID: "r2", Content: "same text"})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "r2", result.ReviewID)
+}
+
+func TestReanalyzeBypassesAndRefreshesCache(t *testing.T) {
+	var prompts []string
+	server := mockOpenAIServer(t, &prompts)
+	defer server.Close()
+
+	a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", ModelEndpoint: server.URL})
+	ctx := context.Background()
+	review := sampleReview()
+
+	_, err := a.Analyze(ctx, review)
+	assert.NoError(t, err)
+	_, err = a.Analyze(ctx, review)
+	assert.NoError(t, err)
+	assert.Len(t, prompts, 1)
+
+	result, err := a.Reanalyze(ctx, review)
+	assert.NoError(t, err)
+	assert.Len(t, prompts, 2)
+	assert.Equal(t, review.ID, result.ReviewID)
+	assert.Equal(t, "bug_report", result.IntentCategory)
+
+	// The fresh result replaces the cached one without counting as a lookup
+	_, err = a.Analyze(ctx, review)
+	assert.NoError(t, err)
+	assert.Len(t, prompts, 2)
+	stats := a.GetStats()
+	assert.Equal(t, uint64(2), stats["cache_hits"])
+	assert.Equal(t, uint64(1), stats["cache_misses"])
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// entityPattern matches whole-word mentions of an entity, ignoring case
+type entityPattern struct {
+	text string
+	re   *regexp.Regexp
+}
+
+// newEntityPatterns compiles a pattern per entity text. Words must match whole,
+// so "dns" does not match inside "wednesday"; multi-word entities match across
+// any whitespace. With plurals set, a trailing "s" is also accepted.
+func newEntityPatterns(plurals bool, texts ...string) []entityPattern {
+	suffix := ""
+	if plurals {
+		suffix = "s?"
+	}
+
+	patterns := make([]entityPattern, 0, len(texts))
+	for _, text := range texts {
+		words := strings.Fields(text)
+		for i, word := range words {
+			words[i] = regexp.QuoteMeta(word)
+		}
+		patterns = append(patterns, entityPattern{
+			text: text,
+			re:   regexp.MustCompile(`(?i)\b` + strings.Join(words, `\s+`) + suffix + `\b`),
+		})
+	}
+	return patterns
+}
+
+var (
+	// productEntityPatterns are Infoblox products and features
+	productEntityPatterns = newEntityPatterns(false,
+		"infoblox", "bloxone", "nios", "ddi", "dhcp", "dns", "ipam", "netmri",
+		"threat defense", "dns firewall", "cloud network automation",
+	)
+
+	// genericEntityPatterns are generic product and feature words, used as a fallback
+	genericEntityPatterns = newEntityPatterns(true,
+		"app", "website", "service", "product", "interface", "platform", "system",
+	)
+
+	// versionPattern matches a versioned product mention such as "NIOS 8.6.2",
+	// "BloxOne v3.x" or "NetMRI version 7.5". A bare number needs a dotted
+	// part, so "NIOS 2 times" is not read as a version.
+	versionPattern = regexp.MustCompile(`(?i)\b(nios|bloxone|netmri)[\s-]+(?:(?:v|version\s*)(\d+(?:\.(?:\d+|x))*)|(\d+(?:\.(?:\d+|x))+))\b`)
+)
+
+// extractEntities returns the product entities mentioned in content, each at
+// the byte offset of its first mention, followed by any product versions
+func extractEntities(content string) []models.Entity {
+	var entities []models.Entity
+	for _, patterns := range [][]entityPattern{productEntityPatterns, genericEntityPatterns} {
+		for _, pattern := range patterns {
+			if loc := pattern.re.FindStringIndex(content); loc != nil && !containsEntityWithText(entities, pattern.text) {
+				entities = append(entities, models.Entity{
+					Text:     pattern.text,
+					Type:     "PRODUCT",
+					Position: loc[0],
+				})
+			}
+		}
+	}
+	return append(entities, extractVersions(content)...)
+}
+
+// extractVersions returns a VERSION entity per distinct versioned product
+// mention, at the offset of the product name. Text is the lower-cased product
+// and normalized version, e.g. "nios 8.6.2" for "NIOS v08.6.2".
+func extractVersions(content string) []models.Entity {
+	var entities []models.Entity
+	for _, match := range versionPattern.FindAllStringSubmatchIndex(content, -1) {
+		// Group 2 holds a prefixed version, group 3 a bare dotted one
+		start, end := match[4], match[5]
+		if start < 0 {
+			start, end = match[6], match[7]
+		}
+		version := content[start:end]
+		text := strings.ToLower(content[match[2]:match[3]]) + " " + normalizeVersion(version)
+		if !containsEntityWithText(entities, text) {
+			entities = append(entities, models.Entity{
+				Text:     text,
+				Type:     "VERSION",
+				Position: match[0],
+			})
+		}
+	}
+	return entities
+}
+
+// normalizeVersion drops leading zeros from each numeric part of a dotted
+// version and lower-cases wildcard parts, so "08.06.X" becomes "8.6.x"
+func normalizeVersion(version string) string {
+	parts := strings.Split(version, ".")
+	for i, part := range parts {
+		if n, err := strconv.Atoi(part); err == nil {
+			parts[i] = strconv.Itoa(n)
+		} else {
+			parts[i] = strings.ToLower(part)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// containsEntityWithText checks if an entity with the given text exists in the slice
+func containsEntityWithText(entities []models.Entity, text string) bool {
+	for _, entity := range entities {
+		if entity.Text == text {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractEntitiesIgnoresSubstrings(t *testing.T) {
+	for _, content := range []string{
+		"See you on Wednesday",
+		"I am so happy with the application",
+		"Addition of new features was slow",
+		"The systematic approach and productivity gains were nice",
+		"Apply the upgrade to the serviceable units",
+		"Windshield and dhcpv6-less dnssec",
+	} {
+		assert.Empty(t, extractEntities(content), content)
+	}
+}
+
+func TestExtractEntitiesFindsWholeWords(t *testing.T) {
+	content := "DNS on Wednesday: the NIOS app crashed, and the dns  firewall apps too."
+
+	assert.Equal(t, []models.Entity{
+		{Text: "nios", Type: "PRODUCT", Position: 22},
+		{Text: "dns", Type: "PRODUCT", Position: 0},
+		{Text: "dns firewall", Type: "PRODUCT", Position: 48},
+		{Text: "app", Type: "PRODUCT", Position: 27},
+	}, extractEntities(content))
+}
+
+func TestExtractEntitiesPositionsIndexOriginalText(t *testing.T) {
+	// "İ" lower-cases to a longer string, so offsets into a lower-cased copy
+	// would drift
+	content := "İİİ BloxOne"
+
+	entities := extractEntities(content)
+
+	if assert.Len(t, entities, 1) {
+		assert.Equal(t, "bloxone", entities[0].Text)
+		assert.Equal(t, "BloxOne", content[entities[0].Position:entities[0].Position+len("bloxone")])
+	}
+}
+
+func TestLocalAnalysisHasNoSpuriousEntities(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+
+	result, err := a.Analyze(context.Background(), models.Review{
+		ID:      "happy-1",
+		Content: "Happy Wednesday! The application handled every request.",
+	})
+
+	assert.NoError(t, err)
+	assert.Empty(t, result.Entities)
+}
+
+func TestExtractVersions(t *testing.T) {
+	tests := []struct {
+		content string
+		want    []string
+	}{
+		{"After the NIOS 8.6.2 upgrade the grid is unstable", []string{"nios 8.6.2"}},
+		{"BloxOne 3.x agents keep disconnecting", []string{"bloxone 3.x"}},
+		{"Running NetMRI version 7.5 on premises", []string{"netmri 7.5"}},
+		{"We moved from nios v08.06 to NIOS 9.0.X last week", []string{"nios 8.6", "nios 9.0.x"}},
+		{"NIOS-9.0 and nios 9.0 are the same release", []string{"nios 9.0"}},
+		{"NIOS 2 times faster than before, BloxOne 3", nil},
+		{"Version 8.6 of the app", nil},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, entity := range extractVersions(tt.content) {
+			assert.Equal(t, "VERSION", entity.Type)
+			got = append(got, entity.Text)
+		}
+		assert.Equal(t, tt.want, got, tt.content)
+	}
+}
+
+func TestExtractEntitiesIncludesVersionsAlongsideProducts(t *testing.T) {
+	content := "Since upgrading to NIOS 8.6.2 the DNS service drops queries"
+
+	assert.Equal(t, []models.Entity{
+		{Text: "nios", Type: "PRODUCT", Position: 19},
+		{Text: "dns", Type: "PRODUCT", Position: 34},
+		{Text: "service", Type: "PRODUCT", Position: 38},
+		{Text: "nios 8.6.2", Type: "VERSION", Position: 19},
+	}, extractEntities(content))
+}
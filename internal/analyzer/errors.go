@@ -0,0 +1,109 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// Errors returned by the remote analysis backends. Check them with errors.Is.
+var (
+	// ErrNotConfigured means the backend cannot be used as configured, such as
+	// a missing or rejected API key; retrying will not help
+	ErrNotConfigured = errors.New("analysis backend is not configured")
+
+	// ErrRateLimited means the backend asked us to slow down; the request can
+	// be retried later
+	ErrRateLimited = errors.New("analysis backend rate limited the request")
+
+	// ErrUpstream means the backend could not be reached or failed the request
+	ErrUpstream = errors.New("analysis backend request failed")
+
+	// ErrParse means the backend answered with a response we could not read
+	ErrParse = errors.New("analysis backend response could not be parsed")
+)
+
+const (
+	// defaultRateLimitWait is how long to wait before retrying a rate-limited
+	// request that did not say when to retry
+	defaultRateLimitWait = time.Second
+
+	// maxRateLimitWait caps the wait a backend's Retry-After can ask for
+	maxRateLimitWait = 30 * time.Second
+)
+
+// rateLimitError is an ErrRateLimited carrying how long the backend asked us to wait
+type rateLimitError struct {
+	backend    string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("%s: %s asked to retry after %s", ErrRateLimited, e.backend, e.retryAfter)
+}
+
+func (e *rateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// statusError converts a backend's non-OK HTTP response into a typed error
+func statusError(backend string, resp *http.Response) error {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		return &rateLimitError{backend: backend, retryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("%w: %s rejected the API key (status code %d)", ErrNotConfigured, backend, resp.StatusCode)
+	default:
+		return fmt.Errorf("%w: %s returned status code %d", ErrUpstream, backend, resp.StatusCode)
+	}
+}
+
+// retryAfter parses a Retry-After header given in seconds, capped at
+// maxRateLimitWait, falling back to defaultRateLimitWait
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return defaultRateLimitWait
+	}
+	if wait := time.Duration(seconds) * time.Second; wait < maxRateLimitWait {
+		return wait
+	}
+	return maxRateLimitWait
+}
+
+// degradedConfidence scales the confidence of local analyses standing in for
+// a remote backend, flagging them as degraded
+const degradedConfidence = 0.5
+
+// analyzeRemoteWithRecovery analyzes a review with a remote backend, retrying
+// once when rate limited. It falls back to local analysis when the backend is
+// not configured, or after any failure when fallbackToLocal is set; degraded
+// reports such a fallback, whose confidence is lowered.
+func (a *Analyzer) analyzeRemoteWithRecovery(ctx context.Context, mode string, review models.Review, fallbackToLocal bool) (result models.AnalysisResult, degraded bool, err error) {
+	result, err = a.analyzeRemote(ctx, mode, review)
+
+	var limited *rateLimitError
+	if errors.As(err, &limited) {
+		a.logger.Debug("remote analysis rate limited, retrying", "mode", mode, "retry_after", limited.retryAfter)
+		select {
+		case <-time.After(limited.retryAfter):
+			result, err = a.analyzeRemote(ctx, mode, review)
+		case <-ctx.Done():
+			err = fmt.Errorf("%w: %w", err, ctx.Err())
+		}
+	}
+
+	if err == nil || !(fallbackToLocal || errors.Is(err, ErrNotConfigured)) {
+		return result, false, err
+	}
+
+	a.logger.Warn("remote analysis failed, falling back to local analysis", "mode", mode, "error", err)
+	result, err = a.analyzeLocal(review)
+	result.Confidence *= degradedConfidence
+	return result, true, err
+}
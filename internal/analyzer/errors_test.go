@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusServer answers every request with status, counting the requests
+func statusServer(status int, header http.Header, calls *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(`{"choices": []}`))
+	}))
+}
+
+func TestOpenAIErrorTypes(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{"rate limited", http.StatusTooManyRequests, ErrRateLimited},
+		{"rejected key", http.StatusUnauthorized, ErrNotConfigured},
+		{"server error", http.StatusBadGateway, ErrUpstream},
+		{"empty answer", http.StatusOK, ErrParse},
+	}
+
+	for _, c := range cases {
+		var calls int32
+		server := statusServer(c.status, nil, &calls)
+		a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", ModelEndpoint: server.URL})
+
+		_, err := a.analyzeWithOpenAI(context.Background(), sampleReview())
+		server.Close()
+
+		assert.ErrorIs(t, err, c.want, c.name)
+		for _, other := range []error{ErrNotConfigured, ErrRateLimited, ErrUpstream, ErrParse} {
+			if other != c.want {
+				assert.False(t, errors.Is(err, other), "%s is not %v", c.name, other)
+			}
+		}
+	}
+}
+
+func TestOpenAIMissingKeyIsNotConfigured(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "openai"})
+
+	_, err := a.analyzeWithOpenAI(context.Background(), sampleReview())
+
+	assert.ErrorIs(t, err, ErrNotConfigured)
+	assert.False(t, errors.Is(err, ErrRateLimited))
+}
+
+func TestRateLimitErrorCarriesRetryAfter(t *testing.T) {
+	var calls int32
+	server := statusServer(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"7"}}, &calls)
+	defer server.Close()
+	a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", ModelEndpoint: server.URL})
+
+	_, err := a.analyzeWithOpenAI(context.Background(), sampleReview())
+
+	var limited *rateLimitError
+	if assert.ErrorAs(t, err, &limited) {
+		assert.Equal(t, 7*time.Second, limited.retryAfter)
+	}
+	assert.Equal(t, defaultRateLimitWait, retryAfter(""))
+	assert.Equal(t, maxRateLimitWait, retryAfter("3600"))
+}
+
+func TestAnalyzeRetriesRateLimitedRequestOnce(t *testing.T) {
+	var calls int32
+	server := statusServer(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"0"}}, &calls)
+	defer server.Close()
+	a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", ModelEndpoint: server.URL})
+
+	_, err := a.Analyze(context.Background(), sampleReview())
+
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestAnalyzeFallsBackToLocalWhenNotConfigured(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "google", NegativeThreshold: -0.3})
+
+	result, err := a.Analyze(context.Background(), sampleReview())
+
+	assert.NoError(t, err)
+	assert.Equal(t, "twitter-1", result.ReviewID)
+	assert.NotEmpty(t, result.IntentCategory)
+}
+
+func TestAnalyzeReturnsUpstreamErrors(t *testing.T) {
+	var calls int32
+	server := statusServer(http.StatusInternalServerError, nil, &calls)
+	defer server.Close()
+	a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", ModelEndpoint: server.URL})
+
+	_, err := a.Analyze(context.Background(), sampleReview())
+
+	assert.ErrorIs(t, err, ErrUpstream)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "upstream failures are not retried")
+}
+
+func TestAnalyzeFallsBackToLocalWhenBackendFails(t *testing.T) {
+	var calls int32
+	server := statusServer(http.StatusServiceUnavailable, nil, &calls)
+	defer server.Close()
+	cfg := config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", ModelEndpoint: server.URL, NegativeThreshold: -0.3, FallbackToLocal: true}
+	a := New(cfg)
+	review := sampleReview()
+
+	result, err := a.Analyze(context.Background(), review)
+
+	assert.NoError(t, err)
+	assert.Equal(t, review.ID, result.ReviewID)
+	local, _ := New(config.AnalyzerConfig{Mode: "local"}).analyzeLocal(review)
+	assert.Equal(t, local.SentimentScore, result.SentimentScore)
+	assert.Equal(t, local.IntentCategory, result.IntentCategory)
+	assert.InDelta(t, local.Confidence*degradedConfidence, result.Confidence, 1e-9, "fallback results are flagged with lower confidence")
+
+	// Degraded results are not cached, so the backend is tried again
+	_, err = a.Analyze(context.Background(), review)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestAnalyzeFallsBackAfterRateLimitRetry(t *testing.T) {
+	var calls int32
+	server := statusServer(http.StatusTooManyRequests, http.Header{"Retry-After": []string{"0"}}, &calls)
+	defer server.Close()
+	a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "test-key", ModelEndpoint: server.URL, FallbackToLocal: true})
+
+	_, err := a.Analyze(context.Background(), sampleReview())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,21 @@
+package analyzer
+
+import "strings"
+
+// zeroWidthRemover strips the invisible characters that copy-pasted and
+// HTML-scraped text picks up
+var zeroWidthRemover = strings.NewReplacer(
+	"\u200b", "", // Zero width space
+	"\u200c", "", // Zero width non-joiner
+	"\u200d", "", // Zero width joiner
+	"\u2060", "", // Word joiner
+	"\ufeff", "", // Byte order mark / zero width no-break space
+)
+
+// collapseSpacing reduces review content to the form used to recognize the
+// same text across sources: without zero-width characters, and with runs of
+// whitespace collapsed to single spaces and trimmed at the ends, keeping its
+// case. It is used for cache keys only; reviews keep their content as scraped.
+func collapseSpacing(content string) string {
+	return strings.Join(strings.Fields(zeroWidthRemover.Replace(content)), " ")
+}
@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollapseSpacing(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"keeps case", "NIOS Upgrade Failed", "NIOS Upgrade Failed"},
+		{"collapses whitespace", "nios\t upgrade\n\nfailed", "nios upgrade failed"},
+		{"trims", "  nios upgrade failed \n", "nios upgrade failed"},
+		{"strips zero-width characters", "\ufeffnios up\u200bgrade fai\u200dled\u2060", "nios upgrade failed"},
+		{"non-breaking spaces are whitespace", "nios\u00a0upgrade failed", "nios upgrade failed"},
+		{"keeps punctuation", "nios upgrade failed!", "nios upgrade failed!"},
+		{"empty", " \u200b ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, collapseSpacing(tt.content))
+		})
+	}
+}
+
+func TestCosmeticallyDifferentReviewsShareCacheEntry(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	ctx := context.Background()
+
+	tweet := models.Review{ID: "t1", Source: "twitter", Content: "The NIOS upgrade  broke our grid again"}
+	scraped := models.Review{ID: "t2", Source: "twitter", Content: "\n  The NIOS upgrade broke\u200b our grid\u00a0again "}
+
+	first, err := a.Analyze(ctx, tweet)
+	assert.NoError(t, err)
+	second, err := a.Analyze(ctx, scraped)
+	assert.NoError(t, err)
+
+	stats := a.cache.Stats()
+	assert.Equal(t, 1, stats.Size)
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, first.SentimentScore, second.SentimentScore)
+	assert.Equal(t, "t2", second.ReviewID)
+
+	// Stored content is left as scraped
+	assert.Equal(t, "\n  The NIOS upgrade broke\u200b our grid\u00a0again ", scraped.Content)
+}
@@ -0,0 +1,51 @@
+package analyzer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Ping checks that the configured analysis backend can be used. Local
+// analysis needs nothing; the OpenAI backend must have an API key and answer
+// at its endpoint without rejecting the key. The returned errors match those
+// of a failed analysis, so they can be checked with errors.Is.
+func (a *Analyzer) Ping(ctx context.Context) error {
+	cfg := a.Config()
+
+	switch cfg.Mode {
+	case "", "local":
+		return nil
+	case "google", "aws", "azure":
+		return fmt.Errorf("%w: %s analysis not implemented", ErrNotConfigured, cfg.Mode)
+	}
+
+	if cfg.APIKey == "" {
+		return fmt.Errorf("%w: OpenAI API key is not configured", ErrNotConfigured)
+	}
+	endpoint := cfg.ModelEndpoint
+	if endpoint == "" {
+		endpoint = defaultOpenAIEndpoint
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error creating OpenAI ping request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: error reaching OpenAI: %w", ErrUpstream, err)
+	}
+	defer resp.Body.Close()
+
+	// The endpoint only accepts POSTs, so a client error other than a rejected
+	// key still shows it is up
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden,
+		resp.StatusCode >= http.StatusInternalServerError:
+		return statusError("OpenAI API", resp)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package analyzer
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPingLocal(t *testing.T) {
+	assert.NoError(t, New(config.AnalyzerConfig{Mode: "local"}).Ping(context.Background()))
+	assert.NoError(t, New(config.AnalyzerConfig{}).Ping(context.Background()))
+}
+
+func TestPingUnconfiguredBackends(t *testing.T) {
+	assert.ErrorIs(t, New(config.AnalyzerConfig{Mode: "openai"}).Ping(context.Background()), ErrNotConfigured)
+	assert.ErrorIs(t, New(config.AnalyzerConfig{Mode: "aws", APIKey: "key"}).Ping(context.Background()), ErrNotConfigured)
+}
+
+func TestPingOpenAI(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		want   error
+	}{
+		{"method not allowed", http.StatusMethodNotAllowed, nil},
+		{"rejected key", http.StatusUnauthorized, ErrNotConfigured},
+		{"server error", http.StatusServiceUnavailable, ErrUpstream},
+	}
+
+	for _, c := range cases {
+		var calls int32
+		server := statusServer(c.status, nil, &calls)
+		a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "key", ModelEndpoint: server.URL})
+
+		err := a.Ping(context.Background())
+		if c.want == nil {
+			assert.NoError(t, err, c.name)
+		} else {
+			assert.ErrorIs(t, err, c.want, c.name)
+		}
+		assert.Equal(t, int32(1), calls, c.name)
+		server.Close()
+	}
+}
+
+func TestPingOpenAIUnreachable(t *testing.T) {
+	var calls int32
+	server := statusServer(http.StatusOK, nil, &calls)
+	server.Close()
+
+	a := New(config.AnalyzerConfig{Mode: "openai", APIKey: "key", ModelEndpoint: server.URL})
+	assert.ErrorIs(t, a.Ping(context.Background()), ErrUpstream)
+}
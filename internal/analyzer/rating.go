@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// defaultRatingWeight is the share of a rated review's sentiment taken from
+// its rating when none is configured
+const defaultRatingWeight = 0.4
+
+// defaultRatingScale is the five-star scale ratings are on unless configured
+var defaultRatingScale = config.RatingScale{Min: 1, Max: 5}
+
+// ratingScale returns the scale ratings from source are on
+func ratingScale(cfg config.AnalyzerConfig, source string) config.RatingScale {
+	if scale, ok := cfg.SourceRatingScales[source]; ok && scale.Max > scale.Min {
+		return scale
+	}
+	if cfg.RatingScale.Max > cfg.RatingScale.Min {
+		return cfg.RatingScale
+	}
+	return defaultRatingScale
+}
+
+// ratingSentiment maps a rating onto the -1 to 1 sentiment range, so the
+// bottom of its scale is -1, the middle 0 and the top 1
+func ratingSentiment(rating float64, scale config.RatingScale) float64 {
+	score := 2*(rating-scale.Min)/(scale.Max-scale.Min) - 1
+	if score < -1 {
+		return -1
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
+
+// blendRating mixes a lexical sentiment score with the review's rating, if it
+// has one, weighted by the configured rating weight
+func blendRating(cfg config.AnalyzerConfig, review models.Review, lexical float64) float64 {
+	if review.Rating == nil {
+		return lexical
+	}
+
+	weight := defaultRatingWeight
+	if cfg.RatingWeight != nil {
+		weight = *cfg.RatingWeight
+	}
+	rating := ratingSentiment(*review.Rating, ratingScale(cfg, review.Source))
+	return lexical*(1-weight) + rating*weight
+}
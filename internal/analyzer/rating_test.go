@@ -0,0 +1,63 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRatingSentimentNormalizesScales(t *testing.T) {
+	fiveStar := config.RatingScale{Min: 1, Max: 5}
+	tenPoint := config.RatingScale{Min: 1, Max: 10}
+	thumbs := config.RatingScale{Min: 0, Max: 1}
+
+	assert.InDelta(t, -1.0, ratingSentiment(1, fiveStar), 1e-9)
+	assert.InDelta(t, 0.0, ratingSentiment(3, fiveStar), 1e-9)
+	assert.InDelta(t, 0.5, ratingSentiment(4, fiveStar), 1e-9)
+	assert.InDelta(t, 1.0, ratingSentiment(10, tenPoint), 1e-9)
+	assert.InDelta(t, 0.0, ratingSentiment(5.5, tenPoint), 1e-9)
+	assert.InDelta(t, -1.0, ratingSentiment(0, thumbs), 1e-9)
+	assert.InDelta(t, 1.0, ratingSentiment(1, thumbs), 1e-9)
+	assert.InDelta(t, 1.0, ratingSentiment(7, fiveStar), 1e-9, "out of range ratings are clamped")
+}
+
+func TestRatingScalePrefersSourceScale(t *testing.T) {
+	cfg := config.AnalyzerConfig{
+		RatingScale:        config.RatingScale{Min: 0, Max: 1},
+		SourceRatingScales: map[string]config.RatingScale{"steam": {Min: 1, Max: 10}},
+	}
+
+	assert.Equal(t, config.RatingScale{Min: 1, Max: 10}, ratingScale(cfg, "steam"))
+	assert.Equal(t, config.RatingScale{Min: 0, Max: 1}, ratingScale(cfg, "g2"))
+	assert.Equal(t, defaultRatingScale, ratingScale(config.AnalyzerConfig{}, "g2"))
+}
+
+func TestBlendRatingDefaultsToFiveStarsAtFortyPercent(t *testing.T) {
+	rating := 1.0
+	review := models.Review{Rating: &rating}
+
+	assert.InDelta(t, 0.5*0.6-1*0.4, blendRating(config.AnalyzerConfig{}, review, 0.5), 1e-9)
+	assert.Equal(t, 0.5, blendRating(config.AnalyzerConfig{}, models.Review{}, 0.5), "unrated reviews keep the lexical score")
+}
+
+func TestAnalyzeLocalBlendsTenPointRatingWithCustomWeight(t *testing.T) {
+	weight := 0.75
+	a := New(config.AnalyzerConfig{
+		Mode:               "local",
+		RatingWeight:       &weight,
+		SourceRatingScales: map[string]config.RatingScale{"steam": {Min: 1, Max: 10}},
+	})
+
+	content := "The upgrade was terrible and the console is slow"
+	unrated, err := a.analyzeLocal(models.Review{ID: "unrated", Source: "steam", Content: content})
+	require.NoError(t, err)
+
+	rating := 8.2 // 0.6 on the -1 to 1 range
+	rated, err := a.analyzeLocal(models.Review{ID: "rated", Source: "steam", Content: content, Rating: &rating})
+	require.NoError(t, err)
+
+	assert.InDelta(t, unrated.SentimentScore*0.25+0.6*0.75, rated.SentimentScore, 1e-9)
+}
@@ -0,0 +1,233 @@
+package analyzer
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+)
+
+// defaultPositiveWords are the positive sentiment words used when none are configured
+var defaultPositiveWords = []string{
+	"good", "great", "awesome", "excellent", "amazing", "love", "best", "fantastic",
+	"perfect", "happy", "pleased", "satisfied", "wonderful", "helpful", "thank", "thanks",
+	"reliable", "secure", "efficient", "intuitive",
+}
+
+// defaultNegativeWords are the negative sentiment words used when none are configured
+var defaultNegativeWords = []string{
+	"bad", "poor", "terrible", "awful", "horrible", "worst", "hate", "disappointed",
+	"frustrating", "useless", "broken", "annoying", "slow", "expensive", "waste",
+	"difficult", "confusing", "crash", "bug", "error", "problem", "issue", "fail", "fails",
+	"failed", "failing", "failure", "cannot", "can't", "won't", "doesn't", "didn't",
+	"insecure", "vulnerability", "breach", "outage", "downtime",
+}
+
+// defaultPositiveEmoji and defaultNegativeEmoji are the emoji that carry
+// sentiment, matched after skin tones and presentation selectors are removed
+var (
+	defaultPositiveEmoji = []rune{
+		'😀', '😃', '😄', '😁', '😊', '🙂', '😍', '🥰', '😘', '🤩', '😎', '🥳',
+		'👍', '👏', '🙌', '💪', '🎉', '❤', '💯', '✅', '⭐', '🚀',
+	}
+	defaultNegativeEmoji = []rune{
+		'😡', '😠', '🤬', '😤', '😞', '😢', '😭', '😩', '😫', '😒', '🙄', '😖',
+		'😣', '😕', '🙁', '☹', '😱', '🤦', '🤢', '🤮', '👎', '💩', '💔', '❌',
+	}
+)
+
+// wordSuffixes are the inflections stripped from a word that is not itself in
+// the lexicon, so "crashes" counts as "crash" while "failover" is not "fail"
+var wordSuffixes = []string{"s", "es", "d", "ed", "ing"}
+
+// shoutingPattern matches runs of capitals, which suggest stronger sentiment
+var shoutingPattern = regexp.MustCompile(`[A-Z]{3,}`)
+
+// sentimentTokenPattern matches the words of a review, keeping contractions whole
+var sentimentTokenPattern = regexp.MustCompile(`[\p{L}\p{N}]+(?:'[\p{L}]+)*`)
+
+// sentimentLexicon scores words and phrases, positive for positive sentiment
+// and negative for negative sentiment
+type sentimentLexicon struct {
+	words   map[string]float64
+	phrases []sentimentPhrase
+	emoji   map[rune]float64
+}
+
+// sentimentPhrase is a multi-word lexicon entry
+type sentimentPhrase struct {
+	words []string
+	score float64
+}
+
+// buildSentimentLexicon builds the lexicon from the configured word lists,
+// falling back to the defaults for a list left empty, plus the sentiment
+// emoji. SentimentWeights scale how strongly a word or emoji counts;
+// unweighted ones count once.
+func buildSentimentLexicon(cfg config.AnalyzerConfig) sentimentLexicon {
+	positive, negative := cfg.PositiveWords, cfg.NegativeWords
+	if len(positive) == 0 {
+		positive = defaultPositiveWords
+	}
+	if len(negative) == 0 {
+		negative = defaultNegativeWords
+	}
+
+	weights := make(map[string]float64, len(cfg.SentimentWeights))
+	for word, weight := range cfg.SentimentWeights {
+		weights[normalizeSentimentText(word)] = weight
+	}
+
+	lexicon := sentimentLexicon{words: make(map[string]float64), emoji: make(map[rune]float64)}
+	add := func(entry string, sign float64) {
+		entry = normalizeSentimentText(entry)
+		score := sign
+		if weight, ok := weights[entry]; ok {
+			score *= weight
+		}
+
+		words := strings.Fields(entry)
+		switch {
+		case len(words) == 1:
+			lexicon.words[entry] = score
+		case len(words) > 1:
+			lexicon.phrases = append(lexicon.phrases, sentimentPhrase{words: words, score: score})
+		}
+	}
+	for _, word := range positive {
+		add(word, 1)
+	}
+	for _, word := range negative {
+		add(word, -1)
+	}
+
+	addEmoji := func(emoji rune, sign float64) {
+		score := sign
+		if weight, ok := weights[string(emoji)]; ok {
+			score *= weight
+		}
+		lexicon.emoji[emoji] = score
+	}
+	for _, emoji := range defaultPositiveEmoji {
+		addEmoji(emoji, 1)
+	}
+	for _, emoji := range defaultNegativeEmoji {
+		addEmoji(emoji, -1)
+	}
+	return lexicon
+}
+
+// sentiment returns the sentiment score of content between -1 (very negative)
+// and 1 (very positive). Many exclamation marks or capitalized words
+// strengthen the score by a fifth.
+func (l sentimentLexicon) sentiment(content string) float64 {
+	positive, negative := l.score(content)
+
+	var score float64
+	if total := positive + negative; total > 0 {
+		score = (positive - negative) / total
+	}
+
+	if strings.Count(content, "!") > 2 || len(shoutingPattern.FindAllString(content, 3)) > 2 {
+		score = math.Max(-1, math.Min(1, score*1.2))
+	}
+	return score
+}
+
+// normalizeSentimentText lower-cases text and straightens curly apostrophes
+func normalizeSentimentText(text string) string {
+	return strings.ReplaceAll(strings.ToLower(text), "’", "'")
+}
+
+// score returns the total weight of the positive and of the negative words,
+// phrases and emoji in content. Words match whole, allowing for plural and
+// tense endings, so a word never matches inside a longer one.
+func (l sentimentLexicon) score(content string) (positive, negative float64) {
+	tokens := sentimentTokenPattern.FindAllString(normalizeSentimentText(content), -1)
+
+	tally := func(score float64) {
+		if score > 0 {
+			positive += score
+		} else {
+			negative -= score
+		}
+	}
+
+	for _, token := range tokens {
+		if score, ok := l.lookup(token); ok {
+			tally(score)
+		}
+	}
+
+	for _, phrase := range l.phrases {
+		for i := 0; i+len(phrase.words) <= len(tokens); i++ {
+			if matchesPhrase(tokens[i:], phrase.words) {
+				tally(phrase.score)
+			}
+		}
+	}
+
+	for _, emoji := range baseEmoji(content) {
+		if score, ok := l.emoji[emoji]; ok {
+			tally(score)
+		}
+	}
+	return positive, negative
+}
+
+// Emoji code points that modify the emoji before them
+const (
+	zeroWidthJoiner = '\u200d'
+	textSelector    = '\ufe0e'
+	emojiSelector   = '\ufe0f'
+	skinToneFirst   = '\U0001F3FB'
+	skinToneLast    = '\U0001F3FF'
+)
+
+// baseEmoji returns the runes of content with emoji modifiers removed. Skin
+// tones and presentation selectors are dropped, and a zero-width joiner
+// sequence such as a gendered facepalm keeps only its first emoji, so each
+// emoji counts once however it is written.
+func baseEmoji(content string) []rune {
+	var runes []rune
+	joined := false
+	for _, r := range content {
+		switch {
+		case r == zeroWidthJoiner:
+			joined = true
+		case r == textSelector, r == emojiSelector, r >= skinToneFirst && r <= skinToneLast:
+		case joined:
+			// The emoji joined onto the previous one is part of it
+			joined = false
+		default:
+			runes = append(runes, r)
+		}
+	}
+	return runes
+}
+
+// lookup returns the score of token, or of the word it inflects
+func (l sentimentLexicon) lookup(token string) (float64, bool) {
+	if score, ok := l.words[token]; ok {
+		return score, true
+	}
+	for _, suffix := range wordSuffixes {
+		if stem := strings.TrimSuffix(token, suffix); stem != token && stem != "" {
+			if score, ok := l.words[stem]; ok {
+				return score, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// matchesPhrase reports whether tokens start with the words of a phrase
+func matchesPhrase(tokens, words []string) bool {
+	for i, word := range words {
+		if tokens[i] != word {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSentimentLexiconMatchesWholeWords(t *testing.T) {
+	lexicon := buildSentimentLexicon(config.AnalyzerConfig{})
+
+	tests := []struct {
+		content  string
+		positive float64
+		negative float64
+	}{
+		{content: "Grid failover worked", positive: 0, negative: 0},
+		{content: "The upgrade failed", positive: 0, negative: 1},
+		{content: "It fails and crashes with errors", positive: 0, negative: 3},
+		{content: "Debugging the issues", positive: 0, negative: 1},
+		{content: "Thankfully the goodness shows", positive: 0, negative: 0},
+		{content: "It doesn’t work, support was GREAT", positive: 1, negative: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			positive, negative := lexicon.score(tt.content)
+			assert.Equal(t, tt.positive, positive)
+			assert.Equal(t, tt.negative, negative)
+		})
+	}
+}
+
+func TestSentimentLexiconUsesConfiguredWordsAndWeights(t *testing.T) {
+	lexicon := buildSentimentLexicon(config.AnalyzerConfig{
+		NegativeWords:    []string{"outage", "not working"},
+		SentimentWeights: map[string]float64{"Outage": 3, "great": 0},
+	})
+
+	positive, negative := lexicon.score("Great, another outage and DNS is not working. The UI is bad.")
+
+	assert.Equal(t, 0.0, positive, "a weight of 0 ignores a word")
+	assert.Equal(t, 4.0, negative, "configured lists replace the defaults, so 'bad' no longer counts")
+}
+
+func TestLocalAnalysisDoesNotCountFailoverAsNegative(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.3})
+
+	failover, err := a.Analyze(context.Background(), models.Review{ID: "failover", Content: "NIOS failover to the passive node took seconds"})
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, failover.SentimentScore)
+	assert.False(t, failover.IsNegative)
+
+	failed, err := a.Analyze(context.Background(), models.Review{ID: "failed", Content: "NIOS failover failed"})
+	assert.NoError(t, err)
+	assert.Equal(t, -1.0, failed.SentimentScore)
+	assert.True(t, failed.IsNegative)
+}
+
+func TestUpdateConfigRebuildsSentimentLexicon(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	review := models.Review{ID: "latency", Content: "Query latency doubled after the upgrade"}
+
+	result, _ := a.Analyze(context.Background(), review)
+	assert.Equal(t, 0.0, result.SentimentScore)
+
+	a.UpdateConfig(config.AnalyzerConfig{Mode: "local", NegativeWords: []string{"latency"}})
+	result, _ = a.Analyze(context.Background(), review)
+	assert.Equal(t, -1.0, result.SentimentScore)
+}
+
+func TestSentimentLexiconCountsWholeWordsOnce(t *testing.T) {
+	lexicon := buildSentimentLexicon(config.AnalyzerConfig{NegativeWords: []string{"not", "issue", "issues"}})
+
+	tests := []struct {
+		content  string
+		negative float64
+	}{
+		{content: "A notable release", negative: 0},
+		{content: "Nothing to report", negative: 0},
+		{content: "It does not start", negative: 1},
+		{content: "Two issues remain", negative: 1},
+		{content: "One issue, then more issues", negative: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			_, negative := lexicon.score(tt.content)
+			assert.Equal(t, tt.negative, negative)
+		})
+	}
+}
+
+func TestSentimentLexiconScoresEmoji(t *testing.T) {
+	lexicon := buildSentimentLexicon(config.AnalyzerConfig{})
+
+	tests := []struct {
+		content  string
+		positive float64
+		negative float64
+	}{
+		{content: "New DNS dashboard 🎉🎉 😍", positive: 3, negative: 0},
+		{content: "Upgrade again 😡👎", positive: 0, negative: 2},
+		{content: "Thumbs up 👍🏽 and heart ❤️", positive: 2, negative: 0},
+		{content: "Support response 🤦🏻‍♂️", positive: 0, negative: 1},
+		{content: "On fire ❤️‍🔥", positive: 1, negative: 0},
+		{content: "Developer 👨‍💻 on call", positive: 0, negative: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.content, func(t *testing.T) {
+			positive, negative := lexicon.score(tt.content)
+			assert.Equal(t, tt.positive, positive)
+			assert.Equal(t, tt.negative, negative)
+		})
+	}
+}
+
+func TestSentimentLexiconWeighsEmoji(t *testing.T) {
+	lexicon := buildSentimentLexicon(config.AnalyzerConfig{SentimentWeights: map[string]float64{"💩": 3, "⭐": 0}})
+
+	positive, negative := lexicon.score("⭐⭐⭐⭐⭐ 💩")
+
+	assert.Equal(t, 0.0, positive)
+	assert.Equal(t, 3.0, negative)
+}
+
+func TestLocalAnalysisFollowsEmojiSentiment(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.3})
+
+	angry, err := a.Analyze(context.Background(), models.Review{ID: "angry", Content: "NIOS upgrade day 😡😡 👎🏼 😭"})
+	assert.NoError(t, err)
+	assert.Less(t, angry.SentimentScore, 0.0)
+	assert.True(t, angry.IsNegative)
+
+	happy, err := a.Analyze(context.Background(), models.Review{ID: "happy", Content: "BloxOne rollout done 🎉🥳 🙌🏾"})
+	assert.NoError(t, err)
+	assert.Greater(t, happy.SentimentScore, 0.0)
+	assert.False(t, happy.IsNegative)
+
+	// Emoji add to the words around them
+	mixed, err := a.Analyze(context.Background(), models.Review{ID: "mixed", Content: "Support was great 👍 but the upgrade failed 😡😡😡"})
+	assert.NoError(t, err)
+	assert.Less(t, mixed.SentimentScore, 0.0)
+}
+
+func TestQuickSentimentMatchesLocalAnalysis(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.3})
+
+	for _, content := range []string{
+		"Support was great but the upgrade failed",
+		"NIOS failover failed AGAIN!!! TERRIBLE SUPPORT",
+		"BloxOne rollout done 🎉🥳",
+		"DNS records migrated last week",
+	} {
+		result, err := a.Analyze(context.Background(), models.Review{ID: content, Content: content})
+		assert.NoError(t, err)
+		assert.Equal(t, result.SentimentScore, a.QuickSentiment(content), content)
+	}
+}
+
+func TestQuickSentimentFollowsConfigUpdates(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	assert.Equal(t, 0.0, a.QuickSentiment("Query latency doubled after the upgrade"))
+
+	a.UpdateConfig(config.AnalyzerConfig{Mode: "local", NegativeWords: []string{"latency"}})
+	assert.Equal(t, -1.0, a.QuickSentiment("Query latency doubled after the upgrade"))
+}
+
+const benchmarkReview = "The NIOS upgrade was smooth and support was helpful, " +
+	"but DHCP failover failed twice and the Grid Manager UI is slow!!! 😡"
+
+func BenchmarkQuickSentiment(b *testing.B) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a.QuickSentiment(benchmarkReview)
+	}
+}
+
+func BenchmarkAnalyzeLocal(b *testing.B) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	review := models.Review{ID: "benchmark", Content: benchmarkReview}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		a.analyzeLocal(review)
+	}
+}
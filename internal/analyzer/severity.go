@@ -0,0 +1,76 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// urgentKeywords mark reviews reporting problems that need prompt attention
+var urgentKeywords = []string{
+	"urgent", "critical", "immediately", "security", "breach",
+	"broken", "unusable", "crash", "down", "outage", "emergency",
+	"compromised", "vulnerability", "attacked", "hacked",
+}
+
+// criticalProducts are products whose negative reviews always need follow-up
+var criticalProducts = []string{"dns", "dhcp", "security", "threat"}
+
+// Sentiment scores at or below which a review is at least medium or high severity
+const (
+	mediumSeverityScore = -0.3
+	highSeverityScore   = -0.7
+)
+
+// assessSeverity decides whether a review needs action and how severe it is,
+// using the same signals as the enricher: a low star rating, urgency keywords,
+// and negative sentiment about a critical product. Results below the relevance
+// threshold are dropped one severity tier, since the analysis is less certain,
+// and so are reviews with a vendor reply when discountReplies is set, since
+// someone is already responding to them.
+func assessSeverity(review models.Review, result models.AnalysisResult, discountReplies bool) (bool, string) {
+	text := strings.ToLower(review.Title + " " + review.Content)
+
+	lowRating := review.Rating != nil && *review.Rating <= 2
+	urgent := containsAny(text, urgentKeywords)
+	critical := result.IsNegative && containsAny(text, criticalProducts)
+	needsAction := lowRating || urgent || critical
+
+	severity := models.SeverityLow
+	switch {
+	case result.SentimentScore <= highSeverityScore || (urgent && result.IsNegative):
+		severity = models.SeverityHigh
+	case result.SentimentScore <= mediumSeverityScore || needsAction:
+		severity = models.SeverityMedium
+	}
+
+	if !result.IsRelevant {
+		severity = lowerSeverity(severity)
+	}
+	if discountReplies && review.HasVendorReply() {
+		severity = lowerSeverity(severity)
+	}
+
+	return needsAction && severity != models.SeverityLow, severity
+}
+
+// lowerSeverity returns the severity one tier below severity
+func lowerSeverity(severity string) string {
+	switch severity {
+	case models.SeverityHigh:
+		return models.SeverityMedium
+	case models.SeverityMedium:
+		return models.SeverityLow
+	}
+	return severity
+}
+
+// containsAny reports whether text contains any of terms
+func containsAny(text string, terms []string) bool {
+	for _, term := range terms {
+		if strings.Contains(text, term) {
+			return true
+		}
+	}
+	return false
+}
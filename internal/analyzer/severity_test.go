@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssessSeverityTiers(t *testing.T) {
+	oneStar, fourStars := 1.0, 4.0
+
+	tests := []struct {
+		name        string
+		review      models.Review
+		result      models.AnalysisResult
+		needsAction bool
+		severity    string
+	}{
+		{
+			name:     "positive review is low",
+			review:   models.Review{Content: "Great product", Rating: &fourStars},
+			result:   models.AnalysisResult{SentimentScore: 0.8, IsRelevant: true},
+			severity: models.SeverityLow,
+		},
+		{
+			name:     "mildly negative review is medium without action",
+			review:   models.Review{Content: "The UI is confusing"},
+			result:   models.AnalysisResult{SentimentScore: -0.4, IsNegative: true, IsRelevant: true},
+			severity: models.SeverityMedium,
+		},
+		{
+			name:        "negative review of a critical product needs action",
+			review:      models.Review{Content: "DHCP leases keep disappearing"},
+			result:      models.AnalysisResult{SentimentScore: -0.2, IsNegative: true, IsRelevant: true},
+			needsAction: true,
+			severity:    models.SeverityMedium,
+		},
+		{
+			name:        "low rating needs action",
+			review:      models.Review{Content: "Not what we expected", Rating: &oneStar},
+			result:      models.AnalysisResult{SentimentScore: 0, IsRelevant: true},
+			needsAction: true,
+			severity:    models.SeverityMedium,
+		},
+		{
+			name:        "very negative review is high",
+			review:      models.Review{Content: "Terrible support experience"},
+			result:      models.AnalysisResult{SentimentScore: -0.9, IsNegative: true, IsRelevant: true},
+			needsAction: false,
+			severity:    models.SeverityHigh,
+		},
+		{
+			name:        "urgent negative review is high",
+			review:      models.Review{Title: "Outage", Content: "Resolvers are down"},
+			result:      models.AnalysisResult{SentimentScore: -0.2, IsNegative: true, IsRelevant: true},
+			needsAction: true,
+			severity:    models.SeverityHigh,
+		},
+		{
+			name:        "low confidence drops a tier",
+			review:      models.Review{Title: "Outage", Content: "Resolvers are down"},
+			result:      models.AnalysisResult{SentimentScore: -0.2, IsNegative: true},
+			needsAction: true,
+			severity:    models.SeverityMedium,
+		},
+		{
+			name:     "low confidence medium becomes low and needs no action",
+			review:   models.Review{Content: "Not what we expected", Rating: &oneStar},
+			result:   models.AnalysisResult{SentimentScore: 0},
+			severity: models.SeverityLow,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			needsAction, severity := assessSeverity(tt.review, tt.result, false)
+			assert.Equal(t, tt.needsAction, needsAction)
+			assert.Equal(t, tt.severity, severity)
+		})
+	}
+}
+
+func TestAnalyzeSetsSeverity(t *testing.T) {
+	a := New(config.AnalyzerConfig{
+		Mode:               "local",
+		Keywords:           []string{"dns"},
+		NegativeThreshold:  -0.2,
+		RelevanceThreshold: 0.3,
+	})
+
+	result, err := a.Analyze(context.Background(), models.Review{
+		ID:      "review-1",
+		Content: "Our DNS is broken after the upgrade, this is a critical outage",
+	})
+
+	assert.NoError(t, err)
+	assert.True(t, result.NeedsAction)
+	assert.Equal(t, models.SeverityHigh, result.Severity)
+}
+
+func TestAssessSeverityDiscountsVendorReplies(t *testing.T) {
+	review := models.Review{Content: "DNS is down again, this outage is unacceptable"}
+	replied := review
+	replied.Replies = []models.Reply{
+		{Author: "customer", Content: "Same here"},
+		{Author: "Infoblox Support", Content: "We are investigating and will follow up", IsVendor: true},
+	}
+	result := models.AnalysisResult{SentimentScore: -0.5, IsNegative: true, IsRelevant: true}
+
+	needsAction, severity := assessSeverity(review, result, true)
+	assert.True(t, needsAction)
+	assert.Equal(t, models.SeverityHigh, severity)
+
+	needsAction, severity = assessSeverity(replied, result, true)
+	assert.True(t, needsAction)
+	assert.Equal(t, models.SeverityMedium, severity, "a vendor reply lowers severity one tier")
+
+	_, severity = assessSeverity(replied, result, false)
+	assert.Equal(t, models.SeverityHigh, severity, "replies are ignored unless enabled")
+
+	// Replies from other users do not count as a vendor response
+	_, severity = assessSeverity(models.Review{Content: review.Content, Replies: replied.Replies[:1]}, result, true)
+	assert.Equal(t, models.SeverityHigh, severity)
+}
+
+func TestAnalyzeDiscountsVendorReplies(t *testing.T) {
+	a := New(config.AnalyzerConfig{
+		Mode:                  "local",
+		Keywords:              []string{"dns"},
+		NegativeThreshold:     -0.2,
+		RelevanceThreshold:    0.3,
+		DiscountVendorReplies: true,
+	})
+	review := models.Review{ID: "review-1", Content: "Our DNS is broken after the upgrade, this is a critical outage"}
+	replied := review
+	replied.ID = "review-2"
+	replied.Replies = []models.Reply{{Content: "Sorry about this, a fix is rolling out", IsVendor: true}}
+
+	result, err := a.Analyze(context.Background(), review)
+	assert.NoError(t, err)
+	repliedResult, err := a.Analyze(context.Background(), replied)
+	assert.NoError(t, err)
+
+	assert.Equal(t, models.SeverityHigh, result.Severity)
+	assert.Equal(t, models.SeverityMedium, repliedResult.Severity)
+}
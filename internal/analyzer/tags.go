@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// Tag families that can be enabled via AnalyzerConfig.AutoTags
+const (
+	TagFamilySentiment   = "sentiment"
+	TagFamilyIntent      = "intent"
+	TagFamilyProducts    = "products"
+	TagFamilyNeedsAction = "needs-action"
+)
+
+// AutoTag appends tags derived from the analysis result to the review, limited
+// to the configured tag families. Existing tags are kept and tags already
+// present (case-insensitively) are not added again.
+func (a *Analyzer) AutoTag(review *models.Review, result models.AnalysisResult) {
+	families := a.Config().AutoTags
+	if len(families) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool, len(review.Tags))
+	for _, tag := range review.Tags {
+		seen[strings.ToLower(tag)] = true
+	}
+	add := func(tag string) {
+		if !seen[strings.ToLower(tag)] {
+			seen[strings.ToLower(tag)] = true
+			review.Tags = append(review.Tags, tag)
+		}
+	}
+
+	for _, family := range families {
+		switch family {
+		case TagFamilySentiment:
+			add("sentiment:" + sentimentLabel(result))
+		case TagFamilyIntent:
+			if result.IntentCategory != "" {
+				add("intent:" + result.IntentCategory)
+			}
+		case TagFamilyProducts:
+			for _, entity := range result.Entities {
+				if entity.Type == "PRODUCT" {
+					add("product:" + strings.ToLower(entity.Text))
+				}
+			}
+		case TagFamilyNeedsAction:
+			if result.IsNegative && result.IsRelevant {
+				add(TagFamilyNeedsAction)
+			}
+		}
+	}
+}
+
+// sentimentLabel converts an analysis result into a coarse sentiment label
+func sentimentLabel(result models.AnalysisResult) string {
+	switch {
+	case result.IsNegative:
+		return "negative"
+	case result.SentimentScore > 0:
+		return "positive"
+	default:
+		return "neutral"
+	}
+}
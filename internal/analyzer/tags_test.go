@@ -0,0 +1,72 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// negativeProductResult returns an analysis of a negative, relevant NIOS review
+func negativeProductResult() models.AnalysisResult {
+	return models.AnalysisResult{
+		SentimentScore: -0.6,
+		IsNegative:     true,
+		IsRelevant:     true,
+		IntentCategory: "bug_report",
+		Entities: []models.Entity{
+			{Text: "nios", Type: "PRODUCT"},
+			{Text: "grid", Type: "FEATURE"},
+		},
+	}
+}
+
+func TestAutoTagAppendsDerivedTagsAndKeepsExisting(t *testing.T) {
+	a := New(config.AnalyzerConfig{
+		Mode:     "local",
+		AutoTags: []string{"sentiment", "intent", "products", "needs-action"},
+	})
+	review := models.Review{ID: "r1", Tags: []string{"DDI", "Product:NIOS", "needs-action"}}
+
+	a.AutoTag(&review, negativeProductResult())
+
+	assert.Equal(t, []string{
+		"DDI", "Product:NIOS", "needs-action",
+		"sentiment:negative", "intent:bug_report",
+	}, review.Tags, "existing tags are preserved and matching derived tags are not duplicated")
+
+	// Tagging again is a no-op
+	a.AutoTag(&review, negativeProductResult())
+	assert.Len(t, review.Tags, 5)
+}
+
+func TestAutoTagOnlyAddsConfiguredFamilies(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local", AutoTags: []string{"sentiment", "products"}})
+	review := models.Review{ID: "r1"}
+
+	a.AutoTag(&review, negativeProductResult())
+
+	assert.Equal(t, []string{"sentiment:negative", "product:nios"}, review.Tags)
+}
+
+func TestAutoTagDisabledByDefault(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local"})
+	review := models.Review{ID: "r1", Tags: []string{"DDI"}}
+
+	a.AutoTag(&review, negativeProductResult())
+
+	assert.Equal(t, []string{"DDI"}, review.Tags)
+}
+
+func TestAutoTagSentimentLabels(t *testing.T) {
+	a := New(config.AnalyzerConfig{Mode: "local", AutoTags: []string{"sentiment", "needs-action"}})
+
+	positive := models.Review{ID: "r1"}
+	a.AutoTag(&positive, models.AnalysisResult{SentimentScore: 0.5, IsRelevant: true})
+	assert.Equal(t, []string{"sentiment:positive"}, positive.Tags)
+
+	neutral := models.Review{ID: "r2"}
+	a.AutoTag(&neutral, models.AnalysisResult{SentimentScore: -0.1})
+	assert.Equal(t, []string{"sentiment:neutral"}, neutral.Tags)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+)
+
+// API key scopes, as listed in config.APIScopes
+const (
+	ScopeAll         = "*"
+	ScopeReviewsRead = "reviews:read"
+	ScopeStatsRead   = "stats:read"
+	ScopeScrapingRun = "scraping:run"
+	ScopeAnalyzeRun  = "analyze:run"
+	ScopeConfigRead  = "config:read"
+	ScopeConfigWrite = "config:write"
+)
+
+// legacyKeyName identifies requests authenticated with the single AuthToken
+const legacyKeyName = "authToken"
+
+// Principal is the API key a request was authenticated with
+type Principal struct {
+	Name   string
+	Scopes []string
+}
+
+// HasScope reports whether the key grants scope
+func (p Principal) HasScope(scope string) bool {
+	for _, granted := range p.Scopes {
+		if granted == ScopeAll || granted == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// principalKey is the request context key for the authenticated Principal
+type principalKey struct{}
+
+// PrincipalFromContext returns the API key that authenticated the request
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(Principal)
+	return p, ok
+}
+
+// credential is an accepted token and the principal it authenticates
+type credential struct {
+	token     []byte
+	principal Principal
+}
+
+// buildCredentials lists the tokens accepted by cfg. The legacy AuthToken
+// keeps working and is granted every scope.
+func buildCredentials(cfg config.APIConfig) []credential {
+	var credentials []credential
+	if cfg.AuthToken != "" {
+		credentials = append(credentials, credential{
+			token:     []byte(cfg.AuthToken),
+			principal: Principal{Name: legacyKeyName, Scopes: []string{ScopeAll}},
+		})
+	}
+	for _, apiKey := range cfg.APIKeys {
+		credentials = append(credentials, credential{
+			token:     []byte(apiKey.Key),
+			principal: Principal{Name: apiKey.Name, Scopes: apiKey.Scopes},
+		})
+	}
+	return credentials
+}
+
+// authenticate returns the principal for token. Every credential is compared
+// in constant time so response timing does not reveal partial matches.
+func (s *Server) authenticate(token string) (Principal, bool) {
+	s.configMutex.RLock()
+	defer s.configMutex.RUnlock()
+
+	var (
+		matched Principal
+		found   bool
+	)
+	for _, c := range s.credentials {
+		if subtle.ConstantTimeCompare([]byte(token), c.token) == 1 && !found {
+			matched, found = c.principal, true
+		}
+	}
+	return matched, found
+}
+
+// requestToken returns the API token sent in the Authorization header, or in
+// the token query parameter for dashboard iframe embedding
+func requestToken(r *http.Request) string {
+	if token := r.Header.Get("Authorization"); token != "" {
+		return strings.TrimPrefix(token, "Bearer ")
+	}
+	return r.URL.Query().Get("token")
+}
+
+// isAuthenticated reports whether the request carried a valid API key. Public
+// endpoints use it to decide whether to include error details.
+func isAuthenticated(r *http.Request) bool {
+	_, ok := PrincipalFromContext(r.Context())
+	return ok
+}
+
+// requireScope rejects requests whose API key does not grant scope
+func (s *Server) requireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := PrincipalFromContext(r.Context())
+			if !ok || !principal.HasScope(scope) {
+				s.respondError(w, r, http.StatusForbidden, fmt.Sprintf("API key lacks the %q scope", scope))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newScopedTestServer returns a server with a read-only key and a config-writing key
+func newScopedTestServer() *Server {
+	return newTestServer(&config.Config{
+		Analyzer: config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.3, RelevanceThreshold: 0.5},
+		API: config.APIConfig{APIKeys: []config.APIKeyConfig{
+			{Name: "dashboard", Key: "read-key", Scopes: []string{ScopeReviewsRead, ScopeStatsRead}},
+			{Name: "ops", Key: "ops-key", Scopes: []string{ScopeConfigRead, ScopeConfigWrite}},
+		}},
+	})
+}
+
+// doKeyRequest sends a request authenticated with key
+func doKeyRequest(s *Server, key, method, path, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+key)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestScopedKeyCanUseGrantedEndpoints(t *testing.T) {
+	s := newScopedTestServer()
+
+	assert.Equal(t, http.StatusOK, doKeyRequest(s, "read-key", http.MethodGet, "/api/v1/reviews/", "").Code)
+	assert.Equal(t, http.StatusOK, doKeyRequest(s, "read-key", http.MethodGet, "/api/v1/scraping/stats", "").Code)
+
+	rec := doKeyRequest(s, "ops-key", http.MethodPut, "/api/v1/config/analyzer", `{"negativeThreshold": -0.1}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, -0.1, s.analyzer.Config().NegativeThreshold)
+}
+
+func TestScopedKeyIsForbiddenFromOtherEndpoints(t *testing.T) {
+	s := newScopedTestServer()
+
+	rec := doKeyRequest(s, "read-key", http.MethodPost, "/api/v1/scraping/run", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, `API key lacks the "scraping:run" scope`, decodeResponse(t, rec, nil).Error)
+
+	rec = doKeyRequest(s, "read-key", http.MethodPut, "/api/v1/config/analyzer", `{"negativeThreshold": -0.1}`)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.Equal(t, -0.3, s.analyzer.Config().NegativeThreshold, "a forbidden update must not be applied")
+
+	assert.Equal(t, http.StatusForbidden, doKeyRequest(s, "ops-key", http.MethodGet, "/api/v1/reviews/", "").Code)
+}
+
+func TestLegacyAuthTokenGrantsAllScopes(t *testing.T) {
+	s := newScopedTestServer()
+
+	assert.Equal(t, http.StatusOK, doKeyRequest(s, testAuthToken, http.MethodGet, "/api/v1/reviews/", "").Code)
+	assert.Equal(t, http.StatusOK, doKeyRequest(s, testAuthToken, http.MethodGet, "/api/v1/config/analyzer", "").Code)
+}
+
+func TestUnknownKeyIsUnauthorized(t *testing.T) {
+	s := newScopedTestServer()
+
+	assert.Equal(t, http.StatusUnauthorized, doKeyRequest(s, "read-ke", http.MethodGet, "/api/v1/reviews/", "").Code)
+}
+
+func TestReloadRevokesAPIKeys(t *testing.T) {
+	s := newScopedTestServer()
+
+	next := *s.appConfig
+	next.API.APIKeys = []config.APIKeyConfig{{Name: "ops", Key: "ops-key", Scopes: []string{ScopeConfigRead}}}
+	assert.NoError(t, s.Reload(&next))
+
+	assert.Equal(t, http.StatusUnauthorized, doKeyRequest(s, "read-key", http.MethodGet, "/api/v1/reviews/", "").Code)
+	assert.Equal(t, http.StatusForbidden, doKeyRequest(s, "ops-key", http.MethodPut, "/api/v1/config/analyzer", `{}`).Code)
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+const (
+	// batchAnalyzeWorkers bounds how many texts of a batch are analyzed at once
+	batchAnalyzeWorkers = 8
+
+	// maxBatchAnalyzeItems caps the size of a single batch request
+	maxBatchAnalyzeItems = 1000
+)
+
+// BatchAnalyzeResult is the outcome for one item of a batch, at the same
+// index as the request item. Exactly one of Result and Error is set.
+type BatchAnalyzeResult struct {
+	Result *models.AnalysisResult `json:"result,omitempty"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// handleAnalyzeBatch analyzes an array of texts concurrently and returns one
+// result per item in request order. A failing item does not fail the batch.
+func (s *Server) handleAnalyzeBatch(w http.ResponseWriter, r *http.Request) {
+	var reqs []AnalyzeRequest
+	if err := json.NewDecoder(r.Body).Decode(&reqs); err != nil {
+		s.respondError(w, r, http.StatusBadRequest, "Invalid request format: expected an array of {text, source, author}")
+		return
+	}
+	if len(reqs) == 0 {
+		s.respondError(w, r, http.StatusBadRequest, "At least one item is required")
+		return
+	}
+	if len(reqs) > maxBatchAnalyzeItems {
+		s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("A batch may contain at most %d items", maxBatchAnalyzeItems))
+		return
+	}
+
+	results := s.analyzeBatch(r.Context(), reqs)
+	if err := r.Context().Err(); err != nil {
+		s.respondError(w, r, http.StatusServiceUnavailable, fmt.Sprintf("Batch analysis interrupted: %v", err))
+		return
+	}
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    results,
+	})
+}
+
+// analyzeBatch runs the requests through a bounded pool of workers. Items not
+// yet started when ctx is done are reported as failed with the context error.
+func (s *Server) analyzeBatch(ctx context.Context, reqs []AnalyzeRequest) []BatchAnalyzeResult {
+	results := make([]BatchAnalyzeResult, len(reqs))
+	batchID := time.Now().UnixNano()
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	workers := batchAnalyzeWorkers
+	if len(reqs) < workers {
+		workers = len(reqs)
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i] = s.analyzeBatchItem(ctx, reqs[i], fmt.Sprintf("batch-%d-%d", batchID, i))
+			}
+		}()
+	}
+
+	for i := range reqs {
+		if ctx.Err() != nil {
+			results[i] = BatchAnalyzeResult{Error: ctx.Err().Error()}
+			continue
+		}
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
+
+// analyzeBatchItem analyzes a single batch item
+func (s *Server) analyzeBatchItem(ctx context.Context, req AnalyzeRequest, id string) BatchAnalyzeResult {
+	if req.Text == "" {
+		return BatchAnalyzeResult{Error: "Text is required"}
+	}
+	if err := ctx.Err(); err != nil {
+		return BatchAnalyzeResult{Error: err.Error()}
+	}
+
+	result, err := s.analyzer.Analyze(ctx, req.review(id))
+	if err != nil {
+		return BatchAnalyzeResult{Error: fmt.Sprintf("Analysis error: %v", err)}
+	}
+	return BatchAnalyzeResult{Result: &result}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// newBatchTestServer returns a server with the local analyzer
+func newBatchTestServer() *Server {
+	return newTestServer(&config.Config{
+		Analyzer: config.AnalyzerConfig{
+			Mode:               "local",
+			NegativeThreshold:  -0.3,
+			RelevanceThreshold: 0.1,
+			Keywords:           []string{"nios", "dns"},
+		},
+	})
+}
+
+func TestAnalyzeBatchReturnsResultsInOrder(t *testing.T) {
+	s := newBatchTestServer()
+	body := `[
+		{"text": "NIOS upgrade is broken and keeps crashing, terrible", "source": "twitter", "author": "a"},
+		{"text": "", "source": "reddit"},
+		{"text": "Love the new DNS features, great and fast", "source": "g2", "author": "b"}
+	]`
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/analyze/batch", strings.NewReader(body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var results []BatchAnalyzeResult
+	assert.True(t, decodeResponse(t, rec, &results).Success)
+	assert.Len(t, results, 3)
+
+	assert.Empty(t, results[0].Error)
+	assert.NotNil(t, results[0].Result)
+	assert.True(t, results[0].Result.IsNegative)
+
+	assert.Nil(t, results[1].Result, "a failing item is reported without failing the batch")
+	assert.Equal(t, "Text is required", results[1].Error)
+
+	assert.NotNil(t, results[2].Result)
+	assert.False(t, results[2].Result.IsNegative)
+	assert.NotEqual(t, results[0].Result.ReviewID, results[2].Result.ReviewID)
+}
+
+func TestAnalyzeBatchHandlesMoreItemsThanWorkers(t *testing.T) {
+	s := newBatchTestServer()
+	var items []string
+	for i := 0; i < batchAnalyzeWorkers*3; i++ {
+		items = append(items, fmt.Sprintf(`{"text": "DNS review number %d"}`, i))
+	}
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/analyze/batch", strings.NewReader("["+strings.Join(items, ",")+"]"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var results []BatchAnalyzeResult
+	decodeResponse(t, rec, &results)
+	assert.Len(t, results, len(items))
+	for i, result := range results {
+		assert.NotNil(t, result.Result, "item %d", i)
+		assert.Contains(t, result.Result.ReviewID, fmt.Sprintf("-%d", i))
+	}
+}
+
+func TestAnalyzeBatchRejectsInvalidBodies(t *testing.T) {
+	s := newBatchTestServer()
+
+	for _, body := range []string{`{"text": "not an array"}`, `[]`} {
+		rec := doRequest(s, http.MethodPost, "/api/v1/analyze/batch", strings.NewReader(body))
+		assert.Equal(t, http.StatusBadRequest, rec.Code, body)
+	}
+}
+
+func TestAnalyzeBatchStopsOnCancelledContext(t *testing.T) {
+	s := newBatchTestServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := s.analyzeBatch(ctx, []AnalyzeRequest{{Text: "one"}, {Text: "two"}})
+
+	for _, result := range results {
+		assert.Nil(t, result.Result)
+		assert.Equal(t, context.Canceled.Error(), result.Error)
+	}
+}
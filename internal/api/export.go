@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// exportColumns is the CSV header row, matching the JSON field names of exportRow
+var exportColumns = []string{
+	"id", "source", "author", "createdAt", "title", "content", "rating", "language", "url", "tags",
+	"sentimentScore", "isNegative", "isRelevant", "intentCategory", "confidence", "keywords", "department",
+}
+
+// exportRow is a review flattened together with its analysis and routing
+type exportRow struct {
+	ID             string   `json:"id"`
+	Source         string   `json:"source"`
+	Author         string   `json:"author"`
+	CreatedAt      string   `json:"createdAt"`
+	Title          string   `json:"title"`
+	Content        string   `json:"content"`
+	Rating         *float64 `json:"rating"`
+	Language       string   `json:"language"`
+	URL            string   `json:"url"`
+	Tags           []string `json:"tags"`
+	SentimentScore *float64 `json:"sentimentScore"` // Nil when analysis failed
+	IsNegative     bool     `json:"isNegative"`
+	IsRelevant     bool     `json:"isRelevant"`
+	IntentCategory string   `json:"intentCategory"`
+	Confidence     *float64 `json:"confidence"`
+	Keywords       []string `json:"keywords"`
+	Department     string   `json:"department"`
+}
+
+// newExportRow flattens a processed review
+func newExportRow(entry models.AnalyzedReview) exportRow {
+	row := exportRow{
+		ID:         entry.Review.ID,
+		Source:     entry.Review.Source,
+		Author:     entry.Review.Author,
+		Title:      entry.Review.Title,
+		Content:    entry.Review.Content,
+		Rating:     entry.Review.Rating,
+		Language:   entry.Review.Language,
+		URL:        entry.Review.URL,
+		Tags:       entry.Review.Tags,
+		Department: entry.Department,
+	}
+	if !entry.Review.CreatedAt.IsZero() {
+		row.CreatedAt = entry.Review.CreatedAt.UTC().Format(time.RFC3339)
+	}
+	if analysis := entry.Analysis; analysis != nil {
+		row.SentimentScore = &analysis.SentimentScore
+		row.IsNegative = analysis.IsNegative
+		row.IsRelevant = analysis.IsRelevant
+		row.IntentCategory = analysis.IntentCategory
+		row.Confidence = &analysis.Confidence
+		row.Keywords = analysis.Keywords
+	}
+	return row
+}
+
+// csvRecord returns the row's fields in exportColumns order
+func (row exportRow) csvRecord() []string {
+	return []string{
+		row.ID,
+		row.Source,
+		row.Author,
+		row.CreatedAt,
+		row.Title,
+		row.Content,
+		formatOptionalFloat(row.Rating),
+		row.Language,
+		row.URL,
+		strings.Join(row.Tags, ","),
+		formatOptionalFloat(row.SentimentScore),
+		strconv.FormatBool(row.IsNegative),
+		strconv.FormatBool(row.IsRelevant),
+		row.IntentCategory,
+		formatOptionalFloat(row.Confidence),
+		strings.Join(row.Keywords, ","),
+		row.Department,
+	}
+}
+
+// formatOptionalFloat formats f, or returns an empty cell when it is unset
+func formatOptionalFloat(f *float64) string {
+	if f == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*f, 'f', -1, 64)
+}
+
+// handleExportReviews streams the recent reviews with their analyses as CSV
+// (format=csv, the default) or newline-delimited JSON (format=json). It accepts
+// the same filters as the reviews listing and exports every match by default.
+func (s *Server) handleExportReviews(w http.ResponseWriter, r *http.Request) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		format = "csv"
+	}
+	if format != "csv" && format != "json" {
+		s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Unsupported export format %q (expected csv or json)", format))
+		return
+	}
+
+	entries := s.filterRecentReviews(r, maxRecentReviews)
+	filename := fmt.Sprintf("reviews-%s", time.Now().UTC().Format("20060102-150405"))
+
+	if format == "json" {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.ndjson"`, filename))
+		encoder := json.NewEncoder(w)
+		for _, entry := range entries {
+			if err := encoder.Encode(newExportRow(entry)); err != nil {
+				return
+			}
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, filename))
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportColumns); err != nil {
+		return
+	}
+	for _, entry := range entries {
+		// csv.Writer flushes its buffer to the response as it fills, so rows are
+		// streamed rather than held in memory
+		if err := writer.Write(newExportRow(entry).csvRecord()); err != nil {
+			return
+		}
+	}
+	writer.Flush()
+}
@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// newExportTestServer returns a server holding one routed and one unanalyzed review
+func newExportTestServer() *Server {
+	s := newTestServer(&config.Config{})
+	rating := 2.0
+	s.AddRecentReview(models.AnalyzedReview{
+		Review: models.Review{ID: "g2-2", Source: "g2", CreatedAt: time.Date(2025, 3, 2, 9, 0, 0, 0, time.UTC)},
+	})
+	s.AddRecentReview(models.AnalyzedReview{
+		Review: models.Review{
+			ID:        "twitter-1",
+			Source:    "twitter",
+			Author:    "netadmin",
+			Content:   "NIOS upgrade failed, \"grid\" is down",
+			Rating:    &rating,
+			CreatedAt: time.Date(2025, 3, 1, 12, 30, 0, 0, time.UTC),
+			Tags:      []string{"sentiment:negative"},
+		},
+		Analysis: &models.AnalysisResult{
+			SentimentScore: -0.75,
+			IsNegative:     true,
+			IsRelevant:     true,
+			IntentCategory: "bug_report",
+			Confidence:     0.9,
+			Keywords:       []string{"nios", "upgrade"},
+		},
+		Department: "engineering",
+	})
+	return s
+}
+
+func TestExportReviewsAsCSV(t *testing.T) {
+	s := newExportTestServer()
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/reviews/export?format=csv", nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", rec.Header().Get("Content-Type"))
+	assert.Regexp(t, `^attachment; filename="reviews-\d{8}-\d{6}\.csv"$`, rec.Header().Get("Content-Disposition"))
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 3)
+	assert.Equal(t, exportColumns, records[0])
+	assert.Equal(t, []string{
+		"twitter-1", "twitter", "netadmin", "2025-03-01T12:30:00Z", "", `NIOS upgrade failed, "grid" is down`,
+		"2", "", "", "sentiment:negative", "-0.75", "true", "true", "bug_report", "0.9", "nios,upgrade", "engineering",
+	}, records[1])
+	assert.Equal(t, []string{
+		"g2-2", "g2", "", "2025-03-02T09:00:00Z", "", "", "", "", "", "", "", "false", "false", "", "", "", "",
+	}, records[2], "unanalyzed reviews leave the analysis cells empty")
+}
+
+func TestExportReviewsAsNDJSON(t *testing.T) {
+	s := newExportTestServer()
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/reviews/export?format=json&limit=1", nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/x-ndjson", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Header().Get("Content-Disposition"), `.ndjson"`)
+
+	var rows []map[string]interface{}
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var row map[string]interface{}
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+		rows = append(rows, row)
+	}
+	assert.Len(t, rows, 1, "the limit filter applies to exports")
+	assert.Equal(t, "twitter-1", rows[0]["id"])
+	assert.Equal(t, -0.75, rows[0]["sentimentScore"])
+	assert.Equal(t, "engineering", rows[0]["department"])
+	assert.Equal(t, []interface{}{"nios", "upgrade"}, rows[0]["keywords"])
+}
+
+func TestExportReviewsRejectsUnknownFormat(t *testing.T) {
+	s := newExportTestServer()
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/reviews/export?format=xlsx", nil)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.True(t, strings.Contains(decodeResponse(t, rec, nil).Error, "xlsx"))
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// TestNotificationRequest selects the channel and department of a test notification
+type TestNotificationRequest struct {
+	Channel    string `json:"channel"`
+	Department string `json:"department"` // Department ID; empty sends to the channel's default target
+}
+
+// handleTestNotification sends a synthetic notification through one channel
+// and reports whether it was delivered, with the underlying error if not
+func (s *Server) handleTestNotification(w http.ResponseWriter, r *http.Request) {
+	var req TestNotificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		s.respondError(w, r, http.StatusBadRequest, "Invalid request format: expected {channel, department}")
+		return
+	}
+
+	department := models.Department{ID: "test", Name: "Test"}
+	if req.Department != "" {
+		dept, exists := s.deptRouter.GetDepartment(req.Department)
+		if !exists {
+			s.respondError(w, r, http.StatusNotFound, "Department not found")
+			return
+		}
+		department = dept
+	}
+
+	err := s.notifier.SendTest(r.Context(), req.Channel, department)
+	if errors.Is(err, notifier.ErrUnknownChannel) {
+		s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Unknown channel %q (expected one of %s)",
+			req.Channel, strings.Join(notifier.TestChannels, ", ")))
+		return
+	}
+	if err != nil {
+		s.respondError(w, r, http.StatusBadGateway, err.Error())
+		return
+	}
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("Test notification sent via %s to %s", req.Channel, department.Name),
+	})
+}
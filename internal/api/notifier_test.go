@@ -0,0 +1,94 @@
+package api
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSlack records the messages posted to a Slack webhook
+type mockSlack struct {
+	mu       sync.Mutex
+	messages []notifier.SlackMessage
+}
+
+func (m *mockSlack) handler(w http.ResponseWriter, r *http.Request) {
+	var message notifier.SlackMessage
+	body, _ := io.ReadAll(r.Body)
+	json.Unmarshal(body, &message)
+	m.mu.Lock()
+	m.messages = append(m.messages, message)
+	m.mu.Unlock()
+	w.Write([]byte("ok"))
+}
+
+func TestTestNotificationDeliversToSlack(t *testing.T) {
+	slack := &mockSlack{}
+	defaultHook := httptest.NewServer(http.HandlerFunc(slack.handler))
+	defer defaultHook.Close()
+	securityHook := &mockSlack{}
+	securityServer := httptest.NewServer(http.HandlerFunc(securityHook.handler))
+	defer securityServer.Close()
+
+	// The channel is tested before it is enabled
+	s := newTestServer(&config.Config{
+		Notifier: config.NotifierConfig{Slack: config.SlackConfig{
+			WebhookURL:   defaultHook.URL,
+			DeptChannels: map[string]string{"security": securityServer.URL},
+		}},
+	})
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/notifier/test", strings.NewReader(`{"channel": "slack", "department": "security"}`))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	resp := decodeResponse(t, rec, nil)
+	assert.True(t, resp.Success)
+	assert.Contains(t, resp.Message, "Test notification sent via slack")
+	assert.Empty(t, slack.messages)
+	if assert.Len(t, securityHook.messages, 1) {
+		assert.Contains(t, securityHook.messages[0].Text, "Security")
+	}
+	assert.Equal(t, 0, s.notifier.GetStats()["notifications_cached"], "test notifications are not recorded")
+}
+
+func TestTestNotificationReportsDeliveryError(t *testing.T) {
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no_service"))
+	}))
+	defer slack.Close()
+	s := newTestServer(&config.Config{
+		Notifier: config.NotifierConfig{Slack: config.SlackConfig{WebhookURL: slack.URL}},
+	})
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/notifier/test", strings.NewReader(`{"channel": "slack"}`))
+
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	resp := decodeResponse(t, rec, nil)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "slack test notification failed")
+	assert.Contains(t, resp.Error, "404")
+}
+
+func TestTestNotificationValidatesRequest(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/notifier/test", strings.NewReader(`{"channel": "pager"}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, `Unknown channel "pager" (expected one of email, slack, webhook)`, decodeResponse(t, rec, nil).Error)
+
+	rec = doRequest(s, http.MethodPost, "/api/v1/notifier/test", strings.NewReader(`{"channel": "slack", "department": "legal"}`))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = doRequest(s, http.MethodPost, "/api/v1/notifier/test", strings.NewReader(`{"channel": "email"}`))
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Equal(t, "email test notification failed: SMTP not configured", decodeResponse(t, rec, nil).Error)
+}
@@ -0,0 +1,120 @@
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// defaultRateLimitWindow is used when the API config leaves RateLimitWindow unset
+const defaultRateLimitWindow = time.Minute
+
+// clientLimiter is a client's token bucket and when it was last used
+type clientLimiter struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter hands out a token bucket per client IP. Each bucket holds limit
+// tokens and refills at limit per window, so a client can burst its whole
+// budget and then continues at the steady rate.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	clients   map[string]*clientLimiter
+	lastSweep time.Time
+}
+
+// newRateLimiter creates a limiter allowing limit requests per window per client
+func newRateLimiter(limit int, window time.Duration) *rateLimiter {
+	if window <= 0 {
+		window = defaultRateLimitWindow
+	}
+	return &rateLimiter{
+		limit:   limit,
+		window:  window,
+		now:     time.Now,
+		clients: make(map[string]*clientLimiter),
+	}
+}
+
+// reserve takes a token for client, returning how long to wait if none is left
+func (l *rateLimiter) reserve(client string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	c, exists := l.clients[client]
+	if !exists {
+		perSecond := rate.Limit(float64(l.limit) / l.window.Seconds())
+		c = &clientLimiter{limiter: rate.NewLimiter(perSecond, l.limit)}
+		l.clients[client] = c
+	}
+	c.lastSeen = now
+
+	reservation := c.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return delay
+	}
+	return 0
+}
+
+// sweep forgets clients idle for a full window; their buckets would have
+// refilled completely, so a fresh limiter is equivalent
+func (l *rateLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for client, c := range l.clients {
+		if now.Sub(c.lastSeen) >= l.window {
+			delete(l.clients, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// rateLimitMiddleware rejects clients that exceed the configured request budget
+// for the versioned API with 429 Too Many Requests, whether or not they are
+// authenticated. The health check is exempt, as it is from auth.
+func (s *Server) rateLimitMiddleware(next http.Handler) http.Handler {
+	if s.limiter == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.URL.Path, "/api/v1/") || r.URL.Path == "/api/v1/health" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if delay := s.limiter.reserve(clientIP(r)); delay > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			s.respondError(w, r, http.StatusTooManyRequests, "Rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the address the request came from. middleware.RealIP has
+// already replaced RemoteAddr with the forwarded client IP when present.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
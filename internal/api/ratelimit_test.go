@@ -0,0 +1,106 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// doRequestFrom sends an authenticated GET from the given client address
+func doRequestFrom(s *Server, remoteAddr, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = remoteAddr
+	req.Header.Set("Authorization", "Bearer "+testAuthToken)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitRejectsRequestsOverBudget(t *testing.T) {
+	s := newTestServer(&config.Config{
+		API: config.APIConfig{RateLimit: 5, RateLimitWindow: config.Duration(time.Minute)},
+	})
+
+	var statuses []int
+	for i := 0; i < 8; i++ {
+		statuses = append(statuses, doRequestFrom(s, "203.0.113.7:5000", "/api/v1/reviews/").Code)
+	}
+	assert.Equal(t, []int{200, 200, 200, 200, 200, 429, 429, 429}, statuses)
+
+	rec := doRequestFrom(s, "203.0.113.7:5001", "/api/v1/reviews/")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "12", rec.Header().Get("Retry-After"), "one token refills every 60s/5")
+	assert.False(t, decodeResponse(t, rec, nil).Success)
+
+	// Other clients have their own budget
+	assert.Equal(t, http.StatusOK, doRequestFrom(s, "198.51.100.2:5000", "/api/v1/reviews/").Code)
+}
+
+func TestRateLimitUsesForwardedClientIP(t *testing.T) {
+	s := newTestServer(&config.Config{API: config.APIConfig{RateLimit: 1}})
+
+	send := func(forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews/", nil)
+		req.Header.Set("Authorization", "Bearer "+testAuthToken)
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, send("203.0.113.7"))
+	assert.Equal(t, http.StatusTooManyRequests, send("203.0.113.7"))
+	assert.Equal(t, http.StatusOK, send("203.0.113.8"))
+}
+
+func TestRateLimitExemptsHealthCheck(t *testing.T) {
+	s := newTestServer(&config.Config{API: config.APIConfig{RateLimit: 1}})
+
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, http.StatusOK, doRequestFrom(s, "203.0.113.7:5000", "/api/v1/health").Code)
+	}
+}
+
+func TestRateLimitDisabledByDefault(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, http.StatusOK, doRequestFrom(s, "203.0.113.7:5000", "/api/v1/reviews/").Code)
+	}
+}
+
+func TestRateLimiterRefillsAndForgetsIdleClients(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	l := newRateLimiter(2, time.Minute)
+	l.now = func() time.Time { return now }
+
+	assert.Zero(t, l.reserve("a"))
+	assert.Zero(t, l.reserve("a"))
+	assert.Equal(t, 30*time.Second, l.reserve("a"))
+
+	now = now.Add(30 * time.Second)
+	assert.Zero(t, l.reserve("a"))
+
+	now = now.Add(2 * time.Minute)
+	l.reserve("b")
+	assert.NotContains(t, l.clients, "a")
+}
+
+func TestRateLimitCountsUnauthenticatedRequests(t *testing.T) {
+	s := newTestServer(&config.Config{API: config.APIConfig{RateLimit: 2}})
+
+	var statuses []int
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/reviews/", nil)
+		req.RemoteAddr = "203.0.113.7:5000"
+		req.Header.Set("Authorization", "Bearer wrong-token")
+		rec := httptest.NewRecorder()
+		s.router.ServeHTTP(rec, req)
+		statuses = append(statuses, rec.Code)
+	}
+	assert.Equal(t, []int{401, 401, 429}, statuses, "guessing tokens uses up the budget")
+}
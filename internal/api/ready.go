@@ -0,0 +1,109 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// readinessPath is served without authentication so orchestrators can probe it
+const readinessPath = "/api/v1/ready"
+
+// readinessTimeout bounds a readiness check of every dependency
+const readinessTimeout = 5 * time.Second
+
+// readinessCacheTTL is how long a readiness result is reused, so frequent or
+// anonymous callers do not each contact every dependency
+const readinessCacheTTL = 5 * time.Second
+
+// readinessCheck reports whether a named dependency is reachable
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+// AddReadinessCheck adds a dependency checked by the readiness endpoint. It
+// must be called before Start.
+func (s *Server) AddReadinessCheck(name string, check func(ctx context.Context) error) {
+	s.readinessChecks = append(s.readinessChecks, readinessCheck{name: name, check: check})
+}
+
+// handleReadinessCheck reports each dependency's status, answering 503 when
+// any of them is down. Unlike the health check, it contacts the dependencies,
+// at most once per readinessCacheTTL. Errors are only shown to callers with a
+// valid API key.
+func (s *Server) handleReadinessCheck(w http.ResponseWriter, r *http.Request) {
+	statuses := s.readiness()
+	if !isAuthenticated(r) {
+		for i := range statuses {
+			statuses[i].Error = ""
+		}
+	}
+
+	code, status, message := http.StatusOK, "ready", "Service is ready"
+	for _, dependency := range statuses {
+		if !dependency.Healthy {
+			code, status, message = http.StatusServiceUnavailable, "not_ready", "Service is not ready: a dependency is unreachable"
+			break
+		}
+	}
+
+	s.respond(w, r, code, models.APIResponse{
+		Success: code == http.StatusOK,
+		Message: message,
+		Data: map[string]interface{}{
+			"status":       status,
+			"timestamp":    time.Now(),
+			"dependencies": statuses,
+		},
+	})
+}
+
+// readiness returns a copy of the latest dependency statuses, checking every
+// dependency concurrently when the cached result is older than
+// readinessCacheTTL. Concurrent callers wait for a single check.
+func (s *Server) readiness() []models.DependencyStatus {
+	s.readinessMutex.Lock()
+	defer s.readinessMutex.Unlock()
+
+	if s.readinessStatuses == nil || time.Since(s.readinessCheckedAt) >= readinessCacheTTL {
+		s.readinessStatuses = s.checkReadiness()
+		s.readinessCheckedAt = time.Now()
+	}
+
+	statuses := make([]models.DependencyStatus, len(s.readinessStatuses))
+	copy(statuses, s.readinessStatuses)
+	return statuses
+}
+
+// checkReadiness checks every dependency concurrently. The checks are not
+// tied to any request, since their result is shared by later callers.
+func (s *Server) checkReadiness() []models.DependencyStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), readinessTimeout)
+	defer cancel()
+
+	statuses := make([]models.DependencyStatus, len(s.readinessChecks))
+	var wg sync.WaitGroup
+	for i, c := range s.readinessChecks {
+		wg.Add(1)
+		go func(i int, c readinessCheck) {
+			defer wg.Done()
+
+			start := time.Now()
+			err := c.check(ctx)
+			statuses[i] = models.DependencyStatus{
+				Name:      c.name,
+				Healthy:   err == nil,
+				LatencyMs: time.Since(start).Milliseconds(),
+			}
+			if err != nil {
+				statuses[i].Error = err.Error()
+			}
+		}(i, c)
+	}
+	wg.Wait()
+	return statuses
+}
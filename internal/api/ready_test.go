@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// readiness is the data of a readiness response
+type readiness struct {
+	Status       string                    `json:"status"`
+	Dependencies []models.DependencyStatus `json:"dependencies"`
+}
+
+// getReady sends an unauthenticated readiness request
+func getReady(s *Server) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/ready", nil))
+	return rec
+}
+
+func TestReadinessCheckReady(t *testing.T) {
+	s := newTestServer(&config.Config{Analyzer: config.AnalyzerConfig{Mode: "local"}})
+	s.AddReadinessCheck("storage", func(ctx context.Context) error { return nil })
+
+	rec := getReady(s)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var ready readiness
+	assert.True(t, decodeResponse(t, rec, &ready).Success)
+	assert.Equal(t, "ready", ready.Status)
+	names := make([]string, len(ready.Dependencies))
+	for i, dependency := range ready.Dependencies {
+		names[i] = dependency.Name
+		assert.True(t, dependency.Healthy, dependency.Name)
+	}
+	assert.Equal(t, []string{"analyzer", "notifier", "storage"}, names)
+}
+
+func TestReadinessCheckStorageDown(t *testing.T) {
+	s := newTestServer(&config.Config{Analyzer: config.AnalyzerConfig{Mode: "local"}})
+	s.AddReadinessCheck("storage", func(ctx context.Context) error {
+		return errors.New("failed to reach warehouse: connection refused")
+	})
+
+	rec := getReady(s)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var ready readiness
+	assert.False(t, decodeResponse(t, rec, &ready).Success)
+	assert.Equal(t, "not_ready", ready.Status)
+	if assert.Len(t, ready.Dependencies, 3) {
+		assert.True(t, ready.Dependencies[0].Healthy)
+		assert.True(t, ready.Dependencies[1].Healthy)
+		assert.Equal(t, models.DependencyStatus{
+			Name:      "storage",
+			LatencyMs: ready.Dependencies[2].LatencyMs,
+		}, ready.Dependencies[2], "anonymous callers should not see the error")
+	}
+
+	// Authenticated callers see why the dependency is down
+	rec = doRequest(s, http.MethodGet, "/api/v1/ready", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	ready = readiness{}
+	decodeResponse(t, rec, &ready)
+	if assert.Len(t, ready.Dependencies, 3) {
+		assert.Equal(t, "failed to reach warehouse: connection refused", ready.Dependencies[2].Error)
+	}
+
+	// The liveness check stays lightweight and healthy
+	assert.Equal(t, http.StatusOK, doRequest(s, http.MethodGet, "/api/v1/health", nil).Code)
+}
+
+func TestReadinessCheckAnalyzerNotConfigured(t *testing.T) {
+	s := newTestServer(&config.Config{Analyzer: config.AnalyzerConfig{Mode: "openai"}})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/ready", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+
+	var ready readiness
+	decodeResponse(t, rec, &ready)
+	if assert.NotEmpty(t, ready.Dependencies) {
+		assert.Equal(t, "analyzer", ready.Dependencies[0].Name)
+		assert.False(t, ready.Dependencies[0].Healthy)
+		assert.Contains(t, ready.Dependencies[0].Error, "API key is not configured")
+	}
+}
+
+func TestReadinessCheckReusesRecentResult(t *testing.T) {
+	s := newTestServer(&config.Config{Analyzer: config.AnalyzerConfig{Mode: "local"}})
+	var checks int
+	s.AddReadinessCheck("storage", func(ctx context.Context) error {
+		checks++
+		return nil
+	})
+
+	assert.Equal(t, http.StatusOK, getReady(s).Code)
+	assert.Equal(t, http.StatusOK, getReady(s).Code)
+	assert.Equal(t, 1, checks)
+
+	// An expired result is checked again
+	s.readinessCheckedAt = time.Now().Add(-readinessCacheTTL)
+	assert.Equal(t, http.StatusOK, getReady(s).Code)
+	assert.Equal(t, 2, checks)
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// recentReview returns the stored entry for a review ID
+func (s *Server) recentReview(id string) (models.AnalyzedReview, bool) {
+	s.reviewsMutex.RLock()
+	defer s.reviewsMutex.RUnlock()
+
+	for _, entry := range s.recentReviews {
+		if entry.Review.ID == id {
+			return entry, true
+		}
+	}
+	return models.AnalyzedReview{}, false
+}
+
+// updateRecentAnalysis replaces the stored analysis of a review, reporting
+// whether the review is still stored
+func (s *Server) updateRecentAnalysis(id string, result models.AnalysisResult) bool {
+	s.reviewsMutex.Lock()
+	defer s.reviewsMutex.Unlock()
+
+	for i, entry := range s.recentReviews {
+		if entry.Review.ID != id {
+			continue
+		}
+		// Copy rather than write in place, so snapshots taken by readers stay valid
+		updated := make([]models.AnalyzedReview, len(s.recentReviews))
+		copy(updated, s.recentReviews)
+		updated[i].Analysis = &result
+		s.recentReviews = updated
+		return true
+	}
+	return false
+}
+
+// handleReanalyzeReview analyzes a stored review again with the current
+// analyzer configuration, bypassing the result cache, and stores the result
+func (s *Server) handleReanalyzeReview(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	entry, found := s.recentReview(id)
+	if !found {
+		s.respondError(w, r, http.StatusNotFound, "Review not found")
+		return
+	}
+
+	result, err := s.analyzer.Reanalyze(r.Context(), entry.Review)
+	if err != nil {
+		s.respondError(w, r, http.StatusInternalServerError, fmt.Sprintf("Analysis error: %v", err))
+		return
+	}
+
+	// The review may have aged out of the recent list during analysis
+	if !s.updateRecentAnalysis(id, result) {
+		s.respondError(w, r, http.StatusNotFound, "Review not found")
+		return
+	}
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    result,
+	})
+}
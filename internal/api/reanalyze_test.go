@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReanalyzeReviewUpdatesStoredAnalysis(t *testing.T) {
+	s := newTestServer(&config.Config{Analyzer: config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.3}})
+	review := models.Review{ID: "g2-1", Source: "G2", Content: "The NIOS upgrade failed and support was terrible"}
+	stale := models.AnalysisResult{ReviewID: "g2-1", SentimentScore: 0.4, IntentCategory: "feature_request"}
+	s.AddRecentReview(models.AnalyzedReview{Review: review, Analysis: &stale, Department: "product"})
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/reviews/g2-1/reanalyze", nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var result models.AnalysisResult
+	assert.True(t, decodeResponse(t, rec, &result).Success)
+	assert.Equal(t, "g2-1", result.ReviewID)
+	assert.Equal(t, -1.0, result.SentimentScore)
+	assert.True(t, result.IsNegative)
+
+	entry, found := s.recentReview("g2-1")
+	assert.True(t, found)
+	if assert.NotNil(t, entry.Analysis) {
+		assert.Equal(t, result, *entry.Analysis)
+	}
+	assert.Equal(t, "product", entry.Department)
+}
+
+func TestReanalyzeUnknownReview(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/reviews/missing/reanalyze", nil)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "Review not found", decodeResponse(t, rec, nil).Error)
+}
+
+func TestReanalyzeRequiresAnalyzeScope(t *testing.T) {
+	s := newScopedTestServer()
+	s.AddRecentReview(models.AnalyzedReview{Review: models.Review{ID: "g2-1", Content: "Great product"}})
+
+	rec := doKeyRequest(s, "read-key", http.MethodPost, "/api/v1/reviews/g2-1/reanalyze", "")
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	// Reading reviews still only needs the read scope
+	assert.Equal(t, http.StatusOK, doKeyRequest(s, "read-key", http.MethodGet, "/api/v1/reviews/g2-1", "").Code)
+}
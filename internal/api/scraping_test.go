@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/scraper"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// getScrapingStatus fetches and decodes the scraping run status
+func getScrapingStatus(t *testing.T, s *Server) scraper.RunStatus {
+	rec := doRequest(s, http.MethodGet, "/api/v1/scraping/status", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var status scraper.RunStatus
+	decodeResponse(t, rec, &status)
+	return status
+}
+
+func TestScrapingStatusIdle(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	status := getScrapingStatus(t, s)
+
+	assert.Equal(t, scraper.RunStateIdle, status.State)
+	assert.Nil(t, status.StartedAt)
+}
+
+func TestScrapingStatusWhileRunning(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	run, err := s.scraperManager.StartRun()
+	assert.NoError(t, err)
+
+	status := getScrapingStatus(t, s)
+	assert.Equal(t, scraper.RunStateRunning, status.State)
+	assert.NotNil(t, status.StartedAt)
+
+	_, err = run(context.Background())
+	assert.NoError(t, err)
+
+	status = getScrapingStatus(t, s)
+	assert.Equal(t, scraper.RunStateIdle, status.State)
+	assert.NotNil(t, status.FinishedAt)
+}
+
+func TestRunScrapingConflictsWithRunInProgress(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	run, err := s.scraperManager.StartRun()
+	assert.NoError(t, err)
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/scraping/run", nil)
+
+	assert.Equal(t, http.StatusConflict, rec.Code)
+	assert.Equal(t, "Scraping is already running", decodeResponse(t, rec, nil).Error)
+
+	// Once the run finishes another can start
+	_, err = run(context.Background())
+	assert.NoError(t, err)
+	rec = doRequest(s, http.MethodPost, "/api/v1/scraping/run", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Eventually(t, func() bool {
+		return s.scraperManager.RunStatus().State == scraper.RunStateIdle
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStopEndsScrapingRunsAndRejectsNewOnes(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	s.httpServer = &http.Server{}
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/scraping/run", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	assert.NoError(t, s.Stop(ctx))
+	assert.Equal(t, scraper.RunStateIdle, s.scraperManager.RunStatus().State, "Stop waits for the run to end")
+
+	rec = doRequest(s, http.MethodPost, "/api/v1/scraping/run", nil)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, scraper.RunStateIdle, s.scraperManager.RunStatus().State)
+}
+
+func TestRunScrapingUsesPipeline(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	processed := make(chan []models.Review, 1)
+	s.SetPipeline(func(ctx context.Context, reviews []models.Review) []models.Review {
+		processed <- reviews
+		return nil
+	})
+
+	rec := doRequest(s, http.MethodPost, "/api/v1/scraping/run", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	select {
+	case reviews := <-processed:
+		assert.Empty(t, reviews)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the run's reviews were not passed to the pipeline")
+	}
+}
@@ -3,6 +3,7 @@ package api
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -12,7 +13,9 @@ import (
 	"time"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
+	"github.com/Infoblox-CTO/review-scraper/internal/buildinfo"
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/metrics"
 	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
 	"github.com/Infoblox-CTO/review-scraper/internal/router"
 	"github.com/Infoblox-CTO/review-scraper/internal/scraper"
@@ -22,33 +25,67 @@ import (
 	"github.com/go-chi/cors"
 )
 
+// maxRecentReviews is how many processed reviews the server keeps in memory
+const maxRecentReviews = 100
+
+// maxTrendDays bounds the dashboard trend window a client can request
+const maxTrendDays = 365
+
 // Server represents the API server
 type Server struct {
-	config         config.APIConfig
-	router         *chi.Mux
-	httpServer     *http.Server
-	scraperManager *scraper.Manager
-	analyzer       *analyzer.Analyzer
-	deptRouter     *router.Router
-	notifier       *notifier.Notifier
-	recentReviews  []models.Review
-	reviewsMutex   sync.RWMutex
+	config          config.APIConfig
+	appConfig       *config.Config
+	configMutex     sync.RWMutex
+	router          *chi.Mux
+	httpServer      *http.Server
+	scraperManager  *scraper.Manager
+	analyzer        *analyzer.Analyzer
+	deptRouter      *router.Router
+	notifier        *notifier.Notifier
+	tenantNotifiers []*notifier.Notifier // Notifiers of configured tenants, for Slack interactions
+	pipeline        Pipeline             // Processes the reviews of runs started through the API
+	recentReviews   []models.AnalyzedReview
+	reviewsMutex    sync.RWMutex
+	metrics         *metrics.Metrics
+	limiter         *rateLimiter // nil when RateLimit is unset
+	credentials     []credential // Guarded by configMutex; API keys are reloadable
+	readinessChecks []readinessCheck
+
+	readinessMutex     sync.Mutex
+	readinessStatuses  []models.DependencyStatus // Latest readiness result, reused for readinessCacheTTL
+	readinessCheckedAt time.Time
+
+	runs       sync.WaitGroup  // Scraping runs started through the API
+	runsMutex  sync.Mutex      // Orders runs.Add against Stop
+	stopping   bool            // Set by Stop; no run starts from then on
+	runCtx     context.Context // Cancelled by Stop to end those runs
+	cancelRuns context.CancelFunc
 }
 
 // NewServer creates a new API server
-func NewServer(cfg config.APIConfig, scraperManager *scraper.Manager,
+func NewServer(cfg *config.Config, scraperManager *scraper.Manager,
 	analyzer *analyzer.Analyzer, deptRouter *router.Router, notifier *notifier.Notifier) *Server {
 
 	s := &Server{
-		config:         cfg,
+		config:         cfg.API,
+		appConfig:      cfg,
 		scraperManager: scraperManager,
 		analyzer:       analyzer,
 		deptRouter:     deptRouter,
 		notifier:       notifier,
-		recentReviews:  make([]models.Review, 0, 100), // Keep last 100 reviews
+		recentReviews:  make([]models.AnalyzedReview, 0, maxRecentReviews),
 		reviewsMutex:   sync.RWMutex{},
+		credentials:    buildCredentials(cfg.API),
+	}
+	s.runCtx, s.cancelRuns = context.WithCancel(context.Background())
+
+	if cfg.API.RateLimit > 0 {
+		s.limiter = newRateLimiter(cfg.API.RateLimit, cfg.API.RateLimitWindow.Duration())
 	}
 
+	s.AddReadinessCheck("analyzer", analyzer.Ping)
+	s.AddReadinessCheck("notifier", notifier.Probe)
+
 	s.setupRouter()
 
 	return s
@@ -75,22 +112,37 @@ func (s *Server) setupRouter() {
 		MaxAge:           300,
 	}))
 
+	// Per-client request budget for the versioned API, ahead of
+	// authentication so failed attempts count against it too
+	r.Use(s.rateLimitMiddleware)
+
 	// Authentication middleware
 	r.Use(s.authMiddleware)
 
+	// Prometheus metrics, served outside the versioned API
+	r.Get("/metrics", s.handleMetrics)
+
 	// API routes
 	r.Route("/api/v1", func(r chi.Router) {
-		// Health check endpoint
+		// Liveness and readiness endpoints
 		r.Get("/health", s.handleHealthCheck)
+		r.Get("/ready", s.handleReadinessCheck)
 
 		// Reviews endpoints
 		r.Route("/reviews", func(r chi.Router) {
-			r.Get("/", s.handleGetReviews)
-			r.Get("/{id}", s.handleGetReview)
+			r.Group(func(r chi.Router) {
+				r.Use(s.requireScope(ScopeReviewsRead))
+				r.Get("/", s.handleGetReviews)
+				r.Get("/export", s.handleExportReviews)
+				r.Get("/{id}", s.handleGetReview)
+				r.Get("/{id}/similar", s.handleGetSimilarReviews)
+			})
+			r.With(s.requireScope(ScopeAnalyzeRun)).Post("/{id}/reanalyze", s.handleReanalyzeReview)
 		})
 
 		// Departments endpoints
 		r.Route("/departments", func(r chi.Router) {
+			r.Use(s.requireScope(ScopeReviewsRead))
 			r.Get("/", s.handleGetDepartments)
 			r.Get("/{id}", s.handleGetDepartment)
 			r.Get("/{id}/reviews", s.handleGetDepartmentReviews)
@@ -98,25 +150,38 @@ func (s *Server) setupRouter() {
 
 		// Dashboard endpoints
 		r.Route("/dashboard", func(r chi.Router) {
+			r.Use(s.requireScope(ScopeStatsRead))
 			r.Get("/metrics", s.handleGetDashboardMetrics)
+			r.Get("/products", s.handleGetProductSentiment)
 			r.Get("/stats", s.handleGetSystemStats)
 		})
 
 		// Scraping endpoints
 		r.Route("/scraping", func(r chi.Router) {
-			r.Post("/run", s.handleRunScraping)
-			r.Get("/stats", s.handleGetScrapingStats)
+			r.With(s.requireScope(ScopeScrapingRun)).Post("/run", s.handleRunScraping)
+			r.With(s.requireScope(ScopeStatsRead)).Get("/stats", s.handleGetScrapingStats)
+			r.With(s.requireScope(ScopeStatsRead)).Get("/status", s.handleGetScrapingStatus)
 		})
 
 		// Analysis endpoints
 		r.Route("/analyze", func(r chi.Router) {
+			r.Use(s.requireScope(ScopeAnalyzeRun))
 			r.Post("/", s.handleAnalyzeText)
+			r.Post("/batch", s.handleAnalyzeBatch)
 		})
 
+		// Notifier endpoints
+		r.Route("/notifier", func(r chi.Router) {
+			r.With(s.requireScope(ScopeConfigWrite)).Post("/test", s.handleTestNotification)
+		})
+
+		// Slack interactivity, authenticated by Slack's request signature
+		r.Post("/slack/interactions", s.handleSlackInteraction)
+
 		// Config endpoints
 		r.Route("/config", func(r chi.Router) {
-			r.Get("/{component}", s.handleGetConfig)
-			r.Put("/{component}", s.handleUpdateConfig)
+			r.With(s.requireScope(ScopeConfigRead)).Get("/{component}", s.handleGetConfig)
+			r.With(s.requireScope(ScopeConfigWrite)).Put("/{component}", s.handleUpdateConfig)
 		})
 	})
 
@@ -142,51 +207,70 @@ func (s *Server) Start() error {
 	return s.httpServer.ListenAndServe()
 }
 
-// Stop gracefully stops the API server
+// Stop gracefully stops the API server, then cancels the scraping runs it
+// started and waits for them to end
 func (s *Server) Stop(ctx context.Context) error {
 	log.Println("Stopping API server...")
-	return s.httpServer.Shutdown(ctx)
+	err := s.httpServer.Shutdown(ctx)
+
+	// Stop scraping runs started through the API before their notifications
+	// are drained, so none starts sending after the drain
+	s.runsMutex.Lock()
+	s.stopping = true
+	s.runsMutex.Unlock()
+	s.cancelRuns()
+	done := make(chan struct{})
+	go func() {
+		s.runs.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for scraping runs: %w", ctx.Err())
+	}
+	return err
 }
 
-// AddRecentReview adds a review to the recent reviews list
-func (s *Server) AddRecentReview(review models.Review) {
+// AddRecentReview adds a processed review to the recent reviews list, which
+// backs the reviews and dashboard endpoints
+func (s *Server) AddRecentReview(entry models.AnalyzedReview) {
 	s.reviewsMutex.Lock()
 	defer s.reviewsMutex.Unlock()
 
 	// Add to front of slice
-	s.recentReviews = append([]models.Review{review}, s.recentReviews...)
+	s.recentReviews = append([]models.AnalyzedReview{entry}, s.recentReviews...)
 
-	// Keep only the last 100 reviews
-	if len(s.recentReviews) > 100 {
-		s.recentReviews = s.recentReviews[:100]
+	// Keep only the most recent reviews
+	if len(s.recentReviews) > maxRecentReviews {
+		s.recentReviews = s.recentReviews[:maxRecentReviews]
 	}
 }
 
-// authMiddleware handles authentication
+// authMiddleware authenticates the request's token against the configured API
+// keys and attaches the matching Principal to the request context
 func (s *Server) authMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Skip auth for health check and swagger
-		if r.URL.Path == "/api/v1/health" || strings.HasPrefix(r.URL.Path, "/swagger") {
+		token := requestToken(r)
+		principal, ok := s.authenticate(token)
+
+		// Skip auth for health and readiness checks, metrics and swagger; Slack interactions carry a signature instead.
+		// A valid token on these paths is still recorded so they can show callers more detail.
+		if r.URL.Path == "/api/v1/health" || r.URL.Path == readinessPath || r.URL.Path == "/metrics" || strings.HasPrefix(r.URL.Path, "/swagger") ||
+			r.URL.Path == slackInteractionsPath {
+			if ok && token != "" {
+				r = r.WithContext(context.WithValue(r.Context(), principalKey{}, principal))
+			}
 			next.ServeHTTP(w, r)
 			return
 		}
 
-		// Check for token authentication
-		token := r.Header.Get("Authorization")
-		if token == "" {
-			// Also check query param for token (useful for dashboard iframe embedding)
-			token = r.URL.Query().Get("token")
-		} else {
-			// Remove "Bearer " prefix if present
-			token = strings.TrimPrefix(token, "Bearer ")
-		}
-
-		if token == "" || token != s.config.AuthToken {
+		if token == "" || !ok {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		next.ServeHTTP(w, r)
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
 	})
 }
 
@@ -213,69 +297,145 @@ func (s *Server) respondError(w http.ResponseWriter, r *http.Request, statusCode
 
 // Route handlers
 
-// handleHealthCheck handles the health check endpoint
+// SetMetrics provides the metrics served at /metrics when EnableMetrics is set
+func (s *Server) SetMetrics(m *metrics.Metrics) {
+	s.metrics = m
+}
+
+// Pipeline processes the reviews of a scraping run and returns those to be
+// retried, which the next run scrapes again
+type Pipeline func(ctx context.Context, reviews []models.Review) (retry []models.Review)
+
+// SetPipeline sets the pipeline processing the reviews of scraping runs
+// started through the API. Until it is set, their reviews are only recorded.
+func (s *Server) SetPipeline(p Pipeline) {
+	s.pipeline = p
+}
+
+// recordReviews is the pipeline of a server without one, recording reviews unanalyzed
+func (s *Server) recordReviews(ctx context.Context, reviews []models.Review) []models.Review {
+	for _, review := range reviews {
+		s.AddRecentReview(models.AnalyzedReview{Review: review})
+	}
+	return nil
+}
+
+// AddTenantNotifier registers the notifier of a configured tenant, so Slack
+// button clicks on the tenant's notifications are applied to it
+func (s *Server) AddTenantNotifier(n *notifier.Notifier) {
+	s.tenantNotifiers = append(s.tenantNotifiers, n)
+}
+
+// handleMetrics serves Prometheus metrics
+func (s *Server) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	if !s.config.EnableMetrics || s.metrics == nil {
+		http.NotFound(w, r)
+		return
+	}
+	s.metrics.Handler().ServeHTTP(w, r)
+}
+
+// handleHealthCheck is a lightweight liveness check. It reports the notifier
+// channels' last probe results without contacting any dependency. Probe
+// errors are only shown to callers with a valid API key.
 func (s *Server) handleHealthCheck(w http.ResponseWriter, r *http.Request) {
+	status := "ok"
+	message := "Service is healthy"
+
+	degraded, probes := s.notifier.Health()
+	build := buildinfo.Get()
+	if !isAuthenticated(r) {
+		// Probe errors describe the notification channels; anonymous callers only see which are down
+		for i := range probes {
+			probes[i].Error = ""
+		}
+	}
+	if degraded {
+		status = "degraded"
+		message = "Service is degraded: notification channels failed their connectivity check"
+	}
+
 	s.respond(w, r, http.StatusOK, models.APIResponse{
 		Success: true,
-		Message: "Service is healthy",
+		Message: message,
 		Data: map[string]interface{}{
-			"version":   "1.0.0",
+			"version":   build.Version,
+			"commit":    build.Commit,
+			"buildTime": build.BuildTime,
 			"timestamp": time.Now(),
+			"status":    status,
+			"notifier":  probes,
 		},
 	})
 }
 
 // handleGetReviews gets recent reviews
 func (s *Server) handleGetReviews(w http.ResponseWriter, r *http.Request) {
-	s.reviewsMutex.RLock()
-	defer s.reviewsMutex.RUnlock()
+	entries := s.filterRecentReviews(r, 50)
+	reviews := make([]models.Review, len(entries))
+	for i, entry := range entries {
+		reviews[i] = entry.Review
+	}
 
-	// Parse query parameters
-	limitStr := r.URL.Query().Get("limit")
-	limit := 50 // Default limit
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    reviews,
+	})
+}
 
-	if limitStr != "" {
+// filterRecentReviews returns the recent reviews selected by the request's query
+// parameters, newest first. limit caps the count and defaults to defaultLimit.
+func (s *Server) filterRecentReviews(r *http.Request, defaultLimit int) []models.AnalyzedReview {
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
 		parsedLimit, err := strconv.Atoi(limitStr)
 		if err == nil && parsedLimit > 0 {
 			limit = parsedLimit
 		}
 	}
 
-	// Apply limit
-	reviews := s.recentReviews
-	if len(reviews) > limit {
-		reviews = reviews[:limit]
-	}
+	// AddRecentReview replaces the slice rather than writing into it, so the
+	// snapshot stays valid after the lock is released
+	s.reviewsMutex.RLock()
+	entries := s.recentReviews
+	s.reviewsMutex.RUnlock()
 
-	s.respond(w, r, http.StatusOK, models.APIResponse{
-		Success: true,
-		Data:    reviews,
-	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries
 }
 
 // handleGetReview gets a specific review
 func (s *Server) handleGetReview(w http.ResponseWriter, r *http.Request) {
-	id := chi.URLParam(r, "id")
-
-	s.reviewsMutex.RLock()
-	defer s.reviewsMutex.RUnlock()
-
-	for _, review := range s.recentReviews {
-		if review.ID == id {
-			s.respond(w, r, http.StatusOK, models.APIResponse{
-				Success: true,
-				Data:    review,
-			})
-			return
-		}
+	entry, found := s.recentReview(chi.URLParam(r, "id"))
+	if !found {
+		s.respondError(w, r, http.StatusNotFound, "Review not found")
+		return
 	}
 
-	s.respondError(w, r, http.StatusNotFound, "Review not found")
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    entry.Review,
+	})
 }
 
-// handleGetDepartments gets all departments
+// handleGetDepartments gets the departments sorted by ID, optionally paged
+// with the limit and offset query parameters
 func (s *Server) handleGetDepartments(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := s.pageParams(w, r)
+	if !ok {
+		return
+	}
+
 	departments := s.deptRouter.GetAllDepartments()
+	if offset > len(departments) {
+		offset = len(departments)
+	}
+	departments = departments[offset:]
+	if limit > 0 && limit < len(departments) {
+		departments = departments[:limit]
+	}
 
 	s.respond(w, r, http.StatusOK, models.APIResponse{
 		Success: true,
@@ -283,6 +443,29 @@ func (s *Server) handleGetDepartments(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// pageParams reads the limit and offset query parameters, writing a bad
+// request response when either is invalid. A zero limit means no limit.
+func (s *Server) pageParams(w http.ResponseWriter, r *http.Request) (limit, offset int, ok bool) {
+	query := r.URL.Query()
+	if limitStr := query.Get("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			s.respondError(w, r, http.StatusBadRequest, "limit must be a positive integer")
+			return 0, 0, false
+		}
+		limit = parsed
+	}
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		parsed, err := strconv.Atoi(offsetStr)
+		if err != nil || parsed < 0 {
+			s.respondError(w, r, http.StatusBadRequest, "offset must be a non-negative integer")
+			return 0, 0, false
+		}
+		offset = parsed
+	}
+	return limit, offset, true
+}
+
 // handleGetDepartment gets a specific department
 func (s *Server) handleGetDepartment(w http.ResponseWriter, r *http.Request) {
 	id := chi.URLParam(r, "id")
@@ -305,40 +488,64 @@ func (s *Server) handleGetDepartmentReviews(w http.ResponseWriter, r *http.Reque
 	s.respondError(w, r, http.StatusNotImplemented, "Feature not yet implemented")
 }
 
-// handleGetDashboardMetrics gets metrics for the dashboard
+// handleGetDashboardMetrics aggregates the recent reviews for the dashboard. The
+// trend window defaults to the configured number of days and can be overridden
+// with the days query parameter.
 func (s *Server) handleGetDashboardMetrics(w http.ResponseWriter, r *http.Request) {
-	// This is a placeholder - in a real implementation, this would generate actual metrics
+	trendDays, ok := s.dashboardDays(w, r)
+	if !ok {
+		return
+	}
+
+	s.reviewsMutex.RLock()
+	dashboard := metrics.Dashboard(s.recentReviews, trendDays, time.Now())
+	s.reviewsMutex.RUnlock()
+
 	s.respond(w, r, http.StatusOK, models.APIResponse{
 		Success: true,
-		Data: models.DashboardMetrics{
-			TotalReviews:     100,
-			NegativeReviews:  25,
-			AverageSentiment: -0.3,
-			TopCategories: map[string]int{
-				"bug_report":       10,
-				"customer_service": 8,
-				"performance":      5,
-			},
-			DepartmentWorkloads: map[string]int{
-				"engineering": 15,
-				"support":     12,
-				"operations":  5,
-			},
-			SourceBreakdown: map[string]int{
-				"twitter":    40,
-				"reddit":     30,
-				"app_store":  20,
-				"play_store": 10,
-			},
-		},
+		Data:    dashboard,
+	})
+}
+
+// handleGetProductSentiment rolls up the recent reviews of the last days by
+// the products they mention, worst average sentiment first
+func (s *Server) handleGetProductSentiment(w http.ResponseWriter, r *http.Request) {
+	days, ok := s.dashboardDays(w, r)
+	if !ok {
+		return
+	}
+
+	s.reviewsMutex.RLock()
+	rollup := metrics.ProductSentiments(s.recentReviews, days, time.Now())
+	s.reviewsMutex.RUnlock()
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    rollup,
 	})
 }
 
+// dashboardDays returns the dashboard window from the days query parameter,
+// defaulting to api.dashboardTrendDays, or responds 400 and returns false
+func (s *Server) dashboardDays(w http.ResponseWriter, r *http.Request) (int, bool) {
+	daysStr := r.URL.Query().Get("days")
+	if daysStr == "" {
+		return s.config.DashboardTrendDays, true
+	}
+	days, err := strconv.Atoi(daysStr)
+	if err != nil || days <= 0 || days > maxTrendDays {
+		s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("days must be between 1 and %d", maxTrendDays))
+		return 0, false
+	}
+	return days, true
+}
+
 // handleGetSystemStats gets system statistics
 func (s *Server) handleGetSystemStats(w http.ResponseWriter, r *http.Request) {
 	stats := map[string]interface{}{
 		"scraper":  s.scraperManager.GetStats(),
 		"analyzer": s.analyzer.GetStats(),
+		"router":   s.deptRouter.GetStats(),
 		"notifier": s.notifier.GetStats(),
 	}
 
@@ -348,44 +555,50 @@ func (s *Server) handleGetSystemStats(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleRunScraping triggers a scraping run
+// trackRun counts a scraping run started through the API, unless the server
+// is stopping
+func (s *Server) trackRun() bool {
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
+	if s.stopping {
+		return false
+	}
+	s.runs.Add(1)
+	return true
+}
+
+// handleRunScraping triggers a scraping run, or responds 409 while one is
+// running and 503 once the server is stopping
 func (s *Server) handleRunScraping(w http.ResponseWriter, r *http.Request) {
-	// Start scraping in background
+	if !s.trackRun() {
+		s.respondError(w, r, http.StatusServiceUnavailable, "Server is shutting down")
+		return
+	}
+	run, err := s.scraperManager.StartRun()
+	if errors.Is(err, scraper.ErrRunInProgress) {
+		s.runs.Done()
+		s.respondError(w, r, http.StatusConflict, "Scraping is already running")
+		return
+	}
+
+	// Start scraping in background, until the server stops
 	go func() {
-		ctx := context.Background()
-		reviews, err := s.scraperManager.ScrapeAll(ctx)
+		defer s.runs.Done()
+		ctx := s.runCtx
+		batch, err := run(ctx)
 		if err != nil {
 			log.Printf("Error during manual scraping: %v", err)
 			return
 		}
 
-		log.Printf("Manual scraping completed: %d reviews found", len(reviews))
-
-		// Process the reviews
-		for _, review := range reviews {
-			// Add to recent reviews
-			s.AddRecentReview(review)
-
-			// Analyze sentiment and intent
-			analysisResult, err := s.analyzer.Analyze(ctx, review)
-			if err != nil {
-				log.Printf("Error analyzing review: %v", err)
-				continue
-			}
+		log.Printf("Manual scraping completed: %d reviews found", len(batch.Reviews))
 
-			// Skip if not negative or not relevant
-			if !analysisResult.IsNegative || !analysisResult.IsRelevant {
-				continue
-			}
-
-			// Route to appropriate department
-			department := s.deptRouter.Route(analysisResult)
-
-			// Send notification
-			if err := s.notifier.Notify(ctx, department, review, analysisResult); err != nil {
-				log.Printf("Error sending notification: %v", err)
-			}
+		// Process the reviews as a scheduled run would
+		process := s.pipeline
+		if process == nil {
+			process = s.recordReviews
 		}
+		batch.Commit(process(ctx, batch.Reviews))
 	}()
 
 	s.respond(w, r, http.StatusOK, models.APIResponse{
@@ -394,6 +607,15 @@ func (s *Server) handleRunScraping(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// handleGetScrapingStatus reports whether a scraping run is in progress and
+// the outcome of the last one
+func (s *Server) handleGetScrapingStatus(w http.ResponseWriter, r *http.Request) {
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    s.scraperManager.RunStatus(),
+	})
+}
+
 // handleGetScrapingStats gets scraping statistics
 func (s *Server) handleGetScrapingStats(w http.ResponseWriter, r *http.Request) {
 	stats := s.scraperManager.GetStats()
@@ -411,6 +633,20 @@ type AnalyzeRequest struct {
 	Author string `json:"author"`
 }
 
+// review builds the review to analyze for the request
+func (req AnalyzeRequest) review(id string) models.Review {
+	now := time.Now()
+	return models.Review{
+		ID:          id,
+		Source:      req.Source,
+		SourceID:    id,
+		Content:     req.Text,
+		Author:      req.Author,
+		CreatedAt:   now,
+		RetrievedAt: now,
+	}
+}
+
 func (s *Server) handleAnalyzeText(w http.ResponseWriter, r *http.Request) {
 	var req AnalyzeRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -424,15 +660,7 @@ func (s *Server) handleAnalyzeText(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Create a review from the request
-	review := models.Review{
-		ID:          fmt.Sprintf("manual-%d", time.Now().UnixNano()),
-		Source:      req.Source,
-		SourceID:    fmt.Sprintf("manual-%d", time.Now().UnixNano()),
-		Content:     req.Text,
-		Author:      req.Author,
-		CreatedAt:   time.Now(),
-		RetrievedAt: time.Now(),
-	}
+	review := req.review(fmt.Sprintf("manual-%d", time.Now().UnixNano()))
 
 	// Analyze the review
 	analysisResult, err := s.analyzer.Analyze(r.Context(), review)
@@ -448,16 +676,140 @@ func (s *Server) handleAnalyzeText(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// handleGetConfig gets configuration for a component
+// Reload records the hot-swappable sections of a reloaded configuration so the
+// config endpoints report what the components are running with, and applies
+// issued or revoked API keys. A section a component rejected should already
+// hold the component's current settings in cfg.
+func (s *Server) Reload(cfg *config.Config) error {
+	s.configMutex.Lock()
+	defer s.configMutex.Unlock()
+
+	s.appConfig.Scrapers = cfg.Scrapers
+	s.appConfig.Analyzer = cfg.Analyzer
+	s.appConfig.Router = cfg.Router
+	s.appConfig.API.APIKeys = cfg.API.APIKeys
+	s.credentials = buildCredentials(s.appConfig.API)
+	return nil
+}
+
+// handleGetConfig gets the redacted configuration for a component
 func (s *Server) handleGetConfig(w http.ResponseWriter, r *http.Request) {
-	// This would typically retrieve redacted configuration (no secrets)
-	s.respondError(w, r, http.StatusNotImplemented, "Feature not yet implemented")
+	component := chi.URLParam(r, "component")
+
+	s.configMutex.RLock()
+	componentConfig, exists := s.componentConfig(component)
+	s.configMutex.RUnlock()
+
+	if !exists {
+		s.respondError(w, r, http.StatusNotFound, fmt.Sprintf("Unknown config component: %s", component))
+		return
+	}
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    config.Redact(componentConfig),
+	})
 }
 
-// handleUpdateConfig updates configuration for a component
+// componentConfig returns the live configuration section for a component.
+// Callers must hold configMutex.
+func (s *Server) componentConfig(component string) (interface{}, bool) {
+	switch component {
+	case "scrapers":
+		return s.appConfig.Scrapers, true
+	case "analyzer":
+		return s.appConfig.Analyzer, true
+	case "router":
+		return s.appConfig.Router, true
+	case "notifier":
+		return s.appConfig.Notifier, true
+	case "api":
+		return s.appConfig.API, true
+	default:
+		return nil, false
+	}
+}
+
+// handleUpdateConfig validates a component's configuration and applies it at runtime.
+// Secrets sent back as "***" (as returned by handleGetConfig) keep their current values.
 func (s *Server) handleUpdateConfig(w http.ResponseWriter, r *http.Request) {
-	// This would typically update configuration elements
-	s.respondError(w, r, http.StatusNotImplemented, "Feature not yet implemented")
+	component := chi.URLParam(r, "component")
+
+	s.configMutex.Lock()
+	defer s.configMutex.Unlock()
+
+	if _, exists := s.componentConfig(component); !exists {
+		s.respondError(w, r, http.StatusNotFound, fmt.Sprintf("Unknown config component: %s", component))
+		return
+	}
+
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+
+	var updated interface{}
+	switch component {
+	case "analyzer":
+		cfg, err := cloneConfig(s.appConfig.Analyzer)
+		if err != nil {
+			s.respondError(w, r, http.StatusInternalServerError, fmt.Sprintf("Failed to copy configuration: %v", err))
+			return
+		}
+		if err := decoder.Decode(&cfg); err != nil {
+			s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid request format: %v", err))
+			return
+		}
+		config.RestoreRedacted(&cfg, s.appConfig.Analyzer)
+		if err := cfg.Validate(); err != nil {
+			s.respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.analyzer.UpdateConfig(cfg)
+		s.appConfig.Analyzer = cfg
+		updated = cfg
+
+	case "router":
+		cfg, err := cloneConfig(s.appConfig.Router)
+		if err != nil {
+			s.respondError(w, r, http.StatusInternalServerError, fmt.Sprintf("Failed to copy configuration: %v", err))
+			return
+		}
+		if err := decoder.Decode(&cfg); err != nil {
+			s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid request format: %v", err))
+			return
+		}
+		if err := cfg.Validate(); err != nil {
+			s.respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.deptRouter.UpdateConfig(cfg)
+		s.appConfig.Router = cfg
+		updated = cfg
+
+	default:
+		s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Config component %s cannot be updated at runtime", component))
+		return
+	}
+
+	log.Printf("Updated %s configuration via API", component)
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("%s configuration updated", component),
+		Data:    config.Redact(updated),
+	})
+}
+
+// cloneConfig deep copies a config section through JSON. An update is decoded
+// over the copy, since decoding over the section itself would write into the
+// slices and maps the running components still read.
+func cloneConfig[T any](cfg T) (T, error) {
+	var clone T
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		return clone, err
+	}
+	err = json.Unmarshal(data, &clone)
+	return clone, err
 }
 
 // handleSwagger serves Swagger UI documentation
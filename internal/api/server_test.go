@@ -0,0 +1,467 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
+	"github.com/Infoblox-CTO/review-scraper/internal/buildinfo"
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/metrics"
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/Infoblox-CTO/review-scraper/internal/router"
+	"github.com/Infoblox-CTO/review-scraper/internal/scraper"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testAuthToken = "test-token"
+
+// newTestServer builds a Server wired to real components from cfg, which
+// must describe scrapers that can be built
+func newTestServer(cfg *config.Config) *Server {
+	if cfg.API.AuthToken == "" {
+		cfg.API.AuthToken = testAuthToken
+	}
+	manager, err := scraper.NewManager(cfg.Scrapers)
+	if err != nil {
+		panic(err)
+	}
+	return NewServer(cfg,
+		manager,
+		analyzer.New(cfg.Analyzer),
+		router.New(cfg.Router),
+		notifier.New(cfg.Notifier),
+	)
+}
+
+// doRequest sends an authenticated request through the server's router
+func doRequest(s *Server, method, path string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	req.Header.Set("Authorization", "Bearer "+testAuthToken)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeResponse parses an APIResponse, re-decoding Data into target when given
+func decodeResponse(t *testing.T, rec *httptest.ResponseRecorder, target interface{}) models.APIResponse {
+	var raw struct {
+		models.APIResponse
+		Data json.RawMessage `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &raw))
+	if target != nil {
+		assert.NoError(t, json.Unmarshal(raw.Data, target))
+	}
+	return raw.APIResponse
+}
+
+func TestHandleGetConfigRedactsSecrets(t *testing.T) {
+	s := newTestServer(&config.Config{
+		Analyzer: config.AnalyzerConfig{Mode: "openai", APIKey: "sk-live", NegativeThreshold: -0.3},
+		Notifier: config.NotifierConfig{
+			Slack: config.SlackConfig{Enabled: true, WebhookURL: "https://hooks.slack.com/services/secret"},
+		},
+	})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/config/analyzer", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var analyzerCfg config.AnalyzerConfig
+	resp := decodeResponse(t, rec, &analyzerCfg)
+	assert.True(t, resp.Success)
+	assert.Equal(t, "openai", analyzerCfg.Mode)
+	assert.Equal(t, -0.3, analyzerCfg.NegativeThreshold)
+	assert.Equal(t, config.RedactedValue, analyzerCfg.APIKey)
+
+	rec = doRequest(s, http.MethodGet, "/api/v1/config/notifier", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var notifierCfg config.NotifierConfig
+	decodeResponse(t, rec, &notifierCfg)
+	assert.True(t, notifierCfg.Slack.Enabled)
+	assert.Equal(t, config.RedactedValue, notifierCfg.Slack.WebhookURL)
+
+	rec = doRequest(s, http.MethodGet, "/api/v1/config/api", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var apiCfg config.APIConfig
+	decodeResponse(t, rec, &apiCfg)
+	assert.Equal(t, config.RedactedValue, apiCfg.AuthToken)
+}
+
+func TestHandleGetConfigUnknownComponent(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/config/database", nil)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	resp := decodeResponse(t, rec, nil)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "database")
+}
+
+func TestHandleUpdateConfigAppliesAnalyzerThresholds(t *testing.T) {
+	s := newTestServer(&config.Config{
+		Analyzer: config.AnalyzerConfig{Mode: "local", APIKey: "sk-keep", NegativeThreshold: -0.5, RelevanceThreshold: 0.3},
+	})
+	review := models.Review{ID: "r1", Content: "Great DNS features but the upgrade is slow and has a bug"}
+
+	// With a -0.5 threshold this mildly negative review is not flagged
+	before, err := s.analyzer.Analyze(context.Background(), review)
+	assert.NoError(t, err)
+	assert.False(t, before.IsNegative)
+
+	body := `{"negativeThreshold": 0.0, "apiKey": "***"}`
+	rec := doRequest(s, http.MethodPut, "/api/v1/config/analyzer", strings.NewReader(body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var updated config.AnalyzerConfig
+	resp := decodeResponse(t, rec, &updated)
+	assert.True(t, resp.Success)
+	assert.Equal(t, 0.0, updated.NegativeThreshold)
+	assert.Equal(t, "local", updated.Mode, "fields absent from the body are left unchanged")
+
+	// The analyzer picks up the new threshold without a restart
+	after, err := s.analyzer.Analyze(context.Background(), review)
+	assert.NoError(t, err)
+	assert.True(t, after.IsNegative)
+
+	// The redacted secret placeholder does not overwrite the real key
+	assert.Equal(t, "sk-keep", s.analyzer.Config().APIKey)
+	assert.Equal(t, 0.0, s.appConfig.Analyzer.NegativeThreshold)
+}
+
+func TestHandleUpdateConfigRejectsInvalidAnalyzer(t *testing.T) {
+	s := newTestServer(&config.Config{
+		Analyzer: config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.3, RelevanceThreshold: 0.5},
+	})
+
+	body := `{"mode": "gpt", "relevanceThreshold": 1.5}`
+	rec := doRequest(s, http.MethodPut, "/api/v1/config/analyzer", strings.NewReader(body))
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	resp := decodeResponse(t, rec, nil)
+	assert.False(t, resp.Success)
+	assert.Contains(t, resp.Error, "analyzer.mode")
+	assert.Contains(t, resp.Error, "analyzer.relevanceThreshold")
+
+	// Nothing was applied
+	assert.Equal(t, "local", s.analyzer.Config().Mode)
+	assert.Equal(t, 0.5, s.appConfig.Analyzer.RelevanceThreshold)
+}
+
+func TestHandleUpdateConfigRejectsMalformedBody(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	rec := doRequest(s, http.MethodPut, "/api/v1/config/analyzer", strings.NewReader(`{"negativeTreshold": -0.5}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code, "unknown fields should be rejected")
+
+	rec = doRequest(s, http.MethodPut, "/api/v1/config/analyzer", strings.NewReader(`not json`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestHandleUpdateConfigAppliesRouterMappings(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	body := `{"mappings": [{"category": "bug_report", "department": "security", "priority": 10}], "defaultDepartment": "support"}`
+	rec := doRequest(s, http.MethodPut, "/api/v1/config/router", strings.NewReader(body))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	dept := s.deptRouter.Route(models.AnalysisResult{IntentCategory: "bug_report"})
+	assert.Equal(t, "security", dept.ID)
+}
+
+func TestHandleUpdateConfigRejectedRouterLeavesConfigUnchanged(t *testing.T) {
+	s := newTestServer(&config.Config{Router: config.RouterConfig{
+		Mappings:          []config.DepartmentMapping{{Category: "bug_report", Department: "engineering", Priority: 10}},
+		DefaultDepartment: "support",
+	}})
+	live := s.appConfig.Router.Mappings
+
+	body := `{"mappings": [{"category": "bug_report", "department": "security", "priority": 10}], "escalation": {"severityThreshold": "extreme"}}`
+	rec := doRequest(s, http.MethodPut, "/api/v1/config/router", strings.NewReader(body))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	assert.Equal(t, "engineering", live[0].Department)
+	assert.Equal(t, "engineering", s.appConfig.Router.Mappings[0].Department)
+	assert.Equal(t, "engineering", s.deptRouter.Route(models.AnalysisResult{IntentCategory: "bug_report"}).ID)
+}
+
+func TestSystemStatsIncludesRouter(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	s.deptRouter.Route(models.AnalysisResult{IntentCategory: "security"})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/dashboard/stats", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var resp struct {
+		Data struct {
+			Router struct {
+				RoutesTotal      int            `json:"routes_total"`
+				DepartmentRoutes map[string]int `json:"department_routes"`
+			} `json:"router"`
+		} `json:"data"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, 1, resp.Data.Router.RoutesTotal)
+	assert.Equal(t, map[string]int{"security": 1}, resp.Data.Router.DepartmentRoutes)
+}
+
+func TestHandleUpdateConfigRejectsStaticComponents(t *testing.T) {
+	s := newTestServer(&config.Config{API: config.APIConfig{Port: 8080}})
+
+	rec := doRequest(s, http.MethodPut, "/api/v1/config/api", strings.NewReader(`{"port": 9090}`))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, 8080, s.appConfig.API.Port)
+
+	rec = doRequest(s, http.MethodPut, "/api/v1/config/unknown", strings.NewReader(`{}`))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHealthCheckReportsDegradedNotifier(t *testing.T) {
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("invalid_token"))
+	}))
+	defer slack.Close()
+
+	s := newTestServer(&config.Config{
+		Notifier: config.NotifierConfig{Slack: config.SlackConfig{Enabled: true, WebhookURL: slack.URL}},
+	})
+	assert.Error(t, s.notifier.Probe(context.Background()))
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/health", nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var health struct {
+		Status   string                 `json:"status"`
+		Notifier []notifier.ProbeResult `json:"notifier"`
+	}
+	resp := decodeResponse(t, rec, &health)
+	assert.Contains(t, resp.Message, "degraded")
+	assert.Equal(t, "degraded", health.Status)
+	assert.Len(t, health.Notifier, 1)
+	assert.Equal(t, "slack", health.Notifier[0].Channel)
+	assert.False(t, health.Notifier[0].Healthy)
+	assert.Contains(t, health.Notifier[0].Error, "403")
+
+	// Anonymous callers see which channels are down, but not why
+	rec = httptest.NewRecorder()
+	s.router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	health.Notifier = nil
+	decodeResponse(t, rec, &health)
+	assert.Equal(t, "degraded", health.Status)
+	if assert.Len(t, health.Notifier, 1) {
+		assert.False(t, health.Notifier[0].Healthy)
+		assert.Empty(t, health.Notifier[0].Error)
+	}
+}
+
+func TestHealthCheckReportsBuildInfo(t *testing.T) {
+	defer func(version, commit, buildTime string) {
+		buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = version, commit, buildTime
+	}(buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime)
+	buildinfo.Version, buildinfo.Commit, buildinfo.BuildTime = "v1.4.0", "abc1234", "2025-04-01T12:00:00Z"
+
+	rec := doRequest(newTestServer(&config.Config{}), http.MethodGet, "/api/v1/health", nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var health struct {
+		Version   string `json:"version"`
+		Commit    string `json:"commit"`
+		BuildTime string `json:"buildTime"`
+	}
+	decodeResponse(t, rec, &health)
+	assert.Equal(t, "v1.4.0", health.Version)
+	assert.Equal(t, "abc1234", health.Commit)
+	assert.Equal(t, "2025-04-01T12:00:00Z", health.BuildTime)
+}
+
+func TestMetricsEndpointAfterPipelineRun(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+
+	s := newTestServer(&config.Config{
+		Scrapers: config.ScrapersConfig{Reddit: config.RedditScraperConfig{Enabled: true}},
+		Analyzer: config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.1},
+		Notifier: config.NotifierConfig{Webhook: config.WebhookConfig{Enabled: true, URL: hook.URL}},
+		API:      config.APIConfig{EnableMetrics: true},
+	})
+	m := metrics.New()
+	s.SetMetrics(m)
+	s.scraperManager.SetMetrics(m)
+	s.analyzer.SetMetrics(m)
+	s.notifier.SetMetrics(m)
+
+	// Simulate a pipeline run
+	ctx := context.Background()
+	_, err := s.scraperManager.ScrapeAll(ctx)
+	assert.NoError(t, err)
+	review := models.Review{ID: "r1", Source: "reddit", Content: "NIOS upgrade failed, the grid is broken and slow"}
+	result, err := s.analyzer.Analyze(ctx, review)
+	assert.NoError(t, err)
+	assert.True(t, result.IsNegative)
+	assert.NoError(t, s.notifier.Notify(ctx, s.deptRouter.Route(result), review, result))
+
+	// No auth header: metrics are served outside the authenticated API
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	for _, name := range []string{
+		`review_scraper_reviews_scraped_total{source="Reddit"} 0`,
+		`review_scraper_scraper_duration_seconds_count{source="Reddit"} 1`,
+		`review_scraper_reviews_analyzed_total{mode="local"} 1`,
+		`review_scraper_negative_reviews_total 1`,
+		`review_scraper_analyzer_duration_seconds_count{mode="local"} 1`,
+		`review_scraper_notifications_sent_total{channel="webhook"} 1`,
+	} {
+		assert.Contains(t, body, name)
+	}
+}
+
+func TestMetricsEndpointDisabled(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	s.SetMetrics(metrics.New())
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestDashboardMetricsReflectRecentReviews(t *testing.T) {
+	s := newTestServer(&config.Config{API: config.APIConfig{DashboardTrendDays: 2}})
+	now := time.Now()
+	s.AddRecentReview(models.AnalyzedReview{
+		Review:     models.Review{ID: "r1", Source: "twitter", CreatedAt: now},
+		Analysis:   &models.AnalysisResult{SentimentScore: -0.6, IsNegative: true, IntentCategory: "bug_report"},
+		Department: "engineering",
+	})
+	s.AddRecentReview(models.AnalyzedReview{
+		Review:   models.Review{ID: "r2", Source: "g2", CreatedAt: now},
+		Analysis: &models.AnalysisResult{SentimentScore: 0.2, IntentCategory: "praise"},
+	})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/dashboard/metrics", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var dashboard models.DashboardMetrics
+	assert.True(t, decodeResponse(t, rec, &dashboard).Success)
+	assert.Equal(t, 2, dashboard.TotalReviews)
+	assert.Equal(t, 1, dashboard.NegativeReviews)
+	assert.InDelta(t, -0.2, dashboard.AverageSentiment, 1e-9)
+	assert.Equal(t, map[string]int{"engineering": 1}, dashboard.DepartmentWorkloads)
+	assert.Equal(t, map[string]int{"twitter": 1, "g2": 1}, dashboard.SourceBreakdown)
+	assert.Len(t, dashboard.RecentTrends, 2)
+	assert.Equal(t, 2, dashboard.RecentTrends[1].ReviewCount)
+
+	// The window can be overridden per request
+	rec = doRequest(s, http.MethodGet, "/api/v1/dashboard/metrics?days=14", nil)
+	decodeResponse(t, rec, &dashboard)
+	assert.Len(t, dashboard.RecentTrends, 14)
+
+	rec = doRequest(s, http.MethodGet, "/api/v1/dashboard/metrics?days=0", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestProductSentimentRollsUpRecentReviews(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	now := time.Now()
+	for i, c := range []struct {
+		content string
+		score   float64
+	}{
+		{"NIOS grid upgrade failed", -0.8},
+		{"NIOS appliance has been solid", 0.4},
+		{"BloxOne DDI onboarding was painful", -0.6},
+	} {
+		s.AddRecentReview(models.AnalyzedReview{
+			Review:   models.Review{ID: fmt.Sprintf("r%d", i), Content: c.content, CreatedAt: now},
+			Analysis: &models.AnalysisResult{SentimentScore: c.score, IsNegative: c.score <= -0.3},
+		})
+	}
+	s.AddRecentReview(models.AnalyzedReview{
+		Review:   models.Review{ID: "old", Content: "BloxOne is great", CreatedAt: now.AddDate(0, 0, -30)},
+		Analysis: &models.AnalysisResult{SentimentScore: 0.9},
+	})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/dashboard/products", nil)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var rollup []models.ProductSentiment
+	assert.True(t, decodeResponse(t, rec, &rollup).Success)
+	if assert.Len(t, rollup, 2) {
+		assert.Equal(t, models.ProductSentiment{
+			Product: "bloxone", ReviewCount: 1, NegativeCount: 1, AverageSentiment: -0.6, NegativeShare: 1,
+		}, rollup[0])
+		assert.Equal(t, "nios", rollup[1].Product)
+		assert.Equal(t, 2, rollup[1].ReviewCount)
+		assert.InDelta(t, -0.2, rollup[1].AverageSentiment, 1e-9)
+		assert.Equal(t, 0.5, rollup[1].NegativeShare)
+	}
+
+	// A longer window takes in the older review
+	rec = doRequest(s, http.MethodGet, "/api/v1/dashboard/products?days=60", nil)
+	decodeResponse(t, rec, &rollup)
+	assert.Equal(t, "nios", rollup[0].Product)
+	assert.Equal(t, "bloxone", rollup[1].Product)
+
+	rec = doRequest(s, http.MethodGet, "/api/v1/dashboard/products?days=abc", nil)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// departmentIDs fetches the departments at path and returns their IDs
+func departmentIDs(t *testing.T, s *Server, path string) []string {
+	rec := doRequest(s, http.MethodGet, path, nil)
+	assert.Equal(t, http.StatusOK, rec.Code, path)
+
+	var departments []models.Department
+	decodeResponse(t, rec, &departments)
+	ids := make([]string, len(departments))
+	for i, dept := range departments {
+		ids[i] = dept.ID
+	}
+	return ids
+}
+
+func TestGetDepartmentsStableOrder(t *testing.T) {
+	s := newTestServer(&config.Config{Router: config.RouterConfig{
+		LanguageDepartments: map[string]string{"de": "support-emea", "ja": "support-apac", "fr": "support-fr"},
+	}})
+
+	all := departmentIDs(t, s, "/api/v1/departments")
+	assert.IsIncreasing(t, all)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, all, departmentIDs(t, s, "/api/v1/departments"))
+	}
+}
+
+func TestGetDepartmentsPagination(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	all := departmentIDs(t, s, "/api/v1/departments")
+	if !assert.Greater(t, len(all), 3) {
+		return
+	}
+
+	assert.Equal(t, all[:2], departmentIDs(t, s, "/api/v1/departments?limit=2"))
+	assert.Equal(t, all[2:4], departmentIDs(t, s, "/api/v1/departments?limit=2&offset=2"))
+	assert.Equal(t, all[1:], departmentIDs(t, s, "/api/v1/departments?offset=1"))
+	assert.Empty(t, departmentIDs(t, s, fmt.Sprintf("/api/v1/departments?offset=%d", len(all)+5)))
+
+	for _, query := range []string{"limit=0", "limit=abc", "offset=-1"} {
+		rec := doRequest(s, http.MethodGet, "/api/v1/departments?"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, query)
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/go-chi/chi/v5"
+)
+
+// minSimilarity is the lowest similarity a review needs to be listed as similar
+const minSimilarity = 0.25
+
+// similarityTerms returns the terms a review's analysis is compared on: its
+// keywords, its entities and its intent category, lower cased
+func similarityTerms(analysis *models.AnalysisResult) map[string]bool {
+	terms := make(map[string]bool)
+	if analysis == nil {
+		return terms
+	}
+	for _, keyword := range analysis.Keywords {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" {
+			terms[keyword] = true
+		}
+	}
+	for _, entity := range analysis.Entities {
+		if text := strings.ToLower(strings.TrimSpace(entity.Text)); text != "" {
+			terms[text] = true
+		}
+	}
+	if analysis.IntentCategory != "" {
+		terms["category:"+analysis.IntentCategory] = true
+	}
+	return terms
+}
+
+// cosineSimilarity returns the cosine similarity of two term sets, and the
+// terms they share in sorted order
+func cosineSimilarity(a, b map[string]bool) (float64, []string) {
+	if len(a) == 0 || len(b) == 0 {
+		return 0, nil
+	}
+	var shared []string
+	for term := range a {
+		if b[term] {
+			shared = append(shared, term)
+		}
+	}
+	sort.Strings(shared)
+	return float64(len(shared)) / math.Sqrt(float64(len(a)*len(b))), shared
+}
+
+// similarReviews ranks the entries other than target by the similarity of
+// their analysis to target's, most similar first, leaving out those below
+// minSimilarity. Ties go to the newer review.
+func similarReviews(target models.AnalyzedReview, entries []models.AnalyzedReview) []models.SimilarReview {
+	targetTerms := similarityTerms(target.Analysis)
+	similar := []models.SimilarReview{}
+	for _, entry := range entries {
+		if entry.Review.ID == target.Review.ID {
+			continue
+		}
+		score, shared := cosineSimilarity(targetTerms, similarityTerms(entry.Analysis))
+		if score < minSimilarity {
+			continue
+		}
+		similar = append(similar, models.SimilarReview{AnalyzedReview: entry, Similarity: score, Shared: shared})
+	}
+
+	sort.SliceStable(similar, func(i, j int) bool {
+		if similar[i].Similarity != similar[j].Similarity {
+			return similar[i].Similarity > similar[j].Similarity
+		}
+		return similar[i].Review.CreatedAt.After(similar[j].Review.CreatedAt)
+	})
+	return similar
+}
+
+// handleGetSimilarReviews lists the recent reviews whose keywords, entities
+// and category overlap most with a review's, optionally paged with the limit
+// and offset query parameters
+func (s *Server) handleGetSimilarReviews(w http.ResponseWriter, r *http.Request) {
+	limit, offset, ok := s.pageParams(w, r)
+	if !ok {
+		return
+	}
+
+	target, found := s.recentReview(chi.URLParam(r, "id"))
+	if !found {
+		s.respondError(w, r, http.StatusNotFound, "Review not found")
+		return
+	}
+
+	s.reviewsMutex.RLock()
+	entries := s.recentReviews
+	s.reviewsMutex.RUnlock()
+
+	similar := similarReviews(target, entries)
+	if offset > len(similar) {
+		offset = len(similar)
+	}
+	similar = similar[offset:]
+	if limit > 0 && limit < len(similar) {
+		similar = similar[:limit]
+	}
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Data:    similar,
+	})
+}
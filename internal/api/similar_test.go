@@ -0,0 +1,73 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// addAnalyzedReview stores a review with the given keywords and category
+func addAnalyzedReview(s *Server, id, category string, createdAt time.Time, keywords ...string) {
+	s.AddRecentReview(models.AnalyzedReview{
+		Review:   models.Review{ID: id, CreatedAt: createdAt},
+		Analysis: &models.AnalysisResult{ReviewID: id, Keywords: keywords, IntentCategory: category},
+	})
+}
+
+func TestSimilarReviewsRankedByOverlap(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	now := time.Now()
+	addAnalyzedReview(s, "target", "bug_report", now, "nios", "upgrade", "failed", "dhcp")
+	addAnalyzedReview(s, "close", "bug_report", now, "nios", "upgrade", "failed")
+	addAnalyzedReview(s, "partial", "bug_report", now, "upgrade", "pricing")
+	addAnalyzedReview(s, "unrelated", "praise", now, "great", "support")
+	s.AddRecentReview(models.AnalyzedReview{Review: models.Review{ID: "unanalyzed"}})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/reviews/target/similar", nil)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var similar []models.SimilarReview
+	assert.True(t, decodeResponse(t, rec, &similar).Success)
+	if assert.Len(t, similar, 2) {
+		assert.Equal(t, "close", similar[0].Review.ID)
+		assert.Equal(t, []string{"category:bug_report", "failed", "nios", "upgrade"}, similar[0].Shared)
+		assert.Equal(t, "partial", similar[1].Review.ID)
+		assert.Greater(t, similar[0].Similarity, similar[1].Similarity)
+	}
+
+	rec = doRequest(s, http.MethodGet, "/api/v1/reviews/target/similar?limit=1&offset=1", nil)
+	decodeResponse(t, rec, &similar)
+	if assert.Len(t, similar, 1) {
+		assert.Equal(t, "partial", similar[0].Review.ID)
+	}
+}
+
+func TestSimilarReviewsPreferNewerOnTies(t *testing.T) {
+	s := newTestServer(&config.Config{})
+	now := time.Now()
+	addAnalyzedReview(s, "target", "bug_report", now, "outage")
+	addAnalyzedReview(s, "newer", "bug_report", now, "outage")
+	addAnalyzedReview(s, "older", "bug_report", now.Add(-time.Hour), "outage")
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/reviews/target/similar", nil)
+
+	var similar []models.SimilarReview
+	decodeResponse(t, rec, &similar)
+	if assert.Len(t, similar, 2) {
+		assert.Equal(t, "newer", similar[0].Review.ID)
+		assert.Equal(t, "older", similar[1].Review.ID)
+	}
+}
+
+func TestSimilarReviewsUnknownReview(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	rec := doRequest(s, http.MethodGet, "/api/v1/reviews/missing/similar", nil)
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "Review not found", decodeResponse(t, rec, nil).Error)
+}
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// slackInteractionsPath receives Slack button clicks. Slack cannot send an API
+// token, so requests are authenticated by their Slack signature instead.
+const slackInteractionsPath = "/api/v1/slack/interactions"
+
+// maxSlackInteractionBytes caps the size of an interaction payload
+const maxSlackInteractionBytes = 1 << 20
+
+// handleSlackInteraction verifies a Slack interaction request and updates the
+// notifications for the review whose button was clicked. The request is
+// applied to each notifier, the top-level one and the tenants', whose Slack
+// signing secret it was signed with.
+func (s *Server) handleSlackInteraction(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlackInteractionBytes))
+	if err != nil {
+		s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Error reading request: %v", err))
+		return
+	}
+
+	now := time.Now()
+	var (
+		verified  []*notifier.Notifier
+		verifyErr error
+	)
+	for _, n := range append([]*notifier.Notifier{s.notifier}, s.tenantNotifiers...) {
+		if err := n.VerifySlackRequest(r.Header, body, now); err != nil {
+			if verifyErr == nil {
+				verifyErr = err
+			}
+			continue
+		}
+		verified = append(verified, n)
+	}
+	if len(verified) == 0 {
+		log.Printf("Rejected Slack interaction: %v", verifyErr)
+		s.respondError(w, r, http.StatusUnauthorized, "Invalid Slack signature")
+		return
+	}
+
+	interaction, err := notifier.ParseSlackInteraction(body)
+	if err != nil {
+		s.respondError(w, r, http.StatusBadRequest, fmt.Sprintf("Invalid Slack interaction: %v", err))
+		return
+	}
+
+	// The review's notifications are held by the notifier of its tenant
+	updated := 0
+	var notFound error
+	for _, n := range verified {
+		count, err := n.HandleSlackInteraction(interaction)
+		updated += count
+		if errors.Is(err, notifier.ErrNoNotifications) {
+			notFound = err
+		} else if err != nil {
+			s.respondError(w, r, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if updated == 0 && notFound != nil {
+		s.respondError(w, r, http.StatusNotFound, notFound.Error())
+		return
+	}
+
+	s.respond(w, r, http.StatusOK, models.APIResponse{
+		Success: true,
+		Message: fmt.Sprintf("%d notifications updated", updated),
+	})
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+const testSlackSigningSecret = "slack-signing-secret"
+
+// doSlackInteraction posts a click on actionID for reviewID, signed with secret
+// and without an API token, as Slack would send it
+func doSlackInteraction(s *Server, secret, actionID, reviewID string) *httptest.ResponseRecorder {
+	payload := fmt.Sprintf(`{"type": "block_actions", "user": {"id": "U123", "username": "oncall.pat"},
+		"actions": [{"action_id": %q, "value": %q}]}`, actionID, reviewID)
+	body := []byte(url.Values{"payload": {payload}}.Encode())
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/slack/interactions", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	req.Header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	rec := httptest.NewRecorder()
+	s.router.ServeHTTP(rec, req)
+	return rec
+}
+
+// newSlackTestServer returns a server whose notifier has sent one notification for reviewID
+func newSlackTestServer(t *testing.T, reviewID string) *Server {
+	s := newTestServer(&config.Config{
+		Notifier: config.NotifierConfig{Slack: config.SlackConfig{SigningSecret: testSlackSigningSecret}},
+	})
+	department := models.Department{ID: "support", Name: "Support"}
+	review := models.Review{ID: reviewID, Source: "twitter", Content: "DNS is down"}
+	assert.NoError(t, s.notifier.Notify(context.Background(), department, review, models.AnalysisResult{ReviewID: reviewID}))
+	return s
+}
+
+func TestSlackInteractionAcknowledgesReview(t *testing.T) {
+	s := newSlackTestServer(t, "review-1")
+
+	rec := doSlackInteraction(s, testSlackSigningSecret, notifier.SlackActionAcknowledge, "review-1")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, decodeResponse(t, rec, nil).Success)
+	notifications := s.notifier.GetNotifications("review-1")
+	if assert.Len(t, notifications, 1) {
+		assert.Equal(t, notifier.StatusAcknowledged, notifications[0].Status)
+		assert.Equal(t, "Acknowledged in Slack by oncall.pat", notifications[0].ResponseInfo)
+	}
+}
+
+func TestSlackInteractionRejectsBadSignature(t *testing.T) {
+	s := newSlackTestServer(t, "review-1")
+
+	rec := doSlackInteraction(s, "wrong-secret", notifier.SlackActionCreateTicket, "review-1")
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Equal(t, notifier.StatusSent, s.notifier.GetNotifications("review-1")[0].Status)
+}
+
+func TestSlackInteractionRejectedWithoutSigningSecret(t *testing.T) {
+	s := newTestServer(&config.Config{})
+
+	rec := doSlackInteraction(s, "", notifier.SlackActionAcknowledge, "review-1")
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestSlackInteractionForUnknownReview(t *testing.T) {
+	s := newSlackTestServer(t, "review-1")
+
+	rec := doSlackInteraction(s, testSlackSigningSecret, notifier.SlackActionAcknowledge, "review-2")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "no notifications for review review-2", decodeResponse(t, rec, nil).Error)
+}
+
+func TestSlackInteractionReachesTenantNotifier(t *testing.T) {
+	s := newSlackTestServer(t, "review-1")
+	tenant := notifier.New(config.NotifierConfig{Slack: config.SlackConfig{SigningSecret: "tenant-secret"}})
+	s.AddTenantNotifier(tenant)
+	department := models.Department{ID: "security", Name: "Security"}
+	review := models.Review{ID: "review-2", Source: "g2", Content: "Threat feeds are stale"}
+	assert.NoError(t, tenant.Notify(context.Background(), department, review, models.AnalysisResult{ReviewID: "review-2"}))
+
+	rec := doSlackInteraction(s, "tenant-secret", notifier.SlackActionAcknowledge, "review-2")
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, notifier.StatusAcknowledged, tenant.GetNotifications("review-2")[0].Status)
+
+	// A tenant's secret does not reach the notifications of other notifiers
+	rec = doSlackInteraction(s, "tenant-secret", notifier.SlackActionAcknowledge, "review-1")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, notifier.StatusSent, s.notifier.GetNotifications("review-1")[0].Status)
+}
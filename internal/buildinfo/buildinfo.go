@@ -0,0 +1,53 @@
+// Package buildinfo identifies the running build. Its variables are set at
+// link time, for example:
+//
+//	go build -ldflags "\
+//	  -X github.com/Infoblox-CTO/review-scraper/internal/buildinfo.Version=v1.4.0 \
+//	  -X github.com/Infoblox-CTO/review-scraper/internal/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/Infoblox-CTO/review-scraper/internal/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" \
+//	  ./cmd/review-scraper
+package buildinfo
+
+import "runtime/debug"
+
+// Injected with -ldflags -X; the defaults mark a development build
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+}
+
+// Get returns the build information. A commit or build time that was not
+// injected is taken from the version control details the Go toolchain embeds,
+// when there are any, and is otherwise "unknown".
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+
+	if info.Commit == "" || info.BuildTime == "" {
+		if build, ok := debug.ReadBuildInfo(); ok {
+			for _, setting := range build.Settings {
+				switch {
+				case setting.Key == "vcs.revision" && info.Commit == "":
+					info.Commit = setting.Value
+				case setting.Key == "vcs.time" && info.BuildTime == "":
+					info.BuildTime = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.Commit == "" {
+		info.Commit = "unknown"
+	}
+	if info.BuildTime == "" {
+		info.BuildTime = "unknown"
+	}
+	return info
+}
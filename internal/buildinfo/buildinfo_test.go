@@ -0,0 +1,23 @@
+package buildinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReturnsInjectedValues(t *testing.T) {
+	defer func(version, commit, buildTime string) {
+		Version, Commit, BuildTime = version, commit, buildTime
+	}(Version, Commit, BuildTime)
+
+	Version, Commit, BuildTime = "v1.4.0", "abc1234", "2025-04-01T12:00:00Z"
+	assert.Equal(t, Info{Version: "v1.4.0", Commit: "abc1234", BuildTime: "2025-04-01T12:00:00Z"}, Get())
+}
+
+func TestGetDefaults(t *testing.T) {
+	info := Get()
+	assert.Equal(t, "dev", info.Version)
+	assert.NotEmpty(t, info.Commit)
+	assert.NotEmpty(t, info.BuildTime)
+}
@@ -3,35 +3,143 @@ package config
 import (
 	"encoding/json"
 	"fmt"
+	"net"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
-// Config represents the main application configuration
+// Config represents the main application configuration.
+// Fields holding credentials are tagged `secret:"true"` so Redact masks them.
 type Config struct {
 	// General settings
-	ScrapingInterval time.Duration `json:"scrapingInterval"`
+	ScrapingInterval Duration `json:"scrapingInterval" yaml:"scrapingInterval" env:"SCRAPING_INTERVAL"`
+	LogLevel         string   `json:"logLevel" yaml:"logLevel" env:"LOG_LEVEL"`                      // debug, info, warn or error; defaults to info
+	PipelineWorkers  int      `json:"pipelineWorkers" yaml:"pipelineWorkers" env:"PIPELINE_WORKERS"` // Reviews analyzed, routed and notified at once; 0 means the default of 4
 
 	// Component-specific configurations
-	Scrapers ScrapersConfig `json:"scrapers"`
-	Analyzer AnalyzerConfig `json:"analyzer"`
-	Router   RouterConfig   `json:"router"`
-	Notifier NotifierConfig `json:"notifier"`
-	API      APIConfig      `json:"api"`
+	Scrapers ScrapersConfig `json:"scrapers" yaml:"scrapers"`
+	Analyzer AnalyzerConfig `json:"analyzer" yaml:"analyzer"`
+	Router   RouterConfig   `json:"router" yaml:"router"`
+	Notifier NotifierConfig `json:"notifier" yaml:"notifier"`
+	API      APIConfig      `json:"api" yaml:"api"`
+
+	// Warehouse exports analysis results for long-term analytics
+	Warehouse WarehouseConfig `json:"warehouse" yaml:"warehouse"`
+
+	// Tracing exports OpenTelemetry spans for pipeline runs
+	Tracing TracingConfig `json:"tracing" yaml:"tracing"`
+
+	// Storage remembers which reviews were notified, so a restart does not
+	// notify them again
+	Storage StorageConfig `json:"storage" yaml:"storage"`
+
+	// Tenants lets one deployment cover several product families, each with
+	// its own lexicon, routing and notification targets
+	Tenants []TenantConfig `json:"tenants" yaml:"tenants"`
+}
+
+// TenantConfig describes a product family processed with its own configuration.
+// A review belongs to the first tenant whose sources or keywords match it;
+// reviews matching no tenant use the top-level analyzer, router and notifier.
+type TenantConfig struct {
+	Name     string         `json:"name" yaml:"name"`
+	Sources  []string       `json:"sources" yaml:"sources"`   // Review sources owned by this tenant, e.g. "g2"
+	Keywords []string       `json:"keywords" yaml:"keywords"` // Terms in the title or content that identify this tenant
+	Analyzer AnalyzerConfig `json:"analyzer" yaml:"analyzer"`
+	Router   RouterConfig   `json:"router" yaml:"router"`
+	Notifier NotifierConfig `json:"notifier" yaml:"notifier"`
+}
+
+// WarehouseConfig contains settings for exporting analysis results to a SQL
+// data warehouse
+type WarehouseConfig struct {
+	Enabled     bool   `json:"enabled" yaml:"enabled" env:"WAREHOUSE_ENABLED"`
+	Driver      string `json:"driver" yaml:"driver" env:"WAREHOUSE_DRIVER"` // database/sql driver name, e.g. "postgres"
+	DSN         string `json:"dsn" yaml:"dsn" secret:"true" env:"WAREHOUSE_DSN"`
+	Table       string `json:"table" yaml:"table" env:"WAREHOUSE_TABLE"`
+	BatchSize   int    `json:"batchSize" yaml:"batchSize" env:"WAREHOUSE_BATCH_SIZE"`
+	Placeholder string `json:"placeholder" yaml:"placeholder" env:"WAREHOUSE_PLACEHOLDER"` // "?" (default) or "$" for numbered $1, $2 parameters
+}
+
+// TracingConfig contains OpenTelemetry tracing settings
+type TracingConfig struct {
+	Enabled     bool   `json:"enabled" yaml:"enabled" env:"TRACING_ENABLED"`
+	Endpoint    string `json:"endpoint" yaml:"endpoint" env:"TRACING_ENDPOINT"`           // OTLP/HTTP collector URL, e.g. http://localhost:4318
+	ServiceName string `json:"serviceName" yaml:"serviceName" env:"TRACING_SERVICE_NAME"` // Reported service name; defaults to review-scraper
+}
+
+// StorageConfig contains settings for the store of notified reviews
+type StorageConfig struct {
+	// Database keeps notified reviews across restarts; when it is disabled
+	// they are only remembered until the process exits
+	Database DatabaseConfig `json:"database" yaml:"database" envPrefix:"STORAGE_"`
+
+	// SeenTTL is how long a notified review is remembered; 0 means 30 days
+	SeenTTL Duration `json:"seenTTL" yaml:"seenTTL" env:"STORAGE_SEEN_TTL"`
 }
 
 // ScrapersConfig contains settings for all scrapers
 type ScrapersConfig struct {
-	Twitter       TwitterScraperConfig      `json:"twitter"`
-	Reddit        RedditScraperConfig       `json:"reddit"`
-	AppStore      AppStoreScraperConfig     `json:"appStore"`
-	GooglePlay    GooglePlayScraperConfig   `json:"googlePlay"`
-	G2            G2ScraperConfig           `json:"g2"`
-	Trustpilot    TrustpilotScraperConfig   `json:"trustpilot"`
-	CustomSites   []CustomSiteScraperConfig `json:"customSites"`
-	RateLimits    RateLimitConfig           `json:"rateLimits"`
-	ProxySettings ProxyConfig               `json:"proxySettings"`
+	Twitter       TwitterScraperConfig      `json:"twitter" yaml:"twitter"`
+	Reddit        RedditScraperConfig       `json:"reddit" yaml:"reddit"`
+	AppStore      AppStoreScraperConfig     `json:"appStore" yaml:"appStore"`
+	GooglePlay    GooglePlayScraperConfig   `json:"googlePlay" yaml:"googlePlay"`
+	G2            G2ScraperConfig           `json:"g2" yaml:"g2"`
+	Trustpilot    TrustpilotScraperConfig   `json:"trustpilot" yaml:"trustpilot"`
+	HackerNews    HackerNewsScraperConfig   `json:"hackerNews" yaml:"hackerNews"`
+	RSS           RSSScraperConfig          `json:"rss" yaml:"rss"`
+	YouTube       YouTubeScraperConfig      `json:"youTube" yaml:"youTube"`
+	CustomSites   []CustomSiteScraperConfig `json:"customSites" yaml:"customSites"`
+	RateLimits    RateLimitConfig           `json:"rateLimits" yaml:"rateLimits"`
+	ProxySettings ProxyConfig               `json:"proxySettings" yaml:"proxySettings"`
+
+	// SourceRateLimits replaces RateLimits for individual sources, keyed by
+	// the source's name in this config, such as twitter, g2 or hackerNews
+	SourceRateLimits map[string]RateLimitConfig `json:"sourceRateLimits" yaml:"sourceRateLimits"`
+
+	// Authors drops scraped reviews by bots, competitors or our own accounts
+	Authors AuthorFilterConfig `json:"authors" yaml:"authors"`
+
+	// PageCache revalidates unchanged pages instead of downloading and parsing them again
+	PageCache PageCacheConfig `json:"pageCache" yaml:"pageCache"`
+
+	// MaxReviewAge drops scraped reviews created longer ago, such as old
+	// reviews resurfacing when a scraper pages through history; 0 keeps all
+	MaxReviewAge Duration `json:"maxReviewAge" yaml:"maxReviewAge" env:"SCRAPER_MAX_REVIEW_AGE"`
+
+	// DropUndatedReviews drops reviews whose date could not be parsed when
+	// MaxReviewAge is set, instead of keeping them
+	DropUndatedReviews bool `json:"dropUndatedReviews" yaml:"dropUndatedReviews" env:"SCRAPER_DROP_UNDATED_REVIEWS"`
+}
+
+// RateLimitsFor returns the rate limits of source, falling back to the global ones
+func (c ScrapersConfig) RateLimitsFor(source string) RateLimitConfig {
+	if rates, ok := c.SourceRateLimits[source]; ok {
+		return rates
+	}
+	return c.RateLimits
+}
+
+// PageCacheConfig controls the cache of scraped pages. Cached pages are
+// requested with their ETag or Last-Modified validators, and a page the site
+// reports unchanged reuses its parsed reviews.
+type PageCacheConfig struct {
+	Enabled    bool     `json:"enabled" yaml:"enabled" env:"PAGE_CACHE_ENABLED"`
+	TTL        Duration `json:"ttl" yaml:"ttl" env:"PAGE_CACHE_TTL"`                       // How long a cached page is revalidated before it is fetched in full; 0 means 1 hour
+	MaxEntries int      `json:"maxEntries" yaml:"maxEntries" env:"PAGE_CACHE_MAX_ENTRIES"` // Pages cached per scraper; 0 means 500
+}
+
+// AuthorFilterConfig filters scraped reviews by author. Patterns match whole
+// author names, ignoring case, and may use * and ? wildcards.
+type AuthorFilterConfig struct {
+	Allow []string `json:"allow" yaml:"allow" env:"SCRAPER_AUTHORS_ALLOW"` // When set, only reviews by matching authors are kept
+	Deny  []string `json:"deny" yaml:"deny" env:"SCRAPER_AUTHORS_DENY"`    // Reviews by matching authors are dropped, even when allowed
 }
 
 // TwitterConfig is an alias for TwitterScraperConfig for backward compatibility
@@ -39,184 +147,392 @@ type TwitterConfig TwitterScraperConfig
 
 // TwitterScraperConfig contains Twitter-specific scraper settings
 type TwitterScraperConfig struct {
-	Enabled      bool     `json:"enabled"`
-	APIKey       string   `json:"apiKey"`
-	APISecret    string   `json:"apiSecret"`
-	AccessToken  string   `json:"accessToken"`
-	AccessSecret string   `json:"accessSecret"`
-	Keywords     []string `json:"keywords"`
-	ExcludeWords []string `json:"excludeWords"`
-	MaxResults   int      `json:"maxResults"`
+	Enabled      bool     `json:"enabled" yaml:"enabled" env:"TWITTER_ENABLED"`
+	APIKey       string   `json:"apiKey" yaml:"apiKey" secret:"true" env:"TWITTER_API_KEY"`
+	APISecret    string   `json:"apiSecret" yaml:"apiSecret" secret:"true" env:"TWITTER_API_SECRET"`
+	AccessToken  string   `json:"accessToken" yaml:"accessToken" secret:"true" env:"TWITTER_ACCESS_TOKEN"`
+	AccessSecret string   `json:"accessSecret" yaml:"accessSecret" secret:"true" env:"TWITTER_ACCESS_SECRET"`
+	Keywords     []string `json:"keywords" yaml:"keywords" env:"TWITTER_KEYWORDS"`
+	ExcludeWords []string `json:"excludeWords" yaml:"excludeWords" env:"TWITTER_EXCLUDE_WORDS"`
+	MaxResults   int      `json:"maxResults" yaml:"maxResults" env:"TWITTER_MAX_RESULTS"`
 }
 
 // RedditScraperConfig contains Reddit-specific scraper settings
 type RedditScraperConfig struct {
-	Enabled      bool     `json:"enabled"`
-	ClientID     string   `json:"clientId"`
-	ClientSecret string   `json:"clientSecret"`
-	Username     string   `json:"username"`
-	Password     string   `json:"password"`
-	Subreddits   []string `json:"subreddits"`
-	Keywords     []string `json:"keywords"`
-	TimeFrame    string   `json:"timeFrame"`
+	Enabled      bool     `json:"enabled" yaml:"enabled" env:"REDDIT_ENABLED"`
+	ClientID     string   `json:"clientId" yaml:"clientId" env:"REDDIT_CLIENT_ID"`
+	ClientSecret string   `json:"clientSecret" yaml:"clientSecret" secret:"true" env:"REDDIT_CLIENT_SECRET"`
+	Username     string   `json:"username" yaml:"username" env:"REDDIT_USERNAME"`
+	Password     string   `json:"password" yaml:"password" secret:"true" env:"REDDIT_PASSWORD"`
+	Subreddits   []string `json:"subreddits" yaml:"subreddits" env:"REDDIT_SUBREDDITS"`
+	Keywords     []string `json:"keywords" yaml:"keywords" env:"REDDIT_KEYWORDS"`
+	TimeFrame    string   `json:"timeFrame" yaml:"timeFrame" env:"REDDIT_TIME_FRAME"`
 }
 
 // AppStoreScraperConfig contains App Store scraper settings
 type AppStoreScraperConfig struct {
-	Enabled   bool     `json:"enabled"`
-	AppIDs    []string `json:"appIds"`
-	Countries []string `json:"countries"`
-	MaxPages  int      `json:"maxPages"`
+	Enabled   bool     `json:"enabled" yaml:"enabled" env:"APPSTORE_ENABLED"`
+	AppIDs    []string `json:"appIds" yaml:"appIds" env:"APPSTORE_APP_IDS"`
+	Countries []string `json:"countries" yaml:"countries" env:"APPSTORE_COUNTRIES"`
+	MaxPages  int      `json:"maxPages" yaml:"maxPages" env:"APPSTORE_MAX_PAGES"`
+	SortModes []string `json:"sortModes" yaml:"sortModes" env:"APPSTORE_SORT_MODES"` // Review feed orders to read; defaults to mostrecent
 }
 
 // GooglePlayScraperConfig contains Google Play Store scraper settings
 type GooglePlayScraperConfig struct {
-	Enabled   bool     `json:"enabled"`
-	AppIDs    []string `json:"appIds"`
-	Countries []string `json:"countries"`
-	MaxPages  int      `json:"maxPages"`
+	Enabled   bool     `json:"enabled" yaml:"enabled" env:"GOOGLEPLAY_ENABLED"`
+	AppIDs    []string `json:"appIds" yaml:"appIds" env:"GOOGLEPLAY_APP_IDS"`
+	Countries []string `json:"countries" yaml:"countries" env:"GOOGLEPLAY_COUNTRIES"`
+	MaxPages  int      `json:"maxPages" yaml:"maxPages" env:"GOOGLEPLAY_MAX_PAGES"`
 }
 
 // G2ScraperConfig contains G2 review site scraper settings
 type G2ScraperConfig struct {
-	Enabled   bool   `json:"enabled"`
-	ProductID string `json:"product_id"`
-	MaxPages  int    `json:"maxPages"`
+	Enabled   bool   `json:"enabled" yaml:"enabled" env:"G2_ENABLED"`
+	ProductID string `json:"product_id" yaml:"product_id" env:"G2_PRODUCT_ID"`
+	APIKey    string `json:"apiKey" yaml:"apiKey" secret:"true" env:"G2_API_KEY"` // RapidAPI key for the G2 reviews API
+	MaxPages  int    `json:"maxPages" yaml:"maxPages" env:"G2_MAX_PAGES"`
 }
 
 // TrustpilotScraperConfig contains Trustpilot scraper settings
 type TrustpilotScraperConfig struct {
-	Enabled    bool   `json:"enabled"`
-	BusinessID string `json:"business_id"`
-	MaxPages   int    `json:"maxPages"`
+	Enabled    bool   `json:"enabled" yaml:"enabled" env:"TRUSTPILOT_ENABLED"`
+	BusinessID string `json:"business_id" yaml:"business_id" env:"TRUSTPILOT_BUSINESS_ID"`
+	MaxPages   int    `json:"maxPages" yaml:"maxPages" env:"TRUSTPILOT_MAX_PAGES"`
+	// PageConcurrency is how many pages are fetched at once; 0 or 1 fetches
+	// them one after another
+	PageConcurrency int `json:"pageConcurrency" yaml:"pageConcurrency" env:"TRUSTPILOT_PAGE_CONCURRENCY"`
+}
+
+// HackerNewsScraperConfig contains Hacker News scraper settings
+type HackerNewsScraperConfig struct {
+	Enabled     bool     `json:"enabled" yaml:"enabled" env:"HACKERNEWS_ENABLED"`
+	Keywords    []string `json:"keywords" yaml:"keywords" env:"HACKERNEWS_KEYWORDS"`
+	HitsPerPage int      `json:"hitsPerPage" yaml:"hitsPerPage" env:"HACKERNEWS_HITS_PER_PAGE"` // Stories and comments per search page; 0 means 50
+	MaxPages    int      `json:"maxPages" yaml:"maxPages" env:"HACKERNEWS_MAX_PAGES"`
+}
+
+// RSSScraperConfig contains RSS and Atom feed scraper settings
+type RSSScraperConfig struct {
+	Enabled  bool     `json:"enabled" yaml:"enabled" env:"RSS_ENABLED"`
+	Feeds    []string `json:"feeds" yaml:"feeds" env:"RSS_FEEDS"`          // RSS or Atom feed URLs
+	Keywords []string `json:"keywords" yaml:"keywords" env:"RSS_KEYWORDS"` // Items mentioning none of these are skipped
+}
+
+// YouTubeScraperConfig contains YouTube comment scraper settings. Comments are
+// read from VideoIDs and from the videos a search of ChannelID and/or
+// SearchQuery finds.
+type YouTubeScraperConfig struct {
+	Enabled     bool     `json:"enabled" yaml:"enabled" env:"YOUTUBE_ENABLED"`
+	APIKey      string   `json:"apiKey" yaml:"apiKey" secret:"true" env:"YOUTUBE_API_KEY"` // YouTube Data API v3 key
+	VideoIDs    []string `json:"videoIds" yaml:"videoIds" env:"YOUTUBE_VIDEO_IDS"`
+	ChannelID   string   `json:"channelId" yaml:"channelId" env:"YOUTUBE_CHANNEL_ID"`
+	SearchQuery string   `json:"searchQuery" yaml:"searchQuery" env:"YOUTUBE_SEARCH_QUERY"`
+	MaxVideos   int      `json:"maxVideos" yaml:"maxVideos" env:"YOUTUBE_MAX_VIDEOS"` // Videos taken from the search, newest first; 0 means 10
+	MaxPages    int      `json:"maxPages" yaml:"maxPages" env:"YOUTUBE_MAX_PAGES"`    // Comment pages read per video; 0 means 1
 }
 
 // CustomSiteScraperConfig contains settings for custom website scrapers
 type CustomSiteScraperConfig struct {
-	Enabled      bool     `json:"enabled"`
-	Name         string   `json:"name"`
-	URL          string   `json:"url"`
-	ReviewURLs   []string `json:"reviewUrls"`
-	ReviewXPaths []string `json:"reviewXPaths"`
-	DateXPath    string   `json:"dateXPath"`
-	AuthorXPath  string   `json:"authorXPath"`
-	RatingXPath  string   `json:"ratingXPath"`
+	Enabled      bool     `json:"enabled" yaml:"enabled"`
+	Name         string   `json:"name" yaml:"name"`
+	URL          string   `json:"url" yaml:"url"`
+	ReviewURLs   []string `json:"reviewUrls" yaml:"reviewUrls"`
+	ReviewXPaths []string `json:"reviewXPaths" yaml:"reviewXPaths"`
+	DateXPath    string   `json:"dateXPath" yaml:"dateXPath"`
+	AuthorXPath  string   `json:"authorXPath" yaml:"authorXPath"`
+	RatingXPath  string   `json:"ratingXPath" yaml:"ratingXPath"`
 }
 
 // RateLimitConfig contains settings for rate limiting
 type RateLimitConfig struct {
-	RequestsPerMinute    int           `json:"requestsPerMinute"`
-	PauseAfterRequests   int           `json:"pauseAfterRequests"`
-	PauseDuration        time.Duration `json:"pauseDuration"`
-	PauseBetweenRequests bool          `json:"pauseBetweenRequests"`
-	RandomizeUserAgents  bool          `json:"randomizeUserAgents"`
-	RandomizePauseTimes  bool          `json:"randomizePauseTimes"`
+	RequestsPerMinute    int      `json:"requestsPerMinute" yaml:"requestsPerMinute" env:"SCRAPER_REQUESTS_PER_MINUTE"`
+	PauseAfterRequests   int      `json:"pauseAfterRequests" yaml:"pauseAfterRequests" env:"SCRAPER_PAUSE_AFTER_REQUESTS"`
+	PauseDuration        Duration `json:"pauseDuration" yaml:"pauseDuration" env:"SCRAPER_PAUSE_DURATION"`
+	PauseBetweenRequests bool     `json:"pauseBetweenRequests" yaml:"pauseBetweenRequests" env:"SCRAPER_PAUSE_BETWEEN_REQUESTS"`
+	RandomizeUserAgents  bool     `json:"randomizeUserAgents" yaml:"randomizeUserAgents" env:"SCRAPER_RANDOMIZE_USER_AGENTS"`
+	RandomizePauseTimes  bool     `json:"randomizePauseTimes" yaml:"randomizePauseTimes" env:"SCRAPER_RANDOMIZE_PAUSE_TIMES"`
+	ScrapeTimeout        Duration `json:"scrapeTimeout" yaml:"scrapeTimeout" env:"SCRAPER_TIMEOUT"` // Deadline for each scraper's run; 0 means 10 minutes
 }
 
 // ProxyConfig contains proxy settings for scrapers
 type ProxyConfig struct {
-	Enabled  bool     `json:"enabled"`
-	URL      string   `json:"url"`
-	URLs     []string `json:"urls"`
-	Username string   `json:"username"`
-	Password string   `json:"password"`
-	Rotate   bool     `json:"rotate"`
+	Enabled  bool     `json:"enabled" yaml:"enabled" env:"PROXY_ENABLED"`
+	URL      string   `json:"url" yaml:"url" secret:"true" env:"PROXY_URL"` // May embed proxy credentials
+	URLs     []string `json:"urls" yaml:"urls" secret:"true" env:"PROXY_URLS"`
+	Username string   `json:"username" yaml:"username" env:"PROXY_USERNAME"`
+	Password string   `json:"password" yaml:"password" secret:"true" env:"PROXY_PASSWORD"`
+	Rotate   bool     `json:"rotate" yaml:"rotate" env:"PROXY_ROTATE"`
 }
 
 // AnalyzerConfig contains settings for the sentiment and intent analyzer
 type AnalyzerConfig struct {
-	Mode               string   `json:"mode"` // local, openai, google, aws, or azure
-	ModelEndpoint      string   `json:"modelEndpoint"`
-	APIKey             string   `json:"apiKey"`
-	NegativeThreshold  float64  `json:"negativeThreshold"`
-	RelevanceThreshold float64  `json:"relevanceThreshold"`
-	Keywords           []string `json:"keywords"`
-	IntentCategories   []string `json:"intentCategories"`
+	Mode                     string                 `json:"mode" yaml:"mode" env:"ANALYZER_MODE"` // local, openai, google, aws, or azure
+	ModelEndpoint            string                 `json:"modelEndpoint" yaml:"modelEndpoint" env:"ANALYZER_MODEL_ENDPOINT"`
+	APIKey                   string                 `json:"apiKey" yaml:"apiKey" secret:"true" env:"ANALYZER_API_KEY"`
+	NegativeThreshold        float64                `json:"negativeThreshold" yaml:"negativeThreshold" env:"ANALYZER_NEGATIVE_THRESHOLD"`
+	RelevanceThreshold       float64                `json:"relevanceThreshold" yaml:"relevanceThreshold" env:"ANALYZER_RELEVANCE_THRESHOLD"`
+	Keywords                 []string               `json:"keywords" yaml:"keywords" env:"ANALYZER_KEYWORDS"`
+	IntentCategories         []string               `json:"intentCategories" yaml:"intentCategories" env:"ANALYZER_INTENT_CATEGORIES"`
+	CategoryKeywords         map[string][]string    `json:"categoryKeywords" yaml:"categoryKeywords"`                                                           // Intent category to the keywords that indicate it, merged over the built-in categories
+	ReplaceDefaultCategories bool                   `json:"replaceDefaultCategories" yaml:"replaceDefaultCategories" env:"ANALYZER_REPLACE_DEFAULT_CATEGORIES"` // Use only CategoryKeywords, dropping the built-in categories
+	PromptMetadata           []string               `json:"promptMetadata" yaml:"promptMetadata" env:"ANALYZER_PROMPT_METADATA"`                                // Review fields added to remote prompts: title, rating, source, tags
+	AutoTags                 []string               `json:"autoTags" yaml:"autoTags" env:"ANALYZER_AUTO_TAGS"`                                                  // Tag families added to analyzed reviews: sentiment, intent, products, needs-action
+	MaxConcurrentRequests    int                    `json:"maxConcurrentRequests" yaml:"maxConcurrentRequests" env:"ANALYZER_MAX_CONCURRENT_REQUESTS"`          // Remote analysis requests allowed in flight at once; 0 means unlimited
+	CacheSize                int                    `json:"cacheSize" yaml:"cacheSize" env:"ANALYZER_CACHE_SIZE"`                                               // Analyses kept in the LRU result cache; 0 means the default of 10000
+	PositiveWords            []string               `json:"positiveWords" yaml:"positiveWords" env:"ANALYZER_POSITIVE_WORDS"`                                   // Local-mode positive sentiment words; empty means the built-in list
+	NegativeWords            []string               `json:"negativeWords" yaml:"negativeWords" env:"ANALYZER_NEGATIVE_WORDS"`                                   // Local-mode negative sentiment words; empty means the built-in list
+	SentimentWeights         map[string]float64     `json:"sentimentWeights" yaml:"sentimentWeights"`                                                           // How strongly a sentiment word counts; unlisted words count 1 and 0 ignores a word
+	DiscountVendorReplies    bool                   `json:"discountVendorReplies" yaml:"discountVendorReplies" env:"ANALYZER_DISCOUNT_VENDOR_REPLIES"`          // Lower the severity of reviews the vendor has already replied to by one tier
+	FallbackToLocal          bool                   `json:"fallbackToLocal" yaml:"fallbackToLocal" env:"ANALYZER_FALLBACK_TO_LOCAL"`                            // Analyze locally, at reduced confidence, when the remote backend fails
+	RatingWeight             *float64               `json:"ratingWeight,omitempty" yaml:"ratingWeight,omitempty" env:"ANALYZER_RATING_WEIGHT"`                  // Share of a rated review's local sentiment taken from its rating, 0 to 1; unset means 0.4
+	RatingScale              RatingScale            `json:"ratingScale" yaml:"ratingScale" envPrefix:"ANALYZER_RATING_SCALE_"`                                  // Scale review ratings are on; unset means 1 to 5 stars
+	SourceRatingScales       map[string]RatingScale `json:"sourceRatingScales" yaml:"sourceRatingScales"`                                                       // Rating scale by review source, overriding RatingScale
+}
+
+// RatingScale is the range a source's review ratings span, such as 1 to 10,
+// or 0 to 1 for thumbs down and up
+type RatingScale struct {
+	Min float64 `json:"min" yaml:"min" env:"MIN"`
+	Max float64 `json:"max" yaml:"max" env:"MAX"`
 }
 
 // RouterConfig contains settings for the department router
 type RouterConfig struct {
-	Mappings          []DepartmentMapping `json:"mappings"`
-	DefaultDepartment string              `json:"defaultDepartment"`
+	Mappings          []DepartmentMapping `json:"mappings" yaml:"mappings"`
+	DefaultDepartment string              `json:"defaultDepartment" yaml:"defaultDepartment" env:"ROUTER_DEFAULT_DEPARTMENT"`
+
+	// LanguageDepartments routes non-English reviews by language code, e.g.
+	// "de" -> "support-emea", ahead of category-based routing
+	LanguageDepartments map[string]string `json:"languageDepartments" yaml:"languageDepartments"`
+
+	// Escalation notifies more departments about severe reviews, on top of
+	// the department they are routed to
+	Escalation EscalationConfig `json:"escalation" yaml:"escalation"`
+}
+
+// EscalationConfig adds departments to the targets of severe reviews
+type EscalationConfig struct {
+	SeverityThreshold string   `json:"severityThreshold" yaml:"severityThreshold" env:"ROUTER_ESCALATION_SEVERITY_THRESHOLD"` // low, medium or high; empty disables escalation
+	Departments       []string `json:"departments" yaml:"departments" env:"ROUTER_ESCALATION_DEPARTMENTS"`                    // Department IDs notified as well, such as "management"
 }
 
 // DepartmentMapping maps a category to a department
 type DepartmentMapping struct {
-	Category   string `json:"category"`
-	Department string `json:"department"`
-	Priority   int    `json:"priority"`
+	Category   string `json:"category" yaml:"category"`
+	Department string `json:"department" yaml:"department"`
+	Priority   int    `json:"priority" yaml:"priority"`
 }
 
 // NotifierConfig contains settings for notifications
 type NotifierConfig struct {
-	Email     EmailConfig     `json:"email"`
-	Slack     SlackConfig     `json:"slack"`
-	Dashboard DashboardConfig `json:"dashboard"`
-	Databases DatabaseConfig  `json:"databases"`
+	Email     EmailConfig     `json:"email" yaml:"email"`
+	Slack     SlackConfig     `json:"slack" yaml:"slack"`
+	Webhook   WebhookConfig   `json:"webhook" yaml:"webhook"`
+	Dashboard DashboardConfig `json:"dashboard" yaml:"dashboard"`
+	Databases DatabaseConfig  `json:"databases" yaml:"databases"`
+
+	// MaxSendsPerMinute caps outbound chat/webhook sends; 0 disables throttling
+	MaxSendsPerMinute int `json:"maxSendsPerMinute" yaml:"maxSendsPerMinute" env:"NOTIFIER_MAX_SENDS_PER_MINUTE"`
+
+	// SkipResolvedReviews suppresses notifications for reviews already actioned
+	// by a department, including before a restart when a review store is
+	// configured, or marked resolved in a vendor reply
+	SkipResolvedReviews bool `json:"skipResolvedReviews" yaml:"skipResolvedReviews" env:"NOTIFIER_SKIP_RESOLVED_REVIEWS"`
+
+	// SeverityThreshold is the lowest analysis severity (low, medium or high)
+	// that is notified; empty notifies every routed review. Reviews below it
+	// are still analyzed and shown on the dashboard.
+	// DepartmentSeverityThresholds overrides it by department ID.
+	SeverityThreshold            string            `json:"severityThreshold" yaml:"severityThreshold" env:"NOTIFIER_SEVERITY_THRESHOLD"`
+	DepartmentSeverityThresholds map[string]string `json:"departmentSeverityThresholds" yaml:"departmentSeverityThresholds"`
+
+	// DedupWindow suppresses repeat notifications for a review notified
+	// within it, such as one scraped again by an overlapping run; 0 disables
+	DedupWindow Duration `json:"dedupWindow" yaml:"dedupWindow" env:"NOTIFIER_DEDUP_WINDOW"`
+
+	// MaxContentLength caps the review text, in characters, shown in Slack
+	// messages and emails; longer reviews are cut at a word boundary and link
+	// to the full review. 0 uses a default of 2000.
+	MaxContentLength int `json:"maxContentLength" yaml:"maxContentLength" env:"NOTIFIER_MAX_CONTENT_LENGTH"`
+
+	// DepartmentTemplates overrides the wording of notifications by
+	// department ID; departments without one get the default wording
+	DepartmentTemplates map[string]NotificationTemplate `json:"departmentTemplates" yaml:"departmentTemplates"`
+
+	// DryRun makes Notify log what each enabled channel would send instead of
+	// sending it; notifications are still cached and counted, but not stored
+	// in the notification database
+	DryRun bool `json:"dryRun" yaml:"dryRun" env:"NOTIFIER_DRY_RUN"`
+
+	// ProbeOnStartup checks SMTP and Slack connectivity when the service starts;
+	// FailOnProbeError aborts startup on failure instead of running degraded
+	ProbeOnStartup   bool `json:"probeOnStartup" yaml:"probeOnStartup" env:"NOTIFIER_PROBE_ON_STARTUP"`
+	FailOnProbeError bool `json:"failOnProbeError" yaml:"failOnProbeError" env:"NOTIFIER_FAIL_ON_PROBE_ERROR"`
+}
+
+// NotificationTemplate holds Go text/templates for a department's
+// notifications, each executed with the models.Notification being sent.
+// Empty fields keep the default wording.
+type NotificationTemplate struct {
+	Subject string `json:"subject" yaml:"subject"` // Email subject line
+	Email   string `json:"email" yaml:"email"`     // Email body
+	Slack   string `json:"slack" yaml:"slack"`     // Slack message text, replacing the default layout
 }
 
 // EmailConfig contains email notification settings
 type EmailConfig struct {
-	Enabled       bool              `json:"enabled"`
-	SMTPServer    string            `json:"smtpServer"`
-	SMTPPort      int               `json:"smtpPort"`
-	Username      string            `json:"username"`
-	Password      string            `json:"password"`
-	FromAddress   string            `json:"fromAddress"`
-	DeptAddresses map[string]string `json:"departmentAddresses"`
+	Enabled       bool              `json:"enabled" yaml:"enabled" env:"EMAIL_ENABLED"`
+	SMTPServer    string            `json:"smtpServer" yaml:"smtpServer" env:"SMTP_SERVER"`
+	SMTPPort      int               `json:"smtpPort" yaml:"smtpPort" env:"SMTP_PORT"`
+	Username      string            `json:"username" yaml:"username" env:"SMTP_USERNAME"`
+	Password      string            `json:"password" yaml:"password" secret:"true" env:"SMTP_PASSWORD"`
+	FromAddress   string            `json:"fromAddress" yaml:"fromAddress" env:"EMAIL_FROM_ADDRESS"`
+	DeptAddresses map[string]string `json:"departmentAddresses" yaml:"departmentAddresses"`
+
+	// UseTLS connects with implicit TLS, as SMTP servers on port 465 expect;
+	// port 465 implies it. Otherwise STARTTLS is used when the server offers it.
+	// Leaving Username empty sends without authentication, for internal relays.
+	UseTLS bool `json:"useTLS" yaml:"useTLS" env:"SMTP_USE_TLS"`
 }
 
 // SlackConfig contains Slack notification settings
 type SlackConfig struct {
-	Enabled      bool              `json:"enabled"`
-	WebhookURL   string            `json:"webhookUrl"`
-	DeptChannels map[string]string `json:"departmentChannels"`
+	Enabled      bool              `json:"enabled" yaml:"enabled" env:"SLACK_ENABLED"`
+	WebhookURL   string            `json:"webhookUrl" yaml:"webhookUrl" secret:"true" env:"SLACK_WEBHOOK_URL"`
+	DeptChannels map[string]string `json:"departmentChannels" yaml:"departmentChannels" secret:"true"`
+
+	// Format is "attachments" (the default) for the legacy attachment layout or
+	// "blocks" for Block Kit messages with Acknowledge and Create Ticket buttons
+	Format string `json:"format" yaml:"format" env:"SLACK_FORMAT"`
+
+	// SigningSecret verifies button clicks posted to /api/v1/slack/interactions
+	SigningSecret string `json:"signingSecret" yaml:"signingSecret" secret:"true" env:"SLACK_SIGNING_SECRET"`
+}
+
+// Slack message formats
+const (
+	SlackFormatAttachments = "attachments"
+	SlackFormatBlocks      = "blocks"
+)
+
+// WebhookConfig contains settings for a generic outbound webhook
+type WebhookConfig struct {
+	Enabled bool              `json:"enabled" yaml:"enabled" env:"WEBHOOK_ENABLED"`
+	URL     string            `json:"url" yaml:"url" secret:"true" env:"WEBHOOK_URL"`
+	Headers map[string]string `json:"headers" yaml:"headers" secret:"true"`                    // Extra headers sent with every request
+	Secret  string            `json:"secret" yaml:"secret" secret:"true" env:"WEBHOOK_SECRET"` // Optional HMAC-SHA256 signing secret
 }
 
 // DashboardConfig contains dashboard settings
 type DashboardConfig struct {
-	Enabled        bool          `json:"enabled"`
-	UpdateInterval time.Duration `json:"updateInterval"`
-	Port           int           `json:"port"`
+	Enabled        bool     `json:"enabled" yaml:"enabled" env:"DASHBOARD_ENABLED"`
+	UpdateInterval Duration `json:"updateInterval" yaml:"updateInterval" env:"DASHBOARD_UPDATE_INTERVAL"`
+	Port           int      `json:"port" yaml:"port" env:"DASHBOARD_PORT"`
 }
 
 // DatabaseConfig contains database settings for storing reviews
 type DatabaseConfig struct {
-	Enabled  bool   `json:"enabled"`
-	Type     string `json:"type"` // mysql, postgres, mongodb
-	Host     string `json:"host"`
-	Port     int    `json:"port"`
-	Username string `json:"username"`
-	Password string `json:"password"`
-	DBName   string `json:"dbName"`
+	Enabled  bool   `json:"enabled" yaml:"enabled" env:"DATABASE_ENABLED"`
+	Type     string `json:"type" yaml:"type" env:"DATABASE_TYPE"` // mysql, postgres, mongodb
+	Host     string `json:"host" yaml:"host" env:"DATABASE_HOST"`
+	Port     int    `json:"port" yaml:"port" env:"DATABASE_PORT"`
+	Username string `json:"username" yaml:"username" env:"DATABASE_USERNAME"`
+	Password string `json:"password" yaml:"password" secret:"true" env:"DATABASE_PASSWORD"`
+	DBName   string `json:"dbName" yaml:"dbName" env:"DATABASE_NAME"`
+}
+
+// PostgresDSN returns the connection URL for a postgres database
+func (c DatabaseConfig) PostgresDSN() string {
+	host := c.Host
+	if c.Port != 0 {
+		host = net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	}
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(c.Username, c.Password),
+		Host:   host,
+		Path:   "/" + c.DBName,
+	}
+	return dsn.String()
+}
+
+// MongoURI returns the connection URL for a mongodb deployment. The database
+// itself is selected by DBName once connected.
+func (c DatabaseConfig) MongoURI() string {
+	host := c.Host
+	if c.Port != 0 {
+		host = net.JoinHostPort(c.Host, strconv.Itoa(c.Port))
+	}
+	uri := url.URL{Scheme: "mongodb", Host: host, Path: "/"}
+	if c.Username != "" {
+		uri.User = url.UserPassword(c.Username, c.Password)
+	}
+	return uri.String()
 }
 
 // APIConfig contains REST API server settings
 type APIConfig struct {
-	Port            int           `json:"port"`
-	EnableSwagger   bool          `json:"enableSwagger"`
-	AuthToken       string        `json:"authToken"`
-	RateLimit       int           `json:"rateLimit"`
-	RateLimitWindow time.Duration `json:"rateLimitWindow"`
+	Port            int      `json:"port" yaml:"port" env:"API_PORT"`
+	EnableSwagger   bool     `json:"enableSwagger" yaml:"enableSwagger" env:"API_ENABLE_SWAGGER"`
+	EnableMetrics   bool     `json:"enableMetrics" yaml:"enableMetrics" env:"API_ENABLE_METRICS"` // Serve Prometheus metrics at /metrics without auth
+	AuthToken       string   `json:"authToken" yaml:"authToken" secret:"true" env:"API_AUTH_TOKEN"`
+	RateLimit       int      `json:"rateLimit" yaml:"rateLimit" env:"API_RATE_LIMIT"`                    // Requests allowed per client IP in each RateLimitWindow; 0 disables limiting
+	RateLimitWindow Duration `json:"rateLimitWindow" yaml:"rateLimitWindow" env:"API_RATE_LIMIT_WINDOW"` // Defaults to one minute
+
+	// DashboardTrendDays is how many days the dashboard's daily trend covers; defaults to 7
+	DashboardTrendDays int `json:"dashboardTrendDays" yaml:"dashboardTrendDays" env:"API_DASHBOARD_TREND_DAYS"`
+
+	// APIKeys are per-client credentials limited to a set of scopes. AuthToken,
+	// if set, remains valid and grants every scope.
+	APIKeys []APIKeyConfig `json:"apiKeys" yaml:"apiKeys"`
 }
 
-// Load reads the application configuration from a file
+// APIKeyConfig is a named API credential and the scopes it grants
+type APIKeyConfig struct {
+	Name   string   `json:"name" yaml:"name"`
+	Key    string   `json:"key" yaml:"key" secret:"true"`
+	Scopes []string `json:"scopes" yaml:"scopes"` // Entries from APIScopes, or "*" for all of them
+}
+
+// Load reads the application configuration from a file, applying any
+// REVIEW_SCRAPER_* environment overrides on top of it
 func Load() (*Config, error) {
-	configPath := getConfigPath()
+	return LoadFile(getConfigPath())
+}
 
+// LoadFile reads the configuration from the given path. Files ending in .yaml
+// or .yml are parsed as YAML; anything else is parsed as JSON.
+func LoadFile(configPath string) (*Config, error) {
 	data, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file: %w", err)
 	}
 
 	var cfg Config
-	if err := json.Unmarshal(data, &cfg); err != nil {
-		return nil, fmt.Errorf("failed to parse config file: %w", err)
+	switch strings.ToLower(filepath.Ext(configPath)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	default:
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
+	// Environment variables win over the file
+	if err := applyEnvOverrides(&cfg); err != nil {
+		return nil, err
 	}
 
 	// Set defaults if not specified
 	if cfg.ScrapingInterval == 0 {
-		cfg.ScrapingInterval = 1 * time.Hour // Default to every hour
+		cfg.ScrapingInterval = Duration(1 * time.Hour) // Default to every hour
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
 	return &cfg, nil
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration is a time.Duration that is written in config files as a
+// human-readable string such as "30s" or "1h30m". Numeric nanosecond values
+// are still accepted so older configs keep working.
+type Duration time.Duration
+
+// Duration returns the value as a time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String formats the duration like time.Duration
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// MarshalJSON writes the duration as a string so it can be read back
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// MarshalYAML writes the duration as a string so it can be read back
+func (d Duration) MarshalYAML() (interface{}, error) {
+	return d.String(), nil
+}
+
+// UnmarshalJSON accepts either a duration string such as "1h30m" or a number
+// of nanoseconds, which is how time.Duration fields were written before
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return d.parse(s)
+	}
+
+	var nanos int64
+	if err := json.Unmarshal(data, &nanos); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\" or a number of nanoseconds, got %s", data)
+	}
+	*d = Duration(nanos)
+	return nil
+}
+
+// UnmarshalYAML accepts either a duration string or a number of nanoseconds
+func (d *Duration) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode && node.Tag == "!!int" {
+		var nanos int64
+		if err := node.Decode(&nanos); err != nil {
+			return err
+		}
+		*d = Duration(nanos)
+		return nil
+	}
+
+	var s string
+	if err := node.Decode(&s); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30s\" or a number of nanoseconds: %w", err)
+	}
+	return d.parse(s)
+}
+
+// parse sets d from a Go duration string
+func (d *Duration) parse(s string) error {
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", s, err)
+	}
+	*d = Duration(parsed)
+	return nil
+}
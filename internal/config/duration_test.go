@@ -0,0 +1,86 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestDurationUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{`"30s"`, 30 * time.Second},
+		{`"1h30m"`, 90 * time.Minute},
+		{`"250ms"`, 250 * time.Millisecond},
+		{`30000000000`, 30 * time.Second},
+		{`5400000000000`, 90 * time.Minute},
+		{`0`, 0},
+	}
+
+	for _, tt := range tests {
+		var d Duration
+		assert.NoError(t, json.Unmarshal([]byte(tt.input), &d), tt.input)
+		assert.Equal(t, tt.want, d.Duration(), tt.input)
+	}
+}
+
+func TestDurationUnmarshalYAML(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+	}{
+		{`30s`, 30 * time.Second},
+		{`"1h30m"`, 90 * time.Minute},
+		{`30000000000`, 30 * time.Second},
+	}
+
+	for _, tt := range tests {
+		var d Duration
+		assert.NoError(t, yaml.Unmarshal([]byte(tt.input), &d), tt.input)
+		assert.Equal(t, tt.want, d.Duration(), tt.input)
+	}
+}
+
+func TestDurationRejectsInvalidValues(t *testing.T) {
+	for _, input := range []string{`"soon"`, `1.5`, `true`, `{}`} {
+		var d Duration
+		assert.Error(t, json.Unmarshal([]byte(input), &d), input)
+	}
+}
+
+func TestDurationNumericAndStringConfigsMatch(t *testing.T) {
+	numeric := `{
+		"scrapingInterval": 3600000000000,
+		"scrapers": {"rateLimits": {"pauseDuration": 30000000000}},
+		"notifier": {"dashboard": {"updateInterval": 60000000000}},
+		"api": {"rateLimitWindow": 60000000000}
+	}`
+	human := `{
+		"scrapingInterval": "1h",
+		"scrapers": {"rateLimits": {"pauseDuration": "30s"}},
+		"notifier": {"dashboard": {"updateInterval": "1m"}},
+		"api": {"rateLimitWindow": "1m"}
+	}`
+
+	var fromNumeric, fromHuman Config
+	assert.NoError(t, json.Unmarshal([]byte(numeric), &fromNumeric))
+	assert.NoError(t, json.Unmarshal([]byte(human), &fromHuman))
+
+	assert.Equal(t, fromHuman, fromNumeric)
+	assert.Equal(t, time.Hour, fromNumeric.ScrapingInterval.Duration())
+	assert.Equal(t, 30*time.Second, fromNumeric.Scrapers.RateLimits.PauseDuration.Duration())
+	assert.Equal(t, time.Minute, fromNumeric.Notifier.Dashboard.UpdateInterval.Duration())
+	assert.Equal(t, time.Minute, fromNumeric.API.RateLimitWindow.Duration())
+}
+
+func TestDurationMarshalsAsString(t *testing.T) {
+	data, err := json.Marshal(Duration(90 * time.Minute))
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"1h30m0s"`, string(data))
+}
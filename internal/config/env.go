@@ -0,0 +1,119 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EnvPrefix is prepended to the name in a field's `env` tag to form the
+// environment variable that overrides it, e.g. REVIEW_SCRAPER_ANALYZER_API_KEY
+const EnvPrefix = "REVIEW_SCRAPER_"
+
+var (
+	durationType       = reflect.TypeOf(time.Duration(0))
+	configDurationType = reflect.TypeOf(Duration(0))
+)
+
+// applyEnvOverrides sets every field tagged `env:"NAME"` from the environment
+// variable EnvPrefix+NAME when it is set, so values from the environment take
+// precedence over the config file. Strings, bools, ints, floats, string slices,
+// durations and pointers to them can be overridden; slices are read as
+// comma-separated lists and durations as Go duration strings such as "30m".
+// Maps and lists of structs, such as router mappings or API keys, come from the
+// file alone, as do per-tenant settings. A nested struct tagged
+// `envPrefix:"PREFIX_"` has PREFIX_ added to the names of its fields, so a type
+// used in several sections is overridden separately in each.
+func applyEnvOverrides(cfg *Config) error {
+	return applyEnv(reflect.ValueOf(cfg).Elem(), "")
+}
+
+// applyEnv walks the fields of a struct value, recursing into nested structs.
+// prefix is added to the env tags of its fields.
+func applyEnv(v reflect.Value, prefix string) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		if name == "" {
+			if field.Type.Kind() == reflect.Struct {
+				if err := applyEnv(v.Field(i), prefix+field.Tag.Get("envPrefix")); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		value, ok := os.LookupEnv(EnvPrefix + prefix + name)
+		if !ok {
+			continue
+		}
+		if err := setFromEnv(v.Field(i), value); err != nil {
+			return fmt.Errorf("invalid value for %s%s%s: %w", EnvPrefix, prefix, name, err)
+		}
+	}
+	return nil
+}
+
+// setFromEnv parses value into the field according to its type
+func setFromEnv(field reflect.Value, value string) error {
+	if field.Kind() == reflect.Pointer {
+		target := reflect.New(field.Type().Elem())
+		if err := setFromEnv(target.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(target)
+		return nil
+	}
+	if field.Type() == durationType || field.Type() == configDurationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return err
+		}
+		field.SetInt(n)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(f)
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported field type %s", field.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported field type %s", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfigFile writes data to a temporary config file and points Load at it
+func writeConfigFile(t *testing.T, data string) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	t.Setenv("REVIEW_SCRAPER_CONFIG", path)
+}
+
+func TestLoadAppliesEnvOverrides(t *testing.T) {
+	writeConfigFile(t, `{
+		"scrapers": {"twitter": {"enabled": false, "apiKey": "file-key", "keywords": ["file"]}},
+		"analyzer": {"mode": "local", "negativeThreshold": -0.3, "apiKey": "file-analyzer-key"},
+		"notifier": {"slack": {"enabled": true, "webhookUrl": "https://hooks.slack.com/file"}},
+		"api": {"port": 8080, "authToken": "file-token"}
+	}`)
+
+	t.Setenv("REVIEW_SCRAPER_SCRAPING_INTERVAL", "30m")
+	t.Setenv("REVIEW_SCRAPER_TWITTER_ENABLED", "true")
+	t.Setenv("REVIEW_SCRAPER_TWITTER_API_KEY", "env-key")
+	t.Setenv("REVIEW_SCRAPER_TWITTER_API_SECRET", "env-secret")
+	t.Setenv("REVIEW_SCRAPER_TWITTER_KEYWORDS", "infoblox, bloxone ,")
+	t.Setenv("REVIEW_SCRAPER_ANALYZER_API_KEY", "env-analyzer-key")
+	t.Setenv("REVIEW_SCRAPER_ANALYZER_NEGATIVE_THRESHOLD", "-0.5")
+	t.Setenv("REVIEW_SCRAPER_SLACK_WEBHOOK_URL", "https://hooks.slack.com/env")
+	t.Setenv("REVIEW_SCRAPER_API_PORT", "9090")
+	t.Setenv("REVIEW_SCRAPER_API_AUTH_TOKEN", "env-token")
+
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, cfg.ScrapingInterval.Duration())
+	assert.True(t, cfg.Scrapers.Twitter.Enabled)
+	assert.Equal(t, "env-key", cfg.Scrapers.Twitter.APIKey)
+	assert.Equal(t, "env-secret", cfg.Scrapers.Twitter.APISecret)
+	assert.Equal(t, []string{"infoblox", "bloxone"}, cfg.Scrapers.Twitter.Keywords)
+	assert.Equal(t, "env-analyzer-key", cfg.Analyzer.APIKey)
+	assert.Equal(t, -0.5, cfg.Analyzer.NegativeThreshold)
+	assert.Equal(t, "https://hooks.slack.com/env", cfg.Notifier.Slack.WebhookURL)
+	assert.Equal(t, 9090, cfg.API.Port)
+	assert.Equal(t, "env-token", cfg.API.AuthToken)
+
+	// Fields without an override keep their file values
+	assert.Equal(t, "local", cfg.Analyzer.Mode)
+	assert.True(t, cfg.Notifier.Slack.Enabled)
+}
+
+func TestEnvPrefixSeparatesSharedTypes(t *testing.T) {
+	writeConfigFile(t, `{
+		"notifier": {"databases": {"host": "notifications-db"}},
+		"storage": {"database": {"host": "file-host"}}
+	}`)
+	t.Setenv("REVIEW_SCRAPER_STORAGE_DATABASE_HOST", "store-db")
+	t.Setenv("REVIEW_SCRAPER_STORAGE_DATABASE_PASSWORD", "store-secret")
+
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "store-db", cfg.Storage.Database.Host)
+	assert.Equal(t, "store-secret", cfg.Storage.Database.Password)
+	assert.Equal(t, "notifications-db", cfg.Notifier.Databases.Host)
+	assert.Empty(t, cfg.Notifier.Databases.Password)
+}
+
+func TestLoadEnvOverridesSatisfyValidation(t *testing.T) {
+	// The file leaves the secret out entirely; the environment supplies it
+	writeConfigFile(t, `{"analyzer": {"mode": "openai"}}`)
+	t.Setenv("REVIEW_SCRAPER_ANALYZER_API_KEY", "sk-env")
+
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sk-env", cfg.Analyzer.APIKey)
+}
+
+func TestLoadRejectsInvalidEnvValue(t *testing.T) {
+	writeConfigFile(t, `{}`)
+	t.Setenv("REVIEW_SCRAPER_API_PORT", "eighty")
+
+	_, err := Load()
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "REVIEW_SCRAPER_API_PORT")
+}
+
+func TestEnvOverridesSkipTenants(t *testing.T) {
+	writeConfigFile(t, `{"tenants": [{"name": "security", "keywords": ["threat"], "analyzer": {"mode": "local"}}]}`)
+	t.Setenv("REVIEW_SCRAPER_ANALYZER_MODE", "aws")
+
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "aws", cfg.Analyzer.Mode)
+	assert.Equal(t, "local", cfg.Tenants[0].Analyzer.Mode)
+}
+
+func TestLoadAppliesEnvOverridesToListsAndNestedSettings(t *testing.T) {
+	writeConfigFile(t, `{"analyzer": {"mode": "local", "positiveWords": ["good"]}}`)
+	t.Setenv("REVIEW_SCRAPER_ANALYZER_POSITIVE_WORDS", "solid,reliable")
+	t.Setenv("REVIEW_SCRAPER_ANALYZER_RATING_WEIGHT", "0.25")
+	t.Setenv("REVIEW_SCRAPER_ANALYZER_RATING_SCALE_MAX", "10")
+	t.Setenv("REVIEW_SCRAPER_SCRAPER_REQUESTS_PER_MINUTE", "30")
+	t.Setenv("REVIEW_SCRAPER_SCRAPER_PAUSE_DURATION", "2s")
+	t.Setenv("REVIEW_SCRAPER_ROUTER_ESCALATION_DEPARTMENTS", "management")
+	t.Setenv("REVIEW_SCRAPER_NOTIFIER_MAX_SENDS_PER_MINUTE", "12")
+
+	cfg, err := Load()
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"solid", "reliable"}, cfg.Analyzer.PositiveWords)
+	if assert.NotNil(t, cfg.Analyzer.RatingWeight) {
+		assert.Equal(t, 0.25, *cfg.Analyzer.RatingWeight)
+	}
+	assert.Equal(t, 10.0, cfg.Analyzer.RatingScale.Max)
+	assert.Equal(t, 30, cfg.Scrapers.RateLimits.RequestsPerMinute)
+	assert.Equal(t, 2*time.Second, cfg.Scrapers.RateLimits.PauseDuration.Duration())
+	assert.Equal(t, []string{"management"}, cfg.Router.Escalation.Departments)
+	assert.Equal(t, 12, cfg.Notifier.MaxSendsPerMinute)
+}
+
+// envSettable reports whether setFromEnv can parse a value of type t
+func envSettable(t reflect.Type) bool {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	default:
+		return false
+	}
+}
+
+// TestEnvTagsCoverEverySettableField keeps new settings overridable: every
+// field the environment can set needs an env tag, each tag must name a unique
+// variable, and only maps and lists of structs are left to the file
+func TestEnvTagsCoverEverySettableField(t *testing.T) {
+	names := make(map[string]string)
+	var walk func(typ reflect.Type, path, prefix string)
+	walk = func(typ reflect.Type, path, prefix string) {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			fieldPath := path + "." + field.Name
+			name := field.Tag.Get("env")
+			if name == "" {
+				if field.Type.Kind() == reflect.Struct {
+					walk(field.Type, fieldPath, prefix+field.Tag.Get("envPrefix"))
+					continue
+				}
+				assert.False(t, envSettable(field.Type), "%s can be set from the environment but has no env tag", fieldPath)
+				continue
+			}
+
+			assert.True(t, envSettable(field.Type), "%s has an env tag but its type %s cannot be set", fieldPath, field.Type)
+			variable := EnvPrefix + prefix + name
+			if other, taken := names[variable]; taken {
+				t.Errorf("%s and %s both use %s", other, fieldPath, variable)
+			}
+			names[variable] = fieldPath
+		}
+	}
+	walk(reflect.TypeOf(Config{}), "Config", "")
+}
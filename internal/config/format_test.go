@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const jsonConfig = `{
+	"scrapingInterval": "1h30m",
+	"scrapers": {
+		"twitter": {"enabled": true, "apiKey": "key", "apiSecret": "secret", "keywords": ["infoblox", "bloxone"]},
+		"g2": {"enabled": true, "product_id": "infoblox-ddi", "apiKey": "rapid-key", "maxPages": 3},
+		"rateLimits": {"requestsPerMinute": 30, "pauseDuration": "2s"}
+	},
+	"analyzer": {"mode": "local", "negativeThreshold": -0.3, "relevanceThreshold": 0.5, "keywords": ["nios"]},
+	"router": {
+		"mappings": [{"category": "security", "department": "security", "priority": 1}],
+		"defaultDepartment": "support"
+	},
+	"notifier": {
+		"slack": {"enabled": true, "webhookUrl": "https://hooks.slack.com/x", "departmentChannels": {"security": "https://hooks.slack.com/y"}},
+		"dashboard": {"enabled": true, "updateInterval": "1m", "port": 3000}
+	},
+	"api": {"port": 8080, "authToken": "token", "rateLimit": 100, "rateLimitWindow": "1m"},
+	"tenants": [{"name": "security", "keywords": ["threat defense"], "analyzer": {"mode": "local"}}]
+}`
+
+const yamlConfig = `
+scrapingInterval: 1h30m
+scrapers:
+  twitter:
+    enabled: true
+    apiKey: key
+    apiSecret: secret
+    keywords: [infoblox, bloxone]
+  g2:
+    enabled: true
+    product_id: infoblox-ddi
+    apiKey: rapid-key
+    maxPages: 3
+  rateLimits:
+    requestsPerMinute: 30
+    pauseDuration: 2s
+analyzer:
+  mode: local
+  negativeThreshold: -0.3
+  relevanceThreshold: 0.5
+  keywords: [nios]
+router:
+  mappings:
+    - category: security
+      department: security
+      priority: 1
+  defaultDepartment: support
+notifier:
+  slack:
+    enabled: true
+    webhookUrl: https://hooks.slack.com/x
+    departmentChannels:
+      security: https://hooks.slack.com/y
+  dashboard:
+    enabled: true
+    updateInterval: 1m
+    port: 3000
+api:
+  port: 8080
+  authToken: token
+  rateLimit: 100
+  rateLimitWindow: 1m
+tenants:
+  - name: security
+    keywords: [threat defense]
+    analyzer:
+      mode: local
+`
+
+// loadFromFile writes data to a temp file with the given name and loads it
+// through REVIEW_SCRAPER_CONFIG
+func loadFromFile(t *testing.T, name, data string) *Config {
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+	t.Setenv("REVIEW_SCRAPER_CONFIG", path)
+
+	cfg, err := Load()
+	assert.NoError(t, err)
+	return cfg
+}
+
+func TestLoadJSONAndYAMLAreEquivalent(t *testing.T) {
+	fromJSON := loadFromFile(t, "config.json", jsonConfig)
+	fromYAML := loadFromFile(t, "config.yaml", yamlConfig)
+	fromYML := loadFromFile(t, "config.yml", yamlConfig)
+
+	assert.Equal(t, fromJSON, fromYAML)
+	assert.Equal(t, fromJSON, fromYML)
+
+	assert.Equal(t, 90*time.Minute, fromYAML.ScrapingInterval.Duration())
+	assert.Equal(t, 2*time.Second, fromYAML.Scrapers.RateLimits.PauseDuration.Duration())
+	assert.Equal(t, time.Minute, fromYAML.Notifier.Dashboard.UpdateInterval.Duration())
+	assert.Equal(t, time.Minute, fromYAML.API.RateLimitWindow.Duration())
+	assert.Equal(t, "infoblox-ddi", fromYAML.Scrapers.G2.ProductID)
+	assert.Equal(t, "https://hooks.slack.com/y", fromYAML.Notifier.Slack.DeptChannels["security"])
+}
+
+func TestLoadRejectsInvalidDurations(t *testing.T) {
+	for name, data := range map[string]string{
+		"config.json": `{"scrapingInterval": "soon"}`,
+		"config.yaml": "scrapingInterval: soon\n",
+	} {
+		path := filepath.Join(t.TempDir(), name)
+		assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+		_, err := LoadFile(path)
+		assert.ErrorContains(t, err, `invalid duration "soon"`, name)
+	}
+}
+
+func TestLoadSampleConfig(t *testing.T) {
+	cfg, err := LoadFile(filepath.Join("..", "..", "configs", "config.sample.json"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, cfg.ScrapingInterval.Duration())
+}
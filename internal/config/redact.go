@@ -0,0 +1,130 @@
+package config
+
+import (
+	"reflect"
+)
+
+// RedactedValue replaces secret values in redacted configuration
+const RedactedValue = "***"
+
+// Redact returns a deep copy of a configuration value with every field tagged
+// `secret:"true"` masked. Secret strings, and the values of secret string maps
+// and slices, are replaced with RedactedValue; empty values are left empty so
+// callers can still tell whether a secret has been configured.
+func Redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	return redactValue(reflect.ValueOf(v), false).Interface()
+}
+
+// redactValue copies src, masking strings when secret is set
+func redactValue(src reflect.Value, secret bool) reflect.Value {
+	dst := reflect.New(src.Type()).Elem()
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			return dst
+		}
+		copied := redactValue(src.Elem(), secret)
+		ptr := reflect.New(copied.Type())
+		ptr.Elem().Set(copied)
+		dst.Set(ptr)
+
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			field := src.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			dst.Field(i).Set(redactValue(src.Field(i), field.Tag.Get("secret") == "true"))
+		}
+
+	case reflect.Slice:
+		if src.IsNil() {
+			return dst
+		}
+		dst.Set(reflect.MakeSlice(src.Type(), src.Len(), src.Len()))
+		for i := 0; i < src.Len(); i++ {
+			dst.Index(i).Set(redactValue(src.Index(i), secret))
+		}
+
+	case reflect.Map:
+		if src.IsNil() {
+			return dst
+		}
+		dst.Set(reflect.MakeMapWithSize(src.Type(), src.Len()))
+		iter := src.MapRange()
+		for iter.Next() {
+			dst.SetMapIndex(iter.Key(), redactValue(iter.Value(), secret))
+		}
+
+	case reflect.String:
+		if secret && src.Len() > 0 {
+			dst.SetString(RedactedValue)
+		} else {
+			dst.Set(src)
+		}
+
+	default:
+		dst.Set(src)
+	}
+
+	return dst
+}
+
+// RestoreRedacted copies secrets from current into updated wherever updated still
+// holds RedactedValue, so a redacted config read from the API can be edited and
+// written back without wiping its credentials. updated must be a pointer to the
+// same type as current.
+func RestoreRedacted(updated interface{}, current interface{}) {
+	dst := reflect.ValueOf(updated)
+	if dst.Kind() != reflect.Ptr || dst.IsNil() {
+		return
+	}
+	src := reflect.ValueOf(current)
+	if src.Type() != dst.Elem().Type() {
+		return
+	}
+	restoreValue(dst.Elem(), src, false)
+}
+
+// restoreValue walks dst and src together, restoring masked secrets in dst
+func restoreValue(dst, src reflect.Value, secret bool) {
+	switch dst.Kind() {
+	case reflect.Struct:
+		for i := 0; i < dst.NumField(); i++ {
+			field := dst.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			restoreValue(dst.Field(i), src.Field(i), field.Tag.Get("secret") == "true")
+		}
+
+	case reflect.Slice:
+		for i := 0; i < dst.Len() && i < src.Len(); i++ {
+			restoreValue(dst.Index(i), src.Index(i), secret)
+		}
+
+	case reflect.Map:
+		if dst.IsNil() || src.IsNil() {
+			return
+		}
+		iter := dst.MapRange()
+		for iter.Next() {
+			old := src.MapIndex(iter.Key())
+			if !old.IsValid() {
+				continue
+			}
+			if secret && iter.Value().Kind() == reflect.String && iter.Value().String() == RedactedValue {
+				dst.SetMapIndex(iter.Key(), old)
+			}
+		}
+
+	case reflect.String:
+		if secret && dst.String() == RedactedValue {
+			dst.Set(src)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Reloadable is implemented by components that can apply a new configuration
+// while the process keeps running
+type Reloadable interface {
+	Reload(cfg *Config) error
+}
+
+// Reload re-reads the configuration file for a running process. Settings that
+// cannot be swapped safely (the API listener, notifier channels, tenants, the
+// review store, warehouse export, tracing, pipeline workers, scraping interval
+// and log level) keep their current values; a warning is returned for each one
+// that changed on disk so the caller can report it. API keys are the
+// exception within the API section and are always reloaded. Tenant pipelines
+// are built from their own sections at startup, so reloaded scraper, analyzer
+// and router settings reach only the default pipeline.
+func Reload(current *Config) (*Config, []string, error) {
+	next, err := Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	warnings := keepStatic(next, current)
+	return next, warnings, nil
+}
+
+// keepStatic copies restart-only settings from current into next
+func keepStatic(next, current *Config) []string {
+	var warnings []string
+	keep := func(name string, dst, src interface{}) {
+		d := reflect.ValueOf(dst).Elem()
+		s := reflect.ValueOf(src).Elem()
+		if !reflect.DeepEqual(d.Interface(), s.Interface()) {
+			warnings = append(warnings, fmt.Sprintf("%s changed but requires a restart; keeping the current value", name))
+			d.Set(s)
+		}
+	}
+
+	keep("scrapingInterval", &next.ScrapingInterval, &current.ScrapingInterval)
+	keep("logLevel", &next.LogLevel, &current.LogLevel)
+	keep("pipelineWorkers", &next.PipelineWorkers, &current.PipelineWorkers)
+	keep("notifier", &next.Notifier, &current.Notifier)
+
+	// API keys can be issued and revoked without a restart
+	apiKeys := next.API.APIKeys
+	next.API.APIKeys = current.API.APIKeys
+	keep("api", &next.API, &current.API)
+	next.API.APIKeys = apiKeys
+
+	keep("warehouse", &next.Warehouse, &current.Warehouse)
+	keep("tracing", &next.Tracing, &current.Tracing)
+	keep("storage", &next.Storage, &current.Storage)
+	keep("tenants", &next.Tenants, &current.Tenants)
+
+	return warnings
+}
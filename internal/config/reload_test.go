@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadKeepsRestartOnlySettings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("REVIEW_SCRAPER_CONFIG", path)
+
+	data := `{
+		"analyzer": {"mode": "local", "negativeThreshold": -0.1},
+		"api": {"port": 9090}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	current := &Config{
+		ScrapingInterval: Duration(time.Hour),
+		Analyzer:         AnalyzerConfig{Mode: "local", NegativeThreshold: -0.5},
+		API:              APIConfig{Port: 8080},
+	}
+
+	next, warnings, err := Reload(current)
+
+	assert.NoError(t, err)
+	assert.Equal(t, -0.1, next.Analyzer.NegativeThreshold, "hot-swappable settings are applied")
+	assert.Equal(t, 8080, next.API.Port, "the API port keeps its current value")
+	assert.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "api")
+}
+
+func TestReloadKeepsPipelineResources(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("REVIEW_SCRAPER_CONFIG", path)
+
+	data := `{
+		"pipelineWorkers": 16,
+		"warehouse": {"enabled": true, "driver": "postgres", "dsn": "postgres://warehouse", "table": "analyses"},
+		"tracing": {"enabled": true, "endpoint": "http://collector:4318"},
+		"storage": {"seenTTL": "1h"}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	current := &Config{ScrapingInterval: Duration(time.Hour), PipelineWorkers: 4}
+
+	next, warnings, err := Reload(current)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, next.PipelineWorkers)
+	assert.Equal(t, current.Warehouse, next.Warehouse)
+	assert.Equal(t, current.Tracing, next.Tracing)
+	assert.Equal(t, current.Storage, next.Storage)
+	assert.Len(t, warnings, 4)
+	for i, name := range []string{"pipelineWorkers", "warehouse", "tracing", "storage"} {
+		assert.Contains(t, warnings[i], name)
+	}
+}
+
+func TestReloadAppliesAPIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	t.Setenv("REVIEW_SCRAPER_CONFIG", path)
+
+	data := `{
+		"api": {"port": 8080, "apiKeys": [{"name": "dashboard", "key": "k-new", "scopes": ["stats:read"]}]}
+	}`
+	assert.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+
+	current := &Config{
+		ScrapingInterval: Duration(time.Hour),
+		API: APIConfig{Port: 8080, APIKeys: []APIKeyConfig{
+			{Name: "ci", Key: "k-revoked", Scopes: []string{"scraping:run"}},
+		}},
+	}
+
+	next, warnings, err := Reload(current)
+
+	assert.NoError(t, err)
+	assert.Empty(t, warnings, "API key changes do not require a restart")
+	assert.Equal(t, []APIKeyConfig{{Name: "dashboard", Key: "k-new", Scopes: []string{"stats:read"}}}, next.API.APIKeys)
+}
+
+func TestReloadReportsUnreadableFile(t *testing.T) {
+	t.Setenv("REVIEW_SCRAPER_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+
+	_, _, err := Reload(&Config{})
+
+	assert.Error(t, err)
+}
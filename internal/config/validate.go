@@ -0,0 +1,637 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/query"
+)
+
+// AnalyzerModes lists the supported analyzer backends
+var AnalyzerModes = []string{"local", "openai", "google", "aws", "azure"}
+
+// AutoTagFamilies lists the tag families the analyzer can derive
+var AutoTagFamilies = []string{"sentiment", "intent", "products", "needs-action"}
+
+// LogLevels lists the accepted log levels
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Severities lists the analysis severities, lowest first
+var Severities = []string{"low", "medium", "high"}
+
+// APIScopes lists the scopes an API key can be granted
+var APIScopes = []string{"reviews:read", "stats:read", "scraping:run", "analyze:run", "config:read", "config:write"}
+
+// AppStoreSortModes lists the orders the App Store review feed can be read in
+var AppStoreSortModes = []string{"mostrecent", "mosthelpful"}
+
+// RateLimitedSources lists the sources that can have their own rate limits
+var RateLimitedSources = []string{"twitter", "reddit", "appStore", "googlePlay", "g2", "trustpilot", "hackerNews", "rss", "youTube", "customSites"}
+
+// StorageDatabaseTypes lists the databases the review store can use
+var StorageDatabaseTypes = []string{"postgres", "mongodb"}
+
+// ValidationError lists every problem found while validating configuration
+type ValidationError struct {
+	Problems []string
+}
+
+// Error returns all problems as a single message
+func (e *ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e.Problems, "; ")
+}
+
+// validator collects field-qualified problems
+type validator struct {
+	problems []string
+}
+
+// addf records a problem for the given field path
+func (v *validator) addf(field, format string, args ...interface{}) {
+	v.problems = append(v.problems, field+": "+fmt.Sprintf(format, args...))
+}
+
+// err returns a ValidationError if any problems were recorded
+func (v *validator) err() error {
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Problems: v.problems}
+}
+
+// Validate checks the whole configuration and returns a ValidationError listing
+// every problem found, each qualified with the JSON path of the offending field
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	if c.ScrapingInterval < 0 {
+		v.addf("scrapingInterval", "must not be negative, got %s", c.ScrapingInterval)
+	}
+	if c.LogLevel != "" && !containsString(LogLevels, strings.ToLower(c.LogLevel)) {
+		v.addf("logLevel", "unknown level %q (expected one of %s)", c.LogLevel, strings.Join(LogLevels, ", "))
+	}
+	if c.PipelineWorkers < 0 {
+		v.addf("pipelineWorkers", "must not be negative, got %d", c.PipelineWorkers)
+	}
+
+	c.Scrapers.validate(v, "scrapers")
+	c.Analyzer.validate(v, "analyzer")
+	c.Router.validate(v, "router")
+	c.Notifier.validate(v, "notifier")
+	c.API.validate(v, "api")
+	c.Warehouse.validate(v, "warehouse")
+	c.Tracing.validate(v, "tracing")
+	c.Storage.validate(v, "storage")
+
+	names := make(map[string]bool, len(c.Tenants))
+	for i, tenant := range c.Tenants {
+		prefix := fmt.Sprintf("tenants[%d]", i)
+		if tenant.Name == "" {
+			v.addf(prefix+".name", "required")
+		} else if names[tenant.Name] {
+			v.addf(prefix+".name", "duplicate tenant name %q", tenant.Name)
+		}
+		names[tenant.Name] = true
+
+		if len(tenant.Sources) == 0 && len(tenant.Keywords) == 0 {
+			v.addf(prefix, "at least one of sources or keywords is required to match reviews")
+		}
+		tenant.Analyzer.validate(v, prefix+".analyzer")
+		tenant.Router.validate(v, prefix+".router")
+		tenant.Notifier.validate(v, prefix+".notifier")
+	}
+
+	return v.err()
+}
+
+func (c ScrapersConfig) validate(v *validator, prefix string) {
+	if c.Twitter.Enabled {
+		field := prefix + ".twitter"
+		if c.Twitter.APIKey == "" {
+			v.addf(field+".apiKey", "required when the Twitter scraper is enabled")
+		}
+		if c.Twitter.APISecret == "" {
+			v.addf(field+".apiSecret", "required when the Twitter scraper is enabled")
+		}
+		if len(c.Twitter.Keywords) == 0 {
+			v.addf(field+".keywords", "at least one keyword is required when the Twitter scraper is enabled")
+		}
+	}
+	validateKeywordExpressions(v, prefix+".twitter.keywords", c.Twitter.Keywords)
+	if c.Twitter.MaxResults < 0 {
+		v.addf(prefix+".twitter.maxResults", "must not be negative, got %d", c.Twitter.MaxResults)
+	}
+
+	if c.Reddit.Enabled {
+		if c.Reddit.ClientID == "" {
+			v.addf(prefix+".reddit.clientId", "required when the Reddit scraper is enabled")
+		}
+		if c.Reddit.ClientSecret == "" {
+			v.addf(prefix+".reddit.clientSecret", "required when the Reddit scraper is enabled")
+		}
+	}
+
+	validateKeywordExpressions(v, prefix+".reddit.keywords", c.Reddit.Keywords)
+
+	if c.AppStore.Enabled && len(c.AppStore.AppIDs) == 0 {
+		v.addf(prefix+".appStore.appIds", "at least one app ID is required when the App Store scraper is enabled")
+	}
+	for i, mode := range c.AppStore.SortModes {
+		if !containsString(AppStoreSortModes, strings.ToLower(mode)) {
+			v.addf(fmt.Sprintf("%s.appStore.sortModes[%d]", prefix, i), "unknown sort mode %q (expected one of %s)", mode, strings.Join(AppStoreSortModes, ", "))
+		}
+	}
+	if c.GooglePlay.Enabled && len(c.GooglePlay.AppIDs) == 0 {
+		v.addf(prefix+".googlePlay.appIds", "at least one app ID is required when the Google Play scraper is enabled")
+	}
+	if c.G2.Enabled {
+		if c.G2.ProductID == "" {
+			v.addf(prefix+".g2.product_id", "required when the G2 scraper is enabled")
+		}
+		if c.G2.APIKey == "" {
+			v.addf(prefix+".g2.apiKey", "required when the G2 scraper is enabled")
+		}
+	}
+	if c.Trustpilot.Enabled && c.Trustpilot.BusinessID == "" {
+		v.addf(prefix+".trustpilot.business_id", "required when the Trustpilot scraper is enabled")
+	}
+	if c.HackerNews.Enabled && len(c.HackerNews.Keywords) == 0 {
+		v.addf(prefix+".hackerNews.keywords", "at least one keyword is required when the Hacker News scraper is enabled")
+	}
+	validateKeywordExpressions(v, prefix+".hackerNews.keywords", c.HackerNews.Keywords)
+	validateAlgoliaExpressions(v, prefix+".hackerNews.keywords", c.HackerNews.Keywords)
+	if c.PageCache.TTL < 0 {
+		v.addf(prefix+".pageCache.ttl", "must not be negative, got %s", c.PageCache.TTL)
+	}
+	if c.PageCache.MaxEntries < 0 {
+		v.addf(prefix+".pageCache.maxEntries", "must not be negative, got %d", c.PageCache.MaxEntries)
+	}
+	if c.MaxReviewAge < 0 {
+		v.addf(prefix+".maxReviewAge", "must not be negative, got %s", c.MaxReviewAge)
+	}
+	for i, pattern := range c.Authors.Allow {
+		if strings.TrimSpace(pattern) == "" {
+			v.addf(fmt.Sprintf("%s.authors.allow[%d]", prefix, i), "must not be empty")
+		}
+	}
+	for i, pattern := range c.Authors.Deny {
+		if strings.TrimSpace(pattern) == "" {
+			v.addf(fmt.Sprintf("%s.authors.deny[%d]", prefix, i), "must not be empty")
+		}
+	}
+	if c.HackerNews.HitsPerPage < 0 || c.HackerNews.HitsPerPage > 1000 {
+		v.addf(prefix+".hackerNews.hitsPerPage", "must be between 0 and 1000, got %d", c.HackerNews.HitsPerPage)
+	}
+	if c.RSS.Enabled {
+		if len(c.RSS.Feeds) == 0 {
+			v.addf(prefix+".rss.feeds", "at least one feed URL is required when the RSS scraper is enabled")
+		}
+		if len(c.RSS.Keywords) == 0 {
+			v.addf(prefix+".rss.keywords", "at least one keyword is required when the RSS scraper is enabled")
+		}
+	}
+	if c.YouTube.Enabled {
+		if c.YouTube.APIKey == "" {
+			v.addf(prefix+".youTube.apiKey", "required when the YouTube scraper is enabled")
+		}
+		if len(c.YouTube.VideoIDs) == 0 && c.YouTube.ChannelID == "" && c.YouTube.SearchQuery == "" {
+			v.addf(prefix+".youTube.videoIds", "videoIds, channelId or searchQuery is required when the YouTube scraper is enabled")
+		}
+	}
+	if c.YouTube.MaxVideos < 0 || c.YouTube.MaxVideos > 50 {
+		v.addf(prefix+".youTube.maxVideos", "must be between 0 and 50, got %d", c.YouTube.MaxVideos)
+	}
+	if c.Trustpilot.PageConcurrency < 0 {
+		v.addf(prefix+".trustpilot.pageConcurrency", "must not be negative, got %d", c.Trustpilot.PageConcurrency)
+	}
+
+	for _, pages := range []struct {
+		name  string
+		value int
+	}{
+		{"appStore", c.AppStore.MaxPages},
+		{"googlePlay", c.GooglePlay.MaxPages},
+		{"g2", c.G2.MaxPages},
+		{"trustpilot", c.Trustpilot.MaxPages},
+		{"hackerNews", c.HackerNews.MaxPages},
+		{"youTube", c.YouTube.MaxPages},
+	} {
+		if pages.value < 0 {
+			v.addf(prefix+"."+pages.name+".maxPages", "must not be negative, got %d", pages.value)
+		}
+	}
+
+	for i, site := range c.CustomSites {
+		if !site.Enabled {
+			continue
+		}
+		field := fmt.Sprintf("%s.customSites[%d]", prefix, i)
+		if site.Name == "" {
+			v.addf(field+".name", "required")
+		}
+		if site.URL == "" && len(site.ReviewURLs) == 0 {
+			v.addf(field+".url", "url or reviewUrls is required when the site is enabled")
+		}
+	}
+
+	c.RateLimits.validate(v, prefix+".rateLimits")
+	sources := make([]string, 0, len(c.SourceRateLimits))
+	for source := range c.SourceRateLimits {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		field := prefix + ".sourceRateLimits." + source
+		if !containsString(RateLimitedSources, source) {
+			v.addf(field, "unknown source %q (expected one of %s)", source, strings.Join(RateLimitedSources, ", "))
+			continue
+		}
+		c.SourceRateLimits[source].validate(v, field)
+	}
+
+	if c.ProxySettings.Enabled && c.ProxySettings.URL == "" && len(c.ProxySettings.URLs) == 0 {
+		v.addf(prefix+".proxySettings.urls", "url or urls is required when proxies are enabled")
+	}
+}
+
+func (c NotifierConfig) validate(v *validator, prefix string) {
+	if c.Email.Enabled {
+		field := prefix + ".email"
+		if c.Email.SMTPServer == "" {
+			v.addf(field+".smtpServer", "required when email notifications are enabled")
+		}
+		if c.Email.SMTPPort <= 0 || c.Email.SMTPPort > 65535 {
+			v.addf(field+".smtpPort", "must be between 1 and 65535, got %d", c.Email.SMTPPort)
+		}
+		if c.Email.FromAddress == "" {
+			v.addf(field+".fromAddress", "required when email notifications are enabled")
+		}
+	}
+
+	if c.Slack.Enabled && c.Slack.WebhookURL == "" && len(c.Slack.DeptChannels) == 0 {
+		v.addf(prefix+".slack.webhookUrl", "required when Slack notifications are enabled and no departmentChannels are set")
+	}
+	switch c.Slack.Format {
+	case "", SlackFormatAttachments, SlackFormatBlocks:
+	default:
+		v.addf(prefix+".slack.format", "must be %q or %q, got %q", SlackFormatAttachments, SlackFormatBlocks, c.Slack.Format)
+	}
+
+	if c.Webhook.Enabled && c.Webhook.URL == "" {
+		v.addf(prefix+".webhook.url", "required when webhook notifications are enabled")
+	}
+
+	if c.Databases.Enabled {
+		if c.Databases.Type == "" {
+			v.addf(prefix+".databases.type", "required when the database is enabled")
+		}
+		if c.Databases.Host == "" {
+			v.addf(prefix+".databases.host", "required when the database is enabled")
+		}
+	}
+
+	if c.MaxSendsPerMinute < 0 {
+		v.addf(prefix+".maxSendsPerMinute", "must not be negative, got %d", c.MaxSendsPerMinute)
+	}
+	if c.MaxContentLength < 0 {
+		v.addf(prefix+".maxContentLength", "must not be negative, got %d", c.MaxContentLength)
+	}
+	if c.DedupWindow < 0 {
+		v.addf(prefix+".dedupWindow", "must not be negative, got %s", c.DedupWindow)
+	}
+
+	if c.SeverityThreshold != "" && !containsString(Severities, c.SeverityThreshold) {
+		v.addf(prefix+".severityThreshold", "unknown severity %q (expected one of %s)", c.SeverityThreshold, strings.Join(Severities, ", "))
+	}
+	departments := make([]string, 0, len(c.DepartmentSeverityThresholds))
+	for department := range c.DepartmentSeverityThresholds {
+		departments = append(departments, department)
+	}
+	sort.Strings(departments)
+	for _, department := range departments {
+		if threshold := c.DepartmentSeverityThresholds[department]; !containsString(Severities, threshold) {
+			v.addf(prefix+".departmentSeverityThresholds."+department, "unknown severity %q (expected one of %s)", threshold, strings.Join(Severities, ", "))
+		}
+	}
+
+	departments = departments[:0]
+	for department := range c.DepartmentTemplates {
+		departments = append(departments, department)
+	}
+	sort.Strings(departments)
+	for _, department := range departments {
+		c.DepartmentTemplates[department].validate(v, prefix+".departmentTemplates."+department)
+	}
+}
+
+func (c NotificationTemplate) validate(v *validator, prefix string) {
+	for _, tmpl := range []struct {
+		name, text string
+	}{
+		{"subject", c.Subject},
+		{"email", c.Email},
+		{"slack", c.Slack},
+	} {
+		if _, err := template.New(tmpl.name).Parse(tmpl.text); err != nil {
+			v.addf(prefix+"."+tmpl.name, "invalid template: %v", err)
+		}
+	}
+}
+
+func (c RateLimitConfig) validate(v *validator, prefix string) {
+	if c.RequestsPerMinute < 0 {
+		v.addf(prefix+".requestsPerMinute", "must not be negative, got %d", c.RequestsPerMinute)
+	}
+	if c.PauseDuration < 0 {
+		v.addf(prefix+".pauseDuration", "must not be negative, got %s", c.PauseDuration)
+	}
+	if c.ScrapeTimeout < 0 {
+		v.addf(prefix+".scrapeTimeout", "must not be negative, got %s", c.ScrapeTimeout)
+	}
+}
+
+func (c TracingConfig) validate(v *validator, prefix string) {
+	if !c.Enabled {
+		return
+	}
+	if c.Endpoint == "" {
+		v.addf(prefix+".endpoint", "required when tracing is enabled")
+		return
+	}
+	if u, err := url.Parse(c.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		v.addf(prefix+".endpoint", "must be an http or https URL, got %q", c.Endpoint)
+	}
+}
+
+func (c StorageConfig) validate(v *validator, prefix string) {
+	if c.Database.Enabled {
+		field := prefix + ".database"
+		if !containsString(StorageDatabaseTypes, c.Database.Type) {
+			v.addf(field+".type", "unknown database type %q (expected one of %s)", c.Database.Type, strings.Join(StorageDatabaseTypes, ", "))
+		}
+		if c.Database.Host == "" {
+			v.addf(field+".host", "required when the database is enabled")
+		}
+		if c.Database.Type == "mongodb" && c.Database.DBName == "" {
+			v.addf(field+".dbName", "required for a mongodb database")
+		}
+	}
+	if c.SeenTTL < 0 {
+		v.addf(prefix+".seenTTL", "must not be negative, got %s", c.SeenTTL)
+	}
+}
+
+func (c WarehouseConfig) validate(v *validator, prefix string) {
+	if !c.Enabled {
+		return
+	}
+	if c.Driver == "" {
+		v.addf(prefix+".driver", "required when the warehouse export is enabled")
+	}
+	if c.DSN == "" {
+		v.addf(prefix+".dsn", "required when the warehouse export is enabled")
+	}
+	if c.Table == "" {
+		v.addf(prefix+".table", "required when the warehouse export is enabled")
+	}
+	if c.BatchSize < 0 {
+		v.addf(prefix+".batchSize", "must not be negative, got %d", c.BatchSize)
+	}
+	if c.Placeholder != "" && c.Placeholder != "?" && c.Placeholder != "$" {
+		v.addf(prefix+".placeholder", "must be \"?\" or \"$\", got %q", c.Placeholder)
+	}
+}
+
+func (c APIConfig) validate(v *validator, prefix string) {
+	if c.Port < 0 || c.Port > 65535 {
+		v.addf(prefix+".port", "must be between 0 and 65535, got %d", c.Port)
+	}
+	if c.RateLimit < 0 {
+		v.addf(prefix+".rateLimit", "must not be negative, got %d", c.RateLimit)
+	}
+	if c.RateLimitWindow < 0 {
+		v.addf(prefix+".rateLimitWindow", "must not be negative, got %s", c.RateLimitWindow)
+	}
+	if c.DashboardTrendDays < 0 {
+		v.addf(prefix+".dashboardTrendDays", "must not be negative, got %d", c.DashboardTrendDays)
+	}
+
+	names := make(map[string]bool)
+	keys := make(map[string]bool)
+	for i, apiKey := range c.APIKeys {
+		keyPrefix := fmt.Sprintf("%s.apiKeys[%d]", prefix, i)
+		if apiKey.Name == "" {
+			v.addf(keyPrefix+".name", "is required")
+		} else if names[apiKey.Name] {
+			v.addf(keyPrefix+".name", "duplicate API key name %q", apiKey.Name)
+		}
+		names[apiKey.Name] = true
+
+		if apiKey.Key == "" {
+			v.addf(keyPrefix+".key", "is required")
+		} else if keys[apiKey.Key] || apiKey.Key == c.AuthToken {
+			v.addf(keyPrefix+".key", "must be unique")
+		}
+		keys[apiKey.Key] = true
+
+		if len(apiKey.Scopes) == 0 {
+			v.addf(keyPrefix+".scopes", "at least one scope is required")
+		}
+		for j, scope := range apiKey.Scopes {
+			if scope != "*" && !containsString(APIScopes, scope) {
+				v.addf(fmt.Sprintf("%s.scopes[%d]", keyPrefix, j), "unknown scope %q (expected \"*\" or one of %s)", scope, strings.Join(APIScopes, ", "))
+			}
+		}
+	}
+}
+
+// Validate checks the analyzer settings
+func (c AnalyzerConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "analyzer")
+	return v.err()
+}
+
+func (c AnalyzerConfig) validate(v *validator, prefix string) {
+	if c.Mode != "" && !containsString(AnalyzerModes, c.Mode) {
+		v.addf(prefix+".mode", "unknown mode %q (expected one of %s)", c.Mode, strings.Join(AnalyzerModes, ", "))
+	}
+	if c.Mode == "openai" && c.APIKey == "" {
+		v.addf(prefix+".apiKey", "required when mode is %q", c.Mode)
+	}
+	if c.NegativeThreshold < -1 || c.NegativeThreshold > 1 {
+		v.addf(prefix+".negativeThreshold", "must be between -1 and 1, got %g", c.NegativeThreshold)
+	}
+	if c.RelevanceThreshold < 0 || c.RelevanceThreshold > 1 {
+		v.addf(prefix+".relevanceThreshold", "must be between 0 and 1, got %g", c.RelevanceThreshold)
+	}
+	if c.MaxConcurrentRequests < 0 {
+		v.addf(prefix+".maxConcurrentRequests", "must not be negative, got %d", c.MaxConcurrentRequests)
+	}
+	if c.CacheSize < 0 {
+		v.addf(prefix+".cacheSize", "must not be negative, got %d", c.CacheSize)
+	}
+	categories := make([]string, 0, len(c.CategoryKeywords))
+	for category := range c.CategoryKeywords {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	for _, category := range categories {
+		if category == "" {
+			v.addf(prefix+".categoryKeywords", "category names must not be empty")
+			continue
+		}
+		if len(c.CategoryKeywords[category]) == 0 {
+			v.addf(prefix+".categoryKeywords."+category, "at least one keyword is required")
+		}
+		for i, keyword := range c.CategoryKeywords[category] {
+			if strings.TrimSpace(keyword) == "" {
+				v.addf(fmt.Sprintf("%s.categoryKeywords.%s[%d]", prefix, category, i), "must not be empty")
+			}
+		}
+	}
+	if c.RatingWeight != nil && (*c.RatingWeight < 0 || *c.RatingWeight > 1) {
+		v.addf(prefix+".ratingWeight", "must be between 0 and 1, got %g", *c.RatingWeight)
+	}
+	c.RatingScale.validate(v, prefix+".ratingScale")
+	sources := make([]string, 0, len(c.SourceRatingScales))
+	for source := range c.SourceRatingScales {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+	for _, source := range sources {
+		c.SourceRatingScales[source].validate(v, prefix+".sourceRatingScales."+source)
+	}
+	if c.ReplaceDefaultCategories && len(c.CategoryKeywords) == 0 {
+		v.addf(prefix+".replaceDefaultCategories", "requires categoryKeywords")
+	}
+	for _, list := range []struct {
+		name  string
+		words []string
+	}{
+		{"positiveWords", c.PositiveWords},
+		{"negativeWords", c.NegativeWords},
+	} {
+		for i, word := range list.words {
+			if strings.TrimSpace(word) == "" {
+				v.addf(fmt.Sprintf("%s.%s[%d]", prefix, list.name, i), "must not be empty")
+			}
+		}
+	}
+	weighted := make([]string, 0, len(c.SentimentWeights))
+	for word := range c.SentimentWeights {
+		weighted = append(weighted, word)
+	}
+	sort.Strings(weighted)
+	for _, word := range weighted {
+		if weight := c.SentimentWeights[word]; weight < 0 {
+			v.addf(prefix+".sentimentWeights."+word, "must not be negative, got %g", weight)
+		}
+	}
+	for i, family := range c.AutoTags {
+		if !containsString(AutoTagFamilies, family) {
+			v.addf(fmt.Sprintf("%s.autoTags[%d]", prefix, i), "unknown tag family %q (expected one of %s)", family, strings.Join(AutoTagFamilies, ", "))
+		}
+	}
+}
+
+// Validate checks the router settings
+func (c RouterConfig) Validate() error {
+	v := &validator{}
+	c.validate(v, "router")
+	return v.err()
+}
+
+func (c EscalationConfig) validate(v *validator, prefix string) {
+	if c.SeverityThreshold == "" {
+		return
+	}
+	if !containsString(Severities, c.SeverityThreshold) {
+		v.addf(prefix+".severityThreshold", "unknown severity %q (expected one of %s)", c.SeverityThreshold, strings.Join(Severities, ", "))
+	}
+	if len(c.Departments) == 0 {
+		v.addf(prefix+".departments", "at least one department is required when escalation is enabled")
+	}
+	for i, department := range c.Departments {
+		if department == "" {
+			v.addf(fmt.Sprintf("%s.departments[%d]", prefix, i), "required")
+		}
+	}
+}
+
+func (c RouterConfig) validate(v *validator, prefix string) {
+	c.Escalation.validate(v, prefix+".escalation")
+
+	languages := make([]string, 0, len(c.LanguageDepartments))
+	for language := range c.LanguageDepartments {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+	for _, language := range languages {
+		department := c.LanguageDepartments[language]
+		field := fmt.Sprintf("%s.languageDepartments[%q]", prefix, language)
+		if language == "" || language != strings.ToLower(language) {
+			v.addf(field, "language must be a lowercase code such as \"de\"")
+		}
+		if department == "" {
+			v.addf(field, "department is required")
+		}
+	}
+	for i, mapping := range c.Mappings {
+		field := fmt.Sprintf("%s.mappings[%d]", prefix, i)
+		if mapping.Category == "" {
+			v.addf(field+".category", "required")
+		}
+		if mapping.Department == "" {
+			v.addf(field+".department", "required")
+		}
+		if mapping.Priority < 0 {
+			v.addf(field+".priority", "must not be negative, got %d", mapping.Priority)
+		}
+	}
+}
+
+// validate checks that a configured rating scale spans a range; the zero
+// value leaves the default in place
+func (r RatingScale) validate(v *validator, prefix string) {
+	if r != (RatingScale{}) && r.Max <= r.Min {
+		v.addf(prefix, "max must be greater than min, got %g to %g", r.Min, r.Max)
+	}
+}
+
+// validateKeywordExpressions checks that each search keyword parses as a
+// boolean keyword expression
+func validateKeywordExpressions(v *validator, field string, keywords []string) {
+	for i, keyword := range keywords {
+		if _, err := query.Parse(keyword); err != nil {
+			v.addf(fmt.Sprintf("%s[%d]", field, i), "invalid keyword expression %q: %v", keyword, err)
+		}
+	}
+}
+
+// validateAlgoliaExpressions checks that each valid keyword expression of an
+// Algolia search expands to no more than query.MaxAlgoliaSearches searches
+func validateAlgoliaExpressions(v *validator, field string, keywords []string) {
+	for i, keyword := range keywords {
+		expr, err := query.Parse(keyword)
+		if err != nil {
+			continue
+		}
+		if _, err := expr.Algolia(); err != nil {
+			v.addf(fmt.Sprintf("%s[%d]", field, i), "invalid keyword expression %q: %v", keyword, err)
+		}
+	}
+}
+
+// containsString reports whether s is in list
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
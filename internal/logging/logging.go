@@ -0,0 +1,57 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// Structured field names shared by every component
+const (
+	KeyComponent = "component"
+	KeyReviewID  = "review_id"
+	KeySource    = "source"
+)
+
+// ParseLevel converts a config log level (debug, info, warn or error) to a
+// slog.Level. An empty level means info.
+func ParseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q", level)
+	}
+}
+
+// New returns a logger writing JSON lines to w, dropping records below level
+func New(w io.Writer, level string) (*slog.Logger, error) {
+	lvl, err := ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl})), nil
+}
+
+// Component returns logger tagged with the component name. A nil logger
+// falls back to slog.Default so components work without an injected logger.
+func Component(logger *slog.Logger, name string) *slog.Logger {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return logger.With(KeyComponent, name)
+}
+
+// WithReview returns logger tagged with the review's ID and source
+func WithReview(logger *slog.Logger, review models.Review) *slog.Logger {
+	return logger.With(KeyReviewID, review.ID, KeySource, review.Source)
+}
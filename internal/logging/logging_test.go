@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewLoggerWritesJSONFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "debug")
+	assert.NoError(t, err)
+
+	review := models.Review{ID: "g2-42", Source: "g2"}
+	WithReview(Component(logger, "analyzer"), review).Debug("review analyzed", "negative", true)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "DEBUG", entry["level"])
+	assert.Equal(t, "review analyzed", entry["msg"])
+	assert.Equal(t, "analyzer", entry["component"])
+	assert.Equal(t, "g2-42", entry["review_id"])
+	assert.Equal(t, "g2", entry["source"])
+	assert.Equal(t, true, entry["negative"])
+}
+
+func TestNewFiltersBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn")
+	assert.NoError(t, err)
+
+	logger.Info("dropped")
+	logger.Warn("kept")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"msg":"kept"`)
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := ParseLevel("")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelInfo, level)
+
+	level, err = ParseLevel("ERROR")
+	assert.NoError(t, err)
+	assert.Equal(t, slog.LevelError, level)
+
+	_, err = ParseLevel("verbose")
+	assert.Error(t, err)
+}
@@ -0,0 +1,125 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// DefaultTrendDays is the length of the daily trend series when none is configured
+const DefaultTrendDays = 7
+
+// topCategoryLimit caps how many intent categories the dashboard reports
+const topCategoryLimit = 5
+
+// Dashboard aggregates analyzed reviews into dashboard metrics. RecentTrends
+// holds one entry per UTC day for the trendDays days ending on now, oldest
+// first, so days without reviews still appear with zero counts.
+func Dashboard(reviews []models.AnalyzedReview, trendDays int, now time.Time) models.DashboardMetrics {
+	if trendDays <= 0 {
+		trendDays = DefaultTrendDays
+	}
+
+	dashboard := models.DashboardMetrics{
+		TotalReviews:        len(reviews),
+		DepartmentWorkloads: make(map[string]int),
+		SourceBreakdown:     make(map[string]int),
+		RecentTrends:        make([]models.DailyMetric, trendDays),
+	}
+
+	// Daily buckets, indexed from the oldest day in the window
+	first, end := window(trendDays, now)
+	daySentiment := make([]float64, trendDays)
+	dayAnalyzed := make([]int, trendDays)
+	for i := range dashboard.RecentTrends {
+		dashboard.RecentTrends[i].Date = first.AddDate(0, 0, i)
+	}
+
+	categories := make(map[string]int)
+	var sentimentTotal float64
+	var analyzed int
+
+	for _, entry := range reviews {
+		dashboard.SourceBreakdown[entry.Review.Source]++
+		if entry.Department != "" {
+			dashboard.DepartmentWorkloads[entry.Department]++
+		}
+
+		day := -1
+		if posted := reviewTime(entry.Review); !posted.Before(first) && posted.Before(end) {
+			day = int(posted.Sub(first) / (24 * time.Hour))
+			dashboard.RecentTrends[day].ReviewCount++
+		}
+
+		if entry.Analysis == nil {
+			continue
+		}
+		analyzed++
+		sentimentTotal += entry.Analysis.SentimentScore
+		if entry.Analysis.IntentCategory != "" {
+			categories[entry.Analysis.IntentCategory]++
+		}
+		if entry.Analysis.IsNegative {
+			dashboard.NegativeReviews++
+		}
+
+		if day >= 0 {
+			dayAnalyzed[day]++
+			daySentiment[day] += entry.Analysis.SentimentScore
+			if entry.Analysis.IsNegative {
+				dashboard.RecentTrends[day].NegativeCount++
+			}
+		}
+	}
+
+	if analyzed > 0 {
+		dashboard.AverageSentiment = sentimentTotal / float64(analyzed)
+	}
+	for i := range dashboard.RecentTrends {
+		if dayAnalyzed[i] > 0 {
+			dashboard.RecentTrends[i].AverageSentiment = daySentiment[i] / float64(dayAnalyzed[i])
+		}
+	}
+	dashboard.TopCategories = topCategories(categories, topCategoryLimit)
+
+	return dashboard
+}
+
+// window returns the start of the oldest of the days UTC days ending on now,
+// and the end of the day now falls in
+func window(days int, now time.Time) (first, end time.Time) {
+	today := now.UTC().Truncate(24 * time.Hour)
+	return today.AddDate(0, 0, -(days - 1)), today.AddDate(0, 0, 1)
+}
+
+// reviewTime is when the review was posted, or retrieved if the source gave no date
+func reviewTime(review models.Review) time.Time {
+	if review.CreatedAt.IsZero() {
+		return review.RetrievedAt.UTC()
+	}
+	return review.CreatedAt.UTC()
+}
+
+// topCategories keeps the limit most frequent categories, breaking ties by name
+func topCategories(counts map[string]int, limit int) map[string]int {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	if len(names) > limit {
+		names = names[:limit]
+	}
+
+	top := make(map[string]int, len(names))
+	for _, name := range names {
+		top[name] = counts[name]
+	}
+	return top
+}
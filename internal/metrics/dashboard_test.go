@@ -0,0 +1,77 @@
+package metrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2025, 3, 10, 15, 0, 0, 0, time.UTC)
+
+// analyzed builds a processed review posted daysAgo days before testNow
+func analyzed(source string, daysAgo int, score float64, category, department string) models.AnalyzedReview {
+	return models.AnalyzedReview{
+		Review: models.Review{Source: source, CreatedAt: testNow.AddDate(0, 0, -daysAgo)},
+		Analysis: &models.AnalysisResult{
+			SentimentScore: score,
+			IsNegative:     score <= -0.3,
+			IntentCategory: category,
+		},
+		Department: department,
+	}
+}
+
+func TestDashboardAggregatesReviews(t *testing.T) {
+	reviews := []models.AnalyzedReview{
+		analyzed("twitter", 0, -0.8, "bug_report", "engineering"),
+		analyzed("twitter", 0, 0.4, "praise", ""),
+		analyzed("reddit", 1, -0.6, "bug_report", "engineering"),
+		analyzed("g2", 2, -0.4, "billing_licensing", "finance"),
+		analyzed("g2", 10, 0.2, "feature_request", ""),
+		{Review: models.Review{Source: "reddit", CreatedAt: testNow}}, // analysis failed
+	}
+
+	d := Dashboard(reviews, 3, testNow)
+
+	assert.Equal(t, 6, d.TotalReviews)
+	assert.Equal(t, 3, d.NegativeReviews)
+	assert.InDelta(t, -0.24, d.AverageSentiment, 1e-9, "averaged over analyzed reviews only")
+	assert.Equal(t, map[string]int{"bug_report": 2, "praise": 1, "billing_licensing": 1, "feature_request": 1}, d.TopCategories)
+	assert.Equal(t, map[string]int{"engineering": 2, "finance": 1}, d.DepartmentWorkloads)
+	assert.Equal(t, map[string]int{"twitter": 2, "reddit": 2, "g2": 2}, d.SourceBreakdown)
+
+	// The 10-day-old review falls outside the 3-day window
+	assert.Len(t, d.RecentTrends, 3)
+	assert.Equal(t, time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC), d.RecentTrends[0].Date)
+	assert.Equal(t, models.DailyMetric{Date: time.Date(2025, 3, 8, 0, 0, 0, 0, time.UTC), ReviewCount: 1, NegativeCount: 1, AverageSentiment: -0.4}, d.RecentTrends[0])
+	assert.Equal(t, 1, d.RecentTrends[1].ReviewCount)
+	assert.Equal(t, 3, d.RecentTrends[2].ReviewCount)
+	assert.Equal(t, 1, d.RecentTrends[2].NegativeCount)
+	assert.InDelta(t, -0.2, d.RecentTrends[2].AverageSentiment, 1e-9)
+}
+
+func TestDashboardLimitsTopCategories(t *testing.T) {
+	var reviews []models.AnalyzedReview
+	for i, category := range []string{"a", "b", "b", "c", "c", "c", "d", "e", "f", "g"} {
+		reviews = append(reviews, analyzed("twitter", i%2, 0, category, ""))
+	}
+
+	d := Dashboard(reviews, 0, testNow)
+
+	assert.Equal(t, map[string]int{"c": 3, "b": 2, "a": 1, "d": 1, "e": 1}, d.TopCategories)
+	assert.Len(t, d.RecentTrends, DefaultTrendDays)
+}
+
+func TestDashboardEmpty(t *testing.T) {
+	d := Dashboard(nil, 2, testNow)
+
+	assert.Zero(t, d.TotalReviews)
+	assert.Zero(t, d.AverageSentiment)
+	assert.Empty(t, d.TopCategories)
+	assert.Equal(t, []models.DailyMetric{
+		{Date: time.Date(2025, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{Date: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)},
+	}, d.RecentTrends)
+}
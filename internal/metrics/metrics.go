@@ -0,0 +1,134 @@
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name
+const namespace = "review_scraper"
+
+// Metrics holds the Prometheus collectors for the pipeline. A nil *Metrics is
+// valid and records nothing, so components can be used without instrumentation.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	reviewsScraped    *prometheus.CounterVec
+	scrapeErrors      *prometheus.CounterVec
+	scraperLatency    *prometheus.HistogramVec
+	reviewsAnalyzed   *prometheus.CounterVec
+	negativeReviews   prometheus.Counter
+	analyzerLatency   *prometheus.HistogramVec
+	notificationsSent *prometheus.CounterVec
+	notifyErrors      *prometheus.CounterVec
+}
+
+// New creates a Metrics instance with its own registry
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		reviewsScraped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviews_scraped_total",
+			Help:      "Reviews returned by scrapers, by source.",
+		}, []string{"source"}),
+		scrapeErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "scrape_errors_total",
+			Help:      "Scraper runs that returned an error, by source.",
+		}, []string{"source"}),
+		scraperLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "scraper_duration_seconds",
+			Help:      "Time taken by a single scraper run, by source.",
+			Buckets:   []float64{0.1, 0.5, 1, 5, 15, 30, 60, 120, 300, 600},
+		}, []string{"source"}),
+		reviewsAnalyzed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "reviews_analyzed_total",
+			Help:      "Reviews analyzed, by analyzer mode.",
+		}, []string{"mode"}),
+		negativeReviews: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "negative_reviews_total",
+			Help:      "Analyzed reviews classified as negative.",
+		}),
+		analyzerLatency: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "analyzer_duration_seconds",
+			Help:      "Time taken to analyze a review, by analyzer mode.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"mode"}),
+		notificationsSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notifications_sent_total",
+			Help:      "Notifications delivered, by channel.",
+		}, []string{"channel"}),
+		notifyErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "notification_errors_total",
+			Help:      "Notifications that failed to send, by channel.",
+		}, []string{"channel"}),
+	}
+
+	m.registry.MustRegister(
+		m.reviewsScraped,
+		m.scrapeErrors,
+		m.scraperLatency,
+		m.reviewsAnalyzed,
+		m.negativeReviews,
+		m.analyzerLatency,
+		m.notificationsSent,
+		m.notifyErrors,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveScrape records a scraper run
+func (m *Metrics) ObserveScrape(source string, reviews int, err error, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.scraperLatency.WithLabelValues(source).Observe(duration.Seconds())
+	if err != nil {
+		m.scrapeErrors.WithLabelValues(source).Inc()
+		return
+	}
+	m.reviewsScraped.WithLabelValues(source).Add(float64(reviews))
+}
+
+// ObserveAnalysis records a completed analysis
+func (m *Metrics) ObserveAnalysis(mode string, negative bool, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.reviewsAnalyzed.WithLabelValues(mode).Inc()
+	m.analyzerLatency.WithLabelValues(mode).Observe(duration.Seconds())
+	if negative {
+		m.negativeReviews.Inc()
+	}
+}
+
+// ObserveNotification records a notification attempt on a channel
+func (m *Metrics) ObserveNotification(channel string, err error) {
+	if m == nil {
+		return
+	}
+	if err != nil {
+		m.notifyErrors.WithLabelValues(channel).Inc()
+		return
+	}
+	m.notificationsSent.WithLabelValues(channel).Inc()
+}
@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/Infoblox-CTO/review-scraper/pkg/taxonomy"
+)
+
+// ProductSentiments rolls up the analyzed reviews posted in the days days
+// ending on now by the products they mention, as detected by the shared
+// taxonomy. A review mentioning several products counts for each of them.
+// Products are ordered by average sentiment, worst first.
+func ProductSentiments(reviews []models.AnalyzedReview, days int, now time.Time) []models.ProductSentiment {
+	if days <= 0 {
+		days = DefaultTrendDays
+	}
+	first, end := window(days, now)
+
+	products := make(map[string]*models.ProductSentiment)
+	for _, entry := range reviews {
+		if entry.Analysis == nil {
+			continue
+		}
+		if posted := reviewTime(entry.Review); posted.Before(first) || !posted.Before(end) {
+			continue
+		}
+
+		for product := range taxonomy.ProductMentions(entry.Review.Title + "\n" + entry.Review.Content) {
+			summary, found := products[product]
+			if !found {
+				summary = &models.ProductSentiment{Product: product}
+				products[product] = summary
+			}
+			summary.ReviewCount++
+			summary.AverageSentiment += entry.Analysis.SentimentScore
+			if entry.Analysis.IsNegative {
+				summary.NegativeCount++
+			}
+		}
+	}
+
+	rollup := make([]models.ProductSentiment, 0, len(products))
+	for _, summary := range products {
+		summary.AverageSentiment /= float64(summary.ReviewCount)
+		summary.NegativeShare = float64(summary.NegativeCount) / float64(summary.ReviewCount)
+		rollup = append(rollup, *summary)
+	}
+	sort.Slice(rollup, func(i, j int) bool {
+		if rollup[i].AverageSentiment != rollup[j].AverageSentiment {
+			return rollup[i].AverageSentiment < rollup[j].AverageSentiment
+		}
+		return rollup[i].Product < rollup[j].Product
+	})
+	return rollup
+}
@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// mentioning returns an analyzed review posted daysAgo days before testNow
+func mentioning(content string, daysAgo int, score float64) models.AnalyzedReview {
+	review := analyzed("g2", daysAgo, score, "", "")
+	review.Review.Content = content
+	return review
+}
+
+func TestProductSentimentsRollUpByProduct(t *testing.T) {
+	reviews := []models.AnalyzedReview{
+		mentioning("NIOS grid upgrade failed twice", 0, -0.8),
+		mentioning("The NIOS appliance is rock solid", 1, 0.6),
+		mentioning("BloxOne DHCP leases vanish", 2, -0.6),
+		mentioning("DHCP failover works well", 3, 0.6),
+		mentioning("Great support, quick answers to our questions", 0, 0.9), // No product
+		mentioning("NIOS crashed constantly", 10, -1.0),                     // Outside the window
+		{Review: models.Review{Content: "NIOS", CreatedAt: testNow}},        // Analysis failed
+	}
+
+	rollup := ProductSentiments(reviews, 7, testNow)
+
+	assert.Equal(t, []string{"bloxone", "nios", "dhcp"}, products(rollup))
+	assert.Equal(t, models.ProductSentiment{
+		Product: "bloxone", ReviewCount: 1, NegativeCount: 1, AverageSentiment: -0.6, NegativeShare: 1,
+	}, rollup[0])
+
+	nios := rollup[1]
+	assert.Equal(t, 2, nios.ReviewCount)
+	assert.Equal(t, 1, nios.NegativeCount)
+	assert.InDelta(t, -0.1, nios.AverageSentiment, 1e-9)
+	assert.Equal(t, 0.5, nios.NegativeShare)
+
+	dhcp := rollup[2]
+	assert.Equal(t, 2, dhcp.ReviewCount, "the BloxOne review also mentions DHCP")
+	assert.InDelta(t, 0.0, dhcp.AverageSentiment, 1e-9)
+	assert.Equal(t, 0.5, dhcp.NegativeShare)
+}
+
+func TestProductSentimentsWithoutReviews(t *testing.T) {
+	assert.Empty(t, ProductSentiments(nil, 0, testNow))
+}
+
+// products lists the product names of a rollup in order
+func products(rollup []models.ProductSentiment) []string {
+	names := make([]string, len(rollup))
+	for i, summary := range rollup {
+		names[i] = summary.Product
+	}
+	return names
+}
@@ -0,0 +1,40 @@
+package notifier
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// defaultMaxContentLength is the review length, in characters, notifications
+// show when no limit is configured
+const defaultMaxContentLength = 2000
+
+// contentLimit returns the configured notification content length limit
+func (n *Notifier) contentLimit() int {
+	if n.config.MaxContentLength > 0 {
+		return n.config.MaxContentLength
+	}
+	return defaultMaxContentLength
+}
+
+// truncateContent shortens content to at most limit characters, cutting at
+// the last word boundary and ending with an ellipsis. It reports whether
+// anything was cut; a limit of 0 or less leaves content whole.
+func truncateContent(content string, limit int) (string, bool) {
+	if limit <= 0 || utf8.RuneCountInString(content) <= limit {
+		return content, false
+	}
+
+	// Leave room for the ellipsis
+	runes := []rune(content)
+	cut := string(runes[:limit-1])
+
+	// Back up to the last word boundary unless the cut already falls on one
+	if !unicode.IsSpace(runes[limit-1]) {
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRightFunc(cut, unicode.IsSpace) + "…", true
+}
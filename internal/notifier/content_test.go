@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTruncateContent(t *testing.T) {
+	tests := []struct {
+		content   string
+		limit     int
+		want      string
+		truncated bool
+	}{
+		{"short review", 20, "short review", false},
+		{"exactly twelve", 14, "exactly twelve", false},
+		{"the upgrade broke dns resolution", 20, "the upgrade broke…", true},
+		{"the upgrade broke dns resolution", 19, "the upgrade broke…", true},
+		{"cut after word here", 12, "cut after…", true},
+		{"supercalifragilistic", 8, "superca…", true},
+		{"anything at all", 0, "anything at all", false},
+	}
+
+	for _, tt := range tests {
+		got, truncated := truncateContent(tt.content, tt.limit)
+		assert.Equal(t, tt.want, got, tt.content)
+		assert.Equal(t, tt.truncated, truncated, tt.content)
+		if tt.limit > 0 {
+			assert.LessOrEqual(t, utf8.RuneCountInString(got), tt.limit, tt.content)
+		}
+	}
+}
+
+func TestNotifyTruncatesOversizedReviewForSlack(t *testing.T) {
+	for _, format := range []string{config.SlackFormatAttachments, config.SlackFormatBlocks} {
+		t.Run(format, func(t *testing.T) {
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ = io.ReadAll(r.Body)
+			}))
+			defer server.Close()
+
+			n := New(config.NotifierConfig{
+				Slack:            config.SlackConfig{Enabled: true, WebhookURL: server.URL, Format: format},
+				MaxContentLength: 500,
+			})
+			dept, review, analysis := testNotificationInputs("review-long")
+			review.URL = "https://example.com/reviews/long"
+			review.Content = strings.Repeat("the dns upgrade failed again ", 1000)
+			require.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+			var message SlackMessage
+			require.NoError(t, json.Unmarshal(body, &message))
+			var text string
+			if format == config.SlackFormatBlocks {
+				text = message.Blocks[1].Text.Text
+			} else {
+				text = message.Attachments[0].Text
+			}
+
+			assert.Less(t, len(text), 4000)
+			assert.Contains(t, text, "failed again the…", "cut at a word boundary")
+			assert.True(t, strings.HasSuffix(text, "\n<https://example.com/reviews/long|View full review>"), text)
+		})
+	}
+}
+
+func TestNotifyTruncatesOversizedReviewForEmail(t *testing.T) {
+	smtp := newRecordingSMTPServer(t, nil)
+	n := emailNotifier(smtp, config.EmailConfig{}, nil)
+	n.config.MaxContentLength = 100
+
+	dept, review, analysis := testNotificationInputs("review-long")
+	review.URL = "https://example.com/reviews/long"
+	review.Content = strings.Repeat("slow ", 500)
+	require.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	sessions := smtp.received()
+	require.Len(t, sessions, 1)
+	assert.Contains(t, sessions[0].Data, "View full review: https://example.com/reviews/long")
+	assert.NotContains(t, sessions[0].Data, strings.Repeat("slow ", 21))
+}
@@ -0,0 +1,204 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+const (
+	// minReconnectDelay is the wait after the first failed connection attempt;
+	// each further failure doubles it up to maxReconnectDelay
+	minReconnectDelay = time.Second
+	maxReconnectDelay = 5 * time.Minute
+
+	// maxPendingNotifications caps the notifications queued while the database
+	// is unavailable; the oldest are dropped beyond it
+	maxPendingNotifications = 1000
+
+	// dbTimeout bounds each connection attempt and insert
+	dbTimeout = 10 * time.Second
+)
+
+// notificationsTable creates the table notifications are stored in if it does not exist yet
+const notificationsTable = `CREATE TABLE IF NOT EXISTS notifications (
+	id TEXT PRIMARY KEY,
+	review_id TEXT NOT NULL,
+	department_id TEXT NOT NULL,
+	status TEXT NOT NULL,
+	sent_at TIMESTAMPTZ NOT NULL,
+	payload JSONB NOT NULL
+)`
+
+// openDatabase connects to the configured database, checks the connection and
+// creates the notifications table. Only postgres is supported, using the
+// driver the service registers.
+func openDatabase(ctx context.Context, cfg config.DatabaseConfig) (*sql.DB, error) {
+	if cfg.Type != "postgres" {
+		return nil, fmt.Errorf("unsupported database type %q", cfg.Type)
+	}
+
+	db, err := sql.Open("postgres", cfg.PostgresDSN())
+	if err != nil {
+		return nil, fmt.Errorf("failed to open notification database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to reach notification database: %w", err)
+	}
+	if err := createSchema(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// createSchema creates the notifications table in db
+func createSchema(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, notificationsTable); err != nil {
+		return fmt.Errorf("failed to create notifications table: %w", err)
+	}
+	return nil
+}
+
+// connectToDatabase connects to the notification database in the background,
+// retrying with exponential backoff until it succeeds or Close is called.
+// Notifications are queued in the meantime and stored once connected.
+func (n *Notifier) connectToDatabase() {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	n.dbMutex.Lock()
+	n.stopConnect = cancel
+	n.connectDone = done
+	n.dbMutex.Unlock()
+
+	go func() {
+		defer close(done)
+		n.connectWithBackoff(ctx)
+	}()
+}
+
+// connectWithBackoff retries the connection until it succeeds or ctx is done
+func (n *Notifier) connectWithBackoff(ctx context.Context) {
+	delay := n.reconnectDelay
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, dbTimeout)
+		db, err := n.connectDB(attemptCtx)
+		cancel()
+		if err == nil {
+			n.setDatabase(db)
+			return
+		}
+
+		n.logger.Warn("notification database unavailable, retrying",
+			"attempt", attempt, "retry_in", delay.String(), "error", err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return
+		}
+
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// setDatabase marks the database connected and stores the notifications
+// queued while it was not
+func (n *Notifier) setDatabase(db *sql.DB) {
+	n.dbMutex.Lock()
+	n.db = db
+	n.dbConnected = true
+	pending := n.pendingNotifications
+	n.pendingNotifications = nil
+	n.dbMutex.Unlock()
+
+	n.logger.Info("notification database connected", "queued", len(pending))
+	for _, notification := range pending {
+		n.storeNotificationInDB(db, notification)
+	}
+}
+
+// persistNotification stores a notification in the database, queueing it
+// while the database is not yet connected
+func (n *Notifier) persistNotification(notification models.Notification) {
+	n.dbMutex.Lock()
+	if !n.dbConnected {
+		n.pendingNotifications = append(n.pendingNotifications, notification)
+		if overflow := len(n.pendingNotifications) - maxPendingNotifications; overflow > 0 {
+			n.pendingNotifications = n.pendingNotifications[overflow:]
+		}
+		n.dbMutex.Unlock()
+		return
+	}
+	db := n.db
+	n.dbMutex.Unlock()
+
+	n.storeNotificationInDB(db, notification)
+}
+
+// storeNotificationInDB saves a notification to the database. A failed insert
+// is logged rather than failing the notification, which was still sent.
+func (n *Notifier) storeNotificationInDB(db *sql.DB, notification models.Notification) {
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		logging.WithReview(n.logger, notification.Review).Warn("failed to encode notification for storage", "error", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO notifications (id, review_id, department_id, status, sent_at, payload) VALUES ($1, $2, $3, $4, $5, $6)`,
+		notification.ID, notification.Review.ID, notification.Department.ID, notification.Status, notification.SentAt, payload)
+	if err != nil {
+		logging.WithReview(n.logger, notification.Review).Warn("failed to store notification", "notification_id", notification.ID, "error", err)
+	}
+}
+
+// databaseStatus reports whether the database is connected and how many
+// notifications are waiting for it
+func (n *Notifier) databaseStatus() (connected bool, pending int) {
+	n.dbMutex.Lock()
+	defer n.dbMutex.Unlock()
+	return n.dbConnected, len(n.pendingNotifications)
+}
+
+// Close stops connecting to the notification database and closes the
+// connection. Queued notifications that were never stored are dropped.
+func (n *Notifier) Close() error {
+	n.dbMutex.Lock()
+	stop, done := n.stopConnect, n.connectDone
+	n.dbMutex.Unlock()
+
+	if stop == nil {
+		return nil
+	}
+	stop()
+	<-done
+
+	n.dbMutex.Lock()
+	defer n.dbMutex.Unlock()
+	if dropped := len(n.pendingNotifications); dropped > 0 {
+		n.logger.Warn("dropping notifications never stored in the database", "dropped", dropped)
+		n.pendingNotifications = nil
+	}
+	if n.db == nil {
+		return nil
+	}
+	n.dbConnected = false
+	if err := n.db.Close(); err != nil {
+		return fmt.Errorf("failed to close notification database: %w", err)
+	}
+	return nil
+}
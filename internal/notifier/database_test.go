@@ -0,0 +1,179 @@
+package notifier
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// newDatabaseNotifier returns a notifier persisting to the database connect
+// returns, retrying quickly while it fails
+func newDatabaseNotifier(connect func(ctx context.Context) (*sql.DB, error)) *Notifier {
+	n := New(config.NotifierConfig{})
+	n.config.Databases = config.DatabaseConfig{Enabled: true, Type: "postgres", Host: "db.example.com"}
+	n.connectDB = connect
+	n.reconnectDelay = time.Millisecond
+	n.connectToDatabase()
+	return n
+}
+
+// storedNotification returns a stored notification for a review
+func storedNotification(id string) models.Notification {
+	department, review, analysis := testNotificationInputs("review-" + id)
+	return models.Notification{ID: id, Review: review, Analysis: analysis, Department: department, Status: StatusSent}
+}
+
+func TestDatabaseReconnectsAndFlushesQueuedNotifications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	var attempts int32
+	available := make(chan struct{})
+	n := newDatabaseNotifier(func(ctx context.Context) (*sql.DB, error) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			return nil, errors.New("connection refused")
+		}
+		<-available
+		return db, nil
+	})
+
+	// Notifications arriving while the database is down are queued
+	n.cacheNotification(storedNotification("n1"))
+	n.cacheNotification(storedNotification("n2"))
+	connected, pending := n.databaseStatus()
+	assert.False(t, connected)
+	assert.Equal(t, 2, pending)
+
+	for _, id := range []string{"n1", "n2", "n3"} {
+		mock.ExpectExec("INSERT INTO notifications").
+			WithArgs(id, "review-"+id, "engineering", StatusSent, sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	close(available)
+
+	assert.Eventually(t, func() bool {
+		connected, pending := n.databaseStatus()
+		return connected && pending == 0
+	}, time.Second, time.Millisecond)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, true, n.GetStats()["database_connected"])
+
+	// Once connected, notifications are stored directly
+	n.cacheNotification(storedNotification("n3"))
+
+	mock.ExpectClose()
+	assert.NoError(t, n.Close())
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatabaseQueueIsBounded(t *testing.T) {
+	n := newDatabaseNotifier(func(ctx context.Context) (*sql.DB, error) {
+		return nil, errors.New("connection refused")
+	})
+	defer n.Close()
+
+	for i := 0; i < maxPendingNotifications+5; i++ {
+		n.cacheNotification(storedNotification("n"))
+	}
+	_, pending := n.databaseStatus()
+	assert.Equal(t, maxPendingNotifications, pending)
+}
+
+func TestCloseStopsReconnecting(t *testing.T) {
+	var attempts int32
+	n := newDatabaseNotifier(func(ctx context.Context) (*sql.DB, error) {
+		atomic.AddInt32(&attempts, 1)
+		return nil, errors.New("connection refused")
+	})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&attempts) >= 2 }, time.Second, time.Millisecond)
+	assert.NoError(t, n.Close())
+
+	stopped := atomic.LoadInt32(&attempts)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, stopped, atomic.LoadInt32(&attempts))
+}
+
+func TestOpenDatabaseRejectsUnsupportedTypes(t *testing.T) {
+	_, err := openDatabase(context.Background(), config.DatabaseConfig{Type: "mongodb", Host: "localhost"})
+	assert.EqualError(t, err, `unsupported database type "mongodb"`)
+}
+
+func TestCreateSchemaCreatesNotificationsTable(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("CREATE TABLE IF NOT EXISTS notifications (")).WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.NoError(t, createSchema(context.Background(), db))
+
+	mock.ExpectExec("CREATE TABLE").WillReturnError(errors.New("permission denied"))
+	assert.EqualError(t, createSchema(context.Background(), db), "failed to create notifications table: permission denied")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+// recordingStore keeps the notifications saved to it
+type recordingStore struct {
+	mu    sync.Mutex
+	saved []models.Notification
+}
+
+func (s *recordingStore) SaveNotification(ctx context.Context, notification models.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saved = append(s.saved, notification)
+	return nil
+}
+
+func (s *recordingStore) HasNotification(ctx context.Context, review models.Review, status string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Only the latest copy of each notification counts
+	latest := make(map[string]models.Notification)
+	for _, notification := range s.saved {
+		latest[notification.ID] = notification
+	}
+	for _, notification := range latest {
+		if notification.Review.ID == review.ID && notification.Status == status {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func TestNotificationsAndStatusChangesAreSavedToStore(t *testing.T) {
+	store := &recordingStore{}
+	n := New(config.NotifierConfig{})
+	n.SetStore(store)
+
+	n.cacheNotification(storedNotification("n1"))
+	assert.NoError(t, n.UpdateStatus("n1", StatusAcknowledged, "investigating"))
+
+	if assert.Len(t, store.saved, 2) {
+		assert.Equal(t, StatusSent, store.saved[0].Status)
+		assert.Equal(t, "n1", store.saved[1].ID)
+		assert.Equal(t, StatusAcknowledged, store.saved[1].Status)
+		assert.Equal(t, "investigating", store.saved[1].ResponseInfo)
+	}
+}
+
+func TestDryRunNotificationsAreNotSavedToStore(t *testing.T) {
+	store := &recordingStore{}
+	n := New(config.NotifierConfig{DryRun: true})
+	n.SetStore(store)
+
+	n.cacheNotification(storedNotification("n1"))
+
+	assert.Empty(t, store.saved)
+}
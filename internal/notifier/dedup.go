@@ -0,0 +1,53 @@
+package notifier
+
+import "time"
+
+// recentNotification records a review's latest notification to a department
+// within the dedup window
+type recentNotification struct {
+	id     string
+	sentAt time.Time
+}
+
+// claimReview records that reviewID is being notified to departmentID as
+// notificationID at now, unless it was already notified to that department
+// within the dedup window; an escalated review still reaches each of its
+// departments. A suppressed repeat returns the earlier notification's ID and
+// false. Reviews are never suppressed when no window is configured or they
+// have no ID.
+func (n *Notifier) claimReview(reviewID, departmentID, notificationID string, now time.Time) (string, bool) {
+	window := n.config.DedupWindow.Duration()
+	if window <= 0 || reviewID == "" {
+		return notificationID, true
+	}
+
+	n.recentMutex.Lock()
+	defer n.recentMutex.Unlock()
+
+	// Forget reviews whose window has passed, keeping the index small
+	for id, recent := range n.recentNotified {
+		if now.Sub(recent.sentAt) >= window {
+			delete(n.recentNotified, id)
+		}
+	}
+
+	key := reviewID + "\x00" + departmentID
+	if recent, found := n.recentNotified[key]; found {
+		return recent.id, false
+	}
+	n.recentNotified[key] = recentNotification{id: notificationID, sentAt: now}
+	return notificationID, true
+}
+
+// releaseReview forgets the claim notificationID holds on reviewID for
+// departmentID, so a notification that reached no channel can be retried
+// within the dedup window. A later claim is left in place.
+func (n *Notifier) releaseReview(reviewID, departmentID, notificationID string) {
+	n.recentMutex.Lock()
+	defer n.recentMutex.Unlock()
+
+	key := reviewID + "\x00" + departmentID
+	if recent, found := n.recentNotified[key]; found && recent.id == notificationID {
+		delete(n.recentNotified, key)
+	}
+}
@@ -0,0 +1,132 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNotifySuppressesRepeatWithinDedupWindow(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook:     config.WebhookConfig{Enabled: true, URL: server.URL},
+		Dashboard:   config.DashboardConfig{Enabled: true},
+		DedupWindow: config.Duration(time.Hour),
+	})
+
+	dept, review, analysis := testNotificationInputs("review-dup")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	assert.Len(t, capture.bodies, 1, "the repeat is not sent")
+	assert.Len(t, n.GetNotifications(review.ID), 1)
+
+	// Other reviews are unaffected
+	_, other, _ := testNotificationInputs("review-other")
+	assert.NoError(t, n.Notify(context.Background(), dept, other, analysis))
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestNotifyWithoutDedupWindowSendsRepeats(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{Webhook: config.WebhookConfig{Enabled: true, URL: server.URL}})
+
+	dept, review, analysis := testNotificationInputs("review-dup")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestClaimReviewExpires(t *testing.T) {
+	n := New(config.NotifierConfig{DedupWindow: config.Duration(time.Hour)})
+	start := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	id, ok := n.claimReview("review-1", "engineering", "n1", start)
+	assert.True(t, ok)
+	assert.Equal(t, "n1", id)
+
+	id, ok = n.claimReview("review-1", "engineering", "n2", start.Add(59*time.Minute))
+	assert.False(t, ok)
+	assert.Equal(t, "n1", id, "a suppressed repeat reports the earlier notification")
+
+	id, ok = n.claimReview("review-1", "engineering", "n3", start.Add(time.Hour))
+	assert.True(t, ok)
+	assert.Equal(t, "n3", id)
+	assert.Len(t, n.recentNotified, 1)
+
+	_, ok = n.claimReview("", "engineering", "n4", start)
+	assert.True(t, ok, "reviews without an ID are never suppressed")
+}
+
+func TestClaimReviewIsPerDepartment(t *testing.T) {
+	n := New(config.NotifierConfig{DedupWindow: config.Duration(time.Hour)})
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	_, ok := n.claimReview("review-1", "security", "n1", now)
+	assert.True(t, ok)
+	_, ok = n.claimReview("review-1", "management", "n2", now)
+	assert.True(t, ok, "an escalation department is notified too")
+	id, ok := n.claimReview("review-1", "security", "n3", now)
+	assert.False(t, ok)
+	assert.Equal(t, "n1", id)
+}
+
+func TestNotifyRetriesWithinDedupWindowWhenEveryChannelFailed(t *testing.T) {
+	var failing atomic.Bool
+	failing.Store(true)
+	var delivered int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if failing.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		atomic.AddInt32(&delivered, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook:     config.WebhookConfig{Enabled: true, URL: server.URL},
+		DedupWindow: config.Duration(time.Hour),
+	})
+
+	dept, review, analysis := testNotificationInputs("review-retry")
+	assert.Error(t, n.Notify(context.Background(), dept, review, analysis))
+
+	// The failed attempt does not suppress the retry
+	failing.Store(false)
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&delivered))
+
+	// The delivered one does
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&delivered))
+}
+
+func TestReleaseReviewKeepsLaterClaim(t *testing.T) {
+	n := New(config.NotifierConfig{DedupWindow: config.Duration(time.Hour)})
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+
+	n.claimReview("review-1", "security", "n1", now)
+	n.releaseReview("review-1", "security", "n1")
+	_, ok := n.claimReview("review-1", "security", "n2", now)
+	assert.True(t, ok, "a released claim can be taken again")
+
+	n.releaseReview("review-1", "security", "n1")
+	id, ok := n.claimReview("review-1", "security", "n3", now)
+	assert.False(t, ok, "releasing an older claim leaves the later one")
+	assert.Equal(t, "n2", id)
+}
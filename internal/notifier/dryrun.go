@@ -0,0 +1,51 @@
+package notifier
+
+import (
+	"net/url"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// logDryRun logs the channel, recipient and subject of each send Notify would
+// make for notification, and counts it, without any outbound I/O
+func (n *Notifier) logDryRun(notification models.Notification) {
+	logger := logging.WithReview(n.logger, notification.Review).With(
+		"notification_id", notification.ID, "department", notification.Department.ID)
+	subject, err := n.notificationSubject(notification)
+	if err != nil {
+		subject = emailSubject(notification.Analysis)
+	}
+
+	if n.config.Email.Enabled {
+		logger.Info("dry run: would send notification",
+			"channel", "email", "recipient", n.emailRecipient(notification.Department), "subject", subject)
+	}
+	if n.config.Slack.Enabled {
+		// Webhook URLs carry credentials, so name the channel rather than log it
+		recipient := "default channel"
+		if channelURL, exists := n.config.Slack.DeptChannels[notification.Department.ID]; exists && channelURL != "" {
+			recipient = "department channel"
+		}
+		logger.Info("dry run: would send notification",
+			"channel", "slack", "recipient", recipient, "subject", subject)
+	}
+	if n.config.Webhook.Enabled {
+		logger.Info("dry run: would send notification",
+			"channel", "webhook", "recipient", webhookHost(n.config.Webhook.URL), "subject", subject)
+	}
+
+	n.cacheMutex.Lock()
+	n.dryRunCount++
+	n.cacheMutex.Unlock()
+}
+
+// webhookHost returns the host of a webhook URL, leaving out any path or
+// query that may carry a secret
+func webhookHost(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "unknown"
+	}
+	return u.Host
+}
@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyDryRunMakesNoOutboundCalls(t *testing.T) {
+	var httpCalls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&httpCalls, 1)
+	}))
+	defer server.Close()
+
+	smtpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer smtpListener.Close()
+	var smtpConns int32
+	go func() {
+		for {
+			conn, err := smtpListener.Accept()
+			if err != nil {
+				return
+			}
+			atomic.AddInt32(&smtpConns, 1)
+			conn.Close()
+		}
+	}()
+	smtpPort := smtpListener.Addr().(*net.TCPAddr).Port
+
+	n := New(config.NotifierConfig{
+		DryRun: true,
+		Email: config.EmailConfig{
+			Enabled:     true,
+			SMTPServer:  "127.0.0.1",
+			SMTPPort:    smtpPort,
+			FromAddress: "alerts@example.com",
+		},
+		Slack:     config.SlackConfig{Enabled: true, WebhookURL: server.URL},
+		Webhook:   config.WebhookConfig{Enabled: true, URL: server.URL},
+		Databases: config.DatabaseConfig{Enabled: true},
+	})
+	n.connectDB = nil // Any connection attempt would panic
+
+	dept, review, analysis := testNotificationInputs("review-dry")
+	require.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	require.NoError(t, n.Drain(context.Background()))
+
+	assert.Zero(t, atomic.LoadInt32(&httpCalls))
+	assert.Zero(t, atomic.LoadInt32(&smtpConns))
+
+	// The notification is still cached and counted
+	assert.Len(t, n.GetNotifications(review.ID), 1)
+	stats := n.GetStats()
+	assert.Equal(t, true, stats["dry_run"])
+	assert.Equal(t, 1, stats["dry_run_notifications"])
+	assert.Equal(t, 1, stats["notifications_cached"])
+	assert.Equal(t, 0, stats["database_pending"])
+}
+
+func TestWebhookHostOmitsPathAndQuery(t *testing.T) {
+	assert.Equal(t, "hooks.example.com", webhookHost("https://hooks.example.com/services/T000/B000/secret?token=x"))
+	assert.Equal(t, "unknown", webhookHost("not a url"))
+}
@@ -1,20 +1,30 @@
 package notifier
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"net/smtp"
+	"net/mail"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/internal/metrics"
+	"github.com/Infoblox-CTO/review-scraper/internal/tracing"
 	"github.com/Infoblox-CTO/review-scraper/pkg/models"
 	"github.com/google/uuid"
+	"golang.org/x/time/rate"
 )
 
 // Notifier handles sending notifications about negative reviews to departments
@@ -23,34 +33,139 @@ type Notifier struct {
 	httpClient  *http.Client
 	notifCache  map[string]models.Notification
 	cacheMutex  sync.RWMutex
-	db          *sql.DB
-	dbConnected bool
+	sendLimiter *rate.Limiter
+	smtpRootCAs *x509.CertPool // Trusted SMTP server certificates; nil uses the system roots
+	inFlight    sync.WaitGroup // Notify calls still sending
+	drainMutex  sync.Mutex     // Orders inFlight.Add against Drain
+	draining    bool           // Set by Drain; Notify is rejected from then on
+
+	probeResults []ProbeResult
+	probeMutex   sync.RWMutex
+
+	recentNotified map[string]recentNotification // Review and department IDs to their latest notification within the dedup window
+	recentMutex    sync.Mutex
+
+	dryRunCount int // Notifications logged instead of sent; guarded by cacheMutex
+
+	templates map[string]departmentTemplate // Custom wording by department ID
+
+	// The notification database, connected in the background; dbMutex guards
+	// the connection state and the notifications waiting for it
+	dbMutex              sync.Mutex
+	db                   *sql.DB
+	dbConnected          bool
+	pendingNotifications []models.Notification
+	connectDB            func(ctx context.Context) (*sql.DB, error)
+	reconnectDelay       time.Duration
+	stopConnect          context.CancelFunc
+	connectDone          chan struct{}
+
+	store NotificationStore // Keeps notifications alongside their reviews; nil if unset
+
+	metrics *metrics.Metrics
+	logger  *slog.Logger
+}
+
+// NotificationStore persists notifications and their status changes
+type NotificationStore interface {
+	SaveNotification(ctx context.Context, notification models.Notification) error
+
+	// HasNotification reports whether a notification about review with status is stored
+	HasNotification(ctx context.Context, review models.Review, status string) (bool, error)
 }
 
 // New creates a new notifier with the provided configuration
 func New(cfg config.NotifierConfig) *Notifier {
 	n := &Notifier{
-		config:     cfg,
-		httpClient: &http.Client{Timeout: 10 * time.Second},
-		notifCache: make(map[string]models.Notification),
+		config:         cfg,
+		httpClient:     &http.Client{Timeout: 10 * time.Second},
+		notifCache:     make(map[string]models.Notification),
+		logger:         logging.Component(nil, "notifier"),
+		recentNotified: make(map[string]recentNotification),
+		connectDB: func(ctx context.Context) (*sql.DB, error) {
+			return openDatabase(ctx, cfg.Databases)
+		},
+		reconnectDelay: minReconnectDelay,
 	}
 
-	// Connect to database if enabled
-	if cfg.Databases.Enabled {
+	// Departments whose templates don't parse keep the default wording
+	templates, err := parseDepartmentTemplates(cfg.DepartmentTemplates)
+	if err != nil {
+		n.logger.Warn("ignoring notification templates", "error", err)
+	}
+	n.templates = templates
+
+	// Throttle outbound sends so bursty runs don't trip Slack's rate limits
+	if cfg.MaxSendsPerMinute > 0 {
+		perSecond := rate.Limit(float64(cfg.MaxSendsPerMinute) / 60.0)
+		n.sendLimiter = rate.NewLimiter(perSecond, 1)
+	}
+
+	// Connect to database if enabled; a dry run stays off the network
+	if cfg.Databases.Enabled && !cfg.DryRun {
 		n.connectToDatabase()
 	}
 
 	return n
 }
 
-// connectToDatabase establishes a connection to the configured database
-func (n *Notifier) connectToDatabase() {
-	// This is a placeholder - in a real implementation, this would establish a database connection
-	n.dbConnected = false
+// SetMetrics enables instrumentation of notification sends
+func (n *Notifier) SetMetrics(m *metrics.Metrics) {
+	n.metrics = m
 }
 
+// SetStore saves each notification, and each change of its status, to store
+func (n *Notifier) SetStore(store NotificationStore) {
+	n.store = store
+}
+
+// SetLogger sets the structured logger used for delivery events
+func (n *Notifier) SetLogger(logger *slog.Logger) {
+	n.logger = logging.Component(logger, "notifier")
+}
+
+// Notification status values
+const (
+	StatusSent         = "sent"
+	StatusAcknowledged = "acknowledged"
+	StatusActioned     = "actioned"
+)
+
 // Notify sends a notification about a negative review to the appropriate department
 func (n *Notifier) Notify(ctx context.Context, department models.Department, review models.Review, analysis models.AnalysisResult) error {
+	_, err := n.Deliver(ctx, department, review, analysis)
+	return err
+}
+
+// Deliver is Notify reporting whether the notification went out. A review
+// skipped as resolved, below the department's severity threshold or already
+// notified within the dedup window, and one only logged by a dry run, is not
+// sent and returns false with a nil error.
+func (n *Notifier) Deliver(ctx context.Context, department models.Department, review models.Review, analysis models.AnalysisResult) (sent bool, err error) {
+	ctx, span := tracing.Start(ctx, "notifier.Notify",
+		append(tracing.ReviewAttributes(review), tracing.KeyDepartment.String(department.ID))...)
+	defer func() { tracing.End(span, err) }()
+
+	if !n.startSend() {
+		return false, ErrDraining
+	}
+	defer n.inFlight.Done()
+
+	// Don't re-notify reviews that were already dealt with
+	if n.config.SkipResolvedReviews {
+		if reason := n.resolvedReason(ctx, review); reason != "" {
+			logging.WithReview(n.logger, review).Info("skipping notification", "reason", reason)
+			return false, nil
+		}
+	}
+
+	// Only alert departments about reviews severe enough for them
+	if threshold := n.severityThreshold(department.ID); !meetsSeverity(analysis.Severity, threshold) {
+		logging.WithReview(n.logger, review).Debug("skipping notification",
+			"reason", "below severity threshold", "severity", analysis.Severity, "threshold", threshold)
+		return false, nil
+	}
+
 	// Create a notification object
 	notification := models.Notification{
 		ID:         uuid.New().String(),
@@ -58,46 +173,206 @@ func (n *Notifier) Notify(ctx context.Context, department models.Department, rev
 		Analysis:   analysis,
 		Department: department,
 		SentAt:     time.Now(),
-		Status:     "sent",
+		Status:     StatusSent,
+	}
+
+	// Don't page anyone twice for a review scraped again by an overlapping
+	// run; the dashboard still sees the latest analysis
+	if earlierID, ok := n.claimReview(review.ID, department.ID, notification.ID, notification.SentAt); !ok {
+		logging.WithReview(n.logger, review).Info("skipping notification",
+			"reason", "already notified within dedup window", "notification_id", earlierID)
+		if n.config.Dashboard.Enabled {
+			notification.ID = earlierID
+			n.updateDashboard(notification)
+		}
+		return false, nil
 	}
 
 	// Store notification in cache and/or database
 	n.cacheNotification(notification)
 
+	// Log what would be sent rather than sending it
+	if n.config.DryRun {
+		n.logDryRun(notification)
+		return false, nil
+	}
+
 	// Send notifications through all enabled channels
 	var errs []error
+	channels := 0
 
 	// Email notification
 	if n.config.Email.Enabled {
-		if err := n.sendEmailNotification(notification); err != nil {
+		channels++
+		err := n.sendEmailNotification(notification)
+		n.metrics.ObserveNotification("email", err)
+		if err != nil {
 			errs = append(errs, fmt.Errorf("email notification error: %w", err))
 		}
 	}
 
 	// Slack notification
 	if n.config.Slack.Enabled {
-		if err := n.sendSlackNotification(notification); err != nil {
+		channels++
+		err := n.sendSlackNotification(ctx, notification)
+		n.metrics.ObserveNotification("slack", err)
+		if err != nil {
 			errs = append(errs, fmt.Errorf("slack notification error: %w", err))
 		}
 	}
 
+	// Generic webhook notification
+	if n.config.Webhook.Enabled {
+		channels++
+		err := n.sendWebhookNotification(ctx, notification)
+		n.metrics.ObserveNotification("webhook", err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("webhook notification error: %w", err))
+		}
+	}
+
 	// Update dashboard if enabled
 	if n.config.Dashboard.Enabled {
 		n.updateDashboard(notification)
 	}
 
+	// A notification no channel delivered does not hold off a retry
+	sent = channels == 0 || len(errs) < channels
+	if !sent {
+		n.releaseReview(review.ID, department.ID, notification.ID)
+	}
+
 	// If there were errors, return a combined error
 	if len(errs) > 0 {
 		var errMsgs []string
 		for _, err := range errs {
 			errMsgs = append(errMsgs, err.Error())
 		}
-		return fmt.Errorf("notification errors: %s", strings.Join(errMsgs, "; "))
+		return sent, fmt.Errorf("notification errors: %s", strings.Join(errMsgs, "; "))
 	}
 
+	return true, nil
+}
+
+// ErrDraining is returned by Notify once Drain has been called
+var ErrDraining = errors.New("notifier is shutting down")
+
+// startSend counts a Notify call as in flight, unless the notifier is draining
+func (n *Notifier) startSend() bool {
+	n.drainMutex.Lock()
+	defer n.drainMutex.Unlock()
+	if n.draining {
+		return false
+	}
+	n.inFlight.Add(1)
+	return true
+}
+
+// Drain rejects further notifications and waits for those still being sent
+// to finish, so a shutdown does not abandon them mid-send. It gives up when
+// ctx is done.
+func (n *Notifier) Drain(ctx context.Context) error {
+	n.drainMutex.Lock()
+	n.draining = true
+	n.drainMutex.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		n.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting for in-flight notifications: %w", ctx.Err())
+	}
+}
+
+// severityThreshold returns the lowest severity notified to department
+func (n *Notifier) severityThreshold(department string) string {
+	if threshold, ok := n.config.DepartmentSeverityThresholds[department]; ok {
+		return threshold
+	}
+	return n.config.SeverityThreshold
+}
+
+// meetsSeverity reports whether severity is at or above threshold. An empty
+// threshold admits every review.
+func meetsSeverity(severity, threshold string) bool {
+	if threshold == "" {
+		return true
+	}
+	return models.SeverityRank(severity) >= models.SeverityRank(threshold)
+}
+
+// resolvedReason explains why a review needs no further notification, or returns
+// an empty string if it should be notified
+func (n *Notifier) resolvedReason(ctx context.Context, review models.Review) string {
+	// A vendor reply marked resolved by the source platform
+	if review.HasVendorReply() {
+		if resolved, _ := review.Metadata["resolved"].(bool); resolved {
+			return "vendor response marked resolved"
+		}
+	}
+
+	// A previous notification for the same review that a department actioned
+	n.cacheMutex.RLock()
+	for _, notification := range n.notifCache {
+		if notification.Review.ID == review.ID && notification.Status == StatusActioned {
+			n.cacheMutex.RUnlock()
+			return fmt.Sprintf("already actioned via notification %s", notification.ID)
+		}
+	}
+	n.cacheMutex.RUnlock()
+
+	// One actioned before a restart is only in the store. If the store cannot
+	// tell, notify rather than risk losing the alert.
+	if n.store != nil {
+		actioned, err := n.store.HasNotification(ctx, review, StatusActioned)
+		if err != nil {
+			logging.WithReview(n.logger, review).Warn("failed to look up actioned notifications", "error", err)
+		} else if actioned {
+			return "already actioned via a stored notification"
+		}
+	}
+
+	return ""
+}
+
+// UpdateStatus records a department's response to a notification
+func (n *Notifier) UpdateStatus(notificationID, status, responseInfo string) error {
+	n.cacheMutex.Lock()
+	notification, exists := n.notifCache[notificationID]
+	if !exists {
+		n.cacheMutex.Unlock()
+		return fmt.Errorf("notification %s not found", notificationID)
+	}
+
+	notification.Status = status
+	notification.ResponseInfo = responseInfo
+	n.notifCache[notificationID] = notification
+	n.cacheMutex.Unlock()
+
+	n.saveNotification(notification)
 	return nil
 }
 
+// GetNotifications returns all cached notifications for a review
+func (n *Notifier) GetNotifications(reviewID string) []models.Notification {
+	n.cacheMutex.RLock()
+	defer n.cacheMutex.RUnlock()
+
+	var notifications []models.Notification
+	for _, notification := range n.notifCache {
+		if notification.Review.ID == reviewID {
+			notifications = append(notifications, notification)
+		}
+	}
+	return notifications
+}
+
 // cacheNotification stores a notification in the cache and/or database
 func (n *Notifier) cacheNotification(notification models.Notification) {
 	// Store in memory cache
@@ -105,15 +380,40 @@ func (n *Notifier) cacheNotification(notification models.Notification) {
 	n.notifCache[notification.ID] = notification
 	n.cacheMutex.Unlock()
 
-	// Store in database if connected
-	if n.dbConnected {
-		n.storeNotificationInDB(notification)
+	// Store in database, or queue it until the database is connected
+	if n.config.Databases.Enabled && !n.config.DryRun {
+		n.persistNotification(notification)
+	}
+
+	n.saveNotification(notification)
+}
+
+// saveNotification writes a notification to the store, if one is set and this
+// is not a dry run. A failed write is logged rather than failing the notification.
+func (n *Notifier) saveNotification(notification models.Notification) {
+	if n.store == nil || n.config.DryRun {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dbTimeout)
+	defer cancel()
+	if err := n.store.SaveNotification(ctx, notification); err != nil {
+		logging.WithReview(n.logger, notification.Review).Warn("failed to save notification", "notification_id", notification.ID, "error", err)
 	}
 }
 
-// storeNotificationInDB saves a notification to the database
-func (n *Notifier) storeNotificationInDB(notification models.Notification) {
-	// This is a placeholder - in a real implementation, this would store data in a database
+// emailRecipient returns the address a department's emails go to
+func (n *Notifier) emailRecipient(department models.Department) string {
+	if address, exists := n.config.Email.DeptAddresses[department.ID]; exists {
+		return address
+	}
+	return department.ContactInfo
+}
+
+// emailSubject returns the subject line of a notification email
+func emailSubject(analysis models.AnalysisResult) string {
+	return fmt.Sprintf("[%s Priority] Negative Customer Feedback - %s",
+		severityLabel(analysis), analysis.IntentCategory)
 }
 
 // sendEmailNotification sends an email notification about a review
@@ -124,30 +424,40 @@ func (n *Notifier) sendEmailNotification(notification models.Notification) error
 	}
 
 	// Get recipient email for department
-	toEmail, exists := n.config.Email.DeptAddresses[notification.Department.ID]
-	if !exists {
-		toEmail = notification.Department.ContactInfo
+	to, err := mail.ParseAddress(sanitizeHeaderValue(n.emailRecipient(notification.Department)))
+	if err != nil {
+		return fmt.Errorf("invalid email address for department %s: %w", notification.Department.ID, err)
 	}
-
-	if !strings.Contains(toEmail, "@") {
-		return fmt.Errorf("invalid email address for department: %s", notification.Department.ID)
+	from, err := mail.ParseAddress(sanitizeHeaderValue(n.config.Email.FromAddress))
+	if err != nil {
+		return fmt.Errorf("invalid from address: %w", err)
 	}
 
-	// Determine severity level for email subject
-	severityLevel := "Medium"
-	if notification.Analysis.SentimentScore < -0.7 {
-		severityLevel = "High"
-	} else if notification.Analysis.SentimentScore > -0.3 {
-		severityLevel = "Low"
+	// Create email subject
+	subject, err := n.notificationSubject(notification)
+	if err != nil {
+		return err
 	}
 
-	// Create email subject
-	subject := fmt.Sprintf("[%s Priority] Negative Customer Feedback - %s",
-		severityLevel, notification.Analysis.IntentCategory)
+	// Use the department's own wording when it has some
+	if tmpl := n.templates[notification.Department.ID].email; tmpl != nil {
+		body, err := renderTemplate(tmpl, notification, n.contentLimit())
+		if err != nil {
+			return err
+		}
+		return n.sendEmail(from, to, subject, body, notification)
+	}
 
 	// Format review creation time
 	reviewTime := notification.Review.CreatedAt.Format("Jan 2, 2006 at 15:04")
 
+	// Shorten long reviews, pointing to the full text
+	content, truncated := truncateContent(notification.Review.Content, n.contentLimit())
+	fullReview := ""
+	if truncated && notification.Review.URL != "" {
+		fullReview = "\nView full review: " + notification.Review.URL
+	}
+
 	// Create email body
 	body := fmt.Sprintf(`
 Dear %s Team,
@@ -159,7 +469,7 @@ Review Details:
 Source: %s
 Time: %s
 Author: %s
-Content: "%s"
+Content: "%s"%s
 URL: %s
 Sentiment Score: %.2f
 Category: %s
@@ -176,49 +486,28 @@ This is an automated message from the Customer Feedback Analysis System.
 		notification.Review.Source,
 		reviewTime,
 		notification.Review.Author,
-		notification.Review.Content,
+		content,
+		fullReview,
 		notification.Review.URL,
 		notification.Analysis.SentimentScore,
 		notification.Analysis.IntentCategory,
 		formatAnalysisDetails(notification.Analysis),
 	)
 
-	// Set up email authentication
-	auth := smtp.PlainAuth("",
-		n.config.Email.Username,
-		n.config.Email.Password,
-		n.config.Email.SMTPServer,
-	)
-
-	// Set up email headers
-	headers := make(map[string]string)
-	headers["From"] = n.config.Email.FromAddress
-	headers["To"] = toEmail
-	headers["Subject"] = subject
-	headers["MIME-Version"] = "1.0"
-	headers["Content-Type"] = "text/plain; charset=\"utf-8\""
-
-	// Construct message with headers
-	message := ""
-	for key, value := range headers {
-		message += fmt.Sprintf("%s: %s\r\n", key, value)
-	}
-	message += "\r\n" + body
-
-	// Send the email
-	err := smtp.SendMail(
-		fmt.Sprintf("%s:%d", n.config.Email.SMTPServer, n.config.Email.SMTPPort),
-		auth,
-		n.config.Email.FromAddress,
-		[]string{toEmail},
-		[]byte(message),
-	)
+	return n.sendEmail(from, to, subject, body, notification)
+}
 
-	if err != nil {
+// sendEmail sends a notification email with the given subject and body
+func (n *Notifier) sendEmail(from, to *mail.Address, subject, body string, notification models.Notification) error {
+	ctx, cancel := context.WithTimeout(context.Background(), smtpSendTimeout)
+	defer cancel()
+	message := emailMessage(from, to, subject, body)
+	if err := sendSMTPMessage(ctx, n.config.Email, n.smtpRootCAs, from.Address, []string{to.Address}, message); err != nil {
 		return fmt.Errorf("failed to send email: %w", err)
 	}
 
-	log.Printf("Email notification sent to %s for review %s", toEmail, notification.Review.ID)
+	logging.WithReview(n.logger, notification.Review).Info("notification sent",
+		"channel", "email", "department", notification.Department.ID, "to", to.Address)
 	return nil
 }
 
@@ -256,6 +545,7 @@ func formatAnalysisDetails(analysis models.AnalysisResult) string {
 // SlackMessage represents a formatted Slack message
 type SlackMessage struct {
 	Text        string       `json:"text,omitempty"`
+	Blocks      []Block      `json:"blocks,omitempty"`
 	Attachments []Attachment `json:"attachments,omitempty"`
 }
 
@@ -278,8 +568,20 @@ type Field struct {
 	Short bool   `json:"short"`
 }
 
+// waitForSendSlot blocks until the send limiter allows another outbound message.
+// Sends over budget are delayed rather than dropped; it only fails if ctx is done.
+func (n *Notifier) waitForSendSlot(ctx context.Context) error {
+	if n.sendLimiter == nil {
+		return nil
+	}
+	if err := n.sendLimiter.Wait(ctx); err != nil {
+		return fmt.Errorf("waiting for send rate limit: %w", err)
+	}
+	return nil
+}
+
 // sendSlackNotification sends a notification to Slack
-func (n *Notifier) sendSlackNotification(notification models.Notification) error {
+func (n *Notifier) sendSlackNotification(ctx context.Context, notification models.Notification) error {
 	// Skip if webhook is not configured
 	if n.config.Slack.WebhookURL == "" {
 		return fmt.Errorf("Slack webhook URL not configured")
@@ -291,6 +593,60 @@ func (n *Notifier) sendSlackNotification(notification models.Notification) error
 		webhookURL = channelURL
 	}
 
+	// Create Slack message in the department's wording or the configured format
+	var message SlackMessage
+	if tmpl := n.templates[notification.Department.ID].slack; tmpl != nil {
+		text, err := renderTemplate(tmpl, notification, n.contentLimit())
+		if err != nil {
+			return err
+		}
+		message = SlackMessage{Text: text}
+	} else if n.config.Slack.Format == config.SlackFormatBlocks {
+		message = slackBlockMessage(notification, n.contentLimit())
+	} else {
+		message = slackAttachmentMessage(notification, n.contentLimit())
+	}
+
+	// Convert message to JSON
+	jsonData, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack message: %w", err)
+	}
+
+	// Respect the outbound send budget before hitting the webhook
+	if err := n.waitForSendSlot(ctx); err != nil {
+		return err
+	}
+
+	// Create HTTP request
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, strings.NewReader(string(jsonData)))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+
+	// Set headers
+	req.Header.Set("Content-Type", "application/json")
+
+	// Send the request
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Slack notification: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Check response status
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("Slack API returned non-OK status: %d", resp.StatusCode)
+	}
+
+	logging.WithReview(n.logger, notification.Review).Info("notification sent",
+		"channel", "slack", "department", notification.Department.ID)
+	return nil
+}
+
+// slackAttachmentMessage formats a notification with the legacy attachment
+// layout, truncating review text longer than maxContent characters
+func slackAttachmentMessage(notification models.Notification, maxContent int) SlackMessage {
 	// Determine color based on sentiment (red for very negative, orange for somewhat negative)
 	var color string
 	if notification.Analysis.SentimentScore < -0.7 {
@@ -301,15 +657,19 @@ func (n *Notifier) sendSlackNotification(notification models.Notification) error
 		color = "#FFCC00" // Yellow
 	}
 
-	// Create Slack message
-	message := SlackMessage{
+	text, truncated := truncateContent(notification.Review.Content, maxContent)
+	if truncated {
+		text += slackFullReviewLink(notification.Review.URL)
+	}
+
+	return SlackMessage{
 		Text: fmt.Sprintf("Negative Customer Feedback for %s Team", notification.Department.Name),
 		Attachments: []Attachment{
 			{
 				Color:     color,
 				Title:     fmt.Sprintf("Customer Review from %s", notification.Review.Source),
 				TitleLink: notification.Review.URL,
-				Text:      notification.Review.Content,
+				Text:      text,
 				Fields: []Field{
 					{
 						Title: "Author",
@@ -342,59 +702,112 @@ func (n *Notifier) sendSlackNotification(notification models.Notification) error
 			},
 		},
 	}
+}
 
-	// Convert message to JSON
-	jsonData, err := json.Marshal(message)
+// sendWebhookNotification POSTs the full notification as JSON to the configured webhook
+func (n *Notifier) sendWebhookNotification(ctx context.Context, notification models.Notification) error {
+	// Skip if webhook is not configured
+	if n.config.Webhook.URL == "" {
+		return fmt.Errorf("webhook URL not configured")
+	}
+
+	// Convert notification to JSON
+	payload, err := json.Marshal(notification)
 	if err != nil {
-		return fmt.Errorf("failed to marshal Slack message: %w", err)
+		return fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+
+	// Respect the outbound send budget before hitting the webhook
+	if err := n.waitForSendSlot(ctx); err != nil {
+		return err
 	}
 
 	// Create HTTP request
-	req, err := http.NewRequest(http.MethodPost, webhookURL, strings.NewReader(string(jsonData)))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.config.Webhook.URL, bytes.NewReader(payload))
 	if err != nil {
 		return fmt.Errorf("failed to create HTTP request: %w", err)
 	}
 
-	// Set headers
+	// Set headers, letting configured headers add to the defaults
 	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.config.Webhook.Headers {
+		req.Header.Set(key, value)
+	}
+
+	// Sign the body so receivers can verify it came from us
+	if n.config.Webhook.Secret != "" {
+		req.Header.Set("X-Signature", signPayload(n.config.Webhook.Secret, payload))
+	}
 
 	// Send the request
 	resp, err := n.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("failed to send Slack notification: %w", err)
+		return fmt.Errorf("failed to send webhook notification: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("Slack API returned non-OK status: %d", resp.StatusCode)
+	// Accept any 2xx response
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned non-2xx status: %d", resp.StatusCode)
 	}
 
-	log.Printf("Slack notification sent to %s channel for review %s",
-		notification.Department.Name, notification.Review.ID)
+	logging.WithReview(n.logger, notification.Review).Info("notification sent",
+		"channel", "webhook", "department", notification.Department.ID)
 	return nil
 }
 
+// signPayload returns the hex-encoded HMAC-SHA256 of payload, prefixed with the algorithm
+func signPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
 // updateDashboard sends data to the dashboard system
 func (n *Notifier) updateDashboard(notification models.Notification) {
 	// This is a placeholder - in a real implementation, this would update a dashboard system
 	// Could publish to a message queue, update a database, or notify a websocket server
-	log.Printf("Dashboard updated with notification %s", notification.ID)
+	logging.WithReview(n.logger, notification.Review).Debug("dashboard updated", "notification_id", notification.ID)
 }
 
 // GetStats returns statistics about the notifier
 func (n *Notifier) GetStats() map[string]interface{} {
+	dbConnected, dbPending := n.databaseStatus()
+
 	n.cacheMutex.RLock()
 	defer n.cacheMutex.RUnlock()
 
 	stats := map[string]interface{}{
-		"notifications_cached": len(n.notifCache),
-		"email_enabled":        n.config.Email.Enabled,
-		"slack_enabled":        n.config.Slack.Enabled,
-		"dashboard_enabled":    n.config.Dashboard.Enabled,
-		"database_enabled":     n.config.Databases.Enabled,
-		"database_connected":   n.dbConnected,
+		"notifications_cached":  len(n.notifCache),
+		"email_enabled":         n.config.Email.Enabled,
+		"slack_enabled":         n.config.Slack.Enabled,
+		"webhook_enabled":       n.config.Webhook.Enabled,
+		"dashboard_enabled":     n.config.Dashboard.Enabled,
+		"database_enabled":      n.config.Databases.Enabled,
+		"database_connected":    dbConnected,
+		"database_pending":      dbPending,
+		"max_sends_per_minute":  n.config.MaxSendsPerMinute,
+		"dry_run":               n.config.DryRun,
+		"dry_run_notifications": n.dryRunCount,
 	}
 
+	degraded, _ := n.Health()
+	stats["degraded"] = degraded
+
 	return stats
 }
+
+// severityLabel returns the analysis severity as a title for display. Analyses
+// without a severity fall back to tiers of the sentiment score.
+func severityLabel(analysis models.AnalysisResult) string {
+	severity := analysis.Severity
+	if severity == "" {
+		severity = models.SeverityMedium
+		if analysis.SentimentScore < -0.7 {
+			severity = models.SeverityHigh
+		} else if analysis.SentimentScore > -0.3 {
+			severity = models.SeverityLow
+		}
+	}
+	return strings.ToUpper(severity[:1]) + severity[1:]
+}
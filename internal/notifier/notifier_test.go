@@ -0,0 +1,447 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// testNotificationInputs returns a department, review and analysis suitable for Notify
+func testNotificationInputs(reviewID string) (models.Department, models.Review, models.AnalysisResult) {
+	department := models.Department{
+		ID:          "engineering",
+		Name:        "Infoblox Engineering",
+		ContactInfo: "engineering@infoblox.com",
+	}
+	review := models.Review{
+		ID:        reviewID,
+		Source:    "twitter",
+		Content:   "NIOS grid upgrade failed again, DNS outage for two hours",
+		Author:    "NetworkAdmin123",
+		CreatedAt: time.Now(),
+	}
+	analysis := models.AnalysisResult{
+		ReviewID:       reviewID,
+		SentimentScore: -0.8,
+		IsNegative:     true,
+		IsRelevant:     true,
+		IntentCategory: "bug_report",
+		Confidence:     0.7,
+	}
+	return department, review, analysis
+}
+
+func TestNotifyRateLimitsSlackSends(t *testing.T) {
+	var (
+		mu        sync.Mutex
+		sendTimes []time.Time
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		sendTimes = append(sendTimes, time.Now())
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 600 sends per minute = one send every 100ms
+	n := New(config.NotifierConfig{
+		Slack:             config.SlackConfig{Enabled: true, WebhookURL: server.URL},
+		MaxSendsPerMinute: 600,
+	})
+
+	const sends = 5
+	for i := 0; i < sends; i++ {
+		dept, review, analysis := testNotificationInputs(fmt.Sprintf("review-%d", i))
+		assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, sendTimes, sends, "sends over budget should be delayed, not dropped")
+	for i := 1; i < len(sendTimes); i++ {
+		gap := sendTimes[i].Sub(sendTimes[i-1])
+		assert.GreaterOrEqual(t, gap, 90*time.Millisecond, "send %d was not spaced by the limiter", i)
+	}
+}
+
+func TestNotifyRateLimitRespectsContextCancellation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// One send per minute: the second send cannot get a slot before the deadline
+	n := New(config.NotifierConfig{
+		Slack:             config.SlackConfig{Enabled: true, WebhookURL: server.URL},
+		MaxSendsPerMinute: 1,
+	})
+
+	dept, review, analysis := testNotificationInputs("review-1")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	dept, review, analysis = testNotificationInputs("review-2")
+	assert.Error(t, n.Notify(ctx, dept, review, analysis))
+}
+
+// captureWebhook records the body and headers of every request it receives
+type captureWebhook struct {
+	mu      sync.Mutex
+	bodies  [][]byte
+	headers []http.Header
+}
+
+func (c *captureWebhook) handler(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	c.bodies = append(c.bodies, body)
+	c.headers = append(c.headers, r.Header.Clone())
+	c.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestWebhookNotificationPayload(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook: config.WebhookConfig{
+			Enabled: true,
+			URL:     server.URL,
+			Headers: map[string]string{"X-Team": "csi"},
+		},
+	})
+
+	dept, review, analysis := testNotificationInputs("review-webhook")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	assert.Len(t, capture.bodies, 1)
+	var payload models.Notification
+	assert.NoError(t, json.Unmarshal(capture.bodies[0], &payload))
+	assert.NotEmpty(t, payload.ID)
+	assert.Equal(t, "sent", payload.Status)
+	assert.Equal(t, review.ID, payload.Review.ID)
+	assert.Equal(t, review.Content, payload.Review.Content)
+	assert.Equal(t, analysis.IntentCategory, payload.Analysis.IntentCategory)
+	assert.Equal(t, dept.ID, payload.Department.ID)
+
+	assert.Equal(t, "application/json", capture.headers[0].Get("Content-Type"))
+	assert.Equal(t, "csi", capture.headers[0].Get("X-Team"))
+	assert.Empty(t, capture.headers[0].Get("X-Signature"), "unsigned webhooks should not carry a signature")
+}
+
+func TestWebhookNotificationSignature(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	const secret = "s3cr3t"
+	n := New(config.NotifierConfig{
+		Webhook: config.WebhookConfig{Enabled: true, URL: server.URL, Secret: secret},
+	})
+
+	dept, review, analysis := testNotificationInputs("review-signed")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	assert.Len(t, capture.bodies, 1)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(capture.bodies[0])
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	assert.Equal(t, expected, capture.headers[0].Get("X-Signature"))
+}
+
+func TestWebhookNotificationErrorIsAggregated(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook: config.WebhookConfig{Enabled: true, URL: server.URL},
+	})
+
+	dept, review, analysis := testNotificationInputs("review-failing")
+	err := n.Notify(context.Background(), dept, review, analysis)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "webhook notification error")
+}
+
+func TestNotifySkipsPreviouslyActionedReview(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook:             config.WebhookConfig{Enabled: true, URL: server.URL},
+		SkipResolvedReviews: true,
+	})
+
+	// First scrape: notify and have the department action it
+	dept, review, analysis := testNotificationInputs("trustpilot-abc")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	notifications := n.GetNotifications(review.ID)
+	assert.Len(t, notifications, 1)
+	assert.NoError(t, n.UpdateStatus(notifications[0].ID, StatusActioned, "Ticket ENG-42 opened"))
+
+	// Re-scrape of the same review produces no new notification
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Len(t, n.GetNotifications(review.ID), 1)
+	assert.Len(t, capture.bodies, 1)
+
+	// A new review is still notified
+	dept, newReview, analysis := testNotificationInputs("trustpilot-def")
+	assert.NoError(t, n.Notify(context.Background(), dept, newReview, analysis))
+	assert.Len(t, n.GetNotifications(newReview.ID), 1)
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestNotifySkipsReviewActionedBeforeRestart(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+	cfg := config.NotifierConfig{
+		Webhook:             config.WebhookConfig{Enabled: true, URL: server.URL},
+		SkipResolvedReviews: true,
+	}
+	store := &recordingStore{}
+
+	first := New(cfg)
+	first.SetStore(store)
+	dept, review, analysis := testNotificationInputs("trustpilot-abc")
+	assert.NoError(t, first.Notify(context.Background(), dept, review, analysis))
+	assert.NoError(t, first.UpdateStatus(first.GetNotifications(review.ID)[0].ID, StatusActioned, "Ticket ENG-42 opened"))
+
+	// After a restart only the store remembers the actioned notification
+	restarted := New(cfg)
+	restarted.SetStore(store)
+	assert.NoError(t, restarted.Notify(context.Background(), dept, review, analysis))
+	assert.Empty(t, restarted.GetNotifications(review.ID))
+	assert.Len(t, capture.bodies, 1)
+
+	dept, newReview, analysis := testNotificationInputs("trustpilot-def")
+	assert.NoError(t, restarted.Notify(context.Background(), dept, newReview, analysis))
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestNotifySkipsReviewWithResolvedVendorResponse(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook:             config.WebhookConfig{Enabled: true, URL: server.URL},
+		SkipResolvedReviews: true,
+	})
+
+	dept, review, analysis := testNotificationInputs("trustpilot-resolved")
+	review.Replies = []models.Reply{{Content: "Fixed in 9.0.3", IsVendor: true}}
+	review.Metadata = map[string]interface{}{"resolved": true}
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Empty(t, capture.bodies)
+
+	// A vendor reply alone doesn't mean the issue is resolved
+	dept, review, analysis = testNotificationInputs("trustpilot-replied")
+	review.Replies = []models.Reply{{Content: "Sorry to hear that", IsVendor: true}}
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Len(t, capture.bodies, 1)
+
+	// Resolution without a vendor reply isn't the vendor's
+	dept, review, analysis = testNotificationInputs("trustpilot-unreplied")
+	review.Metadata = map[string]interface{}{"resolved": true}
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestNotifyRenotifiesActionedReviewWhenSkipDisabled(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook: config.WebhookConfig{Enabled: true, URL: server.URL},
+	})
+
+	dept, review, analysis := testNotificationInputs("trustpilot-abc")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.NoError(t, n.UpdateStatus(n.GetNotifications(review.ID)[0].ID, StatusActioned, ""))
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestUpdateStatusUnknownNotification(t *testing.T) {
+	n := New(config.NotifierConfig{})
+
+	assert.Error(t, n.UpdateStatus("missing", StatusActioned, ""))
+}
+
+func TestNotifyLogsStructuredJSON(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger, err := logging.New(&buf, "info")
+	assert.NoError(t, err)
+
+	n := New(config.NotifierConfig{Webhook: config.WebhookConfig{Enabled: true, URL: server.URL}})
+	n.SetLogger(logger)
+
+	dept, review, analysis := testNotificationInputs("review-logged")
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	// The dashboard update is logged at debug level and must be filtered out
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 1)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "notification sent", entry["msg"])
+	assert.Equal(t, "notifier", entry["component"])
+	assert.Equal(t, "review-logged", entry["review_id"])
+	assert.Equal(t, "twitter", entry["source"])
+	assert.Equal(t, "webhook", entry["channel"])
+	assert.Equal(t, "engineering", entry["department"])
+}
+
+func TestSeverityLabel(t *testing.T) {
+	assert.Equal(t, "High", severityLabel(models.AnalysisResult{Severity: models.SeverityHigh, SentimentScore: -0.1}))
+	assert.Equal(t, "Low", severityLabel(models.AnalysisResult{Severity: models.SeverityLow, SentimentScore: -0.9}))
+
+	// Analyses without a severity fall back to the sentiment score
+	assert.Equal(t, "High", severityLabel(models.AnalysisResult{SentimentScore: -0.8}))
+	assert.Equal(t, "Medium", severityLabel(models.AnalysisResult{SentimentScore: -0.5}))
+	assert.Equal(t, "Low", severityLabel(models.AnalysisResult{SentimentScore: -0.1}))
+}
+
+func TestDrainWaitsForInFlightSends(t *testing.T) {
+	received := make(chan struct{})
+	release := make(chan struct{})
+	delivered := 0
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(received)
+		<-release
+		mu.Lock()
+		delivered++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook: config.WebhookConfig{Enabled: true, URL: server.URL},
+	})
+
+	// Start a send and simulate shutdown while it is still in progress
+	dept, review, analysis := testNotificationInputs("review-drain")
+	notifyErr := make(chan error, 1)
+	go func() {
+		notifyErr <- n.Notify(context.Background(), dept, review, analysis)
+	}()
+	<-received
+
+	// A drain that runs out of time reports the send is still in flight
+	shortCtx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, n.Drain(shortCtx), context.DeadlineExceeded)
+
+	// Once the send completes the drain returns and nothing was abandoned
+	close(release)
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer drainCancel()
+	assert.NoError(t, n.Drain(drainCtx))
+
+	mu.Lock()
+	assert.Equal(t, 1, delivered)
+	mu.Unlock()
+	assert.NoError(t, <-notifyErr)
+}
+
+func TestNotifyAfterDrainIsRejected(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{Webhook: config.WebhookConfig{Enabled: true, URL: server.URL}})
+	assert.NoError(t, n.Drain(context.Background()))
+
+	dept, review, analysis := testNotificationInputs("review-late")
+	assert.ErrorIs(t, n.Notify(context.Background(), dept, review, analysis), ErrDraining)
+	assert.Zero(t, atomic.LoadInt32(&calls))
+	assert.Empty(t, n.GetNotifications(review.ID))
+}
+
+func TestDrainWithNoSendsReturnsImmediately(t *testing.T) {
+	n := New(config.NotifierConfig{})
+	assert.NoError(t, n.Drain(context.Background()))
+}
+
+func TestNotifySkipsReviewsBelowSeverityThreshold(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Webhook:           config.WebhookConfig{Enabled: true, URL: server.URL},
+		SeverityThreshold: models.SeverityMedium,
+	})
+
+	// A mildly negative review is not notified
+	dept, review, analysis := testNotificationInputs("mild")
+	analysis.SentimentScore, analysis.Severity = -0.2, models.SeverityLow
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Empty(t, n.GetNotifications(review.ID))
+	assert.Empty(t, capture.bodies)
+
+	// Reviews at or above the threshold are
+	for _, severity := range []string{models.SeverityMedium, models.SeverityHigh} {
+		dept, review, analysis := testNotificationInputs("review-" + severity)
+		analysis.Severity = severity
+		assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+		assert.Len(t, n.GetNotifications(review.ID), 1)
+	}
+	assert.Len(t, capture.bodies, 2)
+}
+
+func TestNotifyUsesDepartmentSeverityThreshold(t *testing.T) {
+	n := New(config.NotifierConfig{
+		SeverityThreshold:            models.SeverityHigh,
+		DepartmentSeverityThresholds: map[string]string{"security": models.SeverityLow},
+	})
+
+	dept, review, analysis := testNotificationInputs("engineering-medium")
+	analysis.Severity = models.SeverityMedium
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+	assert.Empty(t, n.GetNotifications(review.ID))
+
+	_, review, analysis = testNotificationInputs("security-low")
+	analysis.Severity = models.SeverityLow
+	security := models.Department{ID: "security", Name: "Security"}
+	assert.NoError(t, n.Notify(context.Background(), security, review, analysis))
+	assert.Len(t, n.GetNotifications(review.ID), 1)
+}
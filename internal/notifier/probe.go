@@ -0,0 +1,135 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// probeTimeout bounds each connectivity check
+const probeTimeout = 10 * time.Second
+
+// ProbeResult records the outcome of a channel connectivity check
+type ProbeResult struct {
+	Channel   string    `json:"channel"`
+	Healthy   bool      `json:"healthy"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// Probe checks that the enabled email and Slack channels are reachable without
+// sending a notification. Results are kept for Health; the returned error lists
+// every channel that failed.
+func (n *Notifier) Probe(ctx context.Context) error {
+	var results []ProbeResult
+	var failures []string
+
+	record := func(channel string, err error) {
+		result := ProbeResult{Channel: channel, Healthy: err == nil, CheckedAt: time.Now()}
+		if err != nil {
+			result.Error = err.Error()
+			failures = append(failures, fmt.Sprintf("%s: %v", channel, err))
+		}
+		results = append(results, result)
+	}
+
+	if n.config.Email.Enabled {
+		record("email", n.probeSMTP(ctx))
+	}
+	if n.config.Slack.Enabled {
+		record("slack", n.probeSlack(ctx))
+	}
+
+	n.probeMutex.Lock()
+	n.probeResults = results
+	n.probeMutex.Unlock()
+
+	if len(failures) > 0 {
+		return fmt.Errorf("notifier probe failed: %s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// Health reports whether any channel failed its last probe, along with the
+// individual results. A notifier that was never probed is not degraded.
+func (n *Notifier) Health() (degraded bool, results []ProbeResult) {
+	n.probeMutex.RLock()
+	defer n.probeMutex.RUnlock()
+
+	results = make([]ProbeResult, len(n.probeResults))
+	copy(results, n.probeResults)
+	for _, result := range results {
+		if !result.Healthy {
+			degraded = true
+		}
+	}
+	return degraded, results
+}
+
+// probeSMTP connects to the SMTP server, authenticates when credentials are
+// configured, and issues a NOOP
+func (n *Notifier) probeSMTP(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	client, err := dialSMTP(ctx, n.config.Email, n.smtpRootCAs)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Noop(); err != nil {
+		return fmt.Errorf("SMTP NOOP failed: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// probeSlack sends a GET to the Slack webhook. Slack answers a GET on a live
+// webhook with a client error about the payload, while revoked or unknown
+// webhooks answer 403, 404 or 410.
+func (n *Notifier) probeSlack(ctx context.Context) error {
+	if n.config.Slack.WebhookURL == "" {
+		return fmt.Errorf("no webhook URL configured")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, n.config.Slack.WebhookURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Slack probe request: %w", withoutURL(err))
+	}
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach Slack webhook: %w", withoutURL(err))
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusForbidden,
+		resp.StatusCode == http.StatusNotFound,
+		resp.StatusCode == http.StatusGone,
+		resp.StatusCode >= http.StatusInternalServerError:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Slack webhook returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return nil
+}
+
+// withoutURL drops the URL an HTTP client error is reported with. A Slack
+// webhook URL is itself the credential, so it must not reach probe results.
+func withoutURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,164 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockSMTPServer accepts SMTP sessions on a local port, answering AUTH with
+// authCode. It returns the host and port to connect to.
+func mockSMTPServer(t *testing.T, authCode string) (string, int) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, authCode)
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return "127.0.0.1", addr.Port
+}
+
+// serveSMTP handles a single SMTP session with canned replies
+func serveSMTP(conn net.Conn, authCode string) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	conn.Write([]byte("220 mock ESMTP\r\n"))
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.ToUpper(strings.Fields(line + " x")[0])
+		switch command {
+		case "EHLO":
+			conn.Write([]byte("250-mock\r\n250 AUTH PLAIN\r\n"))
+		case "AUTH":
+			conn.Write([]byte(authCode + " auth reply\r\n"))
+		case "QUIT":
+			conn.Write([]byte("221 bye\r\n"))
+			return
+		default:
+			conn.Write([]byte("250 ok\r\n"))
+		}
+	}
+}
+
+// probeConfig returns a notifier config with email and Slack pointed at mocks
+func probeConfig(smtpHost string, smtpPort int, slackURL string) config.NotifierConfig {
+	return config.NotifierConfig{
+		Email: config.EmailConfig{
+			Enabled:    true,
+			SMTPServer: smtpHost,
+			SMTPPort:   smtpPort,
+			Username:   "notifications@infoblox.com",
+			Password:   "wrong-password",
+		},
+		Slack: config.SlackConfig{Enabled: true, WebhookURL: slackURL},
+	}
+}
+
+func TestProbeReportsDegradedChannels(t *testing.T) {
+	host, port := mockSMTPServer(t, "535")
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("no_service"))
+	}))
+	defer slack.Close()
+
+	n := New(probeConfig(host, port, slack.URL))
+
+	err := n.Probe(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "email: SMTP authentication failed")
+	assert.Contains(t, err.Error(), "slack: Slack webhook returned status 404: no_service")
+
+	degraded, results := n.Health()
+	assert.True(t, degraded)
+	assert.Len(t, results, 2)
+	for _, result := range results {
+		assert.False(t, result.Healthy, result.Channel)
+		assert.NotEmpty(t, result.Error)
+	}
+	assert.Equal(t, true, n.GetStats()["degraded"])
+}
+
+func TestProbeHealthyChannels(t *testing.T) {
+	host, port := mockSMTPServer(t, "235")
+	slack := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid_payload"))
+	}))
+	defer slack.Close()
+
+	n := New(probeConfig(host, port, slack.URL))
+
+	assert.NoError(t, n.Probe(context.Background()))
+
+	degraded, results := n.Health()
+	assert.False(t, degraded)
+	assert.Len(t, results, 2)
+}
+
+func TestProbeUnreachableSMTP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	n := New(config.NotifierConfig{
+		Email: config.EmailConfig{Enabled: true, SMTPServer: "127.0.0.1", SMTPPort: port},
+	})
+
+	err = n.Probe(context.Background())
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "127.0.0.1:"+strconv.Itoa(port))
+
+	degraded, _ := n.Health()
+	assert.True(t, degraded)
+}
+
+func TestProbeErrorOmitsSlackWebhookURL(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	webhookURL := "http://" + listener.Addr().String() + "/services/T000/B000/SECRET"
+	listener.Close()
+
+	n := New(config.NotifierConfig{Slack: config.SlackConfig{Enabled: true, WebhookURL: webhookURL}})
+
+	err = n.Probe(context.Background())
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "SECRET")
+
+	_, results := n.Health()
+	if assert.Len(t, results, 1) {
+		assert.Contains(t, results[0].Error, "failed to reach Slack webhook")
+		assert.NotContains(t, results[0].Error, "SECRET")
+	}
+}
+
+func TestHealthBeforeProbe(t *testing.T) {
+	n := New(config.NotifierConfig{Slack: config.SlackConfig{Enabled: true}})
+
+	degraded, results := n.Health()
+	assert.False(t, degraded)
+	assert.Empty(t, results)
+}
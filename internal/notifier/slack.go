@@ -0,0 +1,284 @@
+package notifier
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// Slack button action IDs. Each button's value is the review ID.
+const (
+	SlackActionAcknowledge  = "acknowledge"
+	SlackActionCreateTicket = "create_ticket"
+)
+
+// ErrNoNotifications is returned when a response names a review that has no notifications
+var ErrNoNotifications = errors.New("no notifications for review")
+
+// slackSignatureMaxAge is how old a signed Slack request may be before it is
+// rejected as a possible replay
+const slackSignatureMaxAge = 5 * time.Minute
+
+// slackMaxSectionText is Slack's limit on the text of a section block
+const slackMaxSectionText = 3000
+
+// slackEscaper escapes the characters Slack's mrkdwn treats as control characters
+var slackEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// Block is a Slack Block Kit layout block
+type Block struct {
+	Type     string        `json:"type"`
+	BlockID  string        `json:"block_id,omitempty"`
+	Text     *TextObject   `json:"text,omitempty"`
+	Fields   []TextObject  `json:"fields,omitempty"`
+	Elements []interface{} `json:"elements,omitempty"` // TextObjects in context blocks, Buttons in actions blocks
+}
+
+// TextObject is Slack Block Kit text, either "plain_text" or "mrkdwn"
+type TextObject struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Button is an interactive Slack Block Kit button
+type Button struct {
+	Type     string     `json:"type"`
+	ActionID string     `json:"action_id"`
+	Text     TextObject `json:"text"`
+	Value    string     `json:"value"`
+	Style    string     `json:"style,omitempty"`
+}
+
+// slackBlockMessage formats a notification as Block Kit blocks with buttons to
+// acknowledge the review or create a ticket for it. Review text longer than
+// maxContent characters is truncated and links to the full review.
+func slackBlockMessage(notification models.Notification, maxContent int) SlackMessage {
+	review := notification.Review
+	analysis := notification.Analysis
+	heading := fmt.Sprintf("Negative Customer Feedback for %s Team", notification.Department.Name)
+
+	title := fmt.Sprintf("*Customer Review from %s*", slackEscaper.Replace(review.Source))
+	if review.URL != "" {
+		title = fmt.Sprintf("*<%s|Customer Review from %s>*", review.URL, slackEscaper.Replace(review.Source))
+	}
+	content, truncated := truncateContent(review.Content, maxContent)
+	text := title + "\n" + slackEscaper.Replace(content)
+
+	// Keep the section under Slack's limit, with room for the full review link
+	link := slackFullReviewLink(review.URL)
+	if len(text)+len(link) > slackMaxSectionText {
+		text = strings.ToValidUTF8(text[:slackMaxSectionText-len(link)-3], "") + "..."
+		truncated = true
+	}
+	if truncated {
+		text += link
+	}
+
+	keywords := strings.Join(analysis.Keywords, ", ")
+	if keywords == "" {
+		keywords = "None detected"
+	}
+
+	return SlackMessage{
+		Text: heading, // Shown in notifications and by clients without Block Kit
+		Blocks: []Block{
+			{Type: "header", Text: &TextObject{Type: "plain_text", Text: heading}},
+			{Type: "section", Text: &TextObject{Type: "mrkdwn", Text: text}},
+			{
+				Type: "section",
+				Fields: []TextObject{
+					slackField("Author", review.Author),
+					slackField("Severity", severityLabel(analysis)),
+					slackField("Sentiment", fmt.Sprintf("%.2f", analysis.SentimentScore)),
+					slackField("Category", analysis.IntentCategory),
+					slackField("Confidence", fmt.Sprintf("%.2f", analysis.Confidence)),
+					slackField("Keywords", keywords),
+				},
+			},
+			{
+				Type:     "context",
+				Elements: []interface{}{TextObject{Type: "mrkdwn", Text: "Customer Feedback Analysis System"}},
+			},
+			{
+				Type:    "actions",
+				BlockID: "review_actions",
+				Elements: []interface{}{
+					Button{
+						Type:     "button",
+						ActionID: SlackActionAcknowledge,
+						Text:     TextObject{Type: "plain_text", Text: "Acknowledge"},
+						Value:    review.ID,
+					},
+					Button{
+						Type:     "button",
+						ActionID: SlackActionCreateTicket,
+						Text:     TextObject{Type: "plain_text", Text: "Create Ticket"},
+						Value:    review.ID,
+						Style:    "primary",
+					},
+				},
+			},
+		},
+	}
+}
+
+// slackFullReviewLink returns the line linking a truncated review to its full
+// text, or nothing when the review has no URL
+func slackFullReviewLink(reviewURL string) string {
+	if reviewURL == "" {
+		return ""
+	}
+	return fmt.Sprintf("\n<%s|View full review>", reviewURL)
+}
+
+// slackField formats a labelled value for a section block's fields
+func slackField(label, value string) TextObject {
+	return TextObject{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", label, slackEscaper.Replace(value))}
+}
+
+// VerifySlackSignature checks that body was signed by Slack with secret, using
+// the X-Slack-Request-Timestamp and X-Slack-Signature headers. Requests signed
+// more than five minutes from now are rejected so they cannot be replayed.
+func VerifySlackSignature(secret string, header http.Header, body []byte, now time.Time) error {
+	if secret == "" {
+		return errors.New("Slack signing secret not configured")
+	}
+
+	timestamp := header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid Slack request timestamp %q", timestamp)
+	}
+	if age := now.Sub(time.Unix(seconds, 0)); age > slackSignatureMaxAge || age < -slackSignatureMaxAge {
+		return fmt.Errorf("Slack request timestamp is %s from now", age.Round(time.Second))
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "v0:%s:", timestamp)
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(header.Get("X-Slack-Signature"))) {
+		return errors.New("Slack request signature does not match")
+	}
+	return nil
+}
+
+// VerifySlackRequest checks that a Slack request was signed with the
+// notifier's Slack signing secret
+func (n *Notifier) VerifySlackRequest(header http.Header, body []byte, now time.Time) error {
+	return VerifySlackSignature(n.config.Slack.SigningSecret, header, body, now)
+}
+
+// SlackInteraction is the part of a Slack interaction payload needed to act on
+// a button click
+type SlackInteraction struct {
+	Type string `json:"type"`
+	User struct {
+		ID       string `json:"id"`
+		Username string `json:"username"`
+		Name     string `json:"name"`
+	} `json:"user"`
+	Actions []struct {
+		ActionID string `json:"action_id"`
+		Value    string `json:"value"`
+	} `json:"actions"`
+}
+
+// ParseSlackInteraction decodes the form-encoded body Slack posts when a
+// button is clicked
+func ParseSlackInteraction(body []byte) (SlackInteraction, error) {
+	var interaction SlackInteraction
+
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return interaction, fmt.Errorf("invalid form body: %w", err)
+	}
+	payload := form.Get("payload")
+	if payload == "" {
+		return interaction, errors.New("missing payload")
+	}
+	if err := json.Unmarshal([]byte(payload), &interaction); err != nil {
+		return interaction, fmt.Errorf("invalid payload: %w", err)
+	}
+	return interaction, nil
+}
+
+// HandleSlackInteraction applies the buttons clicked in a Slack interaction to
+// the notifications for their review, returning how many were updated.
+// Acknowledge marks them acknowledged and Create Ticket marks them actioned;
+// the clicking Slack user is recorded in the response info.
+func (n *Notifier) HandleSlackInteraction(interaction SlackInteraction) (int, error) {
+	if interaction.Type != "block_actions" {
+		return 0, fmt.Errorf("unsupported Slack interaction type %q", interaction.Type)
+	}
+
+	user := firstNonEmpty(interaction.User.Username, interaction.User.Name, interaction.User.ID)
+
+	updated := 0
+	for _, action := range interaction.Actions {
+		var status, responseInfo string
+		switch action.ActionID {
+		case SlackActionAcknowledge:
+			status = StatusAcknowledged
+			responseInfo = fmt.Sprintf("Acknowledged in Slack by %s", user)
+		case SlackActionCreateTicket:
+			status = StatusActioned
+			responseInfo = fmt.Sprintf("Ticket requested in Slack by %s", user)
+		default:
+			return updated, fmt.Errorf("unknown Slack action %q", action.ActionID)
+		}
+
+		count, err := n.UpdateReviewStatus(action.Value, status, responseInfo)
+		if err != nil {
+			return updated, err
+		}
+		updated += count
+	}
+	return updated, nil
+}
+
+// UpdateReviewStatus records a department's response on every notification
+// sent for a review, and saves each to the store, returning how many were updated
+func (n *Notifier) UpdateReviewStatus(reviewID, status, responseInfo string) (int, error) {
+	n.cacheMutex.Lock()
+	var updated []models.Notification
+	for id, notification := range n.notifCache {
+		if notification.Review.ID != reviewID {
+			continue
+		}
+		notification.Status = status
+		notification.ResponseInfo = responseInfo
+		n.notifCache[id] = notification
+		updated = append(updated, notification)
+	}
+	n.cacheMutex.Unlock()
+
+	if len(updated) == 0 {
+		return 0, fmt.Errorf("%w %s", ErrNoNotifications, reviewID)
+	}
+	for _, notification := range updated {
+		n.saveNotification(notification)
+	}
+	return len(updated), nil
+}
+
+// firstNonEmpty returns the first of values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
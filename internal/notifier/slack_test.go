@@ -0,0 +1,188 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// signSlackRequest returns the headers Slack would send for body signed with secret at ts
+func signSlackRequest(secret string, ts time.Time, body []byte) http.Header {
+	timestamp := strconv.FormatInt(ts.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+
+	header := http.Header{}
+	header.Set("X-Slack-Request-Timestamp", timestamp)
+	header.Set("X-Slack-Signature", "v0="+hex.EncodeToString(mac.Sum(nil)))
+	return header
+}
+
+// slackInteractionBody returns the form body Slack posts for a click on actionID for reviewID
+func slackInteractionBody(actionID, reviewID string) []byte {
+	payload := fmt.Sprintf(`{"type": "block_actions", "user": {"id": "U123", "username": "oncall.pat", "name": "pat"},
+		"actions": [{"action_id": %q, "block_id": "review_actions", "value": %q, "type": "button"}]}`, actionID, reviewID)
+	return []byte(url.Values{"payload": {payload}}.Encode())
+}
+
+func TestSlackBlockMessageRendersReviewAndButtons(t *testing.T) {
+	department, review, analysis := testNotificationInputs("review-42")
+	review.URL = "https://twitter.com/i/web/status/42"
+	review.Content = "Upgrade <broke> DNS & DHCP"
+	analysis.Keywords = []string{"upgrade", "dns"}
+
+	message := slackBlockMessage(models.Notification{Department: department, Review: review, Analysis: analysis}, 0)
+
+	assert.Equal(t, "Negative Customer Feedback for Infoblox Engineering Team", message.Text)
+	assert.Empty(t, message.Attachments)
+	if !assert.Len(t, message.Blocks, 5) {
+		return
+	}
+	assert.Equal(t, "header", message.Blocks[0].Type)
+	assert.Equal(t, "*<https://twitter.com/i/web/status/42|Customer Review from twitter>*\nUpgrade &lt;broke&gt; DNS &amp; DHCP",
+		message.Blocks[1].Text.Text, "review text is escaped for mrkdwn")
+	assert.Contains(t, message.Blocks[2].Fields, TextObject{Type: "mrkdwn", Text: "*Severity*\nHigh"})
+	assert.Contains(t, message.Blocks[2].Fields, TextObject{Type: "mrkdwn", Text: "*Keywords*\nupgrade, dns"})
+
+	actions := message.Blocks[4]
+	assert.Equal(t, "actions", actions.Type)
+	assert.Equal(t, []interface{}{
+		Button{Type: "button", ActionID: SlackActionAcknowledge, Text: TextObject{Type: "plain_text", Text: "Acknowledge"}, Value: "review-42"},
+		Button{Type: "button", ActionID: SlackActionCreateTicket, Text: TextObject{Type: "plain_text", Text: "Create Ticket"}, Value: "review-42", Style: "primary"},
+	}, actions.Elements)
+}
+
+func TestSlackBlockMessageTruncatesLongReviews(t *testing.T) {
+	department, review, analysis := testNotificationInputs("review-long")
+	review.Content = strings.Repeat("é", 4000)
+
+	message := slackBlockMessage(models.Notification{Department: department, Review: review, Analysis: analysis}, 0)
+
+	text := message.Blocks[1].Text.Text
+	assert.LessOrEqual(t, len(text), slackMaxSectionText)
+	assert.True(t, strings.HasSuffix(text, "..."))
+	assert.True(t, strings.ToValidUTF8(text, "") == text, "truncation does not split a character")
+}
+
+func TestNotifySendsConfiguredSlackFormat(t *testing.T) {
+	for _, format := range []string{"", config.SlackFormatAttachments, config.SlackFormatBlocks} {
+		t.Run("format "+format, func(t *testing.T) {
+			var payload map[string]json.RawMessage
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				assert.NoError(t, json.Unmarshal(body, &payload))
+			}))
+			defer server.Close()
+
+			n := New(config.NotifierConfig{Slack: config.SlackConfig{Enabled: true, WebhookURL: server.URL, Format: format}})
+			department, review, analysis := testNotificationInputs("review-1")
+			assert.NoError(t, n.Notify(context.Background(), department, review, analysis))
+
+			if format == config.SlackFormatBlocks {
+				assert.Contains(t, payload, "blocks")
+				assert.NotContains(t, payload, "attachments")
+			} else {
+				assert.Contains(t, payload, "attachments")
+				assert.NotContains(t, payload, "blocks")
+			}
+		})
+	}
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	now := time.Unix(1741084200, 0)
+	body := slackInteractionBody(SlackActionAcknowledge, "review-1")
+
+	assert.NoError(t, VerifySlackSignature("shh", signSlackRequest("shh", now, body), body, now))
+	assert.NoError(t, VerifySlackSignature("shh", signSlackRequest("shh", now.Add(-4*time.Minute), body), body, now))
+
+	assert.EqualError(t, VerifySlackSignature("shh", signSlackRequest("other", now, body), body, now),
+		"Slack request signature does not match")
+	assert.EqualError(t, VerifySlackSignature("shh", signSlackRequest("shh", now, body), append(body, 'x'), now),
+		"Slack request signature does not match", "a tampered body fails")
+	assert.EqualError(t, VerifySlackSignature("shh", signSlackRequest("shh", now.Add(-10*time.Minute), body), body, now),
+		"Slack request timestamp is 10m0s from now", "old requests cannot be replayed")
+	assert.EqualError(t, VerifySlackSignature("shh", http.Header{}, body, now), `invalid Slack request timestamp ""`)
+	assert.EqualError(t, VerifySlackSignature("", signSlackRequest("", now, body), body, now),
+		"Slack signing secret not configured")
+}
+
+func TestHandleSlackInteractionUpdatesReviewNotifications(t *testing.T) {
+	n := New(config.NotifierConfig{})
+	department, review, analysis := testNotificationInputs("review-7")
+	assert.NoError(t, n.Notify(context.Background(), department, review, analysis))
+	assert.NoError(t, n.Notify(context.Background(), models.Department{ID: "security", Name: "Security"}, review, analysis))
+
+	interaction, err := ParseSlackInteraction(slackInteractionBody(SlackActionAcknowledge, "review-7"))
+	assert.NoError(t, err)
+	updated, err := n.HandleSlackInteraction(interaction)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updated)
+	for _, notification := range n.GetNotifications("review-7") {
+		assert.Equal(t, StatusAcknowledged, notification.Status)
+		assert.Equal(t, "Acknowledged in Slack by oncall.pat", notification.ResponseInfo)
+	}
+
+	interaction, _ = ParseSlackInteraction(slackInteractionBody(SlackActionCreateTicket, "review-7"))
+	_, err = n.HandleSlackInteraction(interaction)
+	assert.NoError(t, err)
+	for _, notification := range n.GetNotifications("review-7") {
+		assert.Equal(t, StatusActioned, notification.Status)
+		assert.Equal(t, "Ticket requested in Slack by oncall.pat", notification.ResponseInfo)
+	}
+}
+
+func TestHandleSlackInteractionSavesStatusToStore(t *testing.T) {
+	store := &recordingStore{}
+	n := New(config.NotifierConfig{})
+	n.SetStore(store)
+	department, review, analysis := testNotificationInputs("review-7")
+	assert.NoError(t, n.Notify(context.Background(), department, review, analysis))
+	assert.NoError(t, n.Notify(context.Background(), models.Department{ID: "security", Name: "Security"}, review, analysis))
+
+	interaction, err := ParseSlackInteraction(slackInteractionBody(SlackActionCreateTicket, "review-7"))
+	assert.NoError(t, err)
+	_, err = n.HandleSlackInteraction(interaction)
+	assert.NoError(t, err)
+
+	// Both notifications were saved when sent, then again with the new status
+	if assert.Len(t, store.saved, 4) {
+		for _, notification := range store.saved[2:] {
+			assert.Equal(t, "review-7", notification.Review.ID)
+			assert.Equal(t, StatusActioned, notification.Status)
+			assert.Equal(t, "Ticket requested in Slack by oncall.pat", notification.ResponseInfo)
+		}
+		assert.ElementsMatch(t, []string{store.saved[0].ID, store.saved[1].ID}, []string{store.saved[2].ID, store.saved[3].ID})
+	}
+}
+
+func TestHandleSlackInteractionRejectsUnknownInput(t *testing.T) {
+	n := New(config.NotifierConfig{})
+
+	interaction, _ := ParseSlackInteraction(slackInteractionBody(SlackActionAcknowledge, "missing"))
+	_, err := n.HandleSlackInteraction(interaction)
+	assert.ErrorIs(t, err, ErrNoNotifications)
+
+	interaction, _ = ParseSlackInteraction(slackInteractionBody("delete_everything", "missing"))
+	_, err = n.HandleSlackInteraction(interaction)
+	assert.EqualError(t, err, `unknown Slack action "delete_everything"`)
+
+	_, err = ParseSlackInteraction([]byte("token=abc"))
+	assert.EqualError(t, err, "missing payload")
+}
@@ -0,0 +1,149 @@
+package notifier
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+)
+
+// smtpsPort is the standard port for SMTP over implicit TLS
+const smtpsPort = 465
+
+// smtpSendTimeout bounds a whole email delivery, from connecting to QUIT
+const smtpSendTimeout = 30 * time.Second
+
+// usesImplicitTLS reports whether the connection is TLS from the start, as on
+// port 465, rather than upgraded with STARTTLS
+func usesImplicitTLS(cfg config.EmailConfig) bool {
+	return cfg.UseTLS || cfg.SMTPPort == smtpsPort
+}
+
+// dialSMTP opens an SMTP session to the configured server. With implicit TLS
+// the connection is encrypted before the greeting; otherwise it is upgraded
+// with STARTTLS when the server offers it. When a username is configured the
+// session is authenticated; internal relays without one are used as is.
+// rootCAs overrides the system roots and is nil outside tests.
+func dialSMTP(ctx context.Context, cfg config.EmailConfig, rootCAs *x509.CertPool) (*smtp.Client, error) {
+	addr := net.JoinHostPort(cfg.SMTPServer, fmt.Sprint(cfg.SMTPPort))
+	tlsConfig := &tls.Config{ServerName: cfg.SMTPServer, RootCAs: rootCAs}
+
+	var (
+		conn net.Conn
+		err  error
+	)
+	if usesImplicitTLS(cfg) {
+		dialer := tls.Dialer{Config: tlsConfig}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, cfg.SMTPServer)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+
+	if !usesImplicitTLS(cfg) {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				client.Close()
+				return nil, fmt.Errorf("failed to start TLS: %w", err)
+			}
+		}
+	}
+
+	// Authenticate whenever credentials are configured. A server that does not
+	// advertise AUTH fails here rather than being sent mail unauthenticated.
+	if cfg.Username != "" {
+		if ok, _ := client.Extension("AUTH"); !ok {
+			client.Close()
+			return nil, errors.New("SMTP authentication failed: server doesn't support AUTH")
+		}
+		auth := smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.SMTPServer)
+		if err := client.Auth(auth); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	return client, nil
+}
+
+// sendSMTPMessage delivers message from the sender to the recipients
+func sendSMTPMessage(ctx context.Context, cfg config.EmailConfig, rootCAs *x509.CertPool, from string, to []string, message string) error {
+	client, err := dialSMTP(ctx, cfg, rootCAs)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if err := client.Mail(from); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	for _, recipient := range to {
+		if err := client.Rcpt(recipient); err != nil {
+			return fmt.Errorf("RCPT TO %s rejected: %w", recipient, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+	if _, err := w.Write([]byte(message)); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// headerLineBreaks removes the line breaks that would let a value start a new
+// header or end the header block
+var headerLineBreaks = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// sanitizeHeaderValue makes text safe to use as a single header value
+func sanitizeHeaderValue(text string) string {
+	return strings.TrimSpace(headerLineBreaks.Replace(text))
+}
+
+// emailMessage builds a plain-text message with fixed headers. Addresses are
+// formatted by net/mail and the subject is MIME-encoded when it is not ASCII,
+// so no value can inject a header.
+func emailMessage(from, to *mail.Address, subject, body string) string {
+	headers := []struct{ key, value string }{
+		{"From", from.String()},
+		{"To", to.String()},
+		{"Subject", mime.QEncoding.Encode("utf-8", sanitizeHeaderValue(subject))},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/plain; charset="utf-8"`},
+	}
+
+	var message strings.Builder
+	for _, header := range headers {
+		message.WriteString(header.key + ": " + header.value + "\r\n")
+	}
+	message.WriteString("\r\n" + body)
+	return message.String()
+}
@@ -0,0 +1,268 @@
+package notifier
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"mime"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpSession is what a recordingSMTPServer received in one session
+type smtpSession struct {
+	Commands []string
+	Data     string
+}
+
+// recordingSMTPServer is a local SMTP server that accepts every message and
+// records each session. It advertises AUTH unless withoutAuth is set.
+type recordingSMTPServer struct {
+	host        string
+	port        int
+	withoutAuth bool
+
+	mu       sync.Mutex
+	sessions []smtpSession
+}
+
+// newRecordingSMTPServer starts a recording SMTP server. With tlsConfig the
+// listener speaks implicit TLS, as on port 465.
+func newRecordingSMTPServer(t *testing.T, tlsConfig *tls.Config) *recordingSMTPServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	s := &recordingSMTPServer{host: "127.0.0.1", port: listener.Addr().(*net.TCPAddr).Port}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s
+}
+
+// serve handles one SMTP session and records it once the client quits
+func (s *recordingSMTPServer) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	conn.Write([]byte("220 mock ESMTP\r\n"))
+
+	var session smtpSession
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(strings.Fields(line + " x")[0])
+		session.Commands = append(session.Commands, command)
+
+		switch command {
+		case "EHLO":
+			if s.withoutAuth {
+				conn.Write([]byte("250 mock\r\n"))
+			} else {
+				conn.Write([]byte("250-mock\r\n250 AUTH PLAIN\r\n"))
+			}
+		case "AUTH":
+			conn.Write([]byte("235 authenticated\r\n"))
+		case "DATA":
+			conn.Write([]byte("354 go ahead\r\n"))
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			session.Data = data.String()
+			conn.Write([]byte("250 queued\r\n"))
+		case "QUIT":
+			conn.Write([]byte("221 bye\r\n"))
+			s.mu.Lock()
+			s.sessions = append(s.sessions, session)
+			s.mu.Unlock()
+			return
+		default:
+			conn.Write([]byte("250 ok\r\n"))
+		}
+	}
+}
+
+// received returns the sessions completed so far
+func (s *recordingSMTPServer) received() []smtpSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]smtpSession(nil), s.sessions...)
+}
+
+// testTLSConfig returns a server TLS config for 127.0.0.1 and a pool trusting it
+func testTLSConfig(t *testing.T) (*tls.Config, *x509.CertPool) {
+	server := httptest.NewTLSServer(nil)
+	t.Cleanup(server.Close)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.Certificate())
+	return &tls.Config{Certificates: server.TLS.Certificates}, roots
+}
+
+// emailNotifier returns a notifier sending email to smtp as the engineering department
+func emailNotifier(smtp *recordingSMTPServer, cfg config.EmailConfig, roots *x509.CertPool) *Notifier {
+	cfg.Enabled = true
+	cfg.SMTPServer = smtp.host
+	cfg.SMTPPort = smtp.port
+	cfg.FromAddress = "reviews@infoblox.com"
+	n := New(config.NotifierConfig{Email: cfg})
+	n.smtpRootCAs = roots
+	return n
+}
+
+func TestSendEmailOverImplicitTLS(t *testing.T) {
+	serverTLS, roots := testTLSConfig(t)
+	smtp := newRecordingSMTPServer(t, serverTLS)
+	n := emailNotifier(smtp, config.EmailConfig{
+		UseTLS:   true,
+		Username: "notifications@infoblox.com",
+		Password: "secret",
+	}, roots)
+
+	dept, review, analysis := testNotificationInputs("email-tls")
+	err := n.Notify(context.Background(), dept, review, analysis)
+
+	assert.NoError(t, err)
+	sessions := smtp.received()
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, []string{"EHLO", "AUTH", "MAIL", "RCPT", "DATA", "QUIT"}, sessions[0].Commands)
+		assert.Contains(t, sessions[0].Data, "To: <engineering@infoblox.com>")
+		assert.Contains(t, sessions[0].Data, review.Content)
+	}
+}
+
+func TestSendEmailImplicitTLSRejectsUntrustedCertificate(t *testing.T) {
+	serverTLS, _ := testTLSConfig(t)
+	smtp := newRecordingSMTPServer(t, serverTLS)
+	n := emailNotifier(smtp, config.EmailConfig{UseTLS: true}, nil)
+
+	dept, review, analysis := testNotificationInputs("email-untrusted")
+	err := n.Notify(context.Background(), dept, review, analysis)
+
+	assert.ErrorContains(t, err, "failed to connect")
+	assert.Empty(t, smtp.received())
+}
+
+func TestSendEmailWithoutAuth(t *testing.T) {
+	smtp := newRecordingSMTPServer(t, nil)
+	n := emailNotifier(smtp, config.EmailConfig{}, nil)
+
+	dept, review, analysis := testNotificationInputs("email-relay")
+	err := n.Notify(context.Background(), dept, review, analysis)
+
+	assert.NoError(t, err)
+	sessions := smtp.received()
+	if assert.Len(t, sessions, 1) {
+		assert.Equal(t, []string{"EHLO", "MAIL", "RCPT", "DATA", "QUIT"}, sessions[0].Commands)
+	}
+}
+
+func TestSendEmailFailsWhenServerDoesNotOfferAuth(t *testing.T) {
+	smtp := newRecordingSMTPServer(t, nil)
+	smtp.withoutAuth = true
+	n := emailNotifier(smtp, config.EmailConfig{
+		Username: "notifications@infoblox.com",
+		Password: "secret",
+	}, nil)
+
+	dept, review, analysis := testNotificationInputs("email-no-auth")
+	err := n.Notify(context.Background(), dept, review, analysis)
+
+	assert.ErrorContains(t, err, "server doesn't support AUTH")
+	assert.Empty(t, smtp.received(), "no mail is sent unauthenticated")
+}
+
+func TestUsesImplicitTLS(t *testing.T) {
+	assert.True(t, usesImplicitTLS(config.EmailConfig{SMTPPort: 465}))
+	assert.True(t, usesImplicitTLS(config.EmailConfig{SMTPPort: 2465, UseTLS: true}))
+	assert.False(t, usesImplicitTLS(config.EmailConfig{SMTPPort: 587}))
+}
+
+// messageHeaders returns the header lines of an email message
+func messageHeaders(message string) []string {
+	header, _, _ := strings.Cut(message, "\r\n\r\n")
+	return strings.Split(header, "\r\n")
+}
+
+func TestEmailMessageResistsHeaderInjection(t *testing.T) {
+	from := &mail.Address{Address: "reviews@infoblox.com"}
+	to := &mail.Address{Name: "Ops\r\nBcc: attacker@evil.example", Address: "ops@infoblox.com"}
+
+	message := emailMessage(from, to, "[High Priority] bug_report\r\nBcc: attacker@evil.example", "body")
+
+	headers := messageHeaders(message)
+	assert.Len(t, headers, 5)
+	for _, header := range headers {
+		assert.False(t, strings.HasPrefix(header, "Bcc:"), header)
+	}
+	assert.Equal(t, "Subject: [High Priority] bug_report Bcc: attacker@evil.example", headers[2])
+}
+
+func TestEmailMessageEncodesNonASCIISubject(t *testing.T) {
+	from := &mail.Address{Name: "Rückmeldung", Address: "reviews@infoblox.com"}
+	to := &mail.Address{Address: "ops@infoblox.com"}
+
+	message := emailMessage(from, to, "Négatif – problème DNS", "body")
+
+	headers := messageHeaders(message)
+	assert.Equal(t, "From: =?utf-8?q?R=C3=BCckmeldung?= <reviews@infoblox.com>", headers[0])
+	assert.True(t, strings.HasPrefix(headers[2], "Subject: =?utf-8?q?"), headers[2])
+	decoded, err := new(mime.WordDecoder).DecodeHeader(strings.TrimPrefix(headers[2], "Subject: "))
+	assert.NoError(t, err)
+	assert.Equal(t, "Négatif – problème DNS", decoded)
+}
+
+func TestSendEmailSanitizesAnalysisInSubject(t *testing.T) {
+	smtp := newRecordingSMTPServer(t, nil)
+	n := emailNotifier(smtp, config.EmailConfig{}, nil)
+
+	dept, review, analysis := testNotificationInputs("email-injection")
+	analysis.IntentCategory = "bug_report\r\nBcc: attacker@evil.example"
+	assert.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	sessions := smtp.received()
+	if assert.Len(t, sessions, 1) {
+		assert.NotContains(t, messageHeaders(sessions[0].Data), "Bcc: attacker@evil.example")
+		assert.Equal(t, []string{"EHLO", "MAIL", "RCPT", "DATA", "QUIT"}, sessions[0].Commands, "only the department is a recipient")
+	}
+}
+
+func TestSendEmailRejectsInvalidRecipient(t *testing.T) {
+	smtp := newRecordingSMTPServer(t, nil)
+	n := emailNotifier(smtp, config.EmailConfig{}, nil)
+
+	for _, contact := range []string{"#eng-alerts", "eng@", "ops@infoblox.com, attacker@evil.example"} {
+		dept, review, analysis := testNotificationInputs("email-invalid")
+		dept.ContactInfo = contact
+		err := n.Notify(context.Background(), dept, review, analysis)
+		assert.ErrorContains(t, err, "invalid email address for department engineering", contact)
+	}
+	assert.Empty(t, smtp.received())
+}
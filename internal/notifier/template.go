@@ -0,0 +1,80 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// departmentTemplate holds a department's parsed notification templates; a
+// nil template keeps the default wording
+type departmentTemplate struct {
+	subject *template.Template
+	email   *template.Template
+	slack   *template.Template
+}
+
+// parseDepartmentTemplates parses the configured templates by department ID.
+// A department with a template that does not parse keeps the default wording
+// and is reported in the returned error.
+func parseDepartmentTemplates(cfg map[string]config.NotificationTemplate) (map[string]departmentTemplate, error) {
+	templates := make(map[string]departmentTemplate, len(cfg))
+	var errs []error
+	for department, tmpl := range cfg {
+		parsed, err := parseDepartmentTemplate(tmpl)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("department %s: %w", department, err))
+			continue
+		}
+		templates[department] = parsed
+	}
+	return templates, errors.Join(errs...)
+}
+
+// parseDepartmentTemplate parses one department's templates
+func parseDepartmentTemplate(cfg config.NotificationTemplate) (departmentTemplate, error) {
+	var parsed departmentTemplate
+	for _, tmpl := range []struct {
+		name, text string
+		dest       **template.Template
+	}{
+		{"subject", cfg.Subject, &parsed.subject},
+		{"email", cfg.Email, &parsed.email},
+		{"slack", cfg.Slack, &parsed.slack},
+	} {
+		if tmpl.text == "" {
+			continue
+		}
+		t, err := template.New(tmpl.name).Parse(tmpl.text)
+		if err != nil {
+			return departmentTemplate{}, fmt.Errorf("invalid %s template: %w", tmpl.name, err)
+		}
+		*tmpl.dest = t
+	}
+	return parsed, nil
+}
+
+// renderTemplate executes tmpl with notification, shortening the review text
+// to maxContent characters as the default wording does
+func renderTemplate(tmpl *template.Template, notification models.Notification, maxContent int) (string, error) {
+	notification.Review.Content, _ = truncateContent(notification.Review.Content, maxContent)
+
+	var out strings.Builder
+	if err := tmpl.Execute(&out, notification); err != nil {
+		return "", fmt.Errorf("failed to render %s template: %w", tmpl.Name(), err)
+	}
+	return out.String(), nil
+}
+
+// notificationSubject returns the email subject for notification, from its
+// department's subject template when one is set
+func (n *Notifier) notificationSubject(notification models.Notification) (string, error) {
+	if tmpl := n.templates[notification.Department.ID].subject; tmpl != nil {
+		return renderTemplate(tmpl, notification, n.contentLimit())
+	}
+	return emailSubject(notification.Analysis), nil
+}
@@ -0,0 +1,94 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// salesTemplate is a brief summary wording for the sales department
+var salesTemplate = config.NotificationTemplate{
+	Subject: "[{{.Review.Source}}] unhappy customer",
+	Email:   "Heads up, {{.Department.Name}}: {{.Review.Author}} wrote {{printf \"%q\" .Review.Content}}",
+	Slack:   ":money_with_wings: {{.Review.Author}} on {{.Review.Source}}: {{.Review.Content}}",
+}
+
+func TestNotifyUsesDepartmentSlackTemplate(t *testing.T) {
+	var bodies [][]byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, body)
+	}))
+	defer server.Close()
+
+	n := New(config.NotifierConfig{
+		Slack:               config.SlackConfig{Enabled: true, WebhookURL: server.URL},
+		DepartmentTemplates: map[string]config.NotificationTemplate{"sales": salesTemplate},
+	})
+
+	dept, review, analysis := testNotificationInputs("templated-sales")
+	dept.ID = "sales"
+	require.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	// Departments without a template keep the default layout
+	dept, review, analysis = testNotificationInputs("default-engineering")
+	require.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	require.Len(t, bodies, 2)
+	var sales, engineering SlackMessage
+	require.NoError(t, json.Unmarshal(bodies[0], &sales))
+	require.NoError(t, json.Unmarshal(bodies[1], &engineering))
+
+	assert.Equal(t, ":money_with_wings: NetworkAdmin123 on twitter: NIOS grid upgrade failed again, DNS outage for two hours", sales.Text)
+	assert.Empty(t, sales.Attachments)
+	assert.NotContains(t, engineering.Text, ":money_with_wings:")
+	assert.NotEmpty(t, engineering.Attachments)
+}
+
+func TestNotifyUsesDepartmentEmailTemplate(t *testing.T) {
+	smtp := newRecordingSMTPServer(t, nil)
+	n := emailNotifier(smtp, config.EmailConfig{}, nil)
+	n.templates, _ = parseDepartmentTemplates(map[string]config.NotificationTemplate{"sales": salesTemplate})
+
+	dept, review, analysis := testNotificationInputs("templated-email")
+	dept.ID = "sales"
+	dept.Name = "Sales"
+	require.NoError(t, n.Notify(context.Background(), dept, review, analysis))
+
+	sessions := smtp.received()
+	require.Len(t, sessions, 1)
+	assert.Contains(t, messageHeaders(sessions[0].Data), "Subject: [twitter] unhappy customer")
+	assert.Contains(t, sessions[0].Data, `Heads up, Sales: NetworkAdmin123 wrote "NIOS grid upgrade failed again, DNS outage for two hours"`)
+	assert.NotContains(t, sessions[0].Data, "Dear Sales Team")
+}
+
+func TestRenderTemplateTruncatesContent(t *testing.T) {
+	templates, err := parseDepartmentTemplates(map[string]config.NotificationTemplate{"sales": {Slack: "{{.Review.Content}}"}})
+	require.NoError(t, err)
+
+	notification := models.Notification{Review: models.Review{Content: strings.Repeat("outage again ", 100)}}
+	text, err := renderTemplate(templates["sales"].slack, notification, 20)
+	require.NoError(t, err)
+	assert.Equal(t, "outage again outage…", text)
+}
+
+func TestParseDepartmentTemplatesSkipsInvalid(t *testing.T) {
+	templates, err := parseDepartmentTemplates(map[string]config.NotificationTemplate{
+		"sales":       salesTemplate,
+		"engineering": {Email: "{{.Review.Content"},
+	})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "department engineering: invalid email template")
+	assert.Contains(t, templates, "sales")
+	assert.NotContains(t, templates, "engineering")
+}
@@ -0,0 +1,79 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/google/uuid"
+)
+
+// Channels that can be sent a test notification
+const (
+	ChannelEmail   = "email"
+	ChannelSlack   = "slack"
+	ChannelWebhook = "webhook"
+)
+
+// TestChannels lists the channels accepted by SendTest
+var TestChannels = []string{ChannelEmail, ChannelSlack, ChannelWebhook}
+
+// ErrUnknownChannel is returned by SendTest for a channel it cannot send to
+var ErrUnknownChannel = errors.New("unknown notification channel")
+
+// SendTest sends a synthetic notification to department through channel, so
+// its settings can be checked without waiting for a real negative review. The
+// channel does not need to be enabled, and the notification is not recorded.
+func (n *Notifier) SendTest(ctx context.Context, channel string, department models.Department) error {
+	notification := testNotification(department)
+
+	var err error
+	switch channel {
+	case ChannelEmail:
+		err = n.sendEmailNotification(notification)
+	case ChannelSlack:
+		err = n.sendSlackNotification(ctx, notification)
+	case ChannelWebhook:
+		err = n.sendWebhookNotification(ctx, notification)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownChannel, channel)
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s test notification failed: %w", channel, err)
+	}
+	return nil
+}
+
+// testNotification builds a notification about a placeholder review
+func testNotification(department models.Department) models.Notification {
+	id := "test-" + uuid.New().String()
+	now := time.Now()
+	return models.Notification{
+		ID: id,
+		Review: models.Review{
+			ID:          id,
+			Source:      "test",
+			SourceID:    id,
+			Title:       "Test notification",
+			Content:     "This is a test notification from the review scraper. No action is needed.",
+			Author:      "review-scraper",
+			CreatedAt:   now,
+			RetrievedAt: now,
+		},
+		Analysis: models.AnalysisResult{
+			ReviewID:       id,
+			SentimentScore: -0.5,
+			IsNegative:     true,
+			IsRelevant:     true,
+			IntentCategory: "test",
+			Confidence:     1,
+			Severity:       models.SeverityLow,
+		},
+		Department: department,
+		SentAt:     now,
+		Status:     StatusSent,
+	}
+}
@@ -0,0 +1,47 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendTestWebhook(t *testing.T) {
+	capture := &captureWebhook{}
+	server := httptest.NewServer(http.HandlerFunc(capture.handler))
+	defer server.Close()
+	n := New(config.NotifierConfig{Webhook: config.WebhookConfig{URL: server.URL}})
+
+	err := n.SendTest(context.Background(), ChannelWebhook, models.Department{ID: "support", Name: "Support"})
+
+	assert.NoError(t, err)
+	if assert.Len(t, capture.bodies, 1) {
+		var notification models.Notification
+		assert.NoError(t, json.Unmarshal(capture.bodies[0], &notification))
+		assert.Equal(t, "test", notification.Review.Source)
+		assert.Equal(t, "support", notification.Department.ID)
+	}
+	assert.Empty(t, n.GetNotifications(""))
+}
+
+func TestSendTestUnknownChannel(t *testing.T) {
+	n := New(config.NotifierConfig{})
+
+	err := n.SendTest(context.Background(), "sms", models.Department{})
+
+	assert.ErrorIs(t, err, ErrUnknownChannel)
+}
+
+func TestSendTestUnconfiguredChannel(t *testing.T) {
+	n := New(config.NotifierConfig{})
+
+	err := n.SendTest(context.Background(), ChannelSlack, models.Department{})
+
+	assert.EqualError(t, err, "slack test notification failed: Slack webhook URL not configured")
+}
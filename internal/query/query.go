@@ -0,0 +1,318 @@
+// Package query parses boolean keyword expressions such as
+// `infoblox AND (dns OR dhcp)` and translates them into the search syntax of
+// each platform the scrapers query.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Operators combining the terms of an expression. They must be written in
+// upper case; adjacent terms without an operator are ANDed.
+const (
+	And = "AND"
+	Or  = "OR"
+)
+
+// MaxAlgoliaSearches caps the searches Algolia expands an expression into. An
+// AND of OR groups multiplies their alternatives, so a short expression can
+// otherwise stand for thousands of searches.
+const MaxAlgoliaSearches = 25
+
+// Expr is a parsed keyword expression: a single term when Op is empty, or an
+// AND or OR group of at least two sub-expressions
+type Expr struct {
+	Op   string
+	Term string // A word or a quoted phrase, without its quotes
+	Args []Expr
+}
+
+// Parse parses a keyword expression. Terms are single words or double-quoted
+// phrases, grouped with AND, OR and parentheses; AND binds tighter than OR.
+// A plain keyword such as `NIOS upgrade` is the AND of its words.
+func Parse(s string) (Expr, error) {
+	tokens, err := tokenize(s)
+	if err != nil {
+		return Expr{}, err
+	}
+	if len(tokens) == 0 {
+		return Expr{}, errors.New("empty keyword expression")
+	}
+
+	p := &parser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return Expr{}, err
+	}
+	if p.pos < len(p.tokens) {
+		return Expr{}, fmt.Errorf("unexpected %s", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// String returns the expression in the boolean syntax Parse accepts, which
+// Reddit search also understands
+func (e Expr) String() string {
+	return e.render(" AND ", " OR ")
+}
+
+// Twitter returns the expression in Twitter search syntax, where a space
+// means AND
+func (e Expr) Twitter() string {
+	return e.render(" ", " OR ")
+}
+
+// Algolia returns the searches that together cover the expression on an
+// Algolia index such as Hacker News, whose queries match all of their words
+// and have no OR. Each OR alternative becomes its own search. Expressions
+// expanding to more than MaxAlgoliaSearches searches are rejected.
+func (e Expr) Algolia() ([]string, error) {
+	if n := e.alternatives(); n > MaxAlgoliaSearches {
+		return nil, fmt.Errorf("expression expands to more than %d searches", MaxAlgoliaSearches)
+	}
+
+	var queries []string
+	for _, terms := range e.conjunctions() {
+		quoted := make([]string, len(terms))
+		for i, term := range terms {
+			quoted[i] = quote(term)
+		}
+		queries = append(queries, strings.Join(quoted, " "))
+	}
+	return queries, nil
+}
+
+// render writes the expression with the given separators, parenthesizing
+// nested groups
+func (e Expr) render(and, or string) string {
+	if e.Op == "" {
+		return quote(e.Term)
+	}
+
+	sep := and
+	if e.Op == Or {
+		sep = or
+	}
+	parts := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		parts[i] = arg.render(and, or)
+		if arg.Op != "" {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// alternatives counts the AND groups conjunctions would expand the expression
+// into, without expanding it. Counts above MaxAlgoliaSearches are reported as
+// MaxAlgoliaSearches+1, so deep nesting cannot overflow.
+func (e Expr) alternatives() int {
+	limit := MaxAlgoliaSearches + 1
+	switch e.Op {
+	case "":
+		return 1
+	case Or:
+		total := 0
+		for _, arg := range e.Args {
+			total = min(total+arg.alternatives(), limit)
+		}
+		return total
+	default:
+		total := 1
+		for _, arg := range e.Args {
+			total = min(total*arg.alternatives(), limit)
+		}
+		return total
+	}
+}
+
+// conjunctions expands the expression into OR-ed groups of AND-ed terms
+func (e Expr) conjunctions() [][]string {
+	switch e.Op {
+	case "":
+		return [][]string{{e.Term}}
+	case Or:
+		var groups [][]string
+		for _, arg := range e.Args {
+			groups = append(groups, arg.conjunctions()...)
+		}
+		return groups
+	default:
+		groups := [][]string{nil}
+		for _, arg := range e.Args {
+			var next [][]string
+			for _, group := range groups {
+				for _, alternative := range arg.conjunctions() {
+					combined := append(append([]string(nil), group...), alternative...)
+					next = append(next, combined)
+				}
+			}
+			groups = next
+		}
+		return groups
+	}
+}
+
+// quote wraps a term in double quotes when it would not survive as a bare word
+func quote(term string) string {
+	if term == And || term == Or || strings.ContainsAny(term, " \t()\"") {
+		return `"` + term + `"`
+	}
+	return term
+}
+
+// token is a lexical element of an expression
+type token struct {
+	text   string
+	phrase bool // Quoted, so never an operator or parenthesis
+}
+
+func (t token) String() string {
+	if t.phrase {
+		return fmt.Sprintf("phrase %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// is reports whether the token is the given unquoted operator or parenthesis
+func (t token) is(text string) bool {
+	return !t.phrase && t.text == text
+}
+
+// tokenize splits an expression into words, phrases, operators and parentheses
+func tokenize(s string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, token{text: string(c)})
+			i++
+		case c == '"':
+			end := strings.IndexByte(s[i+1:], '"')
+			if end < 0 {
+				return nil, errors.New("unterminated quoted phrase")
+			}
+			phrase := strings.TrimSpace(s[i+1 : i+1+end])
+			if phrase == "" {
+				return nil, errors.New("empty quoted phrase")
+			}
+			tokens = append(tokens, token{text: phrase, phrase: true})
+			i += end + 2
+		default:
+			end := strings.IndexAny(s[i:], " \t\n()\"")
+			if end < 0 {
+				end = len(s) - i
+			}
+			tokens = append(tokens, token{text: s[i : i+end]})
+			i += end
+		}
+	}
+	return tokens, nil
+}
+
+// parser is a recursive descent parser over a token list
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the next token, or false at the end of the expression
+func (p *parser) peek() (token, bool) {
+	if p.pos >= len(p.tokens) {
+		return token{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+// parseOr parses AND groups separated by OR
+func (p *parser) parseOr() (Expr, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return Expr{}, err
+	}
+	args := []Expr{first}
+	for {
+		next, ok := p.peek()
+		if !ok || !next.is(Or) {
+			break
+		}
+		p.pos++
+		arg, err := p.parseAnd()
+		if err != nil {
+			return Expr{}, err
+		}
+		args = append(args, arg)
+	}
+	return group(Or, args), nil
+}
+
+// parseAnd parses operands joined by AND or simply listed one after another
+func (p *parser) parseAnd() (Expr, error) {
+	first, err := p.parseOperand()
+	if err != nil {
+		return Expr{}, err
+	}
+	args := []Expr{first}
+	for {
+		next, ok := p.peek()
+		if !ok || next.is(Or) || next.is(")") {
+			break
+		}
+		if next.is(And) {
+			p.pos++
+		}
+		arg, err := p.parseOperand()
+		if err != nil {
+			return Expr{}, err
+		}
+		args = append(args, arg)
+	}
+	return group(And, args), nil
+}
+
+// parseOperand parses a term or a parenthesized expression
+func (p *parser) parseOperand() (Expr, error) {
+	next, ok := p.peek()
+	if !ok {
+		return Expr{}, errors.New("expression ends where a term was expected")
+	}
+	switch {
+	case next.is(And) || next.is(Or) || next.is(")"):
+		return Expr{}, fmt.Errorf("unexpected %s where a term was expected", next)
+	case next.is("("):
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return Expr{}, err
+		}
+		if closing, ok := p.peek(); !ok || !closing.is(")") {
+			return Expr{}, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return expr, nil
+	default:
+		p.pos++
+		return Expr{Term: next.text}, nil
+	}
+}
+
+// group combines args with op, flattening nested groups of the same operator
+// and returning a lone argument as is
+func group(op string, args []Expr) Expr {
+	if len(args) == 1 {
+		return args[0]
+	}
+	var flat []Expr
+	for _, arg := range args {
+		if arg.Op == op {
+			flat = append(flat, arg.Args...)
+		} else {
+			flat = append(flat, arg)
+		}
+	}
+	return Expr{Op: op, Args: flat}
+}
@@ -0,0 +1,127 @@
+package query
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGroupsTerms(t *testing.T) {
+	expr, err := Parse(`infoblox AND (dns OR dhcp)`)
+	assert.NoError(t, err)
+	assert.Equal(t, Expr{Op: And, Args: []Expr{
+		{Term: "infoblox"},
+		{Op: Or, Args: []Expr{{Term: "dns"}, {Term: "dhcp"}}},
+	}}, expr)
+}
+
+func TestParseAndBindsTighterThanOr(t *testing.T) {
+	expr, err := Parse(`bloxone OR infoblox dns`)
+	assert.NoError(t, err)
+	assert.Equal(t, "bloxone OR (infoblox AND dns)", expr.String())
+}
+
+func TestParseFlattensNestedGroups(t *testing.T) {
+	expr, err := Parse(`(nios AND grid) AND ((dns OR dhcp) OR ipam)`)
+	assert.NoError(t, err)
+	assert.Equal(t, "nios AND grid AND (dns OR dhcp OR ipam)", expr.String())
+}
+
+func TestParsePlainKeywords(t *testing.T) {
+	single, err := Parse("infoblox")
+	assert.NoError(t, err)
+	assert.Equal(t, Expr{Term: "infoblox"}, single)
+
+	// Lower-case operators are ordinary words
+	words, err := Parse("NIOS upgrade and or")
+	assert.NoError(t, err)
+	assert.Equal(t, "NIOS upgrade and or", words.Twitter())
+}
+
+func TestParsePhrases(t *testing.T) {
+	expr, err := Parse(`"grid manager" AND ("AND" OR nios)`)
+	assert.NoError(t, err)
+	assert.Equal(t, "grid manager", expr.Args[0].Term)
+	assert.Equal(t, `"grid manager" ("AND" OR nios)`, expr.Twitter())
+}
+
+func TestParseRejectsMalformedExpressions(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"   ",
+		"infoblox AND",
+		"OR dns",
+		"infoblox AND OR dns",
+		"(dns OR dhcp",
+		"dns OR dhcp)",
+		"()",
+		`"grid manager`,
+		`infoblox ""`,
+	} {
+		_, err := Parse(input)
+		assert.Error(t, err, input)
+	}
+}
+
+func TestPlatformQueries(t *testing.T) {
+	cases := []struct {
+		input   string
+		reddit  string
+		twitter string
+		algolia []string
+	}{
+		{
+			input:   "infoblox",
+			reddit:  "infoblox",
+			twitter: "infoblox",
+			algolia: []string{"infoblox"},
+		},
+		{
+			input:   "infoblox AND (dns OR dhcp)",
+			reddit:  "infoblox AND (dns OR dhcp)",
+			twitter: "infoblox (dns OR dhcp)",
+			algolia: []string{"infoblox dns", "infoblox dhcp"},
+		},
+		{
+			input:   `(nios OR bloxone) ("grid manager" OR ipam)`,
+			reddit:  `(nios OR bloxone) AND ("grid manager" OR ipam)`,
+			twitter: `(nios OR bloxone) ("grid manager" OR ipam)`,
+			algolia: []string{`nios "grid manager"`, "nios ipam", `bloxone "grid manager"`, "bloxone ipam"},
+		},
+		{
+			input:   "infoblox dns OR bloxone",
+			reddit:  "(infoblox AND dns) OR bloxone",
+			twitter: "(infoblox dns) OR bloxone",
+			algolia: []string{"infoblox dns", "bloxone"},
+		},
+	}
+
+	for _, c := range cases {
+		expr, err := Parse(c.input)
+		if !assert.NoError(t, err, c.input) {
+			continue
+		}
+		assert.Equal(t, c.reddit, expr.String(), c.input)
+		assert.Equal(t, c.twitter, expr.Twitter(), c.input)
+		algolia, err := expr.Algolia()
+		assert.NoError(t, err, c.input)
+		assert.Equal(t, c.algolia, algolia, c.input)
+	}
+}
+
+func TestAlgoliaCapsExpansion(t *testing.T) {
+	// Five alternatives in each of two groups make 25 searches, at the cap
+	expr, err := Parse("(a OR b OR c OR d OR e) (f OR g OR h OR i OR j)")
+	assert.NoError(t, err)
+	searches, err := expr.Algolia()
+	assert.NoError(t, err)
+	assert.Len(t, searches, MaxAlgoliaSearches)
+
+	// Forty groups of two would be 2^40 searches
+	expr, err = Parse(strings.Repeat("(dns OR dhcp) ", 40))
+	assert.NoError(t, err)
+	searches, err = expr.Algolia()
+	assert.EqualError(t, err, "expression expands to more than 25 searches")
+	assert.Nil(t, searches)
+}
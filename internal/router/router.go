@@ -1,10 +1,14 @@
 package router
 
 import (
+	"fmt"
+	"log/slog"
 	"sort"
+	"strings"
 	"sync"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
 	"github.com/Infoblox-CTO/review-scraper/pkg/models"
 )
 
@@ -12,8 +16,16 @@ import (
 type Router struct {
 	config       config.RouterConfig
 	departments  map[string]models.Department
-	mappingCache map[string]string
+	mappingCache map[string]config.DepartmentMapping // Keyed by category
 	mu           sync.RWMutex
+
+	// Routing counts, guarded by mu
+	routedCounts  map[string]int // Keyed by department ID
+	totalRoutes   int
+	defaultRoutes int // Routes that matched no language or category
+	escalations   int // Reviews escalated to more departments
+
+	logger *slog.Logger
 }
 
 // New creates a new department router with the provided configuration
@@ -22,31 +34,10 @@ func New(cfg config.RouterConfig) *Router {
 	departments := make(map[string]models.Department)
 
 	// Default mappings if none are provided in config
-	if len(cfg.Mappings) == 0 {
-		defaultMappings := []config.DepartmentMapping{
-			// Infoblox-specific mappings
-			{Category: "product_issue", Department: "engineering", Priority: 10},
-			{Category: "technical_support", Department: "support", Priority: 8},
-			{Category: "performance", Department: "engineering", Priority: 8},
-			{Category: "security", Department: "security", Priority: 10},
-			{Category: "feature_request", Department: "product", Priority: 5},
-			{Category: "ui_ux", Department: "design", Priority: 7},
-			{Category: "billing_licensing", Department: "finance", Priority: 9},
-			{Category: "documentation", Department: "documentation", Priority: 6},
-			{Category: "deployment", Department: "professional_services", Priority: 8},
-			{Category: "cloud_integration", Department: "cloud_team", Priority: 8},
-			{Category: "automation", Department: "automation_team", Priority: 7},
-			{Category: "upgrade_issue", Department: "engineering", Priority: 9},
-			{Category: "general_complaint", Department: "support", Priority: 6},
-		}
-		cfg.Mappings = defaultMappings
-	}
+	cfg.Mappings = mappingsOrDefault(cfg.Mappings)
 
 	// Create a cache for quick lookups
-	mappingCache := make(map[string]string)
-	for _, mapping := range cfg.Mappings {
-		mappingCache[mapping.Category] = mapping.Department
-	}
+	mappingCache := buildMappingCache(cfg.Mappings)
 
 	// Define Infoblox-specific departments with contact info
 	departments["engineering"] = models.Department{
@@ -119,59 +110,213 @@ func New(cfg config.RouterConfig) *Router {
 		Categories:  []string{"automation"},
 	}
 
+	addLanguageDepartments(departments, cfg.LanguageDepartments)
+	addDepartmentIDs(departments, cfg.Escalation.Departments)
+
 	return &Router{
 		config:       cfg,
 		departments:  departments,
 		mappingCache: mappingCache,
 		mu:           sync.RWMutex{},
+		routedCounts: make(map[string]int),
+		logger:       logging.Component(nil, "router"),
+	}
+}
+
+// addLanguageDepartments registers a minimal department for each language
+// department that is not already known, so language routes never dangle
+func addLanguageDepartments(departments map[string]models.Department, languageDepartments map[string]string) {
+	for _, departmentID := range languageDepartments {
+		addDepartmentIDs(departments, []string{departmentID})
+	}
+}
+
+// addDepartmentIDs registers a minimal department for each ID that is not
+// already known
+func addDepartmentIDs(departments map[string]models.Department, departmentIDs []string) {
+	for _, departmentID := range departmentIDs {
+		if _, exists := departments[departmentID]; !exists && departmentID != "" {
+			departments[departmentID] = models.Department{
+				ID:   departmentID,
+				Name: departmentID,
+			}
+		}
+	}
+}
+
+// primaryLanguage normalizes a language tag such as "de-DE" to its primary subtag
+func primaryLanguage(language string) string {
+	language = strings.ToLower(strings.TrimSpace(language))
+	if i := strings.IndexAny(language, "-_"); i >= 0 {
+		language = language[:i]
+	}
+	return language
+}
+
+// mappingsOrDefault returns the Infoblox default mappings when none are configured
+func mappingsOrDefault(mappings []config.DepartmentMapping) []config.DepartmentMapping {
+	if len(mappings) > 0 {
+		return mappings
+	}
+
+	return []config.DepartmentMapping{
+		// Infoblox-specific mappings
+		{Category: "product_issue", Department: "engineering", Priority: 10},
+		{Category: "technical_support", Department: "support", Priority: 8},
+		{Category: "performance", Department: "engineering", Priority: 8},
+		{Category: "security", Department: "security", Priority: 10},
+		{Category: "feature_request", Department: "product", Priority: 5},
+		{Category: "ui_ux", Department: "design", Priority: 7},
+		{Category: "billing_licensing", Department: "finance", Priority: 9},
+		{Category: "documentation", Department: "documentation", Priority: 6},
+		{Category: "deployment", Department: "professional_services", Priority: 8},
+		{Category: "cloud_integration", Department: "cloud_team", Priority: 8},
+		{Category: "automation", Department: "automation_team", Priority: 7},
+		{Category: "upgrade_issue", Department: "engineering", Priority: 9},
+		{Category: "general_complaint", Department: "support", Priority: 6},
+	}
+}
+
+// buildMappingCache creates a category-to-mapping lookup from mappings
+func buildMappingCache(mappings []config.DepartmentMapping) map[string]config.DepartmentMapping {
+	mappingCache := make(map[string]config.DepartmentMapping)
+	for _, mapping := range mappings {
+		mappingCache[mapping.Category] = mapping
+	}
+	return mappingCache
+}
+
+// mappingWeight is how much a mapping's priority multiplies its category's
+// score. Mappings without a priority weigh as the lowest priority.
+func mappingWeight(priority int) float64 {
+	if priority < 1 {
+		return 1
 	}
+	return float64(priority)
+}
+
+// SetLogger sets the structured logger used for routing decisions
+func (r *Router) SetLogger(logger *slog.Logger) {
+	r.logger = logging.Component(logger, "router")
 }
 
 // Route determines the appropriate department for a review based on analysis
 func (r *Router) Route(analysis models.AnalysisResult) models.Department {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
+	dept, fallback := r.route(analysis)
+	r.recordRoute(dept.ID, fallback)
+	r.logger.Debug("review routed",
+		logging.KeyReviewID, analysis.ReviewID,
+		"intent_category", analysis.IntentCategory,
+		"language", analysis.Language,
+		"department", dept.ID,
+		"fallback", fallback)
+	return dept
+}
+
+// Escalate returns the departments to notify about analysis in addition to
+// primary, the department it was routed to. Reviews at or above the
+// escalation severity threshold go to every escalation department other than
+// primary; others, and all reviews when escalation is disabled, get none.
+func (r *Router) Escalate(analysis models.AnalysisResult, primary models.Department) []models.Department {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	escalation := r.config.Escalation
+	if escalation.SeverityThreshold == "" || models.SeverityRank(analysis.Severity) < models.SeverityRank(escalation.SeverityThreshold) {
+		return nil
+	}
 
-	// If we have an explicit category-to-department mapping, use that first
-	if departmentID, exists := r.mappingCache[analysis.IntentCategory]; exists {
-		if dept, exists := r.departments[departmentID]; exists {
-			return dept
+	var targets []models.Department
+	seen := map[string]bool{primary.ID: true}
+	for _, departmentID := range escalation.Departments {
+		dept, exists := r.departments[departmentID]
+		if !exists || seen[departmentID] {
+			continue
 		}
+		seen[departmentID] = true
+		targets = append(targets, dept)
+	}
+	if len(targets) > 0 {
+		r.escalations++
+		r.logger.Info("review escalated",
+			logging.KeyReviewID, analysis.ReviewID,
+			"severity", analysis.Severity,
+			"department", primary.ID,
+			"escalated_to", departmentIDs(targets))
 	}
+	return targets
+}
 
-	// If no direct mapping exists, use the categoryScores to find the best department
-	type categoryScore struct {
-		category string
-		score    float64
+// departmentIDs returns the IDs of departments
+func departmentIDs(departments []models.Department) []string {
+	ids := make([]string, len(departments))
+	for i, dept := range departments {
+		ids[i] = dept.ID
 	}
+	return ids
+}
 
-	var scores []categoryScore
-	for category, score := range analysis.CategoryScores {
-		scores = append(scores, categoryScore{category, score})
+// recordRoute counts a review routed to departmentID
+func (r *Router) recordRoute(departmentID string, fallback bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.routedCounts[departmentID]++
+	r.totalRoutes++
+	if fallback {
+		r.defaultRoutes++
 	}
+}
 
-	// Sort by score in descending order
-	sort.Slice(scores, func(i, j int) bool {
-		return scores[i].score > scores[j].score
-	})
+// GetStats returns routing counts per department, in total and for reviews
+// that fell back to the default department
+func (r *Router) GetStats() map[string]interface{} {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	// Try to find a department for each category, starting with highest score
-	for _, cs := range scores {
-		if departmentID, exists := r.mappingCache[cs.category]; exists {
+	departmentRoutes := make(map[string]int, len(r.routedCounts))
+	for departmentID, count := range r.routedCounts {
+		departmentRoutes[departmentID] = count
+	}
+
+	return map[string]interface{}{
+		"routes_total":       r.totalRoutes,
+		"default_routes":     r.defaultRoutes,
+		"escalations":        r.escalations,
+		"department_routes":  departmentRoutes,
+		"default_department": r.config.DefaultDepartment,
+	}
+}
+
+// route picks the department for analysis: by language, then the best mapped
+// category, then the default department. It reports whether it fell back to
+// the default department.
+func (r *Router) route(analysis models.AnalysisResult) (models.Department, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Non-English reviews go to the team configured for their language, if any
+	if language := primaryLanguage(analysis.Language); language != "" && language != "en" {
+		if departmentID, exists := r.config.LanguageDepartments[language]; exists {
 			if dept, exists := r.departments[departmentID]; exists {
-				return dept
+				return dept, false
 			}
 		}
 	}
 
+	// Weigh every mapped category by its score and its mapping's priority
+	if dept, ok := r.bestMappedDepartment(analysis); ok {
+		return dept, false
+	}
+
 	// If no mapping found, return the default department
 	if dept, exists := r.departments[r.config.DefaultDepartment]; exists {
-		return dept
+		return dept, true
 	}
 
 	// Fallback to support if default department doesn't exist
 	if dept, exists := r.departments["support"]; exists {
-		return dept
+		return dept, true
 	}
 
 	// Ultimate fallback
@@ -180,7 +325,47 @@ func (r *Router) Route(analysis models.AnalysisResult) models.Department {
 		Name:        "Customer Support",
 		ContactInfo: "support@company.com",
 		Categories:  []string{"general_complaint"},
+	}, true
+}
+
+// bestMappedDepartment returns the department of the mapped category with the
+// highest score weighted by its mapping's priority, so a high-priority category
+// can outrank a higher-scoring one. The intent category counts with its score,
+// or a full score when the analysis carries none. Ties go to the higher score,
+// then the category name. Callers must hold r.mu.
+func (r *Router) bestMappedDepartment(analysis models.AnalysisResult) (models.Department, bool) {
+	scores := make(map[string]float64, len(analysis.CategoryScores)+1)
+	for category, score := range analysis.CategoryScores {
+		scores[category] = score
+	}
+	if _, scored := scores[analysis.IntentCategory]; analysis.IntentCategory != "" && !scored {
+		scores[analysis.IntentCategory] = 1
+	}
+
+	var (
+		best                    models.Department
+		bestCategory            string
+		bestWeighted, bestScore float64
+		found                   bool
+	)
+	for category, score := range scores {
+		mapping, exists := r.mappingCache[category]
+		if !exists {
+			continue
+		}
+		dept, exists := r.departments[mapping.Department]
+		if !exists {
+			continue
+		}
+
+		weighted := score * mappingWeight(mapping.Priority)
+		better := !found || weighted > bestWeighted ||
+			(weighted == bestWeighted && (score > bestScore || (score == bestScore && category < bestCategory)))
+		if better {
+			best, bestCategory, bestWeighted, bestScore, found = dept, category, weighted, score, true
+		}
 	}
+	return best, found
 }
 
 // GetDepartment returns a department by ID
@@ -192,7 +377,7 @@ func (r *Router) GetDepartment(departmentID string) (models.Department, bool) {
 	return dept, exists
 }
 
-// GetAllDepartments returns all departments
+// GetAllDepartments returns all departments sorted by ID
 func (r *Router) GetAllDepartments() []models.Department {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -201,6 +386,9 @@ func (r *Router) GetAllDepartments() []models.Department {
 	for _, dept := range r.departments {
 		departments = append(departments, dept)
 	}
+	sort.Slice(departments, func(i, j int) bool {
+		return departments[i].ID < departments[j].ID
+	})
 	return departments
 }
 
@@ -211,16 +399,109 @@ func (r *Router) AddDepartment(department models.Department) {
 
 	r.departments[department.ID] = department
 
-	// Update mappings for the department's categories
+	// Update mappings for the department's categories, keeping their priorities
 	for _, category := range department.Categories {
-		r.mappingCache[category] = department.ID
+		r.setMapping(category, department.ID)
 	}
 }
 
-// UpdateMapping updates a category-to-department mapping
+// UpdateMapping updates a category-to-department mapping, keeping its priority
 func (r *Router) UpdateMapping(category, departmentID string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	r.mappingCache[category] = departmentID
+	r.setMapping(category, departmentID)
+}
+
+// RemoveDepartment removes a department and the mappings that route to it.
+// The default department cannot be removed, since unmatched reviews go there.
+func (r *Router) RemoveDepartment(departmentID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if departmentID == r.config.DefaultDepartment {
+		return fmt.Errorf("department %s is the default department and cannot be removed", departmentID)
+	}
+	if _, exists := r.departments[departmentID]; !exists {
+		return fmt.Errorf("department %s not found", departmentID)
+	}
+
+	delete(r.departments, departmentID)
+	for category, mapping := range r.mappingCache {
+		if mapping.Department == departmentID {
+			delete(r.mappingCache, category)
+		}
+	}
+	r.config.Mappings = filterMappings(r.config.Mappings, func(mapping config.DepartmentMapping) bool {
+		return mapping.Department != departmentID
+	})
+	return nil
+}
+
+// RemoveMapping removes the mapping of category, so reviews in it are routed
+// by their other categories or to the default department
+func (r *Router) RemoveMapping(category string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.mappingCache[category]; !exists {
+		return fmt.Errorf("no mapping for category %s", category)
+	}
+
+	delete(r.mappingCache, category)
+	r.config.Mappings = filterMappings(r.config.Mappings, func(mapping config.DepartmentMapping) bool {
+		return mapping.Category != category
+	})
+	return nil
+}
+
+// filterMappings returns a copy of mappings holding those that keep accepts
+func filterMappings(mappings []config.DepartmentMapping, keep func(config.DepartmentMapping) bool) []config.DepartmentMapping {
+	kept := make([]config.DepartmentMapping, 0, len(mappings))
+	for _, mapping := range mappings {
+		if keep(mapping) {
+			kept = append(kept, mapping)
+		}
+	}
+	return kept
+}
+
+// setMapping points category at departmentID. Callers must hold r.mu for writing.
+func (r *Router) setMapping(category, departmentID string) {
+	mapping := r.mappingCache[category]
+	mapping.Category = category
+	mapping.Department = departmentID
+	r.mappingCache[category] = mapping
+}
+
+// Config returns the router's current configuration
+func (r *Router) Config() config.RouterConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.config
+}
+
+// UpdateConfig swaps the router's mappings and default department at runtime.
+// Departments added via AddDepartment are kept.
+func (r *Router) UpdateConfig(cfg config.RouterConfig) {
+	cfg.Mappings = mappingsOrDefault(cfg.Mappings)
+	mappingCache := buildMappingCache(cfg.Mappings)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.config = cfg
+	r.mappingCache = mappingCache
+	addLanguageDepartments(r.departments, cfg.LanguageDepartments)
+	addDepartmentIDs(r.departments, cfg.Escalation.Departments)
+}
+
+// Reload applies the router section of a reloaded configuration
+func (r *Router) Reload(cfg *config.Config) error {
+	if err := cfg.Router.Validate(); err != nil {
+		return err
+	}
+	r.UpdateConfig(cfg.Router)
+	return nil
 }
@@ -0,0 +1,258 @@
+package router
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouteByLanguage(t *testing.T) {
+	a := analyzer.New(config.AnalyzerConfig{Mode: "local", NegativeThreshold: -0.1})
+	r := New(config.RouterConfig{
+		LanguageDepartments: map[string]string{"de": "support-emea"},
+	})
+	ctx := context.Background()
+
+	german := models.Review{
+		ID:       "r1",
+		Language: "de-DE",
+		Content:  "The NIOS upgrade failed and the dns service crashed. Terrible, slow and broken.",
+	}
+	english := models.Review{
+		ID:       "r2",
+		Language: "en",
+		Content:  "The NIOS upgrade failed and the dns service crashed. Terrible, slow and broken.",
+	}
+
+	germanResult, err := a.Analyze(ctx, german)
+	assert.NoError(t, err)
+	assert.True(t, germanResult.IsNegative)
+	assert.Equal(t, "de-DE", germanResult.Language)
+
+	englishResult, err := a.Analyze(ctx, english)
+	assert.NoError(t, err)
+	assert.True(t, englishResult.IsNegative)
+	assert.Equal(t, "en", englishResult.Language, "cached results carry the language of the review being analyzed")
+
+	// The German review goes to the EMEA team regardless of category
+	assert.Equal(t, "support-emea", r.Route(germanResult).ID)
+
+	// The English review is routed by its category as before
+	categoryRouted := r.Route(models.AnalysisResult{IntentCategory: englishResult.IntentCategory})
+	assert.Equal(t, categoryRouted.ID, r.Route(englishResult).ID)
+	assert.NotEqual(t, "support-emea", r.Route(englishResult).ID)
+}
+
+func TestRouteUnmappedLanguageFallsBackToCategory(t *testing.T) {
+	r := New(config.RouterConfig{LanguageDepartments: map[string]string{"de": "support-emea"}})
+
+	dept := r.Route(models.AnalysisResult{Language: "fr", IntentCategory: "security"})
+
+	assert.Equal(t, "security", dept.ID)
+}
+
+func TestLanguageDepartmentsAreRegistered(t *testing.T) {
+	r := New(config.RouterConfig{})
+	_, exists := r.GetDepartment("support-apac")
+	assert.False(t, exists)
+
+	r.UpdateConfig(config.RouterConfig{LanguageDepartments: map[string]string{"ja": "support-apac"}})
+
+	dept, exists := r.GetDepartment("support-apac")
+	assert.True(t, exists)
+	assert.Equal(t, "support-apac", r.Route(models.AnalysisResult{Language: "ja", IntentCategory: "security"}).ID)
+	assert.Equal(t, "support-apac", dept.Name)
+}
+
+func TestRouteHighPriorityCategoryBeatsHigherScore(t *testing.T) {
+	r := New(config.RouterConfig{
+		Mappings: []config.DepartmentMapping{
+			{Category: "feature_request", Department: "product", Priority: 2},
+			{Category: "security", Department: "security", Priority: 10},
+		},
+	})
+
+	// 0.3 * 10 outweighs 0.7 * 2, even though feature_request is the intent
+	dept := r.Route(models.AnalysisResult{
+		IntentCategory: "feature_request",
+		CategoryScores: map[string]float64{"feature_request": 0.7, "security": 0.3},
+	})
+
+	assert.Equal(t, "security", dept.ID)
+}
+
+func TestRouteHighScoreWinsAtEqualPriority(t *testing.T) {
+	r := New(config.RouterConfig{
+		Mappings: []config.DepartmentMapping{
+			{Category: "feature_request", Department: "product", Priority: 5},
+			{Category: "ui_ux", Department: "design", Priority: 5},
+		},
+	})
+
+	dept := r.Route(models.AnalysisResult{
+		CategoryScores: map[string]float64{"feature_request": 0.4, "ui_ux": 0.6, "unmapped": 0.9},
+	})
+
+	assert.Equal(t, "design", dept.ID)
+}
+
+func TestRouteTiesGoToHigherScoreThenCategoryName(t *testing.T) {
+	r := New(config.RouterConfig{
+		Mappings: []config.DepartmentMapping{
+			{Category: "documentation", Department: "documentation", Priority: 4},
+			{Category: "feature_request", Department: "product", Priority: 2},
+			{Category: "ui_ux", Department: "design", Priority: 2},
+		},
+	})
+
+	// Both weigh 0.8, so the higher raw score of ui_ux decides
+	assert.Equal(t, "design", r.Route(models.AnalysisResult{
+		CategoryScores: map[string]float64{"documentation": 0.2, "ui_ux": 0.4},
+	}).ID)
+
+	// Same weight and score, so the category name decides
+	assert.Equal(t, "product", r.Route(models.AnalysisResult{
+		CategoryScores: map[string]float64{"feature_request": 0.4, "ui_ux": 0.4},
+	}).ID)
+}
+
+func TestUpdateMappingKeepsPriority(t *testing.T) {
+	r := New(config.RouterConfig{
+		Mappings: []config.DepartmentMapping{
+			{Category: "feature_request", Department: "product", Priority: 2},
+			{Category: "security", Department: "security", Priority: 10},
+		},
+	})
+	r.UpdateMapping("security", "engineering")
+
+	dept := r.Route(models.AnalysisResult{
+		CategoryScores: map[string]float64{"feature_request": 0.7, "security": 0.3},
+	})
+
+	assert.Equal(t, "engineering", dept.ID)
+}
+
+func TestRemoveDepartment(t *testing.T) {
+	r := New(config.RouterConfig{DefaultDepartment: "support"})
+
+	assert.NoError(t, r.RemoveDepartment("security"))
+
+	_, exists := r.GetDepartment("security")
+	assert.False(t, exists)
+	for _, mapping := range r.Config().Mappings {
+		assert.NotEqual(t, "security", mapping.Department)
+	}
+
+	// Its categories now fall through to the default department
+	assert.Equal(t, "support", r.Route(models.AnalysisResult{IntentCategory: "security"}).ID)
+	assert.EqualError(t, r.RemoveDepartment("security"), "department security not found")
+}
+
+func TestRemoveDefaultDepartmentIsRejected(t *testing.T) {
+	r := New(config.RouterConfig{DefaultDepartment: "support"})
+
+	err := r.RemoveDepartment("support")
+
+	assert.EqualError(t, err, "department support is the default department and cannot be removed")
+	_, exists := r.GetDepartment("support")
+	assert.True(t, exists)
+	assert.Equal(t, "support", r.Route(models.AnalysisResult{IntentCategory: "technical_support"}).ID)
+}
+
+func TestRemoveMapping(t *testing.T) {
+	r := New(config.RouterConfig{
+		DefaultDepartment: "support",
+		Mappings: []config.DepartmentMapping{
+			{Category: "security", Department: "security", Priority: 10},
+			{Category: "feature_request", Department: "product", Priority: 5},
+		},
+	})
+	analysis := models.AnalysisResult{
+		IntentCategory: "security",
+		CategoryScores: map[string]float64{"security": 0.6, "feature_request": 0.4},
+	}
+
+	assert.NoError(t, r.RemoveMapping("security"))
+
+	// The next mapped category takes over, and the department itself remains
+	assert.Equal(t, "product", r.Route(analysis).ID)
+	_, exists := r.GetDepartment("security")
+	assert.True(t, exists)
+	assert.Equal(t, []config.DepartmentMapping{{Category: "feature_request", Department: "product", Priority: 5}}, r.Config().Mappings)
+	assert.EqualError(t, r.RemoveMapping("security"), "no mapping for category security")
+}
+
+func TestGetStatsCountsRoutes(t *testing.T) {
+	r := New(config.RouterConfig{DefaultDepartment: "support"})
+
+	analyses := []models.AnalysisResult{
+		{IntentCategory: "security"},
+		{IntentCategory: "security"},
+		{IntentCategory: "feature_request"},
+		{IntentCategory: "unmapped"},
+		{},
+	}
+	var wg sync.WaitGroup
+	for _, analysis := range analyses {
+		wg.Add(1)
+		go func(analysis models.AnalysisResult) {
+			defer wg.Done()
+			r.Route(analysis)
+		}(analysis)
+	}
+	wg.Wait()
+
+	stats := r.GetStats()
+	assert.Equal(t, 5, stats["routes_total"])
+	assert.Equal(t, 2, stats["default_routes"])
+	assert.Equal(t, map[string]int{"security": 2, "product": 1, "support": 2}, stats["department_routes"])
+	assert.Equal(t, "support", stats["default_department"])
+}
+
+func TestEscalateSevereReviews(t *testing.T) {
+	r := New(config.RouterConfig{
+		Escalation: config.EscalationConfig{
+			SeverityThreshold: models.SeverityHigh,
+			Departments:       []string{"management", "security"},
+		},
+	})
+	security, _ := r.GetDepartment("security")
+
+	// A high-severity review goes to every escalation department but its own
+	high := models.AnalysisResult{ReviewID: "r1", IntentCategory: "security", Severity: models.SeverityHigh}
+	escalated := r.Escalate(high, security)
+	assert.Equal(t, []models.Department{{ID: "management", Name: "management"}}, escalated)
+
+	engineering, _ := r.GetDepartment("engineering")
+	assert.Equal(t, []string{"management", "security"}, departmentIDs(r.Escalate(high, engineering)))
+
+	// Less severe reviews are not escalated
+	medium := models.AnalysisResult{ReviewID: "r2", Severity: models.SeverityMedium}
+	assert.Empty(t, r.Escalate(medium, engineering))
+
+	assert.Equal(t, 2, r.GetStats()["escalations"])
+}
+
+func TestEscalateDisabled(t *testing.T) {
+	r := New(config.RouterConfig{Escalation: config.EscalationConfig{Departments: []string{"management"}}})
+	engineering, _ := r.GetDepartment("engineering")
+
+	assert.Empty(t, r.Escalate(models.AnalysisResult{Severity: models.SeverityHigh}, engineering))
+}
+
+func TestGetAllDepartmentsSortedByID(t *testing.T) {
+	r := New(config.RouterConfig{LanguageDepartments: map[string]string{"de": "support-emea", "ja": "support-apac"}})
+
+	departments := r.GetAllDepartments()
+	ids := make([]string, len(departments))
+	for i, dept := range departments {
+		ids[i] = dept.ID
+	}
+	assert.IsIncreasing(t, ids)
+	assert.Contains(t, ids, "support-apac")
+}
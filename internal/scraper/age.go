@@ -0,0 +1,48 @@
+package scraper
+
+import (
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// ageFilter drops reviews created before a cutoff
+type ageFilter struct {
+	cutoff      time.Time // Zero keeps every review
+	dropUndated bool
+}
+
+// newAgeFilter returns the filter for reviews older than the configured
+// maximum age at now
+func newAgeFilter(cfg config.ScrapersConfig, now time.Time) ageFilter {
+	maxAge := cfg.MaxReviewAge.Duration()
+	if maxAge <= 0 {
+		return ageFilter{}
+	}
+	return ageFilter{cutoff: now.Add(-maxAge), dropUndated: cfg.DropUndatedReviews}
+}
+
+// admits reports whether a review created at createdAt is recent enough. A
+// zero createdAt means the review's date could not be parsed.
+func (f ageFilter) admits(createdAt time.Time) bool {
+	if createdAt.IsZero() {
+		return !f.dropUndated
+	}
+	return !createdAt.Before(f.cutoff)
+}
+
+// apply returns the reviews recent enough to keep, and how many were dropped
+func (f ageFilter) apply(reviews []models.Review) ([]models.Review, int) {
+	if f.cutoff.IsZero() {
+		return reviews, 0
+	}
+
+	kept := make([]models.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if f.admits(review.CreatedAt) {
+			kept = append(kept, review)
+		}
+	}
+	return kept, len(reviews) - len(kept)
+}
@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// reviewAged returns a review with the given source ID created age ago
+func reviewAged(sourceID string, age time.Duration) models.Review {
+	review := reviewAt(sourceID, 1)
+	review.CreatedAt = time.Now().Add(-age)
+	return review
+}
+
+func TestAgeFilter(t *testing.T) {
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	f := newAgeFilter(config.ScrapersConfig{MaxReviewAge: config.Duration(48 * time.Hour)}, now)
+
+	assert.True(t, f.admits(now.Add(-time.Hour)))
+	assert.True(t, f.admits(now.Add(-48*time.Hour)), "the cutoff itself is kept")
+	assert.False(t, f.admits(now.Add(-49*time.Hour)))
+	assert.True(t, f.admits(time.Time{}), "undated reviews are kept by default")
+
+	f = newAgeFilter(config.ScrapersConfig{MaxReviewAge: config.Duration(48 * time.Hour), DropUndatedReviews: true}, now)
+	assert.False(t, f.admits(time.Time{}))
+}
+
+func TestAgeFilterDisabled(t *testing.T) {
+	reviews := []models.Review{reviewAt("1", 1), {ID: "undated"}}
+	f := newAgeFilter(config.ScrapersConfig{DropUndatedReviews: true}, time.Now())
+
+	kept, dropped := f.apply(reviews)
+	assert.Equal(t, reviews, kept)
+	assert.Zero(t, dropped)
+}
+
+func TestScrapeAllDropsOldReviews(t *testing.T) {
+	undated := models.Review{ID: "fake-undated", SourceID: "undated"}
+	fake := &fakeScraper{}
+	fake.add(
+		reviewAged("1", 90*24*time.Hour),
+		reviewAged("2", 2*time.Hour),
+		reviewAged("3", 40*24*time.Hour),
+		undated,
+		reviewAged("4", time.Hour),
+	)
+	m := newTestManager(t, config.ScrapersConfig{MaxReviewAge: config.Duration(30 * 24 * time.Hour)})
+	m.scrapers = []Scraper{fake}
+
+	reviews, err := m.ScrapeAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-2", "fake-undated", "fake-4"}, reviewIDs(reviews))
+	assert.Equal(t, "4", m.seen.Get("Fake").SourceID, "dropped reviews still advance the watermark")
+}
+
+func TestScrapeAllDropsUndatedReviewsWhenConfigured(t *testing.T) {
+	fake := &fakeScraper{}
+	fake.add(reviewAged("1", time.Hour), models.Review{ID: "fake-undated", SourceID: "undated"})
+	m := newTestManager(t, config.ScrapersConfig{MaxReviewAge: config.Duration(24 * time.Hour), DropUndatedReviews: true})
+	m.scrapers = []Scraper{fake}
+
+	reviews, err := m.ScrapeAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-1"}, reviewIDs(reviews))
+}
@@ -0,0 +1,277 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"golang.org/x/time/rate"
+)
+
+// appStoreBaseURL serves the iTunes customer reviews feed
+const appStoreBaseURL = "https://itunes.apple.com"
+
+// appStoreMaxPages is the deepest page the customer reviews feed serves
+const appStoreMaxPages = 10
+
+// appStoreLabel is the wrapper the iTunes JSON feed puts around each value
+type appStoreLabel struct {
+	Label string `json:"label"`
+}
+
+// AppStoreEntry is one entry of the iTunes customer reviews feed
+type AppStoreEntry struct {
+	ID        appStoreLabel `json:"id"`
+	Title     appStoreLabel `json:"title"`
+	Content   appStoreLabel `json:"content"`
+	Rating    appStoreLabel `json:"im:rating"`
+	Version   appStoreLabel `json:"im:version"`
+	VoteSum   appStoreLabel `json:"im:voteSum"`
+	VoteCount appStoreLabel `json:"im:voteCount"`
+	Updated   appStoreLabel `json:"updated"`
+	Author    struct {
+		Name appStoreLabel `json:"name"`
+		URI  appStoreLabel `json:"uri"`
+	} `json:"author"`
+}
+
+// appStoreEntries holds a feed's entries. The feed sends a lone entry as an
+// object rather than a one-element array.
+type appStoreEntries []AppStoreEntry
+
+// UnmarshalJSON accepts either an array of entries or a single entry
+func (e *appStoreEntries) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '{' {
+		var entry AppStoreEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		*e = appStoreEntries{entry}
+		return nil
+	}
+	var entries []AppStoreEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	*e = entries
+	return nil
+}
+
+// AppStoreFeed is a page of the iTunes customer reviews feed
+type AppStoreFeed struct {
+	Feed struct {
+		Entry appStoreEntries `json:"entry"`
+	} `json:"feed"`
+}
+
+// AppStoreScraper implements the Scraper interface for App Store customer
+// reviews
+type AppStoreScraper struct {
+	config  config.AppStoreScraperConfig
+	client  *http.Client
+	limiter *rate.Limiter // Nil when no requests-per-minute budget is configured
+	baseURL string
+	enabled bool
+}
+
+// NewAppStoreScraper creates a new App Store scraper
+func NewAppStoreScraper(cfg config.AppStoreScraperConfig, rates config.RateLimitConfig,
+	proxies config.ProxyConfig) (*AppStoreScraper, error) {
+
+	client, err := newScraperHTTPClient(proxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AppStoreScraper{
+		config:  cfg,
+		client:  client,
+		limiter: newRequestLimiter(rates),
+		baseURL: appStoreBaseURL,
+		enabled: cfg.Enabled,
+	}, nil
+}
+
+func init() {
+	Register("appStore", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !cfg.AppStore.Enabled {
+			return nil, nil
+		}
+		appStore, err := NewAppStoreScraper(cfg.AppStore, rates, cfg.ProxySettings)
+		if err != nil {
+			return nil, err
+		}
+		appStore.limiter = limiter
+		return []Scraper{appStore}, nil
+	})
+}
+
+// Name returns the name of this scraper
+func (s *AppStoreScraper) Name() string {
+	return "AppStore"
+}
+
+// IsEnabled returns whether this scraper is enabled
+func (s *AppStoreScraper) IsEnabled() bool {
+	return s.enabled
+}
+
+// Scrape retrieves reviews for each configured app and country, reading the
+// feed once per sort mode. A review found under several sort modes, or in
+// several countries, is returned once.
+func (s *AppStoreScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	seen := make(map[string]bool)
+	var allReviews []models.Review
+
+	for _, appID := range s.config.AppIDs {
+		for _, country := range s.countries() {
+			for _, sortMode := range s.sortModes() {
+				for page := 1; page <= s.maxPages(); page++ {
+					// Respect context cancellation
+					if ctx.Err() != nil {
+						return allReviews, ctx.Err()
+					}
+
+					entries, err := s.fetchPage(ctx, appID, country, sortMode, page)
+					if err != nil {
+						return allReviews, fmt.Errorf("error fetching App Store reviews for app '%s' (%s, %s): %w", appID, country, sortMode, err)
+					}
+
+					retrievedAt := time.Now()
+					found := 0
+					for _, entry := range entries {
+						// The first entry of older feeds describes the app itself
+						if entry.Rating.Label == "" || entry.ID.Label == "" {
+							continue
+						}
+						found++
+						if seen[entry.ID.Label] {
+							continue
+						}
+						seen[entry.ID.Label] = true
+						allReviews = append(allReviews, convertAppStoreEntry(entry, appID, country, sortMode, retrievedAt))
+					}
+
+					// An empty page means the feed is exhausted
+					if found == 0 {
+						break
+					}
+				}
+			}
+		}
+	}
+
+	return allReviews, nil
+}
+
+// countries returns the configured storefronts, defaulting to the US store
+func (s *AppStoreScraper) countries() []string {
+	if len(s.config.Countries) == 0 {
+		return []string{"us"}
+	}
+	return s.config.Countries
+}
+
+// sortModes returns the configured feed orders, defaulting to most recent
+func (s *AppStoreScraper) sortModes() []string {
+	if len(s.config.SortModes) == 0 {
+		return []string{"mostrecent"}
+	}
+	modes := make([]string, len(s.config.SortModes))
+	for i, mode := range s.config.SortModes {
+		modes[i] = strings.ToLower(mode)
+	}
+	return modes
+}
+
+// maxPages returns the pages read per feed, capped at what the feed serves
+func (s *AppStoreScraper) maxPages() int {
+	switch {
+	case s.config.MaxPages <= 0:
+		return 1
+	case s.config.MaxPages > appStoreMaxPages:
+		return appStoreMaxPages
+	default:
+		return s.config.MaxPages
+	}
+}
+
+// fetchPage requests one page of an app's reviews in the given sort order
+func (s *AppStoreScraper) fetchPage(ctx context.Context, appID, country, sortMode string, page int) ([]AppStoreEntry, error) {
+	pageURL := fmt.Sprintf("%s/%s/rss/customerreviews/page=%d/id=%s/sortby=%s/json",
+		s.baseURL, strings.ToLower(country), page, strings.TrimPrefix(appID, "id"), sortMode)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	// Respect the shared request budget
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("App Store feed returned status %d", resp.StatusCode)
+	}
+
+	var feed AppStoreFeed
+	if err := json.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return feed.Feed.Entry, nil
+}
+
+// convertAppStoreEntry maps an App Store customer review into the pipeline's
+// review format
+func convertAppStoreEntry(entry AppStoreEntry, appID, country, sortMode string, retrievedAt time.Time) models.Review {
+	numericID := strings.TrimPrefix(appID, "id")
+
+	review := models.Review{
+		ID:          fmt.Sprintf("appstore-%s", entry.ID.Label),
+		Source:      "appstore",
+		SourceID:    entry.ID.Label,
+		Title:       cleanContent(entry.Title.Label),
+		Content:     cleanContent(entry.Content.Label),
+		Author:      entry.Author.Name.Label,
+		URL:         fmt.Sprintf("https://apps.apple.com/%s/app/id%s?see-all=reviews", strings.ToLower(country), numericID),
+		RetrievedAt: retrievedAt,
+		Metadata: map[string]interface{}{
+			"app_id":  numericID,
+			"country": strings.ToLower(country),
+			"sort_by": sortMode,
+		},
+	}
+	if rating, err := strconv.ParseFloat(entry.Rating.Label, 64); err == nil {
+		review.Rating = &rating
+	}
+	if entry.Version.Label != "" {
+		review.Metadata["app_version"] = entry.Version.Label
+	}
+	if votes, err := strconv.Atoi(entry.VoteCount.Label); err == nil {
+		review.Metadata["vote_count"] = votes
+	}
+	if sum, err := strconv.Atoi(entry.VoteSum.Label); err == nil {
+		review.Metadata["vote_sum"] = sum
+	}
+	setReviewDate(&review, entry.Updated.Label)
+
+	return review
+}
@@ -0,0 +1,151 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// appStoreEntryJSON renders a customer review entry as the iTunes feed does
+func appStoreEntryJSON(id string, rating int) string {
+	return fmt.Sprintf(`{
+		"id": {"label": %q},
+		"title": {"label": "Review %s"},
+		"content": {"label": "Body of review %s", "attributes": {"type": "text"}},
+		"im:rating": {"label": "%d"},
+		"im:version": {"label": "4.2.0"},
+		"im:voteSum": {"label": "3"},
+		"im:voteCount": {"label": "5"},
+		"updated": {"label": "2024-05-01T10:00:00-07:00"},
+		"author": {"name": {"label": "user-%s"}, "uri": {"label": "https://itunes.apple.com/us/reviews/id1"}}
+	}`, id, id, id, rating, id)
+}
+
+// appStoreFeedJSON wraps entries in the feed envelope
+func appStoreFeedJSON(entries ...string) string {
+	if len(entries) == 0 {
+		return `{"feed": {"author": {"name": {"label": "iTunes Store"}}}}`
+	}
+	return `{"feed": {"entry": [` + strings.Join(entries, ",") + `]}}`
+}
+
+func TestAppStoreScrapeMergesSortModes(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		switch r.URL.Path {
+		case "/us/rss/customerreviews/page=1/id=123/sortby=mostrecent/json":
+			fmt.Fprint(w, appStoreFeedJSON(appStoreEntryJSON("1", 5), appStoreEntryJSON("2", 1)))
+		case "/us/rss/customerreviews/page=1/id=123/sortby=mosthelpful/json":
+			fmt.Fprint(w, appStoreFeedJSON(appStoreEntryJSON("2", 1), appStoreEntryJSON("3", 4)))
+		default:
+			fmt.Fprint(w, appStoreFeedJSON())
+		}
+	}))
+	defer server.Close()
+
+	s, err := NewAppStoreScraper(config.AppStoreScraperConfig{
+		Enabled:   true,
+		AppIDs:    []string{"id123"},
+		MaxPages:  2,
+		SortModes: []string{"mostrecent", "mosthelpful"},
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	reviews, err := s.Scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"appstore-1", "appstore-2", "appstore-3"}, reviewIDs(reviews))
+	assert.Equal(t, []string{
+		"/us/rss/customerreviews/page=1/id=123/sortby=mostrecent/json",
+		"/us/rss/customerreviews/page=2/id=123/sortby=mostrecent/json",
+		"/us/rss/customerreviews/page=1/id=123/sortby=mosthelpful/json",
+		"/us/rss/customerreviews/page=2/id=123/sortby=mosthelpful/json",
+	}, requested)
+
+	// The review from both feeds keeps the sort mode it was first seen under
+	assert.Equal(t, "mostrecent", reviews[1].Metadata["sort_by"])
+	assert.Equal(t, "mosthelpful", reviews[2].Metadata["sort_by"])
+}
+
+func TestAppStoreScrapeDefaultsToMostRecent(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		fmt.Fprint(w, appStoreFeedJSON())
+	}))
+	defer server.Close()
+
+	s, err := NewAppStoreScraper(config.AppStoreScraperConfig{
+		Enabled: true,
+		AppIDs:  []string{"123"},
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	reviews, err := s.Scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.Empty(t, reviews)
+	assert.Equal(t, []string{"/us/rss/customerreviews/page=1/id=123/sortby=mostrecent/json"}, requested)
+}
+
+func TestAppStoreScrapeSingleEntryFeed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"feed": {"entry": `+appStoreEntryJSON("9", 2)+`}}`)
+	}))
+	defer server.Close()
+
+	s, err := NewAppStoreScraper(config.AppStoreScraperConfig{
+		Enabled:   true,
+		AppIDs:    []string{"id123"},
+		Countries: []string{"GB"},
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	reviews, err := s.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Len(t, reviews, 1)
+
+	review := reviews[0]
+	assert.Equal(t, "appstore-9", review.ID)
+	assert.Equal(t, "appstore", review.Source)
+	assert.Equal(t, "Review 9", review.Title)
+	assert.Equal(t, "Body of review 9", review.Content)
+	assert.Equal(t, "user-9", review.Author)
+	require.NotNil(t, review.Rating)
+	assert.Equal(t, 2.0, *review.Rating)
+	assert.Equal(t, "https://apps.apple.com/gb/app/id123?see-all=reviews", review.URL)
+	assert.True(t, review.CreatedAt.Equal(time.Date(2024, 5, 1, 17, 0, 0, 0, time.UTC)))
+	assert.Equal(t, "gb", review.Metadata["country"])
+	assert.Equal(t, "4.2.0", review.Metadata["app_version"])
+	assert.Equal(t, 5, review.Metadata["vote_count"])
+}
+
+func TestAppStoreScrapeErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	s, err := NewAppStoreScraper(config.AppStoreScraperConfig{
+		Enabled: true,
+		AppIDs:  []string{"123"},
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	_, err = s.Scrape(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "status 503")
+}
@@ -0,0 +1,85 @@
+package scraper
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// authorFilter keeps reviews by allowed authors, dropping denied ones
+type authorFilter struct {
+	allow []*regexp.Regexp // Empty allows every author
+	deny  []*regexp.Regexp
+}
+
+// newAuthorFilter compiles the configured author patterns
+func newAuthorFilter(cfg config.AuthorFilterConfig) authorFilter {
+	return authorFilter{
+		allow: compileAuthorPatterns(cfg.Allow),
+		deny:  compileAuthorPatterns(cfg.Deny),
+	}
+}
+
+// compileAuthorPatterns turns glob patterns into case-insensitive regexps
+// matching whole author names; * matches any run of characters and ? one
+func compileAuthorPatterns(patterns []string) []*regexp.Regexp {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+
+		var expr strings.Builder
+		expr.WriteString(`(?is)^`)
+		for _, r := range pattern {
+			switch r {
+			case '*':
+				expr.WriteString(".*")
+			case '?':
+				expr.WriteString(".")
+			default:
+				expr.WriteString(regexp.QuoteMeta(string(r)))
+			}
+		}
+		expr.WriteString("$")
+		compiled = append(compiled, regexp.MustCompile(expr.String()))
+	}
+	return compiled
+}
+
+// admits reports whether reviews by author are kept
+func (f authorFilter) admits(author string) bool {
+	author = strings.TrimSpace(author)
+	if matchesAny(f.deny, author) {
+		return false
+	}
+	return len(f.allow) == 0 || matchesAny(f.allow, author)
+}
+
+// apply returns the reviews whose authors are admitted, and how many were dropped
+func (f authorFilter) apply(reviews []models.Review) ([]models.Review, int) {
+	if len(f.allow) == 0 && len(f.deny) == 0 {
+		return reviews, 0
+	}
+
+	kept := make([]models.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if f.admits(review.Author) {
+			kept = append(kept, review)
+		}
+	}
+	return kept, len(reviews) - len(kept)
+}
+
+// matchesAny reports whether any pattern matches s
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// reviewBy returns a review with the given source ID and author
+func reviewBy(sourceID, author string, day int) models.Review {
+	review := reviewAt(sourceID, day)
+	review.Author = author
+	return review
+}
+
+func TestAuthorFilterMatchesGlobsIgnoringCase(t *testing.T) {
+	f := newAuthorFilter(config.AuthorFilterConfig{Deny: []string{"InfobloxSupport", "*bot", "spam?"}})
+
+	assert.False(t, f.admits("infobloxsupport"))
+	assert.False(t, f.admits("DealsBot"))
+	assert.False(t, f.admits("Spam1"))
+	assert.True(t, f.admits("spam12"), "? matches exactly one character")
+	assert.True(t, f.admits("botanist"), "patterns match whole names")
+	assert.True(t, f.admits("NetworkAdmin123"))
+	assert.True(t, f.admits(""), "unknown authors pass without an allow list")
+}
+
+func TestAuthorFilterTreatsPatternCharactersLiterally(t *testing.T) {
+	f := newAuthorFilter(config.AuthorFilterConfig{Deny: []string{"u/dns.guy", "[deleted]"}})
+
+	assert.False(t, f.admits("u/DNS.guy"))
+	assert.True(t, f.admits("u/dnsXguy"))
+	assert.False(t, f.admits("[deleted]"))
+	assert.True(t, f.admits("d"))
+}
+
+func TestAuthorFilterAllowList(t *testing.T) {
+	f := newAuthorFilter(config.AuthorFilterConfig{Allow: []string{"verified-*", "NetworkAdmin123"}, Deny: []string{"verified-bot"}})
+
+	assert.True(t, f.admits("Verified-Alice"))
+	assert.True(t, f.admits("networkadmin123"))
+	assert.False(t, f.admits("random_user"))
+	assert.False(t, f.admits(""), "unknown authors are not on the allow list")
+	assert.False(t, f.admits("verified-bot"), "deny wins over allow")
+}
+
+func TestScrapeAllDropsDeniedAuthors(t *testing.T) {
+	fake := &fakeScraper{}
+	fake.add(reviewBy("1", "NetworkAdmin123", 1), reviewBy("2", "DealsBot", 2), reviewBy("3", "InfobloxSupport", 3))
+	m := newTestManager(t, config.ScrapersConfig{Authors: config.AuthorFilterConfig{Deny: []string{"*bot", "infobloxsupport"}}})
+	m.scrapers = []Scraper{fake}
+
+	reviews, err := m.ScrapeAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-1"}, reviewIDs(reviews))
+	assert.Equal(t, "3", m.seen.Get("Fake").SourceID, "dropped reviews still advance the watermark")
+}
+
+func TestScrapeAllEnforcesAllowList(t *testing.T) {
+	fake := &fakeScraper{}
+	fake.add(reviewBy("1", "NetworkAdmin123", 1), reviewBy("2", "CloudEngineer42", 2), reviewBy("3", "", 3))
+	m := newTestManager(t, config.ScrapersConfig{Authors: config.AuthorFilterConfig{Allow: []string{"networkadmin*"}}})
+	m.scrapers = []Scraper{fake}
+
+	reviews, err := m.ScrapeAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-1"}, reviewIDs(reviews))
+}
@@ -0,0 +1,61 @@
+package scraper
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// urlPattern matches links, and the remnant of a link cut short when a
+	// tweet is truncated
+	urlPattern = regexp.MustCompile(`https?://\S*|\bh(?:t(?:t(?:ps?:?/*)?)?)?…$`)
+
+	// leadingMentionsPattern matches the @handles a reply starts with
+	leadingMentionsPattern = regexp.MustCompile(`^(?:\s*@\w+)+`)
+
+	// mentionPattern matches an @handle inside the text
+	mentionPattern = regexp.MustCompile(`@(\w+)`)
+
+	// trailingBoilerplatePattern matches page furniture that review sites
+	// append to the review text, such as expansion links and experience dates
+	trailingBoilerplatePattern = regexp.MustCompile(`(?:\s*(?:Read|See|Show) more|\s*(?i:date of experience):.*)+$`)
+)
+
+// invisibleReplacer turns non-breaking and zero-width characters into plain
+// spaces so they collapse with the surrounding whitespace
+var invisibleReplacer = strings.NewReplacer(
+	"\u00a0", " ",
+	"\u200b", " ",
+	"\u200c", " ",
+	"\u200d", " ",
+	"\ufeff", " ",
+)
+
+// cleanContent normalizes scraped review text: HTML entities are unescaped,
+// including double-escaped ones, trailing site boilerplate is removed and all
+// whitespace is collapsed to single spaces
+func cleanContent(raw string) string {
+	text := raw
+	for i := 0; i < 2; i++ {
+		unescaped := html.UnescapeString(text)
+		if unescaped == text {
+			break
+		}
+		text = unescaped
+	}
+
+	text = invisibleReplacer.Replace(text)
+	text = strings.Join(strings.Fields(text), " ")
+	return strings.TrimSpace(trailingBoilerplatePattern.ReplaceAllString(text, ""))
+}
+
+// cleanTweetText cleans a tweet like cleanContent after removing links and the
+// @handles of a reply. Mentions inside the text keep the handle without the @,
+// since a handle such as @Infoblox is often what makes the tweet relevant.
+func cleanTweetText(raw string) string {
+	text := urlPattern.ReplaceAllString(raw, " ")
+	text = leadingMentionsPattern.ReplaceAllString(text, " ")
+	text = mentionPattern.ReplaceAllString(text, "$1")
+	return cleanContent(text)
+}
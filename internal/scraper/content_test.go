@@ -0,0 +1,50 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanContent(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "plain text is unchanged", raw: "NIOS upgrade failed", want: "NIOS upgrade failed"},
+		{name: "named and numeric entities", raw: "DNS &amp; DHCP don&#39;t sync &quot;at all&quot;", want: `DNS & DHCP don't sync "at all"`},
+		{name: "double escaped entities", raw: "It&amp;#39;s broken &amp;amp; slow", want: "It's broken & slow"},
+		{name: "whitespace runs and newlines", raw: "  Grid  sync\n\n\tkeeps\r\n failing  ", want: "Grid sync keeps failing"},
+		{name: "non-breaking and zero-width spaces", raw: "BloxOne DDI​is&nbsp;down", want: "BloxOne DDI is down"},
+		{name: "trailing read more link", raw: "Support never answered... Read more", want: "Support never answered..."},
+		{name: "trailing experience date", raw: "Licensing is confusing.\n Date of experience: March 03, 2025", want: "Licensing is confusing."},
+		{name: "read more inside the text is kept", raw: "Read more of the docs, they said", want: "Read more of the docs, they said"},
+		{name: "only whitespace", raw: " \n\t ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cleanContent(tt.raw))
+		})
+	}
+}
+
+func TestCleanTweetText(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want string
+	}{
+		{name: "reply mentions are dropped", raw: "@Infoblox @support_team the grid is down again", want: "the grid is down again"},
+		{name: "inline mentions keep the handle", raw: "Thanks @Infoblox for the fast fix", want: "Thanks Infoblox for the fast fix"},
+		{name: "links and truncated links are removed", raw: "BloxOne outage https://t.co/abc123 again and again htt…", want: "BloxOne outage again and again"},
+		{name: "entities and whitespace", raw: "NIOS &amp; DDI\n\n  down  https://t.co/x", want: "NIOS & DDI down"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, cleanTweetText(tt.raw))
+		})
+	}
+}
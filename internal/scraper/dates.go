@@ -0,0 +1,167 @@
+package scraper
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// reviewDateLayouts are the absolute date formats seen on review sites and
+// APIs. Numeric day/month dates are read US style, month first.
+var reviewDateLayouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	time.RubyDate, // Twitter: "Wed Oct 10 20:19:24 +0000 2018"
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.ANSIC,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"January 2, 2006",
+	"January 2 2006",
+	"Jan 2, 2006",
+	"Jan 2 2006",
+	"2 January 2006",
+	"2 Jan 2006",
+	"02 Jan 2006",
+	"Mon, Jan 2, 2006",
+	"Monday, January 2, 2006",
+	"1/2/2006",
+	"01/02/2006",
+	"1/2/06",
+	"January 2006",
+	"Jan 2006",
+}
+
+var (
+	// datePrefixPattern matches labels review sites put before a date
+	datePrefixPattern = regexp.MustCompile(`(?i)^(?:updated|posted|reviewed|published|written|edited)(?: on)?:?\s+`)
+
+	// ordinalPattern matches the suffix of a day such as "3rd"
+	ordinalPattern = regexp.MustCompile(`\b(\d{1,2})(?:st|nd|rd|th)\b`)
+
+	// relativeDatePattern matches dates such as "2 days ago" or "an hour ago"
+	relativeDatePattern = regexp.MustCompile(`^(\d+|an?)\s+(second|minute|hour|day|week|month|year)s?\s+ago$`)
+)
+
+// parseReviewDate parses the date of a review as shown by a review site,
+// trying absolute formats, Unix timestamps and relative dates such as
+// "2 days ago" or "yesterday". It reports false, rather than guessing, when
+// the text is not a date it understands.
+func parseReviewDate(s string) (time.Time, bool) {
+	t, _, ok := parseReviewDateAt(s, time.Now())
+	return t, ok
+}
+
+// parseReviewDateAt parses s like parseReviewDate, resolving relative dates
+// against now. relative reports whether s was a relative date, which is only
+// as exact as its unit and moves with now each time it is parsed.
+func parseReviewDateAt(s string, now time.Time) (t time.Time, relative, ok bool) {
+	s = strings.Join(strings.Fields(s), " ")
+	s = datePrefixPattern.ReplaceAllString(s, "")
+	if s == "" {
+		return time.Time{}, false, false
+	}
+
+	if t, ok := parseRelativeDate(strings.ToLower(s), now); ok {
+		return t, true, true
+	}
+
+	// Unix timestamps in seconds
+	if len(s) == 10 {
+		if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC(), false, true
+		}
+	}
+
+	s = ordinalPattern.ReplaceAllString(s, "$1")
+	for _, layout := range reviewDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, false, true
+		}
+	}
+	return time.Time{}, false, false
+}
+
+// parseRelativeDate parses lower-case relative dates against now
+func parseRelativeDate(s string, now time.Time) (time.Time, bool) {
+	switch s {
+	case "just now", "now", "today":
+		return now, true
+	case "yesterday":
+		return now.AddDate(0, 0, -1), true
+	}
+
+	matches := relativeDatePattern.FindStringSubmatch(s)
+	if matches == nil {
+		return time.Time{}, false
+	}
+
+	count := 1
+	if matches[1] != "a" && matches[1] != "an" {
+		n, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return time.Time{}, false
+		}
+		count = n
+	}
+
+	switch matches[2] {
+	case "second":
+		return now.Add(-time.Duration(count) * time.Second), true
+	case "minute":
+		return now.Add(-time.Duration(count) * time.Minute), true
+	case "hour":
+		return now.Add(-time.Duration(count) * time.Hour), true
+	case "day":
+		return now.AddDate(0, 0, -count), true
+	case "week":
+		return now.AddDate(0, 0, -7*count), true
+	case "month":
+		return now.AddDate(0, -count, 0), true
+	default:
+		return now.AddDate(-count, 0, 0), true
+	}
+}
+
+// setReviewDate sets the creation time of review from the date text raw. When
+// raw cannot be parsed the creation time is left unset and the metadata records
+// date_parsed=false along with the raw text, so no date is ever made up. A
+// relative date such as "2 days ago" is recorded with date_approximate=true,
+// which keeps it out of the source's watermark.
+func setReviewDate(review *models.Review, raw string) {
+	createdAt, relative, ok := parseReviewDateAt(raw, time.Now())
+	if ok {
+		review.CreatedAt = createdAt
+		if relative {
+			setMetadata(review, "date_approximate", true)
+			setMetadata(review, "raw_date", raw)
+		}
+		return
+	}
+
+	setMetadata(review, "date_parsed", false)
+	setMetadata(review, "raw_date", raw)
+}
+
+// setMetadata sets a metadata entry of review, creating the map if needed
+func setMetadata(review *models.Review, key string, value interface{}) {
+	if review.Metadata == nil {
+		review.Metadata = make(map[string]interface{})
+	}
+	review.Metadata[key] = value
+}
+
+// hasApproximateDate reports whether review's creation time was derived from
+// a relative date, so it cannot be compared with other creation times
+func hasApproximateDate(review models.Review) bool {
+	approximate, _ := review.Metadata["date_approximate"].(bool)
+	return approximate
+}
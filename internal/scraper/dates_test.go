@@ -0,0 +1,120 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReviewDateAbsolute(t *testing.T) {
+	day := time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		raw  string
+		want time.Time
+	}{
+		{raw: "2025-03-04T10:30:00Z", want: time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{raw: "2025-03-04T10:30:00.123456Z", want: time.Date(2025, 3, 4, 10, 30, 0, 123456000, time.UTC)},
+		{raw: "2025-03-04T10:30:00", want: time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{raw: "2025-03-04 10:30:00", want: time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{raw: "Tue Mar 04 10:30:00 +0000 2025", want: time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{raw: "Tue, 04 Mar 2025 10:30:00 +0000", want: time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{raw: "1741084200", want: time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{raw: "2025-03-04", want: day},
+		{raw: "2025/03/04", want: day},
+		{raw: "March 4, 2025", want: day},
+		{raw: "Mar 4, 2025", want: day},
+		{raw: "Mar 4 2025", want: day},
+		{raw: "4 March 2025", want: day},
+		{raw: "04 Mar 2025", want: day},
+		{raw: "March 4th, 2025", want: day},
+		{raw: "Tuesday, March 4, 2025", want: day},
+		{raw: "3/4/2025", want: day},
+		{raw: "03/04/2025", want: day},
+		{raw: "Updated Mar 4, 2025", want: day},
+		{raw: "Reviewed on: March 4, 2025", want: day},
+		{raw: "  Mar\n 4,  2025 ", want: day},
+		{raw: "March 2025", want: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, ok := parseReviewDate(tt.raw)
+			assert.True(t, ok)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestParseReviewDateRelative(t *testing.T) {
+	now := time.Date(2025, 3, 4, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		raw  string
+		want time.Time
+	}{
+		{raw: "just now", want: now},
+		{raw: "Today", want: now},
+		{raw: "yesterday", want: now.AddDate(0, 0, -1)},
+		{raw: "30 seconds ago", want: now.Add(-30 * time.Second)},
+		{raw: "a minute ago", want: now.Add(-time.Minute)},
+		{raw: "an hour ago", want: now.Add(-time.Hour)},
+		{raw: "5 hours ago", want: now.Add(-5 * time.Hour)},
+		{raw: "2 days ago", want: now.AddDate(0, 0, -2)},
+		{raw: "1 day ago", want: now.AddDate(0, 0, -1)},
+		{raw: "3 weeks ago", want: now.AddDate(0, 0, -21)},
+		{raw: "a month ago", want: now.AddDate(0, -1, 0)},
+		{raw: "2 Years Ago", want: now.AddDate(-2, 0, 0)},
+		{raw: "Updated 2 days ago", want: now.AddDate(0, 0, -2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, relative, ok := parseReviewDateAt(tt.raw, now)
+			assert.True(t, ok)
+			assert.True(t, relative)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestParseReviewDateUnparsed(t *testing.T) {
+	for _, raw := range []string{"", "   ", "not a date", "sometime last spring", "2025-13-45", "days ago", "12345"} {
+		got, ok := parseReviewDate(raw)
+		assert.False(t, ok, raw)
+		assert.True(t, got.IsZero(), raw)
+	}
+}
+
+func TestSetReviewDate(t *testing.T) {
+	var parsed models.Review
+	setReviewDate(&parsed, "2025-03-04")
+	assert.Equal(t, time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC), parsed.CreatedAt)
+	assert.NotContains(t, parsed.Metadata, "date_parsed")
+	assert.NotContains(t, parsed.Metadata, "date_approximate")
+
+	// Relative dates move with the clock, so they are flagged as approximate
+	var relative models.Review
+	setReviewDate(&relative, "2 days ago")
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, -2), relative.CreatedAt, time.Minute)
+	assert.Equal(t, true, relative.Metadata["date_approximate"])
+	assert.Equal(t, "2 days ago", relative.Metadata["raw_date"])
+	assert.True(t, Watermark{CreatedAt: time.Now(), SourceID: "z"}.Admits(relative))
+
+	// Unparsed dates are flagged instead of made up
+	unparsed := models.Review{Metadata: map[string]interface{}{"platform": "Trustpilot"}}
+	setReviewDate(&unparsed, "sometime last spring")
+	assert.True(t, unparsed.CreatedAt.IsZero())
+	assert.Equal(t, false, unparsed.Metadata["date_parsed"])
+	assert.Equal(t, "sometime last spring", unparsed.Metadata["raw_date"])
+	assert.Equal(t, "Trustpilot", unparsed.Metadata["platform"])
+}
+
+func TestConvertTweetWithUnparsedDate(t *testing.T) {
+	s := &TwitterScraper{}
+
+	review := s.convertTweetToReview(Tweet{ID: "1", Text: "BloxOne is down", CreatedAt: "garbage"})
+
+	assert.True(t, review.CreatedAt.IsZero(), "the scrape time is no longer used as a stand-in")
+	assert.Equal(t, false, review.Metadata["date_parsed"])
+}
@@ -1,203 +1,339 @@
 package scraper
 
 import (
-    "encoding/json"
-    "flag"
-    "fmt"
-    "io"
-    "net/http"
-    "os"
-    "path/filepath"
-    "time"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"golang.org/x/time/rate"
 )
 
 // Review represents a standardized review structure
 type Review struct {
-    ID            int      `json:"id"`
-    ReviewID      int      `json:"reviewID"`
-    Author        string   `json:"author"`
-    Platform      string   `json:"platform"`
-    Title         string   `json:"title"`
-    PostContent   string   `json:"Postcontent"`
-    ReplyContents string   `json:"replyContents"`
-    Timestamp     string   `json:"timestamp"`
-    Tags          []string `json:"tags"`
-    Rating        int      `json:"rating"`
+	ID            int      `json:"id"`
+	ReviewID      int      `json:"reviewID"`
+	Author        string   `json:"author"`
+	Platform      string   `json:"platform"`
+	Title         string   `json:"title"`
+	PostContent   string   `json:"Postcontent"`
+	ReplyContents string   `json:"replyContents"`
+	Timestamp     string   `json:"timestamp"`
+	Tags          []string `json:"tags"`
+	Rating        int      `json:"rating"`
 }
 
 // G2Response represents the expected response structure from G2 API
 // Note: This might need adjustment based on the actual API response
 type G2Response struct {
-    Reviews []struct {
-        ID            string   `json:"id"`
-        ReviewerName  string   `json:"reviewerName"`
-        Title         string   `json:"title"`
-        Content       string   `json:"content"`
-        VendorReply   string   `json:"vendorReply"`
-        ReviewDate    string   `json:"reviewDate"`
-        Tags          []string `json:"tags"`
-        Rating        int      `json:"rating"`
-        // Add other fields as needed
-    } `json:"reviews"`
+	Reviews []struct {
+		ID           string   `json:"id"`
+		ReviewerName string   `json:"reviewerName"`
+		Title        string   `json:"title"`
+		Content      string   `json:"content"`
+		VendorReply  string   `json:"vendorReply"`
+		ReviewDate   string   `json:"reviewDate"`
+		Tags         []string `json:"tags"`
+		Rating       int      `json:"rating"`
+		// Add other fields as needed
+	} `json:"reviews"`
 }
 
+// maxLoggedBody is how much of an unparsable G2 response is logged
+const maxLoggedBody = 512
+
 // G2Client handles API requests to G2
 type G2Client struct {
-    APIKey string
-    Host   string
+	APIKey     string
+	Host       string
+	HTTPClient HTTPDoer     // Nil means http.DefaultClient
+	Logger     *slog.Logger // Nil means slog.Default()
 }
 
 // NewG2Client creates a new G2 API client
 func NewG2Client(apiKey string) *G2Client {
-    return &G2Client{
-        APIKey: apiKey,
-        Host:   "g2-products-reviews-users2.p.rapidapi.com",
-    }
+	return &G2Client{
+		APIKey: apiKey,
+		Host:   "g2-products-reviews-users2.p.rapidapi.com",
+	}
 }
 
 // FetchReviews fetches reviews for a specific product from G2
 func (c *G2Client) FetchReviews(product string, starRating, page int) ([]Review, error) {
-    url := fmt.Sprintf("https://%s/product/%s/reviews?sortOrder=most_recent&page=%d&starRating=%d", 
-        c.Host, product, page, starRating)
-
-    req, err := http.NewRequest("GET", url, nil)
-    if err != nil {
-        return nil, fmt.Errorf("error creating request: %w", err)
-    }
-
-    req.Header.Add("X-RapidAPI-Key", c.APIKey)
-    req.Header.Add("X-RapidAPI-Host", c.Host)
-
-    res, err := http.DefaultClient.Do(req)
-    if err != nil {
-        return nil, fmt.Errorf("error making request: %w", err)
-    }
-    defer res.Body.Close()
-
-    if res.StatusCode != http.StatusOK {
-        return nil, fmt.Errorf("API returned non-200 status: %d", res.StatusCode)
-    }
-
-    body, err := io.ReadAll(res.Body)
-    if err != nil {
-        return nil, fmt.Errorf("error reading response body: %w", err)
-    }
-
-    var g2Response G2Response
-    if err := json.Unmarshal(body, &g2Response); err != nil {
-        // Try to log the response for debugging
-        fmt.Printf("Failed to parse response: %s\n", string(body))
-        return nil, fmt.Errorf("error parsing JSON response: %w", err)
-    }
-
-    // Transform G2 response to our standardized Review format
-    reviews := make([]Review, 0, len(g2Response.Reviews))
-    for i, r := range g2Response.Reviews {
-        reviewID := 0
-        fmt.Sscanf(r.ID, "%d", &reviewID)
-        
-        review := Review{
-            ID:            i + 1,
-            ReviewID:      reviewID,
-            Author:        r.ReviewerName,
-            Platform:      "G2",
-            Title:         r.Title,
-            PostContent:   r.Content,
-            ReplyContents: r.VendorReply,
-            Timestamp:     r.ReviewDate,
-            Tags:          r.Tags,
-            Rating:        r.Rating,
-        }
-        reviews = append(reviews, review)
-    }
-
-    return reviews, nil
+	return c.FetchReviewsContext(context.Background(), product, starRating, page)
+}
+
+// FetchReviewsContext fetches reviews for a specific product from G2, aborting
+// the request when ctx is done
+func (c *G2Client) FetchReviewsContext(ctx context.Context, product string, starRating, page int) ([]Review, error) {
+	url := fmt.Sprintf("https://%s/product/%s/reviews?sortOrder=most_recent&page=%d&starRating=%d",
+		c.Host, product, page, starRating)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	req.Header.Add("X-RapidAPI-Key", c.APIKey)
+	req.Header.Add("X-RapidAPI-Host", c.Host)
+
+	var client HTTPDoer = http.DefaultClient
+	if c.HTTPClient != nil {
+		client = c.HTTPClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned non-200 status: %d", res.StatusCode)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response body: %w", err)
+	}
+
+	var g2Response G2Response
+	if err := json.Unmarshal(body, &g2Response); err != nil {
+		logger := c.Logger
+		if logger == nil {
+			logger = slog.Default()
+		}
+		logger.Warn("failed to parse G2 response", "body", truncateBody(body), "bytes", len(body))
+		return nil, fmt.Errorf("error parsing JSON response: %w", err)
+	}
+
+	// Transform G2 response to our standardized Review format
+	reviews := make([]Review, 0, len(g2Response.Reviews))
+	for i, r := range g2Response.Reviews {
+		reviewID := 0
+		fmt.Sscanf(r.ID, "%d", &reviewID)
+
+		review := Review{
+			ID:            i + 1,
+			ReviewID:      reviewID,
+			Author:        r.ReviewerName,
+			Platform:      "G2",
+			Title:         r.Title,
+			PostContent:   r.Content,
+			ReplyContents: r.VendorReply,
+			Timestamp:     r.ReviewDate,
+			Tags:          r.Tags,
+			Rating:        r.Rating,
+		}
+		reviews = append(reviews, review)
+	}
+
+	return reviews, nil
+}
+
+// truncateBody returns the start of a response body for logging, at most
+// maxLoggedBody bytes long
+func truncateBody(body []byte) string {
+	if len(body) <= maxLoggedBody {
+		return string(body)
+	}
+	return strings.ToValidUTF8(string(body[:maxLoggedBody]), "") + "..."
 }
 
 // SaveReviewsToFile saves reviews to a JSON file
 func SaveReviewsToFile(reviews []Review, product string) (string, error) {
-    // Create output directory if it doesn't exist
-    outputDir := "output"
-    if err := os.MkdirAll(outputDir, 0755); err != nil {
-        return "", fmt.Errorf("error creating output directory: %w", err)
-    }
-
-    // Generate filename with timestamp
-    timestamp := time.Now().Format("20060102_150405")
-    filename := fmt.Sprintf("%s_reviews_%s.json", product, timestamp)
-    filePath := filepath.Join(outputDir, filename)
-
-    // Create file
-    file, err := os.Create(filePath)
-    if err != nil {
-        return "", fmt.Errorf("error creating file: %w", err)
-    }
-    defer file.Close()
-
-    // Write reviews to file with pretty printing
-    encoder := json.NewEncoder(file)
-    encoder.SetIndent("", "  ")
-    if err := encoder.Encode(reviews); err != nil {
-        return "", fmt.Errorf("error encoding reviews to JSON: %w", err)
-    }
-
-    return filePath, nil
-}
-
-func main() {
-    // Parse command line flags
-    apiKey := flag.String("apikey", "", "RapidAPI key (required)")
-    product := flag.String("product", "", "Product name (bloxone-ddi, infoblox-nios, or bloxone-threat-defense)")
-    rating := flag.Int("rating", 0, "Filter by star rating (0-5, 0 means all ratings)")
-    page := flag.Int("page", 1, "Page number")
-    flag.Parse()
-
-    // Validate API key
-    if *apiKey == "" {
-        apiKeyEnv := os.Getenv("RAPID_API_KEY")
-        if apiKeyEnv == "" {
-            fmt.Println("Error: API key is required. Set it with -apikey flag or RAPID_API_KEY environment variable")
-            os.Exit(1)
-        }
-        *apiKey = apiKeyEnv
-    }
-
-    // Validate product name
-    validProducts := map[string]bool{
-        "bloxone-ddi":            true,
-        "infoblox-nios":          true,
-        "bloxone-threat-defense": true,
-    }
-
-    if *product == "" {
-        fmt.Println("Error: Product name is required. Choose from: bloxone-ddi, infoblox-nios, bloxone-threat-defense")
-        os.Exit(1)
-    }
-
-    if !validProducts[*product] {
-        fmt.Printf("Error: Invalid product name. Choose from: bloxone-ddi, infoblox-nios, bloxone-threat-defense\n")
-        os.Exit(1)
-    }
-
-    // Create G2 client
-    client := NewG2Client(*apiKey)
-
-    // Fetch reviews
-    fmt.Printf("Fetching reviews for %s (page %d, rating %d)...\n", *product, *page, *rating)
-    reviews, err := client.FetchReviews(*product, *rating, *page)
-    if err != nil {
-        fmt.Printf("Error fetching reviews: %v\n", err)
-        os.Exit(1)
-    }
-
-    fmt.Printf("Successfully fetched %d reviews\n", len(reviews))
-
-    // Save reviews to file
-    filePath, err := SaveReviewsToFile(reviews, *product)
-    if err != nil {
-        fmt.Printf("Error saving reviews: %v\n", err)
-        os.Exit(1)
-    }
-
-    fmt.Printf("Reviews saved to %s\n", filePath)
-}
\ No newline at end of file
+	// Create output directory if it doesn't exist
+	outputDir := "output"
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("error creating output directory: %w", err)
+	}
+
+	// Generate filename with timestamp
+	timestamp := time.Now().Format("20060102_150405")
+	filename := fmt.Sprintf("%s_reviews_%s.json", product, timestamp)
+	filePath := filepath.Join(outputDir, filename)
+
+	// Create file
+	file, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("error creating file: %w", err)
+	}
+	defer file.Close()
+
+	// Write reviews to file with pretty printing
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(reviews); err != nil {
+		return "", fmt.Errorf("error encoding reviews to JSON: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// G2Scraper implements the Scraper interface for G2 product reviews
+type G2Scraper struct {
+	config     config.G2ScraperConfig
+	rateLimits config.RateLimitConfig
+	client     *G2Client
+	limiter    *rate.Limiter // Nil when no requests-per-minute budget is configured
+	enabled    bool
+}
+
+// NewG2Scraper creates a new G2 scraper
+func NewG2Scraper(cfg config.G2ScraperConfig, rates config.RateLimitConfig, proxies config.ProxyConfig) (*G2Scraper, error) {
+	httpClient, err := newScraperHTTPClient(proxies)
+	if err != nil {
+		return nil, err
+	}
+	client := NewG2Client(cfg.APIKey)
+	client.HTTPClient = httpClient
+	client.Logger = logging.Component(nil, "scraper").With(logging.KeySource, "G2")
+
+	return &G2Scraper{
+		config:     cfg,
+		rateLimits: rates,
+		client:     client,
+		limiter:    newRequestLimiter(rates),
+		enabled:    cfg.Enabled,
+	}, nil
+}
+
+func init() {
+	Register("g2", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !cfg.G2.Enabled {
+			return nil, nil
+		}
+		g2, err := NewG2Scraper(cfg.G2, rates, cfg.ProxySettings)
+		if err != nil {
+			return nil, err
+		}
+		g2.limiter = limiter
+		return []Scraper{g2}, nil
+	})
+}
+
+// Name returns the name of this scraper
+func (s *G2Scraper) Name() string {
+	return "G2"
+}
+
+// IsEnabled returns whether this scraper is enabled
+func (s *G2Scraper) IsEnabled() bool {
+	return s.enabled
+}
+
+// Scrape retrieves the most recent reviews of the configured product from G2
+func (s *G2Scraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	return s.ScrapeSince(ctx, Watermark{})
+}
+
+// ScrapeSince retrieves the reviews of the configured product newer than since.
+// G2 returns the most recent reviews first, so paging stops at the first page
+// reaching a review already seen.
+func (s *G2Scraper) ScrapeSince(ctx context.Context, since Watermark) ([]models.Review, error) {
+	var allReviews []models.Review
+
+	// Set the maximum number of pages to scrape
+	maxPages := s.config.MaxPages
+	if maxPages <= 0 {
+		maxPages = 5 // Default to 5 pages
+	}
+
+	for page := 1; page <= maxPages; page++ {
+		// Respect context cancellation
+		if ctx.Err() != nil {
+			return allReviews, ctx.Err()
+		}
+
+		// Respect the source's request budget
+		if s.limiter != nil {
+			if err := s.limiter.Wait(ctx); err != nil {
+				return allReviews, err
+			}
+		}
+
+		reviews, err := s.client.FetchReviewsContext(ctx, s.config.ProductID, 0, page)
+		if err != nil {
+			return allReviews, fmt.Errorf("error fetching G2 page %d: %w", page, err)
+		}
+
+		// An empty page means we are past the last review
+		if len(reviews) == 0 {
+			break
+		}
+
+		retrievedAt := time.Now()
+		caughtUp := false
+		for _, review := range reviews {
+			converted := convertG2Review(review, s.config.ProductID, retrievedAt)
+			if !since.Admits(converted) {
+				caughtUp = true
+				continue
+			}
+			allReviews = append(allReviews, converted)
+		}
+
+		// Later pages only hold older reviews
+		if caughtUp {
+			break
+		}
+
+		// Respect rate limits
+		if page < maxPages && s.rateLimits.PauseBetweenRequests {
+			select {
+			case <-time.After(s.rateLimits.PauseDuration.Duration()):
+				// Continue after pause
+			case <-ctx.Done():
+				return allReviews, ctx.Err()
+			}
+		}
+	}
+
+	return allReviews, nil
+}
+
+// convertG2Review maps a G2 review of product into the pipeline's review format.
+// The vendor's reply is kept in Replies and the metadata so it is not analyzed
+// as review text.
+func convertG2Review(review Review, product string, retrievedAt time.Time) models.Review {
+	metadata := map[string]interface{}{
+		"product": product,
+	}
+	var replies []models.Reply
+	if review.ReplyContents != "" {
+		reply := cleanContent(review.ReplyContents)
+		metadata["vendorReply"] = reply
+		replies = append(replies, models.Reply{Content: reply, IsVendor: true})
+	}
+
+	converted := models.Review{
+		ID:          fmt.Sprintf("g2-%d", review.ReviewID),
+		Source:      "g2",
+		SourceID:    fmt.Sprintf("%d", review.ReviewID),
+		Content:     cleanContent(review.PostContent),
+		Title:       cleanContent(review.Title),
+		Author:      review.Author,
+		URL:         fmt.Sprintf("https://www.g2.com/products/%s/reviews", product),
+		RetrievedAt: retrievedAt,
+		Tags:        review.Tags,
+		Replies:     replies,
+		Metadata:    metadata,
+	}
+
+	if review.Rating > 0 {
+		rating := float64(review.Rating)
+		converted.Rating = &rating
+	}
+
+	setReviewDate(&converted, review.Timestamp)
+
+	return converted
+}
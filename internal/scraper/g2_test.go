@@ -0,0 +1,161 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertG2Review(t *testing.T) {
+	retrievedAt := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	review := Review{
+		ID:            1,
+		ReviewID:      8675309,
+		Author:        "Network Admin",
+		Platform:      "G2",
+		Title:         "Solid DDI, clunky upgrades",
+		PostContent:   "BloxOne DDI is reliable but upgrades take too long.",
+		ReplyContents: "Thanks for the feedback, we are improving upgrades.",
+		Timestamp:     "2025-03-15",
+		Tags:          []string{"DNS", "DHCP"},
+		Rating:        4,
+	}
+
+	converted := convertG2Review(review, "bloxone-ddi", retrievedAt)
+
+	assert.Equal(t, "g2-8675309", converted.ID)
+	assert.Equal(t, "g2", converted.Source)
+	assert.Equal(t, "8675309", converted.SourceID)
+	assert.Equal(t, review.PostContent, converted.Content)
+	assert.Equal(t, review.Title, converted.Title)
+	assert.Equal(t, review.Author, converted.Author)
+	assert.Equal(t, review.Tags, converted.Tags)
+	if assert.NotNil(t, converted.Rating) {
+		assert.Equal(t, 4.0, *converted.Rating)
+	}
+	assert.Equal(t, time.Date(2025, 3, 15, 0, 0, 0, 0, time.UTC), converted.CreatedAt)
+	assert.Equal(t, retrievedAt, converted.RetrievedAt)
+	assert.Equal(t, "https://www.g2.com/products/bloxone-ddi/reviews", converted.URL)
+	assert.Equal(t, review.ReplyContents, converted.Metadata["vendorReply"])
+	assert.Equal(t, []models.Reply{{Content: review.ReplyContents, IsVendor: true}}, converted.Replies)
+	assert.True(t, converted.HasVendorReply())
+	assert.Equal(t, "bloxone-ddi", converted.Metadata["product"])
+}
+
+func TestConvertG2ReviewWithoutRatingOrReply(t *testing.T) {
+	converted := convertG2Review(Review{ReviewID: 1, Timestamp: "not a date"}, "infoblox-nios", time.Now())
+
+	assert.Nil(t, converted.Rating)
+	assert.True(t, converted.CreatedAt.IsZero())
+	assert.Equal(t, false, converted.Metadata["date_parsed"])
+	assert.NotContains(t, converted.Metadata, "vendorReply")
+	assert.Empty(t, converted.Replies)
+}
+
+func TestG2ScraperScrapesPagesUntilEmpty(t *testing.T) {
+	var pages []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/product/infoblox-nios/reviews", r.URL.Path)
+		assert.Equal(t, "rapid-key", r.Header.Get("X-RapidAPI-Key"))
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		if page == "3" {
+			fmt.Fprint(w, `{"reviews": []}`)
+			return
+		}
+		fmt.Fprintf(w, `{"reviews": [{"id": "%s01", "reviewerName": "a", "content": "NIOS review", "rating": 3, "reviewDate": "2025-01-0%s"}]}`, page, page)
+	}))
+	defer server.Close()
+
+	s, err := NewG2Scraper(config.G2ScraperConfig{Enabled: true, ProductID: "infoblox-nios", APIKey: "rapid-key", MaxPages: 5},
+		config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.client.HTTPClient = server.Client()
+	s.client.Host = hostOf(t, server.URL)
+
+	reviews, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, pages)
+	if assert.Len(t, reviews, 2) {
+		assert.Equal(t, "g2-101", reviews[0].ID)
+		assert.Equal(t, "g2-201", reviews[1].ID)
+		assert.Equal(t, "g2", reviews[1].Source)
+	}
+}
+
+func TestG2ClientLogsTruncatedUnparsableResponse(t *testing.T) {
+	body := "<html>" + strings.Repeat("rate limited ", 200) + "</html>"
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := NewG2Client("rapid-key")
+	client.HTTPClient = server.Client()
+	client.Host = hostOf(t, server.URL)
+	client.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+
+	reviews, err := client.FetchReviews("infoblox-nios", 0, 1)
+
+	assert.ErrorContains(t, err, "error parsing JSON response")
+	assert.Nil(t, reviews)
+	assert.Contains(t, logs.String(), "failed to parse G2 response")
+	assert.Contains(t, logs.String(), "<html>rate limited")
+	assert.NotContains(t, logs.String(), "</html>", "the body is truncated")
+}
+
+func TestManagerRegistersG2Scraper(t *testing.T) {
+	m := newTestManager(t, config.ScrapersConfig{G2: config.G2ScraperConfig{Enabled: true, ProductID: "bloxone-ddi", APIKey: "key"}})
+
+	scrapers := m.GetScrapers()
+	if assert.Len(t, scrapers, 1) {
+		assert.Equal(t, "G2", scrapers[0].Name())
+	}
+}
+
+// hostOf returns the host:port of a test server URL
+func hostOf(t *testing.T, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	assert.NoError(t, err)
+	return u.Host
+}
+
+func TestPackageDefinesNoMain(t *testing.T) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, ".", func(info fs.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go")
+	}, 0)
+	assert.NoError(t, err)
+	assert.Len(t, pkgs, 1)
+
+	for _, pkg := range pkgs {
+		assert.Equal(t, "scraper", pkg.Name)
+		for name, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if ok && fn.Recv == nil {
+					assert.NotEqual(t, "main", fn.Name.Name, "%s defines main", name)
+				}
+			}
+		}
+	}
+}
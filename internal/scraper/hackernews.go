@@ -0,0 +1,280 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/query"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"golang.org/x/time/rate"
+)
+
+// hackerNewsSearchURL is the Algolia Hacker News search API
+const hackerNewsSearchURL = "https://hn.algolia.com/api/v1"
+
+// defaultHackerNewsHitsPerPage is the search page size when none is configured
+const defaultHackerNewsHitsPerPage = 50
+
+// htmlTagPattern matches the markup Algolia leaves in comment and story text
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// HackerNewsHit is a story or comment returned by the Algolia search API
+type HackerNewsHit struct {
+	ObjectID    string   `json:"objectID"`
+	CreatedAt   string   `json:"created_at"`
+	Author      string   `json:"author"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	StoryText   string   `json:"story_text"`
+	CommentText string   `json:"comment_text"`
+	Points      int      `json:"points"`
+	NumComments int      `json:"num_comments"`
+	StoryID     int      `json:"story_id"`
+	StoryTitle  string   `json:"story_title"`
+	StoryURL    string   `json:"story_url"`
+	Tags        []string `json:"_tags"`
+}
+
+// HackerNewsSearchResponse is a page of Algolia search results
+type HackerNewsSearchResponse struct {
+	Hits    []HackerNewsHit `json:"hits"`
+	Page    int             `json:"page"`
+	NbPages int             `json:"nbPages"`
+}
+
+// HackerNewsScraper implements the Scraper interface for Hacker News stories
+// and comments that mention the configured keywords
+type HackerNewsScraper struct {
+	config  config.HackerNewsScraperConfig
+	client  *http.Client
+	limiter *rate.Limiter // Nil when no requests-per-minute budget is configured
+	baseURL string
+	enabled bool
+}
+
+// NewHackerNewsScraper creates a new Hacker News scraper
+func NewHackerNewsScraper(cfg config.HackerNewsScraperConfig, rates config.RateLimitConfig,
+	proxies config.ProxyConfig) (*HackerNewsScraper, error) {
+
+	client, err := newScraperHTTPClient(proxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &HackerNewsScraper{
+		config:  cfg,
+		client:  client,
+		limiter: newRequestLimiter(rates),
+		baseURL: hackerNewsSearchURL,
+		enabled: cfg.Enabled,
+	}, nil
+}
+
+func init() {
+	Register("hackerNews", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !cfg.HackerNews.Enabled {
+			return nil, nil
+		}
+		hackerNews, err := NewHackerNewsScraper(cfg.HackerNews, rates, cfg.ProxySettings)
+		if err != nil {
+			return nil, err
+		}
+		hackerNews.limiter = limiter
+		return []Scraper{hackerNews}, nil
+	})
+}
+
+// Name returns the name of this scraper
+func (s *HackerNewsScraper) Name() string {
+	return "HackerNews"
+}
+
+// IsEnabled returns whether this scraper is enabled
+func (s *HackerNewsScraper) IsEnabled() bool {
+	return s.enabled
+}
+
+// Scrape retrieves recent Hacker News stories and comments mentioning the keywords
+func (s *HackerNewsScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	return s.ScrapeSince(ctx, Watermark{})
+}
+
+// ScrapeSince retrieves Hacker News stories and comments mentioning the
+// keywords that were posted since the watermark. An item matching several
+// keywords is returned once.
+func (s *HackerNewsScraper) ScrapeSince(ctx context.Context, since Watermark) ([]models.Review, error) {
+	var allReviews []models.Review
+	seen := make(map[string]bool)
+
+	maxPages := s.config.MaxPages
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+
+	for _, keyword := range s.config.Keywords {
+		expr, err := query.Parse(keyword)
+		if err != nil {
+			return allReviews, fmt.Errorf("invalid keyword expression '%s': %w", keyword, err)
+		}
+
+		// Algolia has no OR, so each alternative of the expression is searched separately
+		searchQueries, err := expr.Algolia()
+		if err != nil {
+			return allReviews, fmt.Errorf("invalid keyword expression '%s': %w", keyword, err)
+		}
+		for _, searchQuery := range searchQueries {
+			reviews, err := s.searchAllPages(ctx, searchQuery, maxPages, since, seen)
+			allReviews = append(allReviews, reviews...)
+			if err != nil {
+				return allReviews, err
+			}
+		}
+	}
+
+	return allReviews, nil
+}
+
+// searchAllPages collects up to maxPages of results for searchQuery, skipping
+// items already in seen
+func (s *HackerNewsScraper) searchAllPages(ctx context.Context, searchQuery string, maxPages int, since Watermark, seen map[string]bool) ([]models.Review, error) {
+	var reviews []models.Review
+	for page := 0; page < maxPages; page++ {
+		// Respect context cancellation
+		if ctx.Err() != nil {
+			return reviews, ctx.Err()
+		}
+
+		result, err := s.search(ctx, searchQuery, page, since)
+		if err != nil {
+			return reviews, fmt.Errorf("error searching Hacker News for keyword '%s': %w", searchQuery, err)
+		}
+
+		retrievedAt := time.Now()
+		for _, hit := range result.Hits {
+			if seen[hit.ObjectID] {
+				continue
+			}
+			seen[hit.ObjectID] = true
+
+			review := convertHackerNewsHit(hit, searchQuery, retrievedAt)
+			if since.Admits(review) {
+				reviews = append(reviews, review)
+			}
+		}
+
+		// Stop at the last page of results
+		if page+1 >= result.NbPages {
+			break
+		}
+	}
+	return reviews, nil
+}
+
+// search requests one page of the newest stories and comments matching keyword
+func (s *HackerNewsScraper) search(ctx context.Context, keyword string, page int, since Watermark) (*HackerNewsSearchResponse, error) {
+	hitsPerPage := s.config.HitsPerPage
+	if hitsPerPage <= 0 {
+		hitsPerPage = defaultHackerNewsHitsPerPage
+	}
+
+	params := url.Values{}
+	params.Set("query", keyword)
+	params.Set("tags", "(story,comment)") // Parenthesized tags are ORed; a bare comma would AND them
+	params.Set("page", strconv.Itoa(page))
+	params.Set("hitsPerPage", strconv.Itoa(hitsPerPage))
+	if !since.CreatedAt.IsZero() {
+		// Items from the watermark's second are refetched and filtered by ID
+		params.Set("numericFilters", fmt.Sprintf("created_at_i>=%d", since.CreatedAt.Unix()))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/search_by_date?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Respect the shared request budget
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Algolia returned non-OK status: %d", resp.StatusCode)
+	}
+
+	var result HackerNewsSearchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+	return &result, nil
+}
+
+// convertHackerNewsHit maps a Hacker News story or comment found by keyword
+// into the pipeline's review format
+func convertHackerNewsHit(hit HackerNewsHit, keyword string, retrievedAt time.Time) models.Review {
+	itemType := "story"
+	content := hit.StoryText
+	title := hit.Title
+	if hit.CommentText != "" {
+		itemType = "comment"
+		content = hit.CommentText
+		title = hit.StoryTitle
+	}
+	content = cleanContent(htmlTagPattern.ReplaceAllString(content, " "))
+	if content == "" {
+		// Link stories have no text of their own
+		content = cleanContent(title)
+	}
+
+	metadata := map[string]interface{}{
+		"type":         itemType,
+		"keyword":      keyword,
+		"points":       hit.Points,
+		"num_comments": hit.NumComments,
+	}
+	if hit.StoryID != 0 {
+		metadata["story_id"] = hit.StoryID
+	}
+	if storyURL := firstNonEmpty(hit.URL, hit.StoryURL); storyURL != "" {
+		metadata["story_url"] = storyURL
+	}
+
+	review := models.Review{
+		ID:          fmt.Sprintf("hackernews-%s", hit.ObjectID),
+		Source:      "hackernews",
+		SourceID:    hit.ObjectID,
+		Content:     content,
+		Title:       cleanContent(title),
+		Author:      hit.Author,
+		URL:         fmt.Sprintf("https://news.ycombinator.com/item?id=%s", hit.ObjectID),
+		RetrievedAt: retrievedAt,
+		Metadata:    metadata,
+	}
+	setReviewDate(&review, hit.CreatedAt)
+
+	return review
+}
+
+// firstNonEmpty returns the first of values that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
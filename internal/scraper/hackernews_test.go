@@ -0,0 +1,162 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadHackerNewsFixture reads the saved Algolia search response
+func loadHackerNewsFixture(t *testing.T) []byte {
+	data, err := os.ReadFile("testdata/hackernews_search.json")
+	assert.NoError(t, err)
+	return data
+}
+
+// newTestHackerNewsScraper returns a scraper searching server instead of Algolia
+func newTestHackerNewsScraper(t *testing.T, server *httptest.Server, cfg config.HackerNewsScraperConfig) *HackerNewsScraper {
+	cfg.Enabled = true
+	s, err := NewHackerNewsScraper(cfg, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+	return s
+}
+
+func TestConvertHackerNewsHitsFromFixture(t *testing.T) {
+	var response HackerNewsSearchResponse
+	assert.NoError(t, json.Unmarshal(loadHackerNewsFixture(t), &response))
+	assert.Len(t, response.Hits, 2)
+	retrievedAt := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	comment := convertHackerNewsHit(response.Hits[0], "infoblox", retrievedAt)
+	assert.Equal(t, "hackernews-43251234", comment.ID)
+	assert.Equal(t, "hackernews", comment.Source)
+	assert.Equal(t, "43251234", comment.SourceID)
+	assert.Equal(t, "https://news.ycombinator.com/item?id=43251234", comment.URL)
+	assert.Equal(t, "netops_dan", comment.Author)
+	assert.Equal(t, "Ask HN: What do you use for enterprise DNS and DHCP?", comment.Title)
+	assert.Equal(t, "We moved off Infoblox NIOS last year. The grid upgrades kept failing and support took weeks to answer. "+
+		"BloxOne DDI looked better but licensing was confusing & expensive. See https://example.com/notes", comment.Content)
+	assert.Equal(t, time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC), comment.CreatedAt)
+	assert.Equal(t, retrievedAt, comment.RetrievedAt)
+	assert.Nil(t, comment.Rating)
+	assert.Equal(t, "comment", comment.Metadata["type"])
+	assert.Equal(t, "infoblox", comment.Metadata["keyword"])
+	assert.Equal(t, 43251000, comment.Metadata["story_id"])
+	assert.NotContains(t, comment.Metadata, "story_url")
+
+	story := convertHackerNewsHit(response.Hits[1], "infoblox", retrievedAt)
+	assert.Equal(t, "hackernews-43250001", story.ID)
+	assert.Equal(t, "Infoblox discloses DNS flaw in NIOS", story.Title)
+	assert.Equal(t, "Infoblox discloses DNS flaw in NIOS", story.Content, "link stories fall back to their title")
+	assert.Equal(t, "story", story.Metadata["type"])
+	assert.Equal(t, 187, story.Metadata["points"])
+	assert.Equal(t, 42, story.Metadata["num_comments"])
+	assert.Equal(t, "https://example.com/infoblox-nios-advisory", story.Metadata["story_url"])
+}
+
+func TestHackerNewsScraperSearchesEachKeywordOnce(t *testing.T) {
+	fixture := loadHackerNewsFixture(t)
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search_by_date", r.URL.Path)
+		assert.Equal(t, "(story,comment)", r.URL.Query().Get("tags"))
+		assert.Equal(t, "20", r.URL.Query().Get("hitsPerPage"))
+		assert.Empty(t, r.URL.Query().Get("numericFilters"))
+		queries = append(queries, r.URL.Query().Get("query"))
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	s := newTestHackerNewsScraper(t, server, config.HackerNewsScraperConfig{Keywords: []string{"infoblox", "nios"}, HitsPerPage: 20, MaxPages: 3})
+	reviews, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"infoblox", "nios"}, queries, "a single page of results ends paging")
+	assert.Equal(t, []string{"hackernews-43251234", "hackernews-43250001"}, reviewIDs(reviews), "hits found by both keywords are kept once")
+}
+
+func TestHackerNewsScraperSearchesEachAlternativeOfAnExpression(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		queries = append(queries, query)
+		fmt.Fprintf(w, `{"hits": [{"objectID": "%d", "created_at": "2025-03-04T10:30:00Z", "comment_text": "DDI", "author": "a"}], "nbPages": 1}`,
+			len(queries))
+	}))
+	defer server.Close()
+
+	s := newTestHackerNewsScraper(t, server, config.HackerNewsScraperConfig{Keywords: []string{`infoblox AND (dns OR "grid manager")`}})
+	reviews, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"infoblox dns", `infoblox "grid manager"`}, queries)
+	if assert.Len(t, reviews, 2) {
+		assert.Equal(t, "infoblox dns", reviews[0].Metadata["keyword"])
+		assert.Equal(t, `infoblox "grid manager"`, reviews[1].Metadata["keyword"])
+	}
+}
+
+func TestHackerNewsScraperPagesAndFiltersSinceWatermark(t *testing.T) {
+	var pages []string
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+		filters = append(filters, r.URL.Query().Get("numericFilters"))
+		fmt.Fprintf(w, `{"hits": [{"objectID": "%s00", "created_at": "2025-03-0%sT00:00:00Z", "comment_text": "DDI", "author": "a"}], "page": %s, "nbPages": 2}`,
+			page, page, page)
+	}))
+	defer server.Close()
+
+	s := newTestHackerNewsScraper(t, server, config.HackerNewsScraperConfig{Keywords: []string{"ddi"}, MaxPages: 5})
+	since := Watermark{CreatedAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), SourceID: "100"}
+	reviews, err := s.ScrapeSince(context.Background(), since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"0", "1"}, pages)
+	assert.Equal(t, fmt.Sprintf("created_at_i>=%d", since.CreatedAt.Unix()), filters[0])
+	assert.Equal(t, []string{"hackernews-000"}, reviewIDs(reviews), "the watermark item itself is dropped")
+}
+
+func TestHackerNewsScraperReportsHTTPErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	s := newTestHackerNewsScraper(t, server, config.HackerNewsScraperConfig{Keywords: []string{"infoblox"}})
+	_, err := s.Scrape(context.Background())
+
+	assert.EqualError(t, err, "error searching Hacker News for keyword 'infoblox': Algolia returned non-OK status: 429")
+}
+
+func TestNewHackerNewsScraperUsesSharedRateLimit(t *testing.T) {
+	unlimited, err := NewHackerNewsScraper(config.HackerNewsScraperConfig{}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	assert.Nil(t, unlimited.limiter)
+
+	limited, err := NewHackerNewsScraper(config.HackerNewsScraperConfig{}, config.RateLimitConfig{RequestsPerMinute: 30}, config.ProxyConfig{})
+	require.NoError(t, err)
+	if assert.NotNil(t, limited.limiter) {
+		assert.InDelta(t, 0.5, float64(limited.limiter.Limit()), 1e-9)
+	}
+}
+
+func TestManagerRegistersHackerNewsScraper(t *testing.T) {
+	m := newTestManager(t, config.ScrapersConfig{HackerNews: config.HackerNewsScraperConfig{Enabled: true, Keywords: []string{"infoblox"}}})
+
+	scrapers := m.GetScrapers()
+	if assert.Len(t, scrapers, 1) {
+		assert.Equal(t, "HackerNews", scrapers[0].Name())
+	}
+}
@@ -0,0 +1,56 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+)
+
+// HTTPDoer sends HTTP requests for a scraper. *http.Client satisfies it, and
+// tests can supply a stub that answers requests without a network.
+type HTTPDoer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// scraperRequestTimeout bounds each request a scraper sends
+const scraperRequestTimeout = 30 * time.Second
+
+// newScraperHTTPClient returns the client scrapers send requests with, routed
+// through the proxy when proxies is enabled. A proxy URL without a scheme is
+// taken as http. It fails, rather than panicking, on a malformed proxy URL.
+func newScraperHTTPClient(proxies config.ProxyConfig) (*http.Client, error) {
+	client := &http.Client{Timeout: scraperRequestTimeout}
+	if !proxies.Enabled || proxies.URL == "" {
+		return client, nil
+	}
+
+	rawURL := proxies.URL
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		// The parse error repeats the URL, which may hold credentials
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	if proxyURL.Host == "" {
+		return nil, errors.New("invalid proxy URL: missing host")
+	}
+	if proxies.Username != "" && proxies.Password != "" {
+		proxyURL.User = url.UserPassword(proxies.Username, proxies.Password)
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = http.ProxyURL(proxyURL)
+	client.Transport = transport
+	return client, nil
+}
@@ -0,0 +1,55 @@
+package scraper
+
+import (
+	"sync"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"golang.org/x/time/rate"
+)
+
+// newRequestLimiter returns a limiter pacing requests to the requests-per-minute
+// budget of rates, or nil when there is none
+func newRequestLimiter(rates config.RateLimitConfig) *rate.Limiter {
+	if rates.RequestsPerMinute <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(requestLimit(rates), 1)
+}
+
+// requestLimit converts a requests-per-minute budget into a rate
+func requestLimit(rates config.RateLimitConfig) rate.Limit {
+	return rate.Limit(float64(rates.RequestsPerMinute) / 60.0)
+}
+
+// limiterRegistry gives each source its own request limiter, sized to that
+// source's rate limits, so sources are throttled independently of each other
+type limiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// newLimiterRegistry returns an empty registry
+func newLimiterRegistry() *limiterRegistry {
+	return &limiterRegistry{limiters: make(map[string]*rate.Limiter)}
+}
+
+// limiter returns the limiter for source sized to rates, or nil when rates has
+// no requests-per-minute budget. A source keeps its limiter across config
+// reloads, with the new limit applied, so a reload does not reset its budget.
+func (r *limiterRegistry) limiter(source string, rates config.RateLimitConfig) *rate.Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if rates.RequestsPerMinute <= 0 {
+		delete(r.limiters, source)
+		return nil
+	}
+	if limiter, found := r.limiters[source]; found {
+		limiter.SetLimit(requestLimit(rates))
+		return limiter
+	}
+
+	limiter := newRequestLimiter(rates)
+	r.limiters[source] = limiter
+	return limiter
+}
@@ -0,0 +1,73 @@
+package scraper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestLimiterRegistryThrottlesSourcesIndependently(t *testing.T) {
+	limiters := newLimiterRegistry()
+	twitter := limiters.limiter("twitter", config.RateLimitConfig{RequestsPerMinute: 15})
+	g2 := limiters.limiter("g2", config.RateLimitConfig{RequestsPerMinute: 600})
+
+	assert.Equal(t, rate.Limit(0.25), twitter.Limit())
+	assert.Equal(t, rate.Limit(10), g2.Limit())
+
+	// Using up Twitter's budget leaves G2's untouched
+	now := time.Now()
+	assert.True(t, twitter.AllowN(now, 1))
+	assert.False(t, twitter.AllowN(now, 1))
+	assert.True(t, g2.AllowN(now, 1))
+
+	// Each source refills at its own rate
+	later := now.Add(200 * time.Millisecond)
+	assert.True(t, g2.AllowN(later, 1))
+	assert.False(t, twitter.AllowN(later, 1))
+	assert.True(t, twitter.AllowN(now.Add(4*time.Second), 1))
+}
+
+func TestLimiterRegistryKeepsLimitersAcrossReloads(t *testing.T) {
+	limiters := newLimiterRegistry()
+	first := limiters.limiter("twitter", config.RateLimitConfig{RequestsPerMinute: 15})
+
+	second := limiters.limiter("twitter", config.RateLimitConfig{RequestsPerMinute: 30})
+	assert.Same(t, first, second)
+	assert.Equal(t, rate.Limit(0.5), second.Limit())
+
+	assert.Nil(t, limiters.limiter("twitter", config.RateLimitConfig{}))
+	assert.NotSame(t, first, limiters.limiter("twitter", config.RateLimitConfig{RequestsPerMinute: 15}))
+}
+
+func TestInitializeScrapersUsesSourceRateLimits(t *testing.T) {
+	cfg := config.ScrapersConfig{
+		RateLimits: config.RateLimitConfig{RequestsPerMinute: 60},
+		SourceRateLimits: map[string]config.RateLimitConfig{
+			"hackerNews": {RequestsPerMinute: 600},
+		},
+		HackerNews: config.HackerNewsScraperConfig{Enabled: true, Keywords: []string{"infoblox"}},
+		RSS:        config.RSSScraperConfig{Enabled: true, Feeds: []string{"https://example.com/feed"}},
+	}
+
+	scrapers, err := initializeScrapers(cfg, newLimiterRegistry())
+	assert.NoError(t, err)
+	if assert.Len(t, scrapers, 2) {
+		assert.Equal(t, rate.Limit(10), scrapers[0].(*HackerNewsScraper).limiter.Limit())
+		assert.Equal(t, rate.Limit(1), scrapers[1].(*RSSScraper).limiter.Limit(), "sources without an override use the global limits")
+	}
+}
+
+func TestReloadKeepsSourceLimiters(t *testing.T) {
+	cfg := &config.Config{Scrapers: config.ScrapersConfig{
+		SourceRateLimits: map[string]config.RateLimitConfig{"hackerNews": {RequestsPerMinute: 60}},
+		HackerNews:       config.HackerNewsScraperConfig{Enabled: true, Keywords: []string{"infoblox"}},
+	}}
+	m := newTestManager(t, cfg.Scrapers)
+	before := m.GetScrapers()[0].(*HackerNewsScraper).limiter
+
+	assert.NoError(t, m.Reload(cfg))
+	assert.Same(t, before, m.GetScrapers()[0].(*HackerNewsScraper).limiter)
+}
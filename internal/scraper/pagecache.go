@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+const (
+	// defaultPageCacheTTL is how long a cached page is revalidated when TTL is unset
+	defaultPageCacheTTL = time.Hour
+
+	// defaultPageCacheEntries is how many pages are cached when MaxEntries is unset
+	defaultPageCacheEntries = 500
+)
+
+// cachedPage is a fetched page's parsed reviews with the validators needed to
+// ask whether it changed
+type cachedPage struct {
+	url          string
+	etag         string
+	lastModified string
+	reviews      []models.Review
+	hasMore      bool
+	storedAt     time.Time
+}
+
+// setValidators makes req conditional on the page having changed
+func (p *cachedPage) setValidators(req *http.Request) {
+	if p.etag != "" {
+		req.Header.Set("If-None-Match", p.etag)
+	}
+	if p.lastModified != "" {
+		req.Header.Set("If-Modified-Since", p.lastModified)
+	}
+}
+
+// pageCache is an LRU cache of parsed pages keyed by URL, so scrapers can send
+// conditional requests and skip parsing pages the site reports unchanged.
+// Pages older than the TTL are fetched in full again. A nil *pageCache caches
+// nothing.
+type pageCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	capacity int
+	order    *list.List // Front is the most recently used
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+// newPageCache returns the configured page cache, or nil when it is disabled
+func newPageCache(cfg config.PageCacheConfig) *pageCache {
+	if !cfg.Enabled {
+		return nil
+	}
+
+	ttl := cfg.TTL.Duration()
+	if ttl <= 0 {
+		ttl = defaultPageCacheTTL
+	}
+	capacity := cfg.MaxEntries
+	if capacity <= 0 {
+		capacity = defaultPageCacheEntries
+	}
+
+	return &pageCache{
+		ttl:      ttl,
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+// lookup returns a copy of the cached page for url when it is still within its TTL
+func (c *pageCache) lookup(url string) (*cachedPage, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, found := c.entries[url]
+	if !found {
+		return nil, false
+	}
+	page := elem.Value.(*cachedPage)
+	if c.now().Sub(page.storedAt) >= c.ttl {
+		c.order.Remove(elem)
+		delete(c.entries, url)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	copied := *page
+	copied.reviews = cloneReviews(page.reviews)
+	return &copied, true
+}
+
+// store caches the parsed reviews of a page fetched from url. Pages without an
+// ETag or Last-Modified header cannot be revalidated and are not cached.
+func (c *pageCache) store(url string, header http.Header, reviews []models.Review, hasMore bool) {
+	if c == nil {
+		return
+	}
+	page := &cachedPage{
+		url:          url,
+		etag:         header.Get("ETag"),
+		lastModified: header.Get("Last-Modified"),
+		reviews:      cloneReviews(reviews),
+		hasMore:      hasMore,
+	}
+	if page.etag == "" && page.lastModified == "" {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	page.storedAt = c.now()
+	if elem, found := c.entries[url]; found {
+		elem.Value = page
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[url] = c.order.PushFront(page)
+	if c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedPage).url)
+	}
+}
+
+// cloneReviews copies reviews deeply enough that the pipeline's changes to
+// metadata, tags and replies do not reach the cached copies
+func cloneReviews(reviews []models.Review) []models.Review {
+	cloned := make([]models.Review, len(reviews))
+	for i, review := range reviews {
+		if review.Metadata != nil {
+			metadata := make(map[string]interface{}, len(review.Metadata))
+			for key, value := range review.Metadata {
+				metadata[key] = value
+			}
+			review.Metadata = metadata
+		}
+		review.Tags = append([]string(nil), review.Tags...)
+		review.Replies = append([]models.Reply(nil), review.Replies...)
+		cloned[i] = review
+	}
+	return cloned
+}
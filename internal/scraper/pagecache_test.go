@@ -0,0 +1,97 @@
+package scraper
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func etagHeader(etag string) http.Header {
+	header := http.Header{}
+	header.Set("ETag", etag)
+	return header
+}
+
+func TestNewPageCacheDisabled(t *testing.T) {
+	cache := newPageCache(config.PageCacheConfig{})
+	assert.Nil(t, cache)
+
+	// A nil cache stores and finds nothing
+	cache.store("https://example.com/1", etagHeader(`"v1"`), []models.Review{{ID: "a"}}, false)
+	_, found := cache.lookup("https://example.com/1")
+	assert.False(t, found)
+}
+
+func TestPageCacheExpiresAfterTTL(t *testing.T) {
+	cache := newPageCache(config.PageCacheConfig{Enabled: true, TTL: config.Duration(time.Minute)})
+	now := time.Date(2025, 4, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.store("https://example.com/1", etagHeader(`"v1"`), []models.Review{{ID: "a"}}, true)
+
+	now = now.Add(59 * time.Second)
+	page, found := cache.lookup("https://example.com/1")
+	if assert.True(t, found) {
+		assert.Equal(t, `"v1"`, page.etag)
+		assert.True(t, page.hasMore)
+		assert.Equal(t, []string{"a"}, reviewIDs(page.reviews))
+	}
+
+	now = now.Add(time.Second)
+	_, found = cache.lookup("https://example.com/1")
+	assert.False(t, found)
+}
+
+func TestPageCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newPageCache(config.PageCacheConfig{Enabled: true, MaxEntries: 2})
+
+	cache.store("https://example.com/1", etagHeader(`"1"`), nil, false)
+	cache.store("https://example.com/2", etagHeader(`"2"`), nil, false)
+	_, found := cache.lookup("https://example.com/1")
+	assert.True(t, found)
+	cache.store("https://example.com/3", etagHeader(`"3"`), nil, false)
+
+	_, found = cache.lookup("https://example.com/2")
+	assert.False(t, found, "least recently used page should be evicted")
+	_, found = cache.lookup("https://example.com/1")
+	assert.True(t, found)
+	_, found = cache.lookup("https://example.com/3")
+	assert.True(t, found)
+}
+
+func TestPageCacheSkipsPagesWithoutValidators(t *testing.T) {
+	cache := newPageCache(config.PageCacheConfig{Enabled: true})
+
+	cache.store("https://example.com/1", http.Header{}, []models.Review{{ID: "a"}}, false)
+	_, found := cache.lookup("https://example.com/1")
+	assert.False(t, found)
+
+	header := http.Header{}
+	header.Set("Last-Modified", "Tue, 01 Apr 2025 12:00:00 GMT")
+	cache.store("https://example.com/1", header, []models.Review{{ID: "a"}}, false)
+	page, found := cache.lookup("https://example.com/1")
+	if assert.True(t, found) {
+		req, _ := http.NewRequest(http.MethodGet, "https://example.com/1", nil)
+		page.setValidators(req)
+		assert.Equal(t, "Tue, 01 Apr 2025 12:00:00 GMT", req.Header.Get("If-Modified-Since"))
+		assert.Empty(t, req.Header.Get("If-None-Match"))
+	}
+}
+
+func TestPageCacheReturnsIsolatedCopies(t *testing.T) {
+	cache := newPageCache(config.PageCacheConfig{Enabled: true})
+	reviews := []models.Review{{ID: "a", Tags: []string{"dns"}, Metadata: map[string]interface{}{"page": 1}}}
+	cache.store("https://example.com/1", etagHeader(`"v1"`), reviews, false)
+
+	reviews[0].Tags[0] = "changed"
+	page, _ := cache.lookup("https://example.com/1")
+	page.reviews[0].Metadata["page"] = 2
+
+	again, _ := cache.lookup("https://example.com/1")
+	assert.Equal(t, []string{"dns"}, again.reviews[0].Tags)
+	assert.Equal(t, 1, again.reviews[0].Metadata["page"])
+}
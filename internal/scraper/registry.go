@@ -0,0 +1,60 @@
+package scraper
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"golang.org/x/time/rate"
+)
+
+// Factory builds a source's scrapers from the scraper configuration, returning
+// none when the source is disabled and an error when the configuration cannot
+// be used. rates are the source's rate limits and limiter paces its requests;
+// limiter is kept across reloads and is nil when the source has no
+// requests-per-minute budget.
+type Factory func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = make(map[string]Factory)
+)
+
+// Register makes a scraper source available to the manager under name, which
+// is also the key of its per-source rate limits. Sources register from an
+// init function. Register panics if factory is nil or name is already taken.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("scraper: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic(fmt.Sprintf("scraper: Register called twice for source %q", name))
+	}
+	factories[name] = factory
+}
+
+// Sources returns the names of the registered scraper sources, sorted
+func Sources() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// factory returns the factory registered under name
+func factory(name string) (Factory, bool) {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	f, ok := factories[name]
+	return f, ok
+}
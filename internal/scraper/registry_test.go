@@ -0,0 +1,62 @@
+package scraper
+
+import (
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+// registerTestSource registers factory under name for the rest of the test
+func registerTestSource(t *testing.T, name string, factory Factory) {
+	Register(name, factory)
+	t.Cleanup(func() {
+		factoriesMu.Lock()
+		delete(factories, name)
+		factoriesMu.Unlock()
+	})
+}
+
+func TestManagerBuildsRegisteredSources(t *testing.T) {
+	enabled := false
+	var gotRates config.RateLimitConfig
+	var gotLimiter *rate.Limiter
+	registerTestSource(t, "fakeSource", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !enabled {
+			return nil, nil
+		}
+		gotRates, gotLimiter = rates, limiter
+		return []Scraper{&fakeScraper{}}, nil
+	})
+
+	cfg := config.ScrapersConfig{
+		SourceRateLimits: map[string]config.RateLimitConfig{"fakeSource": {RequestsPerMinute: 120}},
+	}
+	assert.Empty(t, newTestManager(t, cfg).GetScrapers(), "disabled sources build no scrapers")
+
+	enabled = true
+	scrapers := newTestManager(t, cfg).GetScrapers()
+	if assert.Len(t, scrapers, 1) {
+		assert.Equal(t, "Fake", scrapers[0].Name())
+	}
+	assert.Equal(t, 120, gotRates.RequestsPerMinute)
+	if assert.NotNil(t, gotLimiter) {
+		assert.Equal(t, rate.Limit(2), gotLimiter.Limit())
+	}
+}
+
+func TestRegisterRejectsDuplicatesAndNil(t *testing.T) {
+	assert.Contains(t, Sources(), "twitter")
+	assert.Panics(t, func() {
+		Register("twitter", func(config.ScrapersConfig, config.RateLimitConfig, *rate.Limiter) ([]Scraper, error) { return nil, nil })
+	})
+	assert.Panics(t, func() { Register("nilSource", nil) })
+	assert.NotContains(t, Sources(), "nilSource")
+}
+
+func TestBuiltInSourcesAreRegistered(t *testing.T) {
+	for _, source := range config.RateLimitedSources {
+		assert.Contains(t, Sources(), source)
+	}
+}
@@ -0,0 +1,181 @@
+package scraper
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/mmcdole/gofeed"
+	"golang.org/x/time/rate"
+)
+
+// RSSScraper implements the Scraper interface for RSS and Atom feeds, keeping
+// the items that mention one of the configured keywords
+type RSSScraper struct {
+	config  config.RSSScraperConfig
+	client  *http.Client
+	limiter *rate.Limiter // Nil when no requests-per-minute budget is configured
+	enabled bool
+}
+
+// NewRSSScraper creates a new RSS and Atom feed scraper
+func NewRSSScraper(cfg config.RSSScraperConfig, rates config.RateLimitConfig, proxies config.ProxyConfig) (*RSSScraper, error) {
+	client, err := newScraperHTTPClient(proxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RSSScraper{
+		config:  cfg,
+		client:  client,
+		limiter: newRequestLimiter(rates),
+		enabled: cfg.Enabled,
+	}, nil
+}
+
+func init() {
+	Register("rss", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !cfg.RSS.Enabled {
+			return nil, nil
+		}
+		rss, err := NewRSSScraper(cfg.RSS, rates, cfg.ProxySettings)
+		if err != nil {
+			return nil, err
+		}
+		rss.limiter = limiter
+		return []Scraper{rss}, nil
+	})
+}
+
+// Name returns the name of this scraper
+func (s *RSSScraper) Name() string {
+	return "RSS"
+}
+
+// IsEnabled returns whether this scraper is enabled
+func (s *RSSScraper) IsEnabled() bool {
+	return s.enabled
+}
+
+// Scrape fetches each configured feed and returns its items that mention a
+// keyword. An item listed by several feeds is returned once.
+func (s *RSSScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	var allReviews []models.Review
+	seen := make(map[string]bool)
+
+	for _, feedURL := range s.config.Feeds {
+		// Respect context cancellation
+		if ctx.Err() != nil {
+			return allReviews, ctx.Err()
+		}
+
+		feed, err := s.fetch(ctx, feedURL)
+		if err != nil {
+			return allReviews, fmt.Errorf("error fetching feed '%s': %w", feedURL, err)
+		}
+
+		retrievedAt := time.Now()
+		for _, item := range feed.Items {
+			keyword, ok := matchFeedItem(item, s.config.Keywords)
+			if !ok {
+				continue
+			}
+
+			review := convertFeedItem(item, feed, feedURL, keyword, retrievedAt)
+			if seen[review.SourceID] {
+				continue
+			}
+			seen[review.SourceID] = true
+			allReviews = append(allReviews, review)
+		}
+	}
+
+	return allReviews, nil
+}
+
+// fetch downloads and parses one RSS or Atom feed
+func (s *RSSScraper) fetch(ctx context.Context, feedURL string) (*gofeed.Feed, error) {
+	// Respect the shared request budget
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	parser := gofeed.NewParser()
+	parser.Client = s.client
+	return parser.ParseURLWithContext(feedURL, ctx)
+}
+
+// feedItemContent returns the text of item with its markup removed, preferring
+// the full content over the summary
+func feedItemContent(item *gofeed.Item) string {
+	content := cleanContent(htmlTagPattern.ReplaceAllString(item.Content, " "))
+	if content == "" {
+		content = cleanContent(htmlTagPattern.ReplaceAllString(item.Description, " "))
+	}
+	return content
+}
+
+// matchFeedItem returns the first of keywords mentioned in the title or text
+// of item, ignoring case
+func matchFeedItem(item *gofeed.Item, keywords []string) (string, bool) {
+	text := strings.ToLower(cleanContent(item.Title) + " " + feedItemContent(item))
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+			return keyword, true
+		}
+	}
+	return "", false
+}
+
+// convertFeedItem maps a feed item matching keyword into the pipeline's review format
+func convertFeedItem(item *gofeed.Item, feed *gofeed.Feed, feedURL, keyword string, retrievedAt time.Time) models.Review {
+	// Feeds are not required to give items a GUID, so fall back to the link
+	sourceID := firstNonEmpty(item.GUID, item.Link)
+	sum := sha256.Sum256([]byte(sourceID))
+
+	metadata := map[string]interface{}{
+		"feed_url":   feedURL,
+		"feed_title": feed.Title,
+		"keyword":    keyword,
+	}
+	if len(item.Categories) > 0 {
+		metadata["categories"] = item.Categories
+	}
+
+	var author string
+	if item.Author != nil {
+		author = firstNonEmpty(item.Author.Name, item.Author.Email)
+	}
+
+	review := models.Review{
+		ID:          "rss-" + hex.EncodeToString(sum[:8]),
+		Source:      "rss",
+		SourceID:    sourceID,
+		Content:     feedItemContent(item),
+		Title:       cleanContent(item.Title),
+		Author:      author,
+		URL:         item.Link,
+		RetrievedAt: retrievedAt,
+		Metadata:    metadata,
+	}
+
+	switch {
+	case item.PublishedParsed != nil:
+		review.CreatedAt = item.PublishedParsed.UTC()
+	case item.UpdatedParsed != nil:
+		// Atom entries need only an updated date
+		review.CreatedAt = item.UpdatedParsed.UTC()
+	default:
+		setReviewDate(&review, firstNonEmpty(item.Published, item.Updated))
+	}
+
+	return review
+}
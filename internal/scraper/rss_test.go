@@ -0,0 +1,125 @@
+package scraper
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFeedServer serves the RSS and Atom fixtures at /rss and /atom
+func newFeedServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/rss", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/feed.rss.xml")
+	})
+	mux.HandleFunc("/atom", func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/feed.atom.xml")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// reviewsByTitle indexes reviews by title
+func reviewsByTitle(reviews []models.Review) map[string]models.Review {
+	byTitle := make(map[string]models.Review)
+	for _, review := range reviews {
+		byTitle[review.Title] = review
+	}
+	return byTitle
+}
+
+func TestRSSScraperParsesRSSFeed(t *testing.T) {
+	server := newFeedServer(t)
+	s, err := NewRSSScraper(config.RSSScraperConfig{Enabled: true, Feeds: []string{server.URL + "/rss"}, Keywords: []string{"Infoblox", "nios"}},
+		config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+
+	reviews, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	byTitle := reviewsByTitle(reviews)
+	assert.Len(t, reviews, 2)
+	assert.NotContains(t, byTitle, "Wi-Fi 7 access points reviewed", "items mentioning no keyword are skipped")
+
+	review := byTitle["Infoblox adds DNS threat feeds to BloxOne"]
+	assert.Equal(t, "rss", review.Source)
+	assert.Equal(t, "nw-1001", review.SourceID)
+	assert.Regexp(t, `^rss-[0-9a-f]{16}$`, review.ID)
+	assert.Equal(t, "https://news.example.com/2025/03/infoblox-threat-feeds", review.URL)
+	assert.Equal(t, "Jane Writer", review.Author)
+	assert.Equal(t, "The new feeds block malicious domains & phishing sites.", review.Content, "full content is preferred over the summary")
+	assert.Equal(t, time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC), review.CreatedAt)
+	assert.Equal(t, "Infoblox", review.Metadata["keyword"])
+	assert.Equal(t, "Network Weekly", review.Metadata["feed_title"])
+	assert.Equal(t, server.URL+"/rss", review.Metadata["feed_url"])
+	assert.Equal(t, []string{"Security"}, review.Metadata["categories"])
+
+	// Items without a GUID are identified by their link and matched on their text
+	ddi := byTitle["Choosing a DDI platform"]
+	assert.Equal(t, "https://news.example.com/2025/03/choosing-ddi", ddi.SourceID)
+	assert.Equal(t, "We compared NIOS with two open source stacks.", ddi.Content)
+	assert.Equal(t, "nios", ddi.Metadata["keyword"])
+}
+
+func TestRSSScraperParsesAtomFeed(t *testing.T) {
+	server := newFeedServer(t)
+	s, err := NewRSSScraper(config.RSSScraperConfig{Enabled: true, Feeds: []string{server.URL + "/atom"}, Keywords: []string{"bloxone"}},
+		config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+
+	reviews, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	if assert.Len(t, reviews, 1) {
+		review := reviews[0]
+		assert.Equal(t, "Six months with BloxOne DDI", review.Title)
+		assert.Equal(t, "urn:uuid:1225c695-cfb8-4ebb-aaaa-80da344efa6a", review.SourceID)
+		assert.Equal(t, "https://blog.example.org/posts/bloxone-six-months", review.URL)
+		assert.Equal(t, "Sam Operator", review.Author)
+		assert.Equal(t, "The cloud portal is fast but the API docs are thin.", review.Content)
+		assert.Equal(t, time.Date(2025, 3, 5, 9, 0, 0, 0, time.UTC), review.CreatedAt, "entries without a published date use their updated date")
+		assert.Equal(t, "Ops Diaries", review.Metadata["feed_title"])
+	}
+}
+
+func TestRSSScraperReturnsItemsListedByTwoFeedsOnce(t *testing.T) {
+	server := newFeedServer(t)
+	s, err := NewRSSScraper(config.RSSScraperConfig{Enabled: true, Feeds: []string{server.URL + "/rss", server.URL + "/rss"}, Keywords: []string{"infoblox"}},
+		config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+
+	reviews, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	assert.Len(t, reviews, 1)
+}
+
+func TestRSSScraperReportsFeedErrors(t *testing.T) {
+	server := newFeedServer(t)
+	s, err := NewRSSScraper(config.RSSScraperConfig{Enabled: true, Feeds: []string{server.URL + "/missing"}, Keywords: []string{"infoblox"}},
+		config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+
+	_, err = s.Scrape(context.Background())
+
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "error fetching feed '"+server.URL+"/missing'")
+	}
+}
+
+func TestManagerRegistersRSSScraper(t *testing.T) {
+	m := newTestManager(t, config.ScrapersConfig{RSS: config.RSSScraperConfig{Enabled: true, Feeds: []string{"https://example.com/feed"}, Keywords: []string{"infoblox"}}})
+
+	scrapers := m.GetScrapers()
+	if assert.Len(t, scrapers, 1) {
+		assert.Equal(t, "RSS", scrapers[0].Name())
+	}
+}
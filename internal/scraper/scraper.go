@@ -2,12 +2,19 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/logging"
+	"github.com/Infoblox-CTO/review-scraper/internal/metrics"
+	"github.com/Infoblox-CTO/review-scraper/internal/tracing"
 	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
 )
 
 // Scraper is the interface that all platform-specific scrapers must implement
@@ -24,67 +31,129 @@ type Scraper interface {
 
 // Manager manages all scraper instances and coordinates scraping operations
 type Manager struct {
+	mu       sync.RWMutex // Guards scrapers and config, which are replaced on reload
 	scrapers []Scraper
 	config   config.ScrapersConfig
+	seen     *Watermarks      // Newest review returned from each source, so runs only return new reviews
+	limiters *limiterRegistry // Each source's request limiter, kept across reloads
+	metrics  *metrics.Metrics
+	logger   *slog.Logger
+
+	runMu sync.Mutex // Guards run
+	run   RunStatus  // Current or most recent scraping run
 }
 
 // NewManager creates a new scraper manager with the provided configuration
-func NewManager(cfg config.ScrapersConfig) *Manager {
+func NewManager(cfg config.ScrapersConfig) (*Manager, error) {
 	m := &Manager{
-		config: cfg,
+		config:   cfg,
+		seen:     NewWatermarks(),
+		limiters: newLimiterRegistry(),
+		logger:   logging.Component(nil, "scraper"),
+		run:      RunStatus{State: RunStateIdle},
 	}
 
 	// Initialize all scrapers
-	m.initializeScrapers()
+	scrapers, err := initializeScrapers(cfg, m.limiters)
+	if err != nil {
+		return nil, err
+	}
+	m.scrapers = scrapers
 
-	return m
+	return m, nil
 }
 
-// initializeScrapers sets up all available scrapers based on configuration
-func (m *Manager) initializeScrapers() {
-	// Initialize Twitter scraper if enabled
-	if m.config.Twitter.Enabled {
-		m.scrapers = append(m.scrapers, NewTwitterScraper(m.config.Twitter, m.config.RateLimits, m.config.ProxySettings))
+// initializeScrapers builds the enabled scrapers of every registered source,
+// in source name order. Each source gets its own rate limits and a request
+// limiter from limiters.
+func initializeScrapers(cfg config.ScrapersConfig, limiters *limiterRegistry) ([]Scraper, error) {
+	var scrapers []Scraper
+	for _, name := range Sources() {
+		build, _ := factory(name)
+		rates := cfg.RateLimitsFor(name)
+		built, err := build(cfg, rates, limiters.limiter(name, rates))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s scraper: %w", name, err)
+		}
+		scrapers = append(scrapers, built...)
 	}
+	return scrapers, nil
+}
 
-	// Initialize Reddit scraper if enabled
-	if m.config.Reddit.Enabled {
-		m.scrapers = append(m.scrapers, NewRedditScraper(m.config.Reddit, m.config.RateLimits, m.config.ProxySettings))
+// Reload rebuilds the scrapers from a reloaded configuration. Runs already in
+// progress finish with the scrapers they started with. When a scraper cannot
+// be built the current scrapers are kept.
+func (m *Manager) Reload(cfg *config.Config) error {
+	scrapers, err := initializeScrapers(cfg.Scrapers, m.limiters)
+	if err != nil {
+		return err
 	}
 
-	// Initialize App Store scraper if enabled
-	if m.config.AppStore.Enabled {
-		m.scrapers = append(m.scrapers, NewAppStoreScraper(m.config.AppStore, m.config.RateLimits, m.config.ProxySettings))
-	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// Initialize Google Play scraper if enabled
-	if m.config.GooglePlay.Enabled {
-		m.scrapers = append(m.scrapers, NewGooglePlayScraper(m.config.GooglePlay, m.config.RateLimits, m.config.ProxySettings))
-	}
+	m.config = cfg.Scrapers
+	m.scrapers = scrapers
+	return nil
+}
 
-	// Initialize custom site scrapers
-	for _, customCfg := range m.config.CustomSites {
-		if customCfg.Enabled {
-			m.scrapers = append(m.scrapers, NewCustomSiteScraper(customCfg, m.config.RateLimits, m.config.ProxySettings))
-		}
-	}
+// SetMetrics enables instrumentation of scraper runs
+func (m *Manager) SetMetrics(recorder *metrics.Metrics) {
+	m.metrics = recorder
+}
+
+// SetLogger sets the structured logger used for scraper runs
+func (m *Manager) SetLogger(logger *slog.Logger) {
+	m.logger = logging.Component(logger, "scraper")
 }
 
-// ScrapeAll runs all enabled scrapers in parallel and aggregates their results
+// snapshot returns the current scrapers
+func (m *Manager) snapshot() []Scraper {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.scrapers
+}
+
+// defaultScrapeTimeout bounds a scraper's run when no scrapeTimeout is configured
+const defaultScrapeTimeout = 10 * time.Minute
+
+// ScrapeAll runs all enabled scrapers in parallel and aggregates their results.
+// Only reviews newer than those returned by earlier runs are returned; a
+// source's watermark advances only when its scraper succeeds. It returns
+// ErrRunInProgress if another run has not finished.
 func (m *Manager) ScrapeAll(ctx context.Context) ([]models.Review, error) {
+	run, err := m.StartRun()
+	if err != nil {
+		return nil, err
+	}
+	batch, err := run(ctx)
+	if err != nil {
+		return nil, err
+	}
+	batch.Commit(nil)
+	return batch.Reviews, nil
+}
+
+// scrapeAll runs all enabled scrapers in parallel and aggregates their
+// results into a batch, leaving the watermarks to advance when it is committed
+func (m *Manager) scrapeAll(ctx context.Context) (_ *Batch, err error) {
+	ctx, span := tracing.Start(ctx, "scraper.ScrapeAll")
+	defer func() { tracing.End(span, err) }()
+
 	var (
-		wg      sync.WaitGroup
-		mu      sync.Mutex
-		results []models.Review
-		errs    []error
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		batch = newBatch(m.seen)
+		errs  []error
 	)
 
-	// Create a context with timeout to prevent hanging scrapers
-	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
-	defer cancel()
+	// Each scraper gets its own deadline so a hung one cannot use up the others' time
+	timeout := m.scrapeTimeout()
+	authors := m.authorFilter()
+	ages := m.ageFilter(time.Now())
 
 	// Start each scraper in its own goroutine
-	for _, s := range m.scrapers {
+	for _, s := range m.snapshot() {
 		if !s.IsEnabled() {
 			continue
 		}
@@ -93,7 +162,19 @@ func (m *Manager) ScrapeAll(ctx context.Context) ([]models.Review, error) {
 		go func(scraper Scraper) {
 			defer wg.Done()
 
-			reviews, err := scraper.Scrape(ctx)
+			ctx, span := tracing.Start(ctx, "scraper.Scrape", tracing.KeyScraper.String(scraper.Name()))
+			start := time.Now()
+			reviews, err := m.scrapeWithTimeout(ctx, scraper, timeout)
+			duration := time.Since(start)
+			span.SetAttributes(attribute.Int("scraper.reviews", len(reviews)))
+			tracing.End(span, err)
+			m.metrics.ObserveScrape(scraper.Name(), len(reviews), err, duration)
+			if err != nil {
+				m.logger.Warn("scraper failed", logging.KeySource, scraper.Name(), "error", err)
+			} else {
+				m.logger.Debug("scraper finished", logging.KeySource, scraper.Name(),
+					"reviews", len(reviews), "duration_ms", duration.Milliseconds())
+			}
 
 			// Lock while updating shared data
 			mu.Lock()
@@ -104,7 +185,17 @@ func (m *Manager) ScrapeAll(ctx context.Context) ([]models.Review, error) {
 				return
 			}
 
-			results = append(results, reviews...)
+			// Filtered reviews still advance the watermark, so they are not fetched again
+			batch.scraped[scraper.Name()] = reviews
+			kept, dropped := authors.apply(reviews)
+			if dropped > 0 {
+				m.logger.Debug("dropped reviews by filtered authors", logging.KeySource, scraper.Name(), "dropped", dropped)
+			}
+			kept, dropped = ages.apply(kept)
+			if dropped > 0 {
+				m.logger.Debug("dropped reviews older than the maximum age", logging.KeySource, scraper.Name(), "dropped", dropped)
+			}
+			batch.Reviews = append(batch.Reviews, kept...)
 		}(s)
 	}
 
@@ -112,7 +203,7 @@ func (m *Manager) ScrapeAll(ctx context.Context) ([]models.Review, error) {
 	wg.Wait()
 
 	// If no reviews were found but errors occurred, return an error
-	if len(results) == 0 && len(errs) > 0 {
+	if len(batch.Reviews) == 0 && len(errs) > 0 {
 		// Combine all error messages
 		var combinedErr error
 		for _, err := range errs {
@@ -125,13 +216,89 @@ func (m *Manager) ScrapeAll(ctx context.Context) ([]models.Review, error) {
 		return nil, combinedErr
 	}
 
-	return results, nil
+	return batch, nil
+}
+
+// authorFilter returns the filter for the configured author allow and deny lists
+func (m *Manager) authorFilter() authorFilter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return newAuthorFilter(m.config.Authors)
+}
+
+// ageFilter returns the filter for reviews older than the configured maximum age at now
+func (m *Manager) ageFilter(now time.Time) ageFilter {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return newAgeFilter(m.config, now)
+}
+
+// scrapeTimeout returns the configured deadline for each scraper's run
+func (m *Manager) scrapeTimeout() time.Duration {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if timeout := m.config.RateLimits.ScrapeTimeout.Duration(); timeout > 0 {
+		return timeout
+	}
+	return defaultScrapeTimeout
+}
+
+// scrapeWithTimeout runs scraper with its own deadline and reports a timeout
+// as its error. A scraper that ignores its context is abandoned at the
+// deadline rather than holding up the results of the others.
+func (m *Manager) scrapeWithTimeout(ctx context.Context, scraper Scraper, timeout time.Duration) ([]models.Review, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	type result struct {
+		reviews []models.Review
+		err     error
+	}
+	done := make(chan result, 1)
+	go func() {
+		reviews, err := m.scrapeNew(ctx, scraper)
+		done <- result{reviews, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return res.reviews, fmt.Errorf("timed out after %s: %w", timeout, res.err)
+		}
+		return res.reviews, res.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("timed out after %s: %w", timeout, ctx.Err())
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// scrapeNew runs scraper and returns only the reviews newer than its source's
+// watermark, letting incremental scrapers stop paging once they reach it
+func (m *Manager) scrapeNew(ctx context.Context, scraper Scraper) ([]models.Review, error) {
+	since := m.seen.Get(scraper.Name())
+
+	var (
+		reviews []models.Review
+		err     error
+	)
+	if incremental, ok := scraper.(IncrementalScraper); ok {
+		reviews, err = incremental.ScrapeSince(ctx, since)
+	} else {
+		reviews, err = scraper.Scrape(ctx)
+	}
+	if err != nil {
+		return reviews, err
+	}
+	return since.Filter(reviews), nil
 }
 
 // GetScrapers returns all enabled scrapers
 func (m *Manager) GetScrapers() []Scraper {
 	var enabledScrapers []Scraper
-	for _, s := range m.scrapers {
+	for _, s := range m.snapshot() {
 		if s.IsEnabled() {
 			enabledScrapers = append(enabledScrapers, s)
 		}
@@ -143,8 +310,9 @@ func (m *Manager) GetScrapers() []Scraper {
 func (m *Manager) GetStats() []models.ScraperStats {
 	// In a real implementation, this would track and return actual stats
 	// This is a placeholder implementation
-	stats := make([]models.ScraperStats, 0, len(m.scrapers))
-	for _, s := range m.scrapers {
+	scrapers := m.snapshot()
+	stats := make([]models.ScraperStats, 0, len(scrapers))
+	for _, s := range scrapers {
 		if s.IsEnabled() {
 			stats = append(stats, models.ScraperStats{
 				Source:         s.Name(),
@@ -157,6 +325,30 @@ func (m *Manager) GetStats() []models.ScraperStats {
 	return stats
 }
 
+func init() {
+	Register("reddit", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, _ *rate.Limiter) ([]Scraper, error) {
+		if !cfg.Reddit.Enabled {
+			return nil, nil
+		}
+		return []Scraper{NewRedditScraper(cfg.Reddit, rates, cfg.ProxySettings)}, nil
+	})
+	Register("googlePlay", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, _ *rate.Limiter) ([]Scraper, error) {
+		if !cfg.GooglePlay.Enabled {
+			return nil, nil
+		}
+		return []Scraper{NewGooglePlayScraper(cfg.GooglePlay, rates, cfg.ProxySettings)}, nil
+	})
+	Register("customSites", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, _ *rate.Limiter) ([]Scraper, error) {
+		var scrapers []Scraper
+		for _, customCfg := range cfg.CustomSites {
+			if customCfg.Enabled {
+				scrapers = append(scrapers, NewCustomSiteScraper(customCfg, rates, cfg.ProxySettings))
+			}
+		}
+		return scrapers, nil
+	})
+}
+
 // NewRedditScraper creates a new Reddit scraper
 func NewRedditScraper(cfg config.RedditScraperConfig, rates config.RateLimitConfig, proxies config.ProxyConfig) Scraper {
 	// TODO: Implement actual Reddit scraper
@@ -167,16 +359,6 @@ func NewRedditScraper(cfg config.RedditScraperConfig, rates config.RateLimitConf
 	}
 }
 
-// NewAppStoreScraper creates a new App Store scraper
-func NewAppStoreScraper(cfg config.AppStoreScraperConfig, rates config.RateLimitConfig, proxies config.ProxyConfig) Scraper {
-	// TODO: Implement actual App Store scraper
-	// This is a stub implementation to make the code compile
-	return &stubScraper{
-		name:    "AppStore",
-		enabled: cfg.Enabled,
-	}
-}
-
 // NewGooglePlayScraper creates a new Google Play Store scraper
 func NewGooglePlayScraper(cfg config.GooglePlayScraperConfig, rates config.RateLimitConfig, proxies config.ProxyConfig) Scraper {
 	// TODO: Implement actual Google Play scraper
@@ -0,0 +1,92 @@
+package scraper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowScraper blocks until its context is done, or forever if it ignores it
+type slowScraper struct {
+	name       string
+	ignoresCtx bool
+}
+
+func (s *slowScraper) Name() string    { return s.name }
+func (s *slowScraper) IsEnabled() bool { return true }
+
+func (s *slowScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	if s.ignoresCtx {
+		select {}
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// newTestManager returns a manager for cfg, failing the test if it cannot be built
+func newTestManager(t *testing.T, cfg config.ScrapersConfig) *Manager {
+	t.Helper()
+	m, err := NewManager(cfg)
+	require.NoError(t, err)
+	return m
+}
+
+// newTimeoutManager returns a manager running scrapers with the given per-scraper timeout
+func newTimeoutManager(t *testing.T, timeout time.Duration, scrapers ...Scraper) *Manager {
+	m := newTestManager(t, config.ScrapersConfig{
+		RateLimits: config.RateLimitConfig{ScrapeTimeout: config.Duration(timeout)},
+	})
+	m.scrapers = scrapers
+	return m
+}
+
+func TestScrapeAllReturnsFastScraperWhenAnotherTimesOut(t *testing.T) {
+	fast := &fakeScraper{}
+	fast.add(reviewAt("1", 1))
+	m := newTimeoutManager(t, 50*time.Millisecond, &slowScraper{name: "Slow"}, fast)
+
+	start := time.Now()
+	reviews, err := m.ScrapeAll(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-1"}, reviewIDs(reviews))
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestScrapeAllAbandonsScraperIgnoringItsContext(t *testing.T) {
+	fast := &fakeScraper{}
+	fast.add(reviewAt("1", 1))
+	m := newTimeoutManager(t, 50*time.Millisecond, &slowScraper{name: "Hung", ignoresCtx: true}, fast)
+
+	done := make(chan []models.Review, 1)
+	go func() {
+		reviews, _ := m.ScrapeAll(context.Background())
+		done <- reviews
+	}()
+
+	select {
+	case reviews := <-done:
+		assert.Equal(t, []string{"fake-1"}, reviewIDs(reviews))
+	case <-time.After(5 * time.Second):
+		t.Fatal("a hung scraper held up the run")
+	}
+}
+
+func TestScrapeWithTimeoutReportsTimeout(t *testing.T) {
+	m := newTimeoutManager(t, time.Minute)
+
+	_, err := m.scrapeWithTimeout(context.Background(), &slowScraper{name: "Slow"}, 20*time.Millisecond)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "timed out after 20ms")
+}
+
+func TestScrapeTimeoutDefault(t *testing.T) {
+	assert.Equal(t, defaultScrapeTimeout, newTimeoutManager(t, 0).scrapeTimeout())
+	assert.Equal(t, 30*time.Second, newTimeoutManager(t, 30*time.Second).scrapeTimeout())
+}
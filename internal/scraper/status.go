@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Run states reported by RunStatus
+const (
+	RunStateIdle    = "idle"
+	RunStateRunning = "running"
+)
+
+// ErrRunInProgress is returned when a scraping run is started while another is running
+var ErrRunInProgress = errors.New("a scraping run is already in progress")
+
+// RunStatus describes the scraping run in progress, or the last one to finish
+type RunStatus struct {
+	State        string     `json:"state"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+	ReviewsFound int        `json:"reviewsFound"`
+	Error        string     `json:"error,omitempty"`
+}
+
+// StartRun marks a scraping run as started and returns the function that
+// performs it and records its outcome. The caller commits the batch it
+// returns once its reviews are processed. It returns ErrRunInProgress while
+// another run, manual or scheduled, has not finished.
+func (m *Manager) StartRun() (func(ctx context.Context) (*Batch, error), error) {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	if m.run.State == RunStateRunning {
+		return nil, ErrRunInProgress
+	}
+
+	startedAt := time.Now()
+	m.run = RunStatus{State: RunStateRunning, StartedAt: &startedAt}
+
+	return func(ctx context.Context) (*Batch, error) {
+		batch, err := m.scrapeAll(ctx)
+		if err != nil {
+			m.finishRun(0, err)
+			return nil, err
+		}
+		m.finishRun(len(batch.Reviews), nil)
+		return batch, nil
+	}, nil
+}
+
+// finishRun records the outcome of the run in progress
+func (m *Manager) finishRun(reviewsFound int, err error) {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+
+	finishedAt := time.Now()
+	m.run.State = RunStateIdle
+	m.run.FinishedAt = &finishedAt
+	m.run.ReviewsFound = reviewsFound
+	if err != nil {
+		m.run.Error = err.Error()
+	}
+}
+
+// RunStatus returns the state of the current or most recent scraping run
+func (m *Manager) RunStatus() RunStatus {
+	m.runMu.Lock()
+	defer m.runMu.Unlock()
+	return m.run
+}
@@ -0,0 +1,103 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStatusTracksRun(t *testing.T) {
+	fake := &fakeScraper{}
+	fake.add(reviewAt("1", 1), reviewAt("2", 2))
+	m := newTestManager(t, config.ScrapersConfig{})
+	m.scrapers = []Scraper{fake}
+
+	assert.Equal(t, RunStatus{State: RunStateIdle}, m.RunStatus())
+
+	run, err := m.StartRun()
+	assert.NoError(t, err)
+	status := m.RunStatus()
+	assert.Equal(t, RunStateRunning, status.State)
+	assert.NotNil(t, status.StartedAt)
+	assert.Nil(t, status.FinishedAt)
+
+	// A second run cannot start until the first finishes
+	_, err = m.StartRun()
+	assert.ErrorIs(t, err, ErrRunInProgress)
+	_, err = m.ScrapeAll(context.Background())
+	assert.ErrorIs(t, err, ErrRunInProgress)
+
+	_, err = run(context.Background())
+	assert.NoError(t, err)
+	status = m.RunStatus()
+	assert.Equal(t, RunStateIdle, status.State)
+	assert.Equal(t, 2, status.ReviewsFound)
+	assert.NotNil(t, status.FinishedAt)
+	assert.Empty(t, status.Error)
+}
+
+func TestRunStatusRecordsError(t *testing.T) {
+	fake := &fakeScraper{err: fmt.Errorf("upstream unavailable")}
+	m := newTestManager(t, config.ScrapersConfig{})
+	m.scrapers = []Scraper{fake}
+
+	_, err := m.ScrapeAll(context.Background())
+	assert.Error(t, err)
+
+	status := m.RunStatus()
+	assert.Equal(t, RunStateIdle, status.State)
+	assert.Equal(t, "Fake scraper error: upstream unavailable", status.Error)
+
+	// The next run clears the previous error
+	fake.err = nil
+	_, err = m.ScrapeAll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, m.RunStatus().Error)
+}
+
+// countingScraper counts its runs and blocks each one until release is closed
+type countingScraper struct {
+	runs    atomic.Int32
+	release chan struct{}
+}
+
+func (s *countingScraper) Name() string    { return "Counting" }
+func (s *countingScraper) IsEnabled() bool { return true }
+
+func (s *countingScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	s.runs.Add(1)
+	<-s.release
+	return nil, nil
+}
+
+func TestScrapeAllRunsOneAtATime(t *testing.T) {
+	counting := &countingScraper{release: make(chan struct{})}
+	m := newTestManager(t, config.ScrapersConfig{})
+	m.scrapers = []Scraper{counting}
+
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, 2)
+	)
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := m.ScrapeAll(context.Background())
+			errs <- err
+		}()
+	}
+
+	// One run is rejected while the other is still scraping
+	assert.ErrorIs(t, <-errs, ErrRunInProgress)
+	close(counting.release)
+	wg.Wait()
+	assert.NoError(t, <-errs)
+	assert.Equal(t, int32(1), counting.runs.Load())
+}
@@ -2,53 +2,61 @@ package scraper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
-	"net/url"
 	"regexp"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
 	"github.com/Infoblox-CTO/review-scraper/pkg/models"
 	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/time/rate"
 )
 
+// trustpilotBaseURL is where Trustpilot review pages are fetched from
+const trustpilotBaseURL = "https://www.trustpilot.com"
+
+// ErrBlocked is returned when a source answers with an anti-bot challenge or
+// an unrendered page instead of its reviews, so an empty result is not
+// mistaken for a source with nothing new
+var ErrBlocked = errors.New("scraping was blocked by the source")
+
+// trustpilotBlockMarkers are lower cased phrases found in the title or body of
+// the CAPTCHA and challenge pages served in place of a review page
+var trustpilotBlockMarkers = []string{
+	"captcha",
+	"verify you are human",
+	"are you a robot",
+	"just a moment",
+	"attention required",
+	"access denied",
+	"enable javascript",
+}
+
 // TrustpilotScraper implements the Scraper interface for Trustpilot
 type TrustpilotScraper struct {
 	config     config.TrustpilotScraperConfig
 	rateLimits config.RateLimitConfig
 	proxies    config.ProxyConfig
-	client     *http.Client
+	client     HTTPDoer
+	limiter    *rate.Limiter // Nil when no requests-per-minute budget is configured
 	userAgents []string
 	enabled    bool
+	baseURL    string
+	pages      *pageCache // Nil unless the page cache is enabled
 }
 
 // NewTrustpilotScraper creates a new Trustpilot scraper
 func NewTrustpilotScraper(cfg config.TrustpilotScraperConfig, rates config.RateLimitConfig,
-	proxies config.ProxyConfig) *TrustpilotScraper {
-
-	// Create client with default timeout
-	client := &http.Client{
-		Timeout: 30 * time.Second,
-	}
+	proxies config.ProxyConfig) (*TrustpilotScraper, error) {
 
-	// Setup proxy if enabled
-	if proxies.Enabled && proxies.URL != "" {
-		proxyURL := proxies.URL
-		if proxies.Username != "" && proxies.Password != "" {
-			// Add auth credentials to proxy URL if provided
-			proxyURL = fmt.Sprintf("http://%s:%s@%s",
-				proxies.Username,
-				proxies.Password,
-				strings.TrimPrefix(proxies.URL, "http://"))
-		}
-
-		transport := &http.Transport{
-			Proxy: http.ProxyURL(MustParseURL(proxyURL)),
-		}
-		client.Transport = transport
+	client, err := newScraperHTTPClient(proxies)
+	if err != nil {
+		return nil, err
 	}
 
 	// Common user agents for rotation
@@ -64,9 +72,32 @@ func NewTrustpilotScraper(cfg config.TrustpilotScraperConfig, rates config.RateL
 		rateLimits: rates,
 		proxies:    proxies,
 		client:     client,
+		limiter:    newRequestLimiter(rates),
 		userAgents: userAgents,
 		enabled:    cfg.Enabled,
-	}
+		baseURL:    trustpilotBaseURL,
+	}, nil
+}
+
+// SetPageCache revalidates review pages fetched before with conditional
+// requests, reusing their parsed reviews when Trustpilot reports them unchanged
+func (s *TrustpilotScraper) SetPageCache(cfg config.PageCacheConfig) {
+	s.pages = newPageCache(cfg)
+}
+
+func init() {
+	Register("trustpilot", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !cfg.Trustpilot.Enabled {
+			return nil, nil
+		}
+		trustpilot, err := NewTrustpilotScraper(cfg.Trustpilot, rates, cfg.ProxySettings)
+		if err != nil {
+			return nil, err
+		}
+		trustpilot.limiter = limiter
+		trustpilot.SetPageCache(cfg.PageCache)
+		return []Scraper{trustpilot}, nil
+	})
 }
 
 // Name returns the name of this scraper
@@ -79,17 +110,17 @@ func (s *TrustpilotScraper) IsEnabled() bool {
 	return s.enabled
 }
 
-// MustParseURL is a helper that panics if URL parsing fails
-func MustParseURL(rawURL string) *url.URL {
-	u, err := url.Parse(rawURL)
-	if err != nil {
-		panic(err)
-	}
-	return u
-}
-
 // Scrape retrieves reviews from Trustpilot
 func (s *TrustpilotScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	return s.ScrapeSince(ctx, Watermark{})
+}
+
+// ScrapeSince retrieves Trustpilot reviews newer than since. Trustpilot lists
+// the most recent reviews first, so paging stops at the first page reaching a
+// review already seen. With a page concurrency above one, pages are fetched
+// in batches of that size, and a batch may fetch a few pages past the one
+// paging stops at.
+func (s *TrustpilotScraper) ScrapeSince(ctx context.Context, since Watermark) ([]models.Review, error) {
 	var allReviews []models.Review
 
 	// Set the maximum number of pages to scrape
@@ -97,32 +128,35 @@ func (s *TrustpilotScraper) Scrape(ctx context.Context) ([]models.Review, error)
 	if maxPages <= 0 {
 		maxPages = 5 // Default to 5 pages
 	}
+	concurrency := max(s.config.PageConcurrency, 1)
 
-	// Scrape each page of reviews
-	for page := 1; page <= maxPages; page++ {
+	// Scrape each batch of pages
+	for first := 1; first <= maxPages; first += concurrency {
 		// Respect context cancellation
 		if ctx.Err() != nil {
 			return allReviews, ctx.Err()
 		}
 
-		// Scrape a page of reviews
-		reviews, hasMorePages, err := s.scrapePage(ctx, page)
-		if err != nil {
-			return allReviews, fmt.Errorf("error scraping Trustpilot page %d: %w", page, err)
-		}
+		last := min(first+concurrency-1, maxPages)
+		for _, result := range s.scrapePages(ctx, first, last) {
+			if result.err != nil {
+				return allReviews, fmt.Errorf("error scraping Trustpilot page %d: %w", result.page, result.err)
+			}
 
-		// Add reviews to the collection
-		allReviews = append(allReviews, reviews...)
+			// Add new reviews to the collection
+			fresh := since.Filter(result.reviews)
+			allReviews = append(allReviews, fresh...)
 
-		// Stop if there are no more pages
-		if !hasMorePages {
-			break
+			// Stop if there are no more pages, or the rest were already seen
+			if !result.hasMore || len(fresh) < len(result.reviews) {
+				return allReviews, nil
+			}
 		}
 
 		// Respect rate limits
-		if page < maxPages && s.rateLimits.PauseBetweenRequests {
+		if last < maxPages && s.rateLimits.PauseBetweenRequests {
 			select {
-			case <-time.After(time.Millisecond * time.Duration(s.rateLimits.PauseDuration)):
+			case <-time.After(s.rateLimits.PauseDuration.Duration()):
 				// Continue after pause
 			case <-ctx.Done():
 				return allReviews, ctx.Err()
@@ -133,10 +167,36 @@ func (s *TrustpilotScraper) Scrape(ctx context.Context) ([]models.Review, error)
 	return allReviews, nil
 }
 
+// trustpilotPageResult is the outcome of fetching one page of reviews
+type trustpilotPageResult struct {
+	page    int
+	reviews []models.Review
+	hasMore bool
+	err     error
+}
+
+// scrapePages fetches pages first through last at once, returning their
+// results in page order. Each request still waits its turn at the limiter.
+func (s *TrustpilotScraper) scrapePages(ctx context.Context, first, last int) []trustpilotPageResult {
+	results := make([]trustpilotPageResult, last-first+1)
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			page := first + i
+			reviews, hasMore, err := s.scrapePage(ctx, page)
+			results[i] = trustpilotPageResult{page: page, reviews: reviews, hasMore: hasMore, err: err}
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
 // scrapePage retrieves reviews from a single page on Trustpilot
 func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.Review, bool, error) {
 	// Construct URL for the page of reviews
-	url := fmt.Sprintf("https://www.trustpilot.com/review/%s?page=%d", s.config.BusinessID, page)
+	url := fmt.Sprintf("%s/review/%s?page=%d", s.baseURL, s.config.BusinessID, page)
 
 	// Create request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
@@ -156,6 +216,19 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 	req.Header.Set("Connection", "keep-alive")
 	req.Header.Set("Upgrade-Insecure-Requests", "1")
 
+	// Ask only for changes to a page fetched before
+	cached, isCached := s.pages.lookup(url)
+	if isCached {
+		cached.setValidators(req)
+	}
+
+	// Respect the source's request budget
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, false, err
+		}
+	}
+
 	// Send request
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -163,6 +236,11 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 	}
 	defer resp.Body.Close()
 
+	// An unchanged page needs no parsing
+	if isCached && resp.StatusCode == http.StatusNotModified {
+		return cached.reviews, cached.hasMore, nil
+	}
+
 	// Check for HTTP errors
 	if resp.StatusCode != http.StatusOK {
 		return nil, false, fmt.Errorf("Trustpilot returned non-OK status: %d", resp.StatusCode)
@@ -174,11 +252,52 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 		return nil, false, fmt.Errorf("error parsing HTML: %w", err)
 	}
 
+	// Prefer the page's structured data, which survives markup changes
 	var reviews []models.Review
+	var hasMorePages bool
 	retrievedTime := time.Now()
+	if data, ok := parseTrustpilotNextData(doc); ok {
+		for i, review := range data.Props.PageProps.Reviews {
+			reviews = append(reviews, convertTrustpilotReview(review, s.config.BusinessID, page, i, retrievedTime))
+		}
+		hasMorePages = data.hasMorePages()
+	} else {
+		reviews, hasMorePages = parseTrustpilotArticles(doc, s.config.BusinessID, page, retrievedTime)
+		if len(reviews) == 0 {
+			if err := detectTrustpilotBlock(doc); err != nil {
+				return nil, false, err
+			}
+		}
+	}
+
+	s.pages.store(url, resp.Header, reviews, hasMorePages)
+	return reviews, hasMorePages, nil
+}
 
-	// Store the business ID in a local variable for use in the callback
-	businessID := s.config.BusinessID
+// detectTrustpilotBlock reports ErrBlocked for a page without reviews that is
+// a CAPTCHA or interstitial, recognized by its title or body text, or a
+// JavaScript shell with no review container and no text to render
+func detectTrustpilotBlock(doc *goquery.Document) error {
+	title := strings.ToLower(doc.Find("title").First().Text())
+	body := doc.Find("body").Clone()
+	body.Find("script, style, noscript").Remove()
+	text := strings.ToLower(strings.Join(strings.Fields(body.Text()), " "))
+
+	for _, marker := range trustpilotBlockMarkers {
+		if strings.Contains(title, marker) || strings.Contains(text, marker) {
+			return fmt.Errorf("%w: page looks like an anti-bot challenge (%q)", ErrBlocked, marker)
+		}
+	}
+	if text == "" && doc.Find("main, article").Length() == 0 {
+		return fmt.Errorf("%w: page has no review content", ErrBlocked)
+	}
+	return nil
+}
+
+// parseTrustpilotArticles extracts reviews from the review markup of a page
+// without a __NEXT_DATA__ island
+func parseTrustpilotArticles(doc *goquery.Document, businessID string, page int, retrievedTime time.Time) ([]models.Review, bool) {
+	var reviews []models.Review
 
 	// Extract reviews from the page
 	// Note: Selectors may need to be updated if Trustpilot changes their HTML structure
@@ -211,13 +330,13 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 		}
 
 		// Extract title
-		title := strings.TrimSpace(s.Find("h2.review-content__title").Text())
+		title := cleanContent(s.Find("h2.review-content__title").Text())
 
 		// Extract content
-		content := strings.TrimSpace(s.Find("p.review-content__text").Text())
+		content := cleanContent(s.Find("p.review-content__text").Text())
 
 		// Extract author
-		author := strings.TrimSpace(s.Find("div.consumer-information__name").Text())
+		author := cleanContent(s.Find("div.consumer-information__name").Text())
 
 		// Extract date
 		dateStr := ""
@@ -225,32 +344,6 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 			dateStr = strings.TrimSpace(dates.Text())
 		})
 
-		// Parse date (format can vary)
-		// This is a simplistic approach; in real code, you'd want more robust date parsing
-		var createdAt time.Time
-		if dateStr != "" {
-			// Try to parse the date in various formats
-			formats := []string{
-				"Jan 2, 2006",
-				"January 2, 2006",
-				"2 Jan 2006",
-				"2006-01-02",
-			}
-
-			for _, format := range formats {
-				parsed, err := time.Parse(format, dateStr)
-				if err == nil {
-					createdAt = parsed
-					break
-				}
-			}
-		}
-
-		// If date parsing failed, use a fallback date
-		if createdAt.IsZero() {
-			createdAt = time.Now().AddDate(0, 0, -((page-1)*10 + i)) // Each review is a day older
-		}
-
 		// Extract URL
 		url := fmt.Sprintf("https://www.trustpilot.com/reviews/%s#%s", businessID, reviewID)
 
@@ -264,7 +357,6 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 			Author:      author,
 			Rating:      rating,
 			URL:         url,
-			CreatedAt:   createdAt,
 			RetrievedAt: retrievedTime,
 			Metadata: map[string]interface{}{
 				"platform":     "Trustpilot",
@@ -273,10 +365,12 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 				"review_index": i,
 			},
 		}
+		setReviewDate(&review, dateStr)
 
 		// Check for vendor response (Trustpilot allows companies to reply to reviews)
-		vendorResponse := strings.TrimSpace(s.Find("div.brand-reply").Text())
+		vendorResponse := cleanContent(s.Find("div.brand-reply").Text())
 		if vendorResponse != "" {
+			review.Replies = []models.Reply{{Content: vendorResponse, IsVendor: true}}
 			review.Metadata["has_vendor_response"] = true
 			review.Metadata["vendor_response"] = vendorResponse
 		} else {
@@ -292,5 +386,5 @@ func (s *TrustpilotScraper) scrapePage(ctx context.Context, page int) ([]models.
 		hasMorePages = true
 	})
 
-	return reviews, hasMorePages, nil
+	return reviews, hasMorePages
 }
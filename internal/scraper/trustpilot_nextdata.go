@@ -0,0 +1,123 @@
+package scraper
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/PuerkitoBio/goquery"
+)
+
+// trustpilotNextData is the part of the __NEXT_DATA__ JSON island that a
+// Trustpilot review page is rendered from
+type trustpilotNextData struct {
+	Props struct {
+		PageProps struct {
+			Reviews []trustpilotReview `json:"reviews"`
+			Filters struct {
+				Pagination struct {
+					CurrentPage int `json:"currentPage"`
+					TotalPages  int `json:"totalPages"`
+				} `json:"pagination"`
+			} `json:"filters"`
+		} `json:"pageProps"`
+	} `json:"props"`
+}
+
+// trustpilotReview is a review object from the __NEXT_DATA__ island
+type trustpilotReview struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title"`
+	Text     string  `json:"text"`
+	Rating   float64 `json:"rating"`
+	Language string  `json:"language"`
+	Dates    struct {
+		ExperiencedDate string `json:"experiencedDate"`
+		PublishedDate   string `json:"publishedDate"`
+	} `json:"dates"`
+	Consumer struct {
+		DisplayName string `json:"displayName"`
+	} `json:"consumer"`
+	Reply *struct {
+		Message       string `json:"message"`
+		PublishedDate string `json:"publishedDate"`
+	} `json:"reply"`
+	Labels struct {
+		Verification struct {
+			IsVerified bool `json:"isVerified"`
+		} `json:"verification"`
+	} `json:"labels"`
+}
+
+// parseTrustpilotNextData reads the __NEXT_DATA__ island of a review page. It
+// reports false when the page has no island, or one without a reviews list,
+// so the caller can fall back to the HTML selectors.
+func parseTrustpilotNextData(doc *goquery.Document) (trustpilotNextData, bool) {
+	var data trustpilotNextData
+
+	script := doc.Find(`script#__NEXT_DATA__`).First()
+	if script.Length() == 0 {
+		return data, false
+	}
+	if err := json.Unmarshal([]byte(script.Text()), &data); err != nil {
+		return data, false
+	}
+	return data, data.Props.PageProps.Reviews != nil
+}
+
+// hasMorePages reports whether the island's pagination lists a later page
+func (d trustpilotNextData) hasMorePages() bool {
+	pagination := d.Props.PageProps.Filters.Pagination
+	return pagination.CurrentPage < pagination.TotalPages
+}
+
+// convertTrustpilotReview maps a review from the __NEXT_DATA__ island into the
+// pipeline's review format
+func convertTrustpilotReview(r trustpilotReview, businessID string, page, index int, retrievedAt time.Time) models.Review {
+	reviewID := r.ID
+	if reviewID == "" {
+		reviewID = fmt.Sprintf("trustpilot-%s-%d-%d", businessID, page, index)
+	}
+
+	review := models.Review{
+		ID:          reviewID,
+		Source:      "trustpilot",
+		SourceID:    reviewID,
+		Content:     cleanContent(r.Text),
+		Title:       cleanContent(r.Title),
+		Author:      strings.TrimSpace(r.Consumer.DisplayName),
+		Language:    strings.ToLower(r.Language),
+		URL:         fmt.Sprintf("https://www.trustpilot.com/reviews/%s", reviewID),
+		RetrievedAt: retrievedAt,
+		Metadata: map[string]interface{}{
+			"platform":            "Trustpilot",
+			"business_id":         businessID,
+			"review_page":         page,
+			"review_index":        index,
+			"verified":            r.Labels.Verification.IsVerified,
+			"has_vendor_response": false,
+		},
+	}
+	if r.Rating > 0 {
+		rating := r.Rating
+		review.Rating = &rating
+	}
+	if r.Dates.ExperiencedDate != "" {
+		review.Metadata["experienced_date"] = r.Dates.ExperiencedDate
+	}
+	setReviewDate(&review, r.Dates.PublishedDate)
+
+	if r.Reply != nil && strings.TrimSpace(r.Reply.Message) != "" {
+		reply := models.Reply{Content: cleanContent(r.Reply.Message), IsVendor: true}
+		if repliedAt, ok := parseReviewDate(r.Reply.PublishedDate); ok {
+			reply.CreatedAt = repliedAt
+		}
+		review.Replies = []models.Reply{reply}
+		review.Metadata["has_vendor_response"] = true
+		review.Metadata["vendor_response"] = reply.Content
+	}
+
+	return review
+}
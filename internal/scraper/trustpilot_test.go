@@ -0,0 +1,347 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const trustpilotPage = `<html><body>
+<article class="review" id="tp-1">
+  <div class="star-rating"><img alt="2 stars"></div>
+  <h2 class="review-content__title">Grid upgrade pain</h2>
+  <p class="review-content__text">The NIOS upgrade took the whole weekend.</p>
+  <div class="consumer-information__name">Network Admin</div>
+</article>
+<article class="review" id="tp-2">
+  <div class="star-rating"><img alt="5 stars"></div>
+  <h2 class="review-content__title">Great DNS</h2>
+  <p class="review-content__text">BloxOne DNS just works.</p>
+  <div class="consumer-information__name">Ops Lead</div>
+</article>
+</body></html>`
+
+func TestTrustpilotScraperReusesUnchangedPages(t *testing.T) {
+	var fullResponses, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/review/infoblox.com", r.URL.Path)
+		if r.Header.Get("If-None-Match") == `"page-1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		fullResponses++
+		w.Header().Set("ETag", `"page-1"`)
+		w.Write([]byte(trustpilotPage))
+	}))
+	defer server.Close()
+
+	s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{Enabled: true, BusinessID: "infoblox.com"}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+	s.SetPageCache(config.PageCacheConfig{Enabled: true})
+
+	first, err := s.Scrape(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tp-1", "tp-2"}, reviewIDs(first))
+
+	second, err := s.Scrape(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+
+	assert.Equal(t, 1, fullResponses)
+	assert.Equal(t, 1, notModified)
+}
+
+func TestTrustpilotScraperWithoutPageCache(t *testing.T) {
+	var conditional int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") != "" {
+			conditional++
+		}
+		w.Header().Set("ETag", `"page-1"`)
+		w.Write([]byte(trustpilotPage))
+	}))
+	defer server.Close()
+
+	s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{Enabled: true, BusinessID: "infoblox.com"}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	for i := 0; i < 2; i++ {
+		reviews, err := s.Scrape(context.Background())
+		assert.NoError(t, err)
+		assert.Len(t, reviews, 2)
+	}
+	assert.Zero(t, conditional)
+}
+
+func TestTrustpilotScraperParsesNextData(t *testing.T) {
+	page, err := os.ReadFile("testdata/trustpilot_next_data.html")
+	require.NoError(t, err)
+
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Query().Get("page"))
+		w.Write(page)
+	}))
+	defer server.Close()
+
+	s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{Enabled: true, BusinessID: "infoblox.com", MaxPages: 1}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	reviews, err := s.Scrape(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, []string{"663f1c2e9b1d4a0012345678", "663e0a1b7c2d3b0098765432"}, reviewIDs(reviews))
+	assert.Equal(t, []string{"1"}, requested)
+
+	first := reviews[0]
+	assert.Equal(t, "trustpilot", first.Source)
+	assert.Equal(t, "Grid upgrade pain", first.Title)
+	assert.Contains(t, first.Content, "The NIOS 8.6.2 upgrade took the whole weekend.")
+	assert.Equal(t, "Network Admin", first.Author)
+	require.NotNil(t, first.Rating)
+	assert.Equal(t, 2.0, *first.Rating)
+	assert.Equal(t, "en", first.Language)
+	assert.True(t, first.CreatedAt.Equal(time.Date(2024, 5, 1, 10, 15, 30, 0, time.UTC)))
+	assert.NotContains(t, first.Metadata, "date_parsed")
+	assert.Equal(t, true, first.Metadata["verified"])
+	assert.Equal(t, "https://www.trustpilot.com/reviews/663f1c2e9b1d4a0012345678", first.URL)
+	require.Len(t, first.Replies, 1)
+	assert.True(t, first.Replies[0].IsVendor)
+	assert.Equal(t, "Sorry to hear that, our team will reach out.", first.Replies[0].Content)
+	assert.True(t, first.Replies[0].CreatedAt.Equal(time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)))
+
+	second := reviews[1]
+	require.NotNil(t, second.Rating)
+	assert.Equal(t, 5.0, *second.Rating)
+	assert.Equal(t, "de", second.Language)
+	assert.False(t, second.HasVendorReply())
+	assert.Equal(t, false, second.Metadata["has_vendor_response"])
+}
+
+func TestTrustpilotNextDataPagination(t *testing.T) {
+	page, err := os.ReadFile("testdata/trustpilot_next_data.html")
+	require.NoError(t, err)
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(page))
+	require.NoError(t, err)
+	data, ok := parseTrustpilotNextData(doc)
+	require.True(t, ok)
+	assert.True(t, data.hasMorePages())
+
+	data.Props.PageProps.Filters.Pagination.CurrentPage = 2
+	assert.False(t, data.hasMorePages())
+
+	// Pages without the island fall back to the review markup
+	doc, err = goquery.NewDocumentFromReader(strings.NewReader(trustpilotPage))
+	require.NoError(t, err)
+	_, ok = parseTrustpilotNextData(doc)
+	assert.False(t, ok)
+}
+
+// trustpilotPageDate is when the reviews on a page were published; later
+// pages hold older reviews
+func trustpilotPageDate(page int) time.Time {
+	return time.Date(2024, 5, 20-page, 10, 0, 0, 0, time.UTC)
+}
+
+// trustpilotNextDataPage renders a review page whose __NEXT_DATA__ island
+// holds a review for each of ids
+func trustpilotNextDataPage(page, totalPages int, ids ...string) string {
+	reviews := make([]string, len(ids))
+	for i, id := range ids {
+		reviews[i] = fmt.Sprintf(`{"id": %q, "text": "Review %s", "rating": 3, "dates": {"publishedDate": %q}}`,
+			id, id, trustpilotPageDate(page).Format(time.RFC3339))
+	}
+	return fmt.Sprintf(`<html><body><script id="__NEXT_DATA__" type="application/json">
+{"props": {"pageProps": {"reviews": [%s], "filters": {"pagination": {"currentPage": %d, "totalPages": %d}}}}}
+</script></body></html>`, strings.Join(reviews, ","), page, totalPages)
+}
+
+func TestTrustpilotScraperFetchesPagesConcurrently(t *testing.T) {
+	const totalPages = 4
+	var (
+		mu                sync.Mutex
+		inFlight, peak    int
+		requested         []int
+		releaseConcurrent = make(chan struct{})
+	)
+	var once sync.Once
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, err := strconv.Atoi(r.URL.Query().Get("page"))
+		assert.NoError(t, err)
+
+		mu.Lock()
+		requested = append(requested, page)
+		inFlight++
+		peak = max(peak, inFlight)
+		if inFlight > 1 {
+			once.Do(func() { close(releaseConcurrent) })
+		}
+		mu.Unlock()
+
+		// Hold the first page until a second one is being fetched alongside it
+		if page == 1 {
+			select {
+			case <-releaseConcurrent:
+			case <-time.After(5 * time.Second):
+			}
+		}
+
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		if page > totalPages {
+			fmt.Fprint(w, trustpilotNextDataPage(page, totalPages))
+			return
+		}
+		fmt.Fprint(w, trustpilotNextDataPage(page, totalPages, fmt.Sprintf("p%d-a", page), fmt.Sprintf("p%d-b", page)))
+	}))
+	defer server.Close()
+
+	s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{
+		Enabled:         true,
+		BusinessID:      "infoblox.com",
+		MaxPages:        6,
+		PageConcurrency: 3,
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	reviews, err := s.Scrape(context.Background())
+	require.NoError(t, err)
+
+	assert.ElementsMatch(t, []string{
+		"p1-a", "p1-b", "p2-a", "p2-b", "p3-a", "p3-b", "p4-a", "p4-b",
+	}, reviewIDs(reviews))
+	assert.Greater(t, peak, 1)
+
+	// The batch holding the last page also fetched the pages after it
+	sort.Ints(requested)
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, requested)
+}
+
+func TestTrustpilotScraperPageConcurrencyStopsAtSeenReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		fmt.Fprint(w, trustpilotNextDataPage(page, 10, fmt.Sprintf("p%d", page)))
+	}))
+	defer server.Close()
+
+	s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{
+		Enabled:         true,
+		BusinessID:      "infoblox.com",
+		MaxPages:        10,
+		PageConcurrency: 4,
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	// Page 3's review was returned by an earlier run, so paging stops there
+	since := Watermark{CreatedAt: trustpilotPageDate(3), SourceID: "p3"}
+	reviews, err := s.ScrapeSince(context.Background(), since)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"p1", "p2"}, reviewIDs(reviews))
+}
+
+func TestTrustpilotScraperReportsBlockedPages(t *testing.T) {
+	captcha, err := os.ReadFile("testdata/trustpilot_captcha.html")
+	require.NoError(t, err)
+
+	for name, page := range map[string][]byte{
+		"captcha":  captcha,
+		"js shell": []byte(`<html><head><title>Trustpilot</title></head><body><div id="__next"></div><script src="/_next/app.js"></script></body></html>`),
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Write(page)
+			}))
+			defer server.Close()
+
+			s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{Enabled: true, BusinessID: "infoblox.com", MaxPages: 1}, config.RateLimitConfig{}, config.ProxyConfig{})
+			require.NoError(t, err)
+			s.baseURL = server.URL
+
+			reviews, err := s.Scrape(context.Background())
+			assert.ErrorIs(t, err, ErrBlocked)
+			assert.Empty(t, reviews)
+		})
+	}
+}
+
+func TestTrustpilotScraperAcceptsPageWithoutReviews(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><main><p>No reviews yet</p></main></body></html>`)
+	}))
+	defer server.Close()
+
+	s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{Enabled: true, BusinessID: "infoblox.com", MaxPages: 1}, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	reviews, err := s.Scrape(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, reviews)
+}
+
+func TestTrustpilotScraperIsRegistered(t *testing.T) {
+	m := newTestManager(t, config.ScrapersConfig{
+		Trustpilot: config.TrustpilotScraperConfig{Enabled: true, BusinessID: "infoblox.com"},
+		PageCache:  config.PageCacheConfig{Enabled: true},
+	})
+
+	scrapers := m.GetScrapers()
+	require.Len(t, scrapers, 1)
+	trustpilot, ok := scrapers[0].(*TrustpilotScraper)
+	require.True(t, ok)
+	assert.NotNil(t, trustpilot.pages, "the manager's scraper uses the configured page cache")
+}
+
+func TestTrustpilotScraperPausesForPauseDuration(t *testing.T) {
+	var mu sync.Mutex
+	var requestedAt []time.Time
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requestedAt = append(requestedAt, time.Now())
+		mu.Unlock()
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		w.Write([]byte(trustpilotNextDataPage(page, 2, fmt.Sprintf("tp-%d", page))))
+	}))
+	defer server.Close()
+
+	const pause = 50 * time.Millisecond
+	s, err := NewTrustpilotScraper(config.TrustpilotScraperConfig{Enabled: true, BusinessID: "infoblox.com", MaxPages: 2},
+		config.RateLimitConfig{PauseBetweenRequests: true, PauseDuration: config.Duration(pause)}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+
+	// PauseDuration is a duration, not a count of milliseconds
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	reviews, err := s.Scrape(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tp-1", "tp-2"}, reviewIDs(reviews))
+
+	mu.Lock()
+	defer mu.Unlock()
+	if assert.Len(t, requestedAt, 2) {
+		assert.GreaterOrEqual(t, requestedAt[1].Sub(requestedAt[0]), pause)
+	}
+}
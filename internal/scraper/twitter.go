@@ -10,13 +10,14 @@ import (
 	"io"
 	"net/http"
 	"net/url"
-	"regexp"
 	"strings"
 	"time"
 
 	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/query"
 	"github.com/Infoblox-CTO/review-scraper/pkg/models"
 	"github.com/google/uuid"
+	"golang.org/x/time/rate"
 )
 
 // TwitterScraper implements the Scraper interface for Twitter/X
@@ -24,7 +25,8 @@ type TwitterScraper struct {
 	config     config.TwitterScraperConfig
 	rateLimits config.RateLimitConfig
 	proxies    config.ProxyConfig
-	client     *http.Client
+	client     HTTPDoer
+	limiter    *rate.Limiter // Nil when no requests-per-minute budget is configured
 	userAgents []string
 	enabled    bool
 }
@@ -51,11 +53,23 @@ func NewTwitterScraper(cfg config.TwitterScraperConfig, rates config.RateLimitCo
 		rateLimits: rates,
 		proxies:    proxies,
 		client:     client,
+		limiter:    newRequestLimiter(rates),
 		userAgents: userAgents,
 		enabled:    cfg.Enabled,
 	}
 }
 
+func init() {
+	Register("twitter", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !cfg.Twitter.Enabled {
+			return nil, nil
+		}
+		twitter := NewTwitterScraper(cfg.Twitter, rates, cfg.ProxySettings)
+		twitter.limiter = limiter
+		return []Scraper{twitter}, nil
+	})
+}
+
 // Name returns the name of this scraper
 func (s *TwitterScraper) Name() string {
 	return "Twitter"
@@ -70,15 +84,20 @@ func (s *TwitterScraper) IsEnabled() bool {
 func (s *TwitterScraper) Scrape(ctx context.Context) ([]models.Review, error) {
 	var reviews []models.Review
 
-	// Construct search queries for each keyword
+	// Construct search queries for each keyword expression
 	for _, keyword := range s.config.Keywords {
 		// Respect context cancellation
 		if ctx.Err() != nil {
 			return reviews, ctx.Err()
 		}
 
-		// Search tweets containing the keyword
-		tweets, err := s.searchTweets(ctx, keyword)
+		expr, err := query.Parse(keyword)
+		if err != nil {
+			return reviews, fmt.Errorf("invalid keyword expression '%s': %w", keyword, err)
+		}
+
+		// Search tweets matching the keyword expression
+		tweets, err := s.searchTweets(ctx, expr.Twitter())
 		if err != nil {
 			return reviews, fmt.Errorf("error searching tweets for keyword '%s': %w", keyword, err)
 		}
@@ -95,7 +114,7 @@ func (s *TwitterScraper) Scrape(ctx context.Context) ([]models.Review, error) {
 		// Respect rate limits
 		if len(s.config.Keywords) > 1 && s.rateLimits.PauseBetweenRequests {
 			select {
-			case <-time.After(s.rateLimits.PauseDuration):
+			case <-time.After(s.rateLimits.PauseDuration.Duration()):
 				// Continue after pause
 			case <-ctx.Done():
 				return reviews, ctx.Err()
@@ -110,6 +129,7 @@ func (s *TwitterScraper) Scrape(ctx context.Context) ([]models.Review, error) {
 type Tweet struct {
 	ID        string `json:"id_str"`
 	Text      string `json:"full_text"`
+	Lang      string `json:"lang"`
 	CreatedAt string `json:"created_at"` // Twitter timestamp format: "Wed Oct 10 20:19:24 +0000 2018"
 	User      struct {
 		ScreenName string `json:"screen_name"`
@@ -137,14 +157,14 @@ type TweetSearchResponse struct {
 	} `json:"search_metadata"`
 }
 
-// searchTweets searches for tweets containing the given keyword
-func (s *TwitterScraper) searchTweets(ctx context.Context, keyword string) ([]Tweet, error) {
+// searchTweets searches for tweets matching the given Twitter search query
+func (s *TwitterScraper) searchTweets(ctx context.Context, searchQuery string) ([]Tweet, error) {
 	// Construct the Twitter API URL for tweet search
 	baseURL := "https://api.twitter.com/1.1/search/tweets.json"
 
 	// URL encode the query parameters
 	query := url.Values{}
-	query.Add("q", keyword)
+	query.Add("q", searchQuery)
 	query.Add("count", fmt.Sprintf("%d", s.config.MaxResults))
 	query.Add("tweet_mode", "extended") // Get full text instead of truncated
 	query.Add("result_type", "recent")  // Get most recent tweets
@@ -172,6 +192,13 @@ func (s *TwitterScraper) searchTweets(ctx context.Context, keyword string) ([]Tw
 		req.Header.Set("User-Agent", userAgent)
 	}
 
+	// Respect the source's request budget
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
 	// Send the request
 	resp, err := s.client.Do(req)
 	if err != nil {
@@ -276,21 +303,8 @@ func (s *TwitterScraper) filterTweets(tweets []Tweet) []Tweet {
 	return filtered
 }
 
-// parseTweetTime parses Twitter's timestamp format
-func parseTweetTime(timestamp string) (time.Time, error) {
-	// Twitter timestamp format: "Wed Oct 10 20:19:24 +0000 2018"
-	layout := "Mon Jan 02 15:04:05 -0700 2006"
-	return time.Parse(layout, timestamp)
-}
-
 // convertTweetToReview converts a Tweet to a Review
 func (s *TwitterScraper) convertTweetToReview(tweet Tweet) models.Review {
-	// Parse tweet creation time
-	createdAt, err := parseTweetTime(tweet.CreatedAt)
-	if err != nil {
-		createdAt = time.Now() // Fallback to current time if parsing fails
-	}
-
 	// Extract sentiment indicators (e.g., hashtags)
 	hashtags := make([]string, 0, len(tweet.Entities.Hashtags))
 	for _, tag := range tweet.Entities.Hashtags {
@@ -300,7 +314,7 @@ func (s *TwitterScraper) convertTweetToReview(tweet Tweet) models.Review {
 	// Construct tweet URL
 	tweetURL := fmt.Sprintf("https://twitter.com/%s/status/%s", tweet.User.ScreenName, tweet.ID)
 
-	// Create metadata
+	// Create metadata; engagement is kept here rather than turned into a rating
 	metadata := map[string]interface{}{
 		"retweet_count":  tweet.RetweetCount,
 		"favorite_count": tweet.FavoriteCount,
@@ -316,29 +330,18 @@ func (s *TwitterScraper) convertTweetToReview(tweet Tweet) models.Review {
 		metadata["in_reply_to_user_id"] = tweet.InReplyToUserID
 	}
 
-	// Generate a rating based on engagement (crude approximation)
-	// In a real implementation, this would use a more sophisticated approach
-	engagementScore := float64(tweet.FavoriteCount+tweet.RetweetCount) / 10.0
-	if engagementScore > 5.0 {
-		engagementScore = 5.0
-	}
-	rating := 5.0 - engagementScore // Invert so high engagement = potentially negative review
-
-	// Clean text - remove URLs
-	text := tweet.Text
-	urlPattern := regexp.MustCompile(`https?://\S+`)
-	text = urlPattern.ReplaceAllString(text, "")
-
-	return models.Review{
+	review := models.Review{
 		ID:          fmt.Sprintf("twitter-%s", tweet.ID),
 		Source:      "twitter",
 		SourceID:    tweet.ID,
-		Content:     strings.TrimSpace(text),
+		Content:     cleanTweetText(tweet.Text),
 		Author:      tweet.User.ScreenName,
-		Rating:      &rating,
 		URL:         tweetURL,
-		CreatedAt:   createdAt,
+		Language:    tweet.Lang,
 		RetrievedAt: time.Now(),
 		Metadata:    metadata,
 	}
+	setReviewDate(&review, tweet.CreatedAt)
+
+	return review
 }
@@ -2,9 +2,8 @@ package scraper
 
 import (
 	"context"
+	"io"
 	"net/http"
-	"net/http/httptest"
-	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -15,9 +14,9 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-// setupMockTwitterServer creates a test server that returns predefined responses
-func setupMockTwitterServer() *httptest.Server {
-	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+// mockTwitterHandler answers Twitter searches with predefined responses
+func mockTwitterHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Check if the request includes Infoblox-related keywords
 		query := r.URL.Query().Get("q")
 		var mockResponse string
@@ -127,7 +126,7 @@ func setupMockTwitterServer() *httptest.Server {
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusOK)
 		w.Write([]byte(mockResponse))
-	}))
+	})
 }
 
 // Mock Twitter API client for testing
@@ -161,7 +160,7 @@ func TestNewTwitterScraper(t *testing.T) {
 	}
 	rateLimitCfg := config.RateLimitConfig{
 		PauseBetweenRequests: true,
-		PauseDuration:        time.Second,
+		PauseDuration:        config.Duration(time.Second),
 		RandomizeUserAgents:  true,
 	}
 	proxyCfg := config.ProxyConfig{
@@ -177,10 +176,6 @@ func TestNewTwitterScraper(t *testing.T) {
 }
 
 func TestFetchReviews(t *testing.T) {
-	// Setup mock server
-	server := setupMockTwitterServer()
-	defer server.Close()
-
 	// Create configs needed for the scraper
 	twitterCfg := config.TwitterScraperConfig{
 		Keywords:     []string{"infoblox", "bloxone", "ddi"},
@@ -195,7 +190,7 @@ func TestFetchReviews(t *testing.T) {
 
 	rateLimitCfg := config.RateLimitConfig{
 		PauseBetweenRequests: true,
-		PauseDuration:        time.Millisecond * 100, // Short pause for tests
+		PauseDuration:        config.Duration(time.Millisecond * 100), // Short pause for tests
 		RandomizeUserAgents:  true,
 	}
 
@@ -206,15 +201,9 @@ func TestFetchReviews(t *testing.T) {
 	// Create the scraper
 	scraper := NewTwitterScraper(twitterCfg, rateLimitCfg, proxyCfg)
 
-	// Override the client to use our test server
-	scraper.client = &http.Client{
-		Transport: &http.Transport{
-			Proxy: func(req *http.Request) (*url.URL, error) {
-				// Redirect all requests to our mock server
-				return url.Parse(server.URL)
-			},
-		},
-	}
+	// Answer the scraper's requests with the mock API
+	doer := &handlerDoer{handler: mockTwitterHandler()}
+	scraper.client = doer
 
 	// Execute
 	ctx := context.Background()
@@ -223,6 +212,7 @@ func TestFetchReviews(t *testing.T) {
 	// Assert
 	assert.NoError(t, err)
 	assert.NotEmpty(t, reviews)
+	assert.Len(t, doer.requests, 3, "one search per keyword")
 
 	// Check that all reviews have proper fields
 	for _, review := range reviews {
@@ -240,6 +230,49 @@ func TestFetchReviews(t *testing.T) {
 	}
 }
 
+// queryRecorder answers Twitter searches with no results, recording each query
+type queryRecorder struct {
+	queries []string
+}
+
+func (r *queryRecorder) Do(req *http.Request) (*http.Response, error) {
+	r.queries = append(r.queries, req.URL.Query().Get("q"))
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"statuses": []}`)),
+		Request:    req,
+	}, nil
+}
+
+func TestTwitterScraperTranslatesKeywordExpressions(t *testing.T) {
+	scraper := NewTwitterScraper(config.TwitterScraperConfig{
+		Enabled:  true,
+		Keywords: []string{"infoblox", "infoblox AND (dns OR dhcp)", `"grid manager" OR nios`},
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	recorder := &queryRecorder{}
+	scraper.client = recorder
+
+	_, err := scraper.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"infoblox", "infoblox (dns OR dhcp)", `"grid manager" OR nios`}, recorder.queries)
+}
+
+func TestTwitterScraperRejectsInvalidKeywordExpression(t *testing.T) {
+	scraper := NewTwitterScraper(config.TwitterScraperConfig{
+		Enabled:  true,
+		Keywords: []string{"infoblox AND"},
+	}, config.RateLimitConfig{}, config.ProxyConfig{})
+	recorder := &queryRecorder{}
+	scraper.client = recorder
+
+	_, err := scraper.Scrape(context.Background())
+
+	assert.ErrorContains(t, err, "invalid keyword expression 'infoblox AND'")
+	assert.Empty(t, recorder.queries)
+}
+
 // Helper function to filter out reviews containing excluded words
 func filterOutExcludedContent(reviews []models.Review, excludeWords []string) []models.Review {
 	var filtered []models.Review
@@ -286,5 +319,9 @@ func TestParseReviewFromTweet(t *testing.T) {
 	assert.Equal(t, "twitter", review.Source)
 	assert.Equal(t, tweet.User.ScreenName, review.Author)
 	assert.True(t, strings.Contains(review.Content, "BloxOne Threat Defense"))
-	assert.NotNil(t, review.Rating)
+
+	// Engagement says nothing about sentiment, so tweets carry no rating
+	assert.Nil(t, review.Rating)
+	assert.Equal(t, 5, review.Metadata["retweet_count"])
+	assert.Equal(t, 10, review.Metadata["favorite_count"])
 }
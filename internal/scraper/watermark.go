@@ -0,0 +1,156 @@
+package scraper
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// Watermark marks the newest review already seen from a source. Reviews are
+// ordered by CreatedAt, with SourceID breaking ties between reviews created
+// at the same time.
+type Watermark struct {
+	CreatedAt time.Time `json:"createdAt"`
+	SourceID  string    `json:"sourceId"`
+}
+
+// IsZero reports whether nothing has been seen yet
+func (w Watermark) IsZero() bool {
+	return w.CreatedAt.IsZero() && w.SourceID == ""
+}
+
+// Admits reports whether review is newer than the watermark. Reviews without
+// a creation time, or with one derived from a relative date such as "2 days
+// ago", cannot be ordered, so they are always admitted.
+func (w Watermark) Admits(review models.Review) bool {
+	if w.IsZero() || review.CreatedAt.IsZero() || hasApproximateDate(review) {
+		return true
+	}
+	if !review.CreatedAt.Equal(w.CreatedAt) {
+		return review.CreatedAt.After(w.CreatedAt)
+	}
+	return review.SourceID > w.SourceID
+}
+
+// Advance returns the watermark moved up to the newest of reviews whose
+// creation time can be ordered
+func (w Watermark) Advance(reviews []models.Review) Watermark {
+	for _, review := range reviews {
+		if review.CreatedAt.IsZero() || hasApproximateDate(review) {
+			continue
+		}
+		if w.IsZero() || w.Admits(review) {
+			w = Watermark{CreatedAt: review.CreatedAt, SourceID: review.SourceID}
+		}
+	}
+	return w
+}
+
+// Filter returns the reviews newer than the watermark
+func (w Watermark) Filter(reviews []models.Review) []models.Review {
+	if w.IsZero() {
+		return reviews
+	}
+	fresh := make([]models.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if w.Admits(review) {
+			fresh = append(fresh, review)
+		}
+	}
+	return fresh
+}
+
+// IncrementalScraper is implemented by scrapers that page newest first, so they
+// can stop requesting pages once they reach reviews already seen
+type IncrementalScraper interface {
+	Scraper
+
+	// ScrapeSince retrieves reviews newer than since
+	ScrapeSince(ctx context.Context, since Watermark) ([]models.Review, error)
+}
+
+// Watermarks records the last-seen watermark of each source, keyed by scraper
+// name. It is safe for concurrent use.
+type Watermarks struct {
+	mu         sync.RWMutex
+	watermarks map[string]Watermark
+}
+
+// NewWatermarks creates an empty watermark store
+func NewWatermarks() *Watermarks {
+	return &Watermarks{watermarks: make(map[string]Watermark)}
+}
+
+// Get returns the watermark of source, or the zero watermark if none is recorded
+func (w *Watermarks) Get(source string) Watermark {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.watermarks[source]
+}
+
+// Advance moves the watermark of source up to the newest of reviews
+func (w *Watermarks) Advance(source string, reviews []models.Review) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.watermarks[source] = w.watermarks[source].Advance(reviews)
+}
+
+// Batch is the reviews returned by one scraping run. The watermarks of its
+// sources advance only when it is committed, once its reviews are processed,
+// so a review whose processing failed can be returned again by the next run.
+type Batch struct {
+	Reviews []models.Review
+
+	scraped map[string][]models.Review // Every review each scraper returned, including those filtered out
+	seen    *Watermarks
+}
+
+// newBatch creates an empty batch advancing seen when committed
+func newBatch(seen *Watermarks) *Batch {
+	return &Batch{scraped: make(map[string][]models.Review), seen: seen}
+}
+
+// Commit advances the watermark of each source in the batch. A source's
+// watermark stops short of the oldest of its reviews in retry, so that review
+// and every newer one is returned again by the next run.
+func (b *Batch) Commit(retry []models.Review) {
+	retried := make(map[string]bool, len(retry))
+	for _, review := range retry {
+		retried[review.ID] = true
+	}
+	for source, reviews := range b.scraped {
+		b.seen.Advance(source, olderThanRetried(reviews, retried))
+	}
+}
+
+// olderThanRetried returns the reviews older than the oldest orderable one
+// whose ID is in retried, or all of them when none is
+func olderThanRetried(reviews []models.Review, retried map[string]bool) []models.Review {
+	var (
+		cutoff Watermark
+		found  bool
+	)
+	for _, review := range reviews {
+		if !retried[review.ID] || review.CreatedAt.IsZero() || hasApproximateDate(review) {
+			continue
+		}
+		if !found || !cutoff.Admits(review) {
+			cutoff = Watermark{CreatedAt: review.CreatedAt, SourceID: review.SourceID}
+			found = true
+		}
+	}
+	if !found {
+		return reviews
+	}
+
+	older := make([]models.Review, 0, len(reviews))
+	for _, review := range reviews {
+		if cutoff.Admits(review) || (review.CreatedAt.Equal(cutoff.CreatedAt) && review.SourceID == cutoff.SourceID) {
+			continue
+		}
+		older = append(older, review)
+	}
+	return older
+}
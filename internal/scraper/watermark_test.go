@@ -0,0 +1,170 @@
+package scraper
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeScraper returns whatever reviews it currently holds
+type fakeScraper struct {
+	mu      sync.Mutex
+	reviews []models.Review
+	err     error
+}
+
+func (s *fakeScraper) Name() string    { return "Fake" }
+func (s *fakeScraper) IsEnabled() bool { return true }
+
+func (s *fakeScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]models.Review(nil), s.reviews...), s.err
+}
+
+// add publishes more reviews for the next scrape
+func (s *fakeScraper) add(reviews ...models.Review) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reviews = append(s.reviews, reviews...)
+}
+
+// reviewAt returns a review with the given source ID created at day of January 2025
+func reviewAt(sourceID string, day int) models.Review {
+	return models.Review{
+		ID:        "fake-" + sourceID,
+		SourceID:  sourceID,
+		CreatedAt: time.Date(2025, 1, day, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// approximateReviewAt returns reviewAt with a date derived from a relative date
+func approximateReviewAt(sourceID string, day int) models.Review {
+	review := reviewAt(sourceID, day)
+	review.Metadata = map[string]interface{}{"date_approximate": true}
+	return review
+}
+
+// reviewIDs returns the IDs of reviews in order
+func reviewIDs(reviews []models.Review) []string {
+	ids := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		ids = append(ids, review.ID)
+	}
+	return ids
+}
+
+func TestWatermarkAdmits(t *testing.T) {
+	mark := Watermark{CreatedAt: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC), SourceID: "b"}
+
+	assert.True(t, Watermark{}.Admits(reviewAt("a", 1)), "nothing seen admits everything")
+	assert.False(t, mark.Admits(reviewAt("z", 1)), "older review")
+	assert.False(t, mark.Admits(reviewAt("b", 2)), "the watermark review itself")
+	assert.False(t, mark.Admits(reviewAt("a", 2)), "same time, lower ID")
+	assert.True(t, mark.Admits(reviewAt("c", 2)), "same time, higher ID")
+	assert.True(t, mark.Admits(reviewAt("a", 3)), "newer review")
+	assert.True(t, mark.Admits(models.Review{SourceID: "undated"}), "undated reviews cannot be ordered")
+	assert.True(t, mark.Admits(approximateReviewAt("a", 1)), "approximate dates cannot be ordered")
+}
+
+func TestWatermarkAdvance(t *testing.T) {
+	mark := Watermark{}.Advance([]models.Review{reviewAt("a", 3), reviewAt("b", 5), {SourceID: "undated"}, reviewAt("c", 4)})
+
+	assert.Equal(t, Watermark{CreatedAt: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC), SourceID: "b"}, mark)
+	assert.Equal(t, mark, mark.Advance([]models.Review{reviewAt("d", 1)}), "older reviews never move it back")
+	assert.Equal(t, mark, mark.Advance([]models.Review{approximateReviewAt("e", 9)}), "approximate dates never move it")
+}
+
+func TestScrapeAllReturnsOnlyNewReviewsOnLaterRuns(t *testing.T) {
+	fake := &fakeScraper{}
+	fake.add(reviewAt("1", 1), reviewAt("2", 2))
+	m := newTestManager(t, config.ScrapersConfig{})
+	m.scrapers = []Scraper{fake}
+
+	first, err := m.ScrapeAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-1", "fake-2"}, reviewIDs(first))
+
+	// Nothing new has been published
+	second, err := m.ScrapeAll(context.Background())
+	assert.NoError(t, err)
+	assert.Empty(t, second)
+
+	fake.add(reviewAt("3", 3))
+	third, err := m.ScrapeAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-3"}, reviewIDs(third))
+}
+
+func TestScrapeAllKeepsWatermarkWhenScraperFails(t *testing.T) {
+	fake := &fakeScraper{}
+	fake.add(reviewAt("1", 1))
+	m := newTestManager(t, config.ScrapersConfig{})
+	m.scrapers = []Scraper{fake}
+
+	fake.err = fmt.Errorf("upstream unavailable")
+	_, err := m.ScrapeAll(context.Background())
+	assert.Error(t, err)
+	assert.True(t, m.seen.Get("Fake").IsZero())
+
+	// The reviews of the failed run are returned once the source recovers
+	fake.err = nil
+	reviews, err := m.ScrapeAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-1"}, reviewIDs(reviews))
+}
+
+func TestBatchCommitStopsShortOfRetriedReviews(t *testing.T) {
+	fake := &fakeScraper{}
+	fake.add(reviewAt("1", 1), reviewAt("2", 2), reviewAt("3", 3))
+	m := newTestManager(t, config.ScrapersConfig{})
+	m.scrapers = []Scraper{fake}
+
+	run, err := m.StartRun()
+	require.NoError(t, err)
+	batch, err := run(context.Background())
+	require.NoError(t, err)
+	assert.True(t, m.seen.Get("Fake").IsZero(), "the watermark waits for the commit")
+	batch.Commit([]models.Review{reviewAt("2", 2)})
+
+	// The retried review and the newer one are returned again
+	reviews, err := m.ScrapeAll(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"fake-2", "fake-3"}, reviewIDs(reviews))
+}
+
+func TestG2ScraperStopsPagingAtWatermark(t *testing.T) {
+	var pages []string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		pages = append(pages, page)
+
+		// Newest first: page 1 holds days 9 and 8, page 2 days 7 and 6, and so on
+		newest := 11 - 2*len(pages)
+		fmt.Fprintf(w, `{"reviews": [{"id": "%d", "reviewDate": "2025-01-0%d"}, {"id": "%d", "reviewDate": "2025-01-0%d"}]}`,
+			newest, newest, newest-1, newest-1)
+	}))
+	defer server.Close()
+
+	s, err := NewG2Scraper(config.G2ScraperConfig{Enabled: true, ProductID: "infoblox-nios", APIKey: "key", MaxPages: 4},
+		config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.client.HTTPClient = server.Client()
+	s.client.Host = hostOf(t, server.URL)
+
+	since := Watermark{CreatedAt: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC), SourceID: "7"}
+	reviews, err := s.ScrapeSince(context.Background(), since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2"}, pages, "paging stops once the watermark is reached")
+	assert.Equal(t, []string{"g2-9", "g2-8"}, reviewIDs(reviews))
+}
@@ -0,0 +1,317 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"golang.org/x/time/rate"
+)
+
+// youTubeAPIURL is the YouTube Data API v3
+const youTubeAPIURL = "https://www.googleapis.com/youtube/v3"
+
+// defaultYouTubeMaxVideos is the number of searched videos read when none is configured
+const defaultYouTubeMaxVideos = 10
+
+// YouTubeCommentSnippet is the content of a YouTube comment
+type YouTubeCommentSnippet struct {
+	VideoID           string `json:"videoId"`
+	ChannelID         string `json:"channelId"`
+	TextDisplay       string `json:"textDisplay"`
+	TextOriginal      string `json:"textOriginal"`
+	AuthorDisplayName string `json:"authorDisplayName"`
+	AuthorChannelURL  string `json:"authorChannelUrl"`
+	LikeCount         int    `json:"likeCount"`
+	PublishedAt       string `json:"publishedAt"`
+	UpdatedAt         string `json:"updatedAt"`
+}
+
+// YouTubeCommentThread is a top-level comment and the size of its reply thread
+type YouTubeCommentThread struct {
+	ID      string `json:"id"`
+	Snippet struct {
+		VideoID         string `json:"videoId"`
+		TopLevelComment struct {
+			ID      string                `json:"id"`
+			Snippet YouTubeCommentSnippet `json:"snippet"`
+		} `json:"topLevelComment"`
+		TotalReplyCount int `json:"totalReplyCount"`
+	} `json:"snippet"`
+}
+
+// YouTubeCommentThreadsResponse is a page of the commentThreads.list API
+type YouTubeCommentThreadsResponse struct {
+	Items         []YouTubeCommentThread `json:"items"`
+	NextPageToken string                 `json:"nextPageToken"`
+}
+
+// YouTubeSearchResponse is a page of video results from the search.list API
+type YouTubeSearchResponse struct {
+	Items []struct {
+		ID struct {
+			VideoID string `json:"videoId"`
+		} `json:"id"`
+	} `json:"items"`
+}
+
+// youTubeErrorResponse is the error body returned by the YouTube Data API
+type youTubeErrorResponse struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Errors  []struct {
+			Reason string `json:"reason"`
+		} `json:"errors"`
+	} `json:"error"`
+}
+
+// YouTubeScraper implements the Scraper interface for top-level comments on
+// YouTube videos
+type YouTubeScraper struct {
+	config  config.YouTubeScraperConfig
+	client  *http.Client
+	limiter *rate.Limiter // Nil when no requests-per-minute budget is configured
+	baseURL string
+	enabled bool
+}
+
+// NewYouTubeScraper creates a new YouTube comment scraper
+func NewYouTubeScraper(cfg config.YouTubeScraperConfig, rates config.RateLimitConfig,
+	proxies config.ProxyConfig) (*YouTubeScraper, error) {
+
+	client, err := newScraperHTTPClient(proxies)
+	if err != nil {
+		return nil, err
+	}
+
+	return &YouTubeScraper{
+		config:  cfg,
+		client:  client,
+		limiter: newRequestLimiter(rates),
+		baseURL: youTubeAPIURL,
+		enabled: cfg.Enabled,
+	}, nil
+}
+
+func init() {
+	Register("youTube", func(cfg config.ScrapersConfig, rates config.RateLimitConfig, limiter *rate.Limiter) ([]Scraper, error) {
+		if !cfg.YouTube.Enabled {
+			return nil, nil
+		}
+		youTube, err := NewYouTubeScraper(cfg.YouTube, rates, cfg.ProxySettings)
+		if err != nil {
+			return nil, err
+		}
+		youTube.limiter = limiter
+		return []Scraper{youTube}, nil
+	})
+}
+
+// Name returns the name of this scraper
+func (s *YouTubeScraper) Name() string {
+	return "YouTube"
+}
+
+// IsEnabled returns whether this scraper is enabled
+func (s *YouTubeScraper) IsEnabled() bool {
+	return s.enabled
+}
+
+// Scrape retrieves top-level comments on the configured and searched videos
+func (s *YouTubeScraper) Scrape(ctx context.Context) ([]models.Review, error) {
+	return s.ScrapeSince(ctx, Watermark{})
+}
+
+// ScrapeSince retrieves top-level comments posted since the watermark. Each
+// video's comments are read newest first, so paging stops once a video's
+// comments reach the watermark.
+func (s *YouTubeScraper) ScrapeSince(ctx context.Context, since Watermark) ([]models.Review, error) {
+	videoIDs, err := s.videoIDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error searching YouTube videos: %w", err)
+	}
+
+	maxPages := s.config.MaxPages
+	if maxPages <= 0 {
+		maxPages = 1
+	}
+
+	var allReviews []models.Review
+	for _, videoID := range videoIDs {
+		pageToken := ""
+		for page := 0; page < maxPages; page++ {
+			// Respect context cancellation
+			if ctx.Err() != nil {
+				return allReviews, ctx.Err()
+			}
+
+			result, err := s.commentThreads(ctx, videoID, pageToken)
+			if err != nil {
+				return allReviews, fmt.Errorf("error fetching comments for video '%s': %w", videoID, err)
+			}
+
+			retrievedAt := time.Now()
+			caughtUp := false
+			for _, thread := range result.Items {
+				review := convertYouTubeCommentThread(thread, retrievedAt)
+				if !since.Admits(review) {
+					caughtUp = true
+					continue
+				}
+				allReviews = append(allReviews, review)
+			}
+
+			// Stop at the last page or once older comments are reached
+			pageToken = result.NextPageToken
+			if pageToken == "" || caughtUp {
+				break
+			}
+		}
+	}
+
+	return allReviews, nil
+}
+
+// videoIDs returns the configured videos followed by the newest videos found
+// by searching the configured channel and query, without repeats
+func (s *YouTubeScraper) videoIDs(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	var ids []string
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	for _, id := range s.config.VideoIDs {
+		add(id)
+	}
+	if s.config.ChannelID == "" && s.config.SearchQuery == "" {
+		return ids, nil
+	}
+
+	maxVideos := s.config.MaxVideos
+	if maxVideos <= 0 {
+		maxVideos = defaultYouTubeMaxVideos
+	}
+
+	params := url.Values{}
+	params.Set("part", "id")
+	params.Set("type", "video")
+	params.Set("order", "date")
+	params.Set("maxResults", strconv.Itoa(maxVideos))
+	if s.config.ChannelID != "" {
+		params.Set("channelId", s.config.ChannelID)
+	}
+	if s.config.SearchQuery != "" {
+		params.Set("q", s.config.SearchQuery)
+	}
+
+	var result YouTubeSearchResponse
+	if err := s.get(ctx, "search", params, &result); err != nil {
+		return ids, err
+	}
+	for _, item := range result.Items {
+		add(item.ID.VideoID)
+	}
+	return ids, nil
+}
+
+// commentThreads requests one page of a video's newest top-level comments
+func (s *YouTubeScraper) commentThreads(ctx context.Context, videoID, pageToken string) (*YouTubeCommentThreadsResponse, error) {
+	params := url.Values{}
+	params.Set("part", "snippet")
+	params.Set("videoId", videoID)
+	params.Set("order", "time")
+	params.Set("textFormat", "plainText")
+	params.Set("maxResults", "100")
+	if pageToken != "" {
+		params.Set("pageToken", pageToken)
+	}
+
+	var result YouTubeCommentThreadsResponse
+	if err := s.get(ctx, "commentThreads", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// get calls a YouTube Data API resource and decodes its JSON response into v
+func (s *YouTubeScraper) get(ctx context.Context, resource string, params url.Values, v interface{}) error {
+	params.Set("key", s.config.APIKey)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.baseURL+"/"+resource+"?"+params.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+
+	// Respect the shared request budget
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return err
+		}
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		// The request URL carries the API key, so keep it out of the error
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr youTubeErrorResponse
+		if json.NewDecoder(resp.Body).Decode(&apiErr) == nil && apiErr.Error.Message != "" {
+			if len(apiErr.Error.Errors) > 0 && apiErr.Error.Errors[0].Reason != "" {
+				return fmt.Errorf("YouTube API returned status %d (%s): %s", resp.StatusCode, apiErr.Error.Errors[0].Reason, apiErr.Error.Message)
+			}
+			return fmt.Errorf("YouTube API returned status %d: %s", resp.StatusCode, apiErr.Error.Message)
+		}
+		return fmt.Errorf("YouTube API returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("error parsing response: %w", err)
+	}
+	return nil
+}
+
+// convertYouTubeCommentThread maps a top-level YouTube comment into the
+// pipeline's review format
+func convertYouTubeCommentThread(thread YouTubeCommentThread, retrievedAt time.Time) models.Review {
+	comment := thread.Snippet.TopLevelComment
+	videoID := firstNonEmpty(thread.Snippet.VideoID, comment.Snippet.VideoID)
+	commentID := firstNonEmpty(comment.ID, thread.ID)
+
+	review := models.Review{
+		ID:          fmt.Sprintf("youtube-%s", commentID),
+		Source:      "youtube",
+		SourceID:    commentID,
+		Content:     cleanContent(firstNonEmpty(comment.Snippet.TextOriginal, comment.Snippet.TextDisplay)),
+		Author:      comment.Snippet.AuthorDisplayName,
+		URL:         fmt.Sprintf("https://www.youtube.com/watch?v=%s&lc=%s", videoID, commentID),
+		RetrievedAt: retrievedAt,
+		Metadata: map[string]interface{}{
+			"video_id":    videoID,
+			"like_count":  comment.Snippet.LikeCount,
+			"reply_count": thread.Snippet.TotalReplyCount,
+		},
+	}
+	if comment.Snippet.AuthorChannelURL != "" {
+		review.Metadata["author_channel_url"] = comment.Snippet.AuthorChannelURL
+	}
+	setReviewDate(&review, comment.Snippet.PublishedAt)
+
+	return review
+}
@@ -0,0 +1,165 @@
+package scraper
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadYouTubeFixture reads the saved commentThreads.list response
+func loadYouTubeFixture(t *testing.T) []byte {
+	data, err := os.ReadFile("testdata/youtube_comment_threads.json")
+	assert.NoError(t, err)
+	return data
+}
+
+// newTestYouTubeScraper returns a scraper calling server instead of the YouTube API
+func newTestYouTubeScraper(t *testing.T, server *httptest.Server, cfg config.YouTubeScraperConfig) *YouTubeScraper {
+	cfg.Enabled = true
+	cfg.APIKey = "test-key"
+	s, err := NewYouTubeScraper(cfg, config.RateLimitConfig{}, config.ProxyConfig{})
+	require.NoError(t, err)
+	s.baseURL = server.URL
+	return s
+}
+
+func TestConvertYouTubeCommentThreadsFromFixture(t *testing.T) {
+	var response YouTubeCommentThreadsResponse
+	assert.NoError(t, json.Unmarshal(loadYouTubeFixture(t), &response))
+	assert.Equal(t, "QURTSl9pMkNvb0pBdGZoT0FfQWRrbW1Y", response.NextPageToken)
+	assert.Len(t, response.Items, 2)
+	retrievedAt := time.Date(2025, 3, 5, 0, 0, 0, 0, time.UTC)
+
+	review := convertYouTubeCommentThread(response.Items[0], retrievedAt)
+	assert.Equal(t, "youtube-UgzK3pQ9xYv1aBcDeF14AaABAg", review.ID)
+	assert.Equal(t, "youtube", review.Source)
+	assert.Equal(t, "UgzK3pQ9xYv1aBcDeF14AaABAg", review.SourceID)
+	assert.Equal(t, "https://www.youtube.com/watch?v=dQw4w9WgXcQ&lc=UgzK3pQ9xYv1aBcDeF14AaABAg", review.URL)
+	assert.Equal(t, "@netadmin_pat", review.Author)
+	assert.Equal(t, "Great walkthrough. The NIOS upgrade at 12:40 failed for us & support was slow.", review.Content)
+	assert.Equal(t, time.Date(2025, 3, 4, 10, 30, 0, 0, time.UTC), review.CreatedAt)
+	assert.Equal(t, retrievedAt, review.RetrievedAt)
+	assert.Nil(t, review.Rating)
+	assert.Equal(t, 17, review.Metadata["like_count"])
+	assert.Equal(t, 3, review.Metadata["reply_count"])
+	assert.Equal(t, "dQw4w9WgXcQ", review.Metadata["video_id"])
+	assert.Equal(t, "http://www.youtube.com/@netadmin_pat", review.Metadata["author_channel_url"])
+
+	quiet := convertYouTubeCommentThread(response.Items[1], retrievedAt)
+	assert.Equal(t, 0, quiet.Metadata["like_count"])
+	assert.NotContains(t, quiet.Metadata, "author_channel_url")
+}
+
+func TestYouTubeScraperPagesUpToMaxPages(t *testing.T) {
+	fixture := loadYouTubeFixture(t)
+	var tokens []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/commentThreads", r.URL.Path)
+		assert.Equal(t, "test-key", r.URL.Query().Get("key"))
+		assert.Equal(t, "abc123", r.URL.Query().Get("videoId"))
+		assert.Equal(t, "time", r.URL.Query().Get("order"))
+		tokens = append(tokens, r.URL.Query().Get("pageToken"))
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	s := newTestYouTubeScraper(t, server, config.YouTubeScraperConfig{VideoIDs: []string{"abc123"}, MaxPages: 2})
+	reviews, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"", "QURTSl9pMkNvb0pBdGZoT0FfQWRrbW1Y"}, tokens, "paging stops at maxPages even with a next page token")
+	assert.Len(t, reviews, 4)
+}
+
+func TestYouTubeScraperStopsPagingAtWatermark(t *testing.T) {
+	fixture := loadYouTubeFixture(t)
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write(fixture)
+	}))
+	defer server.Close()
+
+	s := newTestYouTubeScraper(t, server, config.YouTubeScraperConfig{VideoIDs: []string{"abc123"}, MaxPages: 5})
+	since := Watermark{CreatedAt: time.Date(2025, 3, 3, 0, 0, 0, 0, time.UTC)}
+	reviews, err := s.ScrapeSince(context.Background(), since)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, []string{"youtube-UgzK3pQ9xYv1aBcDeF14AaABAg"}, reviewIDs(reviews))
+}
+
+func TestYouTubeScraperSearchesChannelForVideos(t *testing.T) {
+	var videos []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/search":
+			assert.Equal(t, "UCinfoblox", r.URL.Query().Get("channelId"))
+			assert.Equal(t, "bloxone review", r.URL.Query().Get("q"))
+			assert.Equal(t, "video", r.URL.Query().Get("type"))
+			assert.Equal(t, "3", r.URL.Query().Get("maxResults"))
+			fmt.Fprint(w, `{"items": [{"id": {"kind": "youtube#video", "videoId": "found1"}}, {"id": {"kind": "youtube#video", "videoId": "pinned"}}]}`)
+		case "/commentThreads":
+			videos = append(videos, r.URL.Query().Get("videoId"))
+			fmt.Fprint(w, `{"items": []}`)
+		default:
+			t.Errorf("unexpected request to %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	s := newTestYouTubeScraper(t, server, config.YouTubeScraperConfig{
+		VideoIDs:    []string{"pinned"},
+		ChannelID:   "UCinfoblox",
+		SearchQuery: "bloxone review",
+		MaxVideos:   3,
+	})
+	_, err := s.Scrape(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pinned", "found1"}, videos, "configured videos come first and are read once")
+}
+
+func TestYouTubeScraperReportsQuotaErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"error": {"code": 403, "message": "The request cannot be completed because you have exceeded your quota.", "errors": [{"reason": "quotaExceeded"}]}}`)
+	}))
+	defer server.Close()
+
+	s := newTestYouTubeScraper(t, server, config.YouTubeScraperConfig{VideoIDs: []string{"abc123"}})
+	_, err := s.Scrape(context.Background())
+
+	assert.EqualError(t, err, "error fetching comments for video 'abc123': YouTube API returned status 403 (quotaExceeded): "+
+		"The request cannot be completed because you have exceeded your quota.")
+}
+
+func TestYouTubeScraperKeepsAPIKeyOutOfErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	s := newTestYouTubeScraper(t, server, config.YouTubeScraperConfig{VideoIDs: []string{"abc123"}})
+	server.Close()
+
+	_, err := s.Scrape(context.Background())
+
+	if assert.Error(t, err) {
+		assert.NotContains(t, err.Error(), "test-key")
+	}
+}
+
+func TestManagerRegistersYouTubeScraper(t *testing.T) {
+	m := newTestManager(t, config.ScrapersConfig{YouTube: config.YouTubeScraperConfig{Enabled: true, APIKey: "key", VideoIDs: []string{"abc123"}}})
+
+	scrapers := m.GetScrapers()
+	if assert.Len(t, scrapers, 1) {
+		assert.Equal(t, "YouTube", scrapers[0].Name())
+	}
+}
@@ -0,0 +1,226 @@
+package sink
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// defaultBatchSize is used when the warehouse config leaves BatchSize unset
+const defaultBatchSize = 100
+
+// Columns lists the warehouse table columns in insert order
+var Columns = []string{
+	"review_id",
+	"source",
+	"tenant",
+	"sentiment_score",
+	"is_negative",
+	"is_relevant",
+	"intent_category",
+	"confidence",
+	"keywords",
+	"review_created_at",
+	"analyzed_at",
+}
+
+// tableNamePattern accepts plain or schema-qualified identifiers
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)*$`)
+
+// AnalysisSink receives analysis results for export, independently of notifications
+type AnalysisSink interface {
+	// Write records the analysis of a review; implementations may buffer it
+	Write(ctx context.Context, review models.Review, result models.AnalysisResult) error
+
+	// Flush exports any buffered results
+	Flush(ctx context.Context) error
+
+	// Close flushes and releases resources
+	Close(ctx context.Context) error
+
+	// Ping checks that the export destination is reachable
+	Ping(ctx context.Context) error
+}
+
+// NoopSink discards analysis results. It is used when no export is configured.
+type NoopSink struct{}
+
+// Write discards the result
+func (NoopSink) Write(ctx context.Context, review models.Review, result models.AnalysisResult) error {
+	return nil
+}
+
+// Flush does nothing
+func (NoopSink) Flush(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing
+func (NoopSink) Close(ctx context.Context) error {
+	return nil
+}
+
+// Ping always succeeds
+func (NoopSink) Ping(ctx context.Context) error {
+	return nil
+}
+
+// row is a buffered warehouse record
+type row []interface{}
+
+// SQLSink batches analysis results into multi-row INSERTs on a database/sql
+// connection, so it works with any warehouse that has a Go SQL driver
+type SQLSink struct {
+	db          *sql.DB
+	table       string
+	batchSize   int
+	placeholder string
+	now         func() time.Time
+
+	mu      sync.Mutex
+	pending []row
+}
+
+// NewSQLSink creates a sink writing to the configured table on db
+func NewSQLSink(db *sql.DB, cfg config.WarehouseConfig) (*SQLSink, error) {
+	if !tableNamePattern.MatchString(cfg.Table) {
+		return nil, fmt.Errorf("invalid warehouse table name %q", cfg.Table)
+	}
+
+	batchSize := cfg.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	placeholder := cfg.Placeholder
+	if placeholder == "" {
+		placeholder = "?"
+	}
+
+	return &SQLSink{
+		db:          db,
+		table:       cfg.Table,
+		batchSize:   batchSize,
+		placeholder: placeholder,
+		now:         time.Now,
+	}, nil
+}
+
+// New opens the configured warehouse, or returns a NoopSink when export is disabled
+func New(cfg config.WarehouseConfig) (AnalysisSink, error) {
+	if !cfg.Enabled {
+		return NoopSink{}, nil
+	}
+
+	db, err := sql.Open(cfg.Driver, cfg.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open warehouse connection: %w", err)
+	}
+
+	s, err := NewSQLSink(db, cfg)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Write buffers the result and inserts the batch once it is full
+func (s *SQLSink) Write(ctx context.Context, review models.Review, result models.AnalysisResult) error {
+	tenant, _ := review.Metadata["tenant"].(string)
+
+	s.mu.Lock()
+	s.pending = append(s.pending, row{
+		review.ID,
+		review.Source,
+		tenant,
+		result.SentimentScore,
+		result.IsNegative,
+		result.IsRelevant,
+		result.IntentCategory,
+		result.Confidence,
+		strings.Join(result.Keywords, ","),
+		review.CreatedAt,
+		s.now(),
+	})
+	full := len(s.pending) >= s.batchSize
+	s.mu.Unlock()
+
+	if full {
+		return s.Flush(ctx)
+	}
+	return nil
+}
+
+// Flush inserts all buffered results in batches. A batch that fails to insert
+// is dropped so a broken warehouse cannot grow the buffer without bound.
+func (s *SQLSink) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	for start := 0; start < len(pending); start += s.batchSize {
+		end := start + s.batchSize
+		if end > len(pending) {
+			end = len(pending)
+		}
+		if err := s.insert(ctx, pending[start:end]); err != nil {
+			return fmt.Errorf("failed to export %d analysis results: %w", len(pending)-start, err)
+		}
+	}
+	return nil
+}
+
+// Close flushes buffered results and closes the database connection
+func (s *SQLSink) Close(ctx context.Context) error {
+	flushErr := s.Flush(ctx)
+	if err := s.db.Close(); err != nil && flushErr == nil {
+		return fmt.Errorf("failed to close warehouse connection: %w", err)
+	}
+	return flushErr
+}
+
+// Ping checks the warehouse connection
+func (s *SQLSink) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to reach warehouse: %w", err)
+	}
+	return nil
+}
+
+// insert writes one batch with a single multi-row INSERT
+func (s *SQLSink) insert(ctx context.Context, rows []row) error {
+	var query strings.Builder
+	fmt.Fprintf(&query, "INSERT INTO %s (%s) VALUES ", s.table, strings.Join(Columns, ", "))
+
+	args := make([]interface{}, 0, len(rows)*len(Columns))
+	for i, r := range rows {
+		if i > 0 {
+			query.WriteString(", ")
+		}
+		query.WriteString("(")
+		for j := range r {
+			if j > 0 {
+				query.WriteString(", ")
+			}
+			args = append(args, r[j])
+			if s.placeholder == "$" {
+				fmt.Fprintf(&query, "$%d", len(args))
+			} else {
+				query.WriteString("?")
+			}
+		}
+		query.WriteString(")")
+	}
+
+	_, err := s.db.ExecContext(ctx, query.String(), args...)
+	return err
+}
@@ -0,0 +1,156 @@
+package sink
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testAnalyzedAt = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	testCreatedAt  = time.Date(2025, 2, 28, 9, 30, 0, 0, time.UTC)
+)
+
+// newMockSink returns a SQLSink backed by sqlmock with a fixed clock
+func newMockSink(t *testing.T, cfg config.WarehouseConfig) (*SQLSink, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	s, err := NewSQLSink(db, cfg)
+	assert.NoError(t, err)
+	s.now = func() time.Time { return testAnalyzedAt }
+	return s, mock
+}
+
+// testAnalysis returns a review and its analysis result
+func testAnalysis(id string) (models.Review, models.AnalysisResult) {
+	review := models.Review{
+		ID:        id,
+		Source:    "twitter",
+		CreatedAt: testCreatedAt,
+		Metadata:  map[string]interface{}{"tenant": "ddi"},
+	}
+	result := models.AnalysisResult{
+		ReviewID:       id,
+		SentimentScore: -0.4,
+		IsNegative:     true,
+		IsRelevant:     true,
+		IntentCategory: "bug_report",
+		Confidence:     0.8,
+		Keywords:       []string{"nios", "upgrade"},
+	}
+	return review, result
+}
+
+// expectedArgs returns the insert arguments for testAnalysis(id)
+func expectedArgs(id string) []interface{} {
+	return []interface{}{id, "twitter", "ddi", -0.4, true, true, "bug_report", 0.8, "nios,upgrade", testCreatedAt, testAnalyzedAt}
+}
+
+const insertPrefix = "INSERT INTO analytics.review_analysis (review_id, source, tenant, sentiment_score, is_negative, " +
+	"is_relevant, intent_category, confidence, keywords, review_created_at, analyzed_at) VALUES "
+
+func TestSQLSinkBatchesInserts(t *testing.T) {
+	s, mock := newMockSink(t, config.WarehouseConfig{Table: "analytics.review_analysis", BatchSize: 2})
+	ctx := context.Background()
+
+	row := "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+	var firstBatch []interface{}
+	firstBatch = append(firstBatch, expectedArgs("r1")...)
+	firstBatch = append(firstBatch, expectedArgs("r2")...)
+	mock.ExpectExec(regexp.QuoteMeta(insertPrefix + row + ", " + row)).
+		WithArgs(toDriverValues(firstBatch)...).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta(insertPrefix + row)).
+		WithArgs(toDriverValues(expectedArgs("r3"))...).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	for _, id := range []string{"r1", "r2"} {
+		review, result := testAnalysis(id)
+		assert.NoError(t, s.Write(ctx, review, result))
+	}
+
+	// The third result stays buffered until flushed
+	review, result := testAnalysis("r3")
+	assert.NoError(t, s.Write(ctx, review, result))
+	assert.Len(t, s.pending, 1)
+
+	assert.NoError(t, s.Flush(ctx))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLSinkNumberedPlaceholders(t *testing.T) {
+	s, mock := newMockSink(t, config.WarehouseConfig{Table: "review_analysis", BatchSize: 10, Placeholder: "$"})
+
+	mock.ExpectExec(regexp.QuoteMeta("VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11), " +
+		"($12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)")).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectClose()
+
+	for _, id := range []string{"r1", "r2"} {
+		review, result := testAnalysis(id)
+		assert.NoError(t, s.Write(context.Background(), review, result))
+	}
+
+	assert.NoError(t, s.Close(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLSinkDropsFailedBatch(t *testing.T) {
+	s, mock := newMockSink(t, config.WarehouseConfig{Table: "review_analysis", BatchSize: 1})
+	mock.ExpectExec("INSERT INTO review_analysis").WillReturnError(errors.New("warehouse unavailable"))
+
+	review, result := testAnalysis("r1")
+	err := s.Write(context.Background(), review, result)
+
+	assert.ErrorContains(t, err, "failed to export 1 analysis results: warehouse unavailable")
+	assert.Empty(t, s.pending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestNewSQLSinkRejectsUnsafeTableName(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	_, err = NewSQLSink(db, config.WarehouseConfig{Table: "reviews; DROP TABLE users"})
+	assert.Error(t, err)
+}
+
+func TestNewReturnsNoopWhenDisabled(t *testing.T) {
+	s, err := New(config.WarehouseConfig{})
+
+	assert.NoError(t, err)
+	assert.IsType(t, NoopSink{}, s)
+}
+
+// toDriverValues converts expected arguments for sqlmock.WithArgs
+func toDriverValues(args []interface{}) []driver.Value {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		values[i] = arg
+	}
+	return values
+}
+
+func TestSQLSinkPing(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	assert.NoError(t, err)
+	s, err := NewSQLSink(db, config.WarehouseConfig{Table: "review_analysis"})
+	assert.NoError(t, err)
+
+	mock.ExpectPing()
+	assert.NoError(t, s.Ping(context.Background()))
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	assert.EqualError(t, s.Ping(context.Background()), "failed to reach warehouse: connection refused")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
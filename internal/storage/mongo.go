@@ -0,0 +1,218 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+	"go.mongodb.org/mongo-driver/v2/mongo/readpref"
+)
+
+// Collections of the mongodb review store
+const (
+	seenReviewsCollection   = "seen_reviews"
+	reviewsCollection       = "reviews"
+	notificationsCollection = "notifications"
+)
+
+// sourceKeys indexes documents by the source and source ID of their review
+var sourceKeys = bson.D{{Key: "source", Value: 1}, {Key: "sourceId", Value: 1}}
+
+// mongoIndexes are the indexes CreateIndexes ensures on each collection
+var mongoIndexes = map[string][]mongo.IndexModel{
+	seenReviewsCollection: {
+		{Keys: sourceKeys, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "seenAt", Value: 1}}},
+	},
+	reviewsCollection: {
+		{Keys: sourceKeys, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+	},
+	notificationsCollection: {
+		{Keys: sourceKeys},
+		{Keys: bson.D{{Key: "createdAt", Value: 1}}},
+	},
+}
+
+// mongoCollection is the part of a mongo collection MongoStore uses, so tests
+// can replace it
+type mongoCollection interface {
+	CountDocuments(ctx context.Context, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error)
+	ReplaceOne(ctx context.Context, filter interface{}, replacement interface{}, opts ...options.Lister[options.ReplaceOptions]) (*mongo.UpdateResult, error)
+	DeleteMany(ctx context.Context, filter interface{}, opts ...options.Lister[options.DeleteManyOptions]) (*mongo.DeleteResult, error)
+}
+
+// seenDocument records when a review was notified
+type seenDocument struct {
+	Source   string    `bson:"source"`
+	SourceID string    `bson:"sourceId"`
+	SeenAt   time.Time `bson:"seenAt"`
+}
+
+// reviewDocument is a stored review
+type reviewDocument struct {
+	Source    string        `bson:"source"`
+	SourceID  string        `bson:"sourceId"`
+	CreatedAt time.Time     `bson:"createdAt"`
+	Review    models.Review `bson:"review"`
+}
+
+// notificationDocument is a stored notification, keyed by its ID
+type notificationDocument struct {
+	ID           string              `bson:"_id"`
+	Source       string              `bson:"source"`
+	SourceID     string              `bson:"sourceId"`
+	CreatedAt    time.Time           `bson:"createdAt"`
+	Status       string              `bson:"status"`
+	Notification models.Notification `bson:"notification"`
+}
+
+// MongoStore keeps seen reviews, reviews and notifications in collections of
+// a mongodb database
+type MongoStore struct {
+	client *mongo.Client
+	db     *mongo.Database
+
+	seen          mongoCollection
+	reviews       mongoCollection
+	notifications mongoCollection
+
+	ttl time.Duration
+	now func() time.Time
+}
+
+// OpenMongoStore connects to the mongodb database in cfg and ensures its
+// indexes exist
+func OpenMongoStore(ctx context.Context, cfg config.DatabaseConfig, ttl time.Duration) (*MongoStore, error) {
+	client, err := mongo.Connect(options.Client().ApplyURI(cfg.MongoURI()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open review store: %w", err)
+	}
+	s := NewMongoStore(client, cfg.DBName, ttl)
+	if err := s.CreateIndexes(ctx); err != nil {
+		client.Disconnect(ctx)
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewMongoStore creates a store on the named database of client that forgets
+// reviews after ttl
+func NewMongoStore(client *mongo.Client, database string, ttl time.Duration) *MongoStore {
+	db := client.Database(database)
+	return &MongoStore{
+		client:        client,
+		db:            db,
+		seen:          db.Collection(seenReviewsCollection),
+		reviews:       db.Collection(reviewsCollection),
+		notifications: db.Collection(notificationsCollection),
+		ttl:           ttl,
+		now:           time.Now,
+	}
+}
+
+// CreateIndexes creates the indexes of the store's collections if they do
+// not exist yet
+func (s *MongoStore) CreateIndexes(ctx context.Context) error {
+	for name, indexes := range mongoIndexes {
+		if _, err := s.db.Collection(name).Indexes().CreateMany(ctx, indexes); err != nil {
+			return fmt.Errorf("failed to create %s indexes: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// sourceFilter selects the documents of review
+func sourceFilter(review models.Review) bson.D {
+	source, sourceID := reviewKey(review)
+	return bson.D{{Key: "source", Value: source}, {Key: "sourceId", Value: sourceID}}
+}
+
+// HasSeen reports whether review was marked seen within the TTL
+func (s *MongoStore) HasSeen(ctx context.Context, review models.Review) (bool, error) {
+	filter := append(sourceFilter(review), bson.E{Key: "seenAt", Value: bson.D{{Key: "$gt", Value: s.now().Add(-s.ttl)}}})
+	count, err := s.seen.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to look up seen review: %w", err)
+	}
+	return count > 0, nil
+}
+
+// MarkSeen records that review was notified, renewing an earlier record
+func (s *MongoStore) MarkSeen(ctx context.Context, review models.Review) error {
+	source, sourceID := reviewKey(review)
+	document := seenDocument{Source: source, SourceID: sourceID, SeenAt: s.now()}
+
+	if _, err := s.seen.ReplaceOne(ctx, sourceFilter(review), document, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to mark review seen: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes the reviews marked seen longer ago than the TTL
+func (s *MongoStore) PurgeExpired(ctx context.Context) (int64, error) {
+	filter := bson.D{{Key: "seenAt", Value: bson.D{{Key: "$lte", Value: s.now().Add(-s.ttl)}}}}
+	result, err := s.seen.DeleteMany(ctx, filter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge seen reviews: %w", err)
+	}
+	return result.DeletedCount, nil
+}
+
+// SaveReview stores review, replacing an earlier copy of the same source review
+func (s *MongoStore) SaveReview(ctx context.Context, review models.Review) error {
+	source, sourceID := reviewKey(review)
+	document := reviewDocument{Source: source, SourceID: sourceID, CreatedAt: review.CreatedAt, Review: review}
+
+	if _, err := s.reviews.ReplaceOne(ctx, sourceFilter(review), document, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+	return nil
+}
+
+// SaveNotification stores notification, replacing an earlier copy with the same ID
+func (s *MongoStore) SaveNotification(ctx context.Context, notification models.Notification) error {
+	source, sourceID := reviewKey(notification.Review)
+	document := notificationDocument{
+		ID:           notification.ID,
+		Source:       source,
+		SourceID:     sourceID,
+		CreatedAt:    notification.SentAt,
+		Status:       notification.Status,
+		Notification: notification,
+	}
+
+	filter := bson.D{{Key: "_id", Value: notification.ID}}
+	if _, err := s.notifications.ReplaceOne(ctx, filter, document, options.Replace().SetUpsert(true)); err != nil {
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+	return nil
+}
+
+// HasNotification reports whether a notification about review with status is stored
+func (s *MongoStore) HasNotification(ctx context.Context, review models.Review, status string) (bool, error) {
+	filter := append(sourceFilter(review), bson.E{Key: "status", Value: status})
+	count, err := s.notifications.CountDocuments(ctx, filter, options.Count().SetLimit(1))
+	if err != nil {
+		return false, fmt.Errorf("failed to look up notifications: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Ping checks the database connection
+func (s *MongoStore) Ping(ctx context.Context) error {
+	if err := s.client.Ping(ctx, readpref.Primary()); err != nil {
+		return fmt.Errorf("failed to reach review store: %w", err)
+	}
+	return nil
+}
+
+// Close disconnects from the database
+func (s *MongoStore) Close() error {
+	return s.client.Disconnect(context.Background())
+}
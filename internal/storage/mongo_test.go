@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/mongo"
+	"go.mongodb.org/mongo-driver/v2/mongo/options"
+)
+
+// fakeCollection is an in-memory collection that understands the equality,
+// $gt and $lte filters MongoStore uses. Every call fails with err when set.
+type fakeCollection struct {
+	docs []bson.M
+	err  error
+}
+
+// toDocument encodes v the way the driver would send it
+func toDocument(v interface{}) bson.M {
+	data, err := bson.Marshal(v)
+	if err != nil {
+		panic(err)
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		panic(err)
+	}
+	return doc
+}
+
+// matches reports whether doc satisfies filter
+func matches(doc, filter bson.M) bool {
+	for key, want := range filter {
+		got, ok := doc[key]
+		if !ok {
+			return false
+		}
+		conditions, isCondition := want.(bson.D)
+		if !isCondition {
+			if got != want {
+				return false
+			}
+			continue
+		}
+		for _, condition := range conditions {
+			value, bound := got.(bson.DateTime), condition.Value.(bson.DateTime)
+			switch condition.Key {
+			case "$gt":
+				if value <= bound {
+					return false
+				}
+			case "$lte":
+				if value > bound {
+					return false
+				}
+			default:
+				panic("unsupported operator " + condition.Key)
+			}
+		}
+	}
+	return true
+}
+
+func (c *fakeCollection) CountDocuments(ctx context.Context, filter interface{}, opts ...options.Lister[options.CountOptions]) (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	var count int64
+	for _, doc := range c.docs {
+		if matches(doc, toDocument(filter)) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (c *fakeCollection) ReplaceOne(ctx context.Context, filter interface{}, replacement interface{}, opts ...options.Lister[options.ReplaceOptions]) (*mongo.UpdateResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	var args options.ReplaceOptions
+	for _, opt := range opts {
+		for _, set := range opt.List() {
+			set(&args)
+		}
+	}
+
+	for i, doc := range c.docs {
+		if matches(doc, toDocument(filter)) {
+			c.docs[i] = toDocument(replacement)
+			return &mongo.UpdateResult{MatchedCount: 1, ModifiedCount: 1}, nil
+		}
+	}
+	if args.Upsert == nil || !*args.Upsert {
+		return &mongo.UpdateResult{}, nil
+	}
+	c.docs = append(c.docs, toDocument(replacement))
+	return &mongo.UpdateResult{UpsertedCount: 1}, nil
+}
+
+func (c *fakeCollection) DeleteMany(ctx context.Context, filter interface{}, opts ...options.Lister[options.DeleteManyOptions]) (*mongo.DeleteResult, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	kept := c.docs[:0]
+	for _, doc := range c.docs {
+		if !matches(doc, toDocument(filter)) {
+			kept = append(kept, doc)
+		}
+	}
+	deleted := int64(len(c.docs) - len(kept))
+	c.docs = kept
+	return &mongo.DeleteResult{DeletedCount: deleted}, nil
+}
+
+// newFakeMongoStore returns a MongoStore on in-memory collections with a
+// clock the test controls
+func newFakeMongoStore(now *time.Time) (*MongoStore, *fakeCollection, *fakeCollection, *fakeCollection) {
+	seen, reviews, notifications := &fakeCollection{}, &fakeCollection{}, &fakeCollection{}
+	s := &MongoStore{
+		seen:          seen,
+		reviews:       reviews,
+		notifications: notifications,
+		ttl:           24 * time.Hour,
+		now:           func() time.Time { return *now },
+	}
+	return s, seen, reviews, notifications
+}
+
+func TestMongoStoreMarksReviewsSeen(t *testing.T) {
+	ctx := context.Background()
+	now := testNow
+	s, seenReviews, _, _ := newFakeMongoStore(&now)
+	review := models.Review{ID: "g2-1", Source: "G2", SourceID: "1"}
+
+	seen, err := s.HasSeen(ctx, review)
+	assert.NoError(t, err)
+	assert.False(t, seen)
+
+	assert.NoError(t, s.MarkSeen(ctx, review))
+	assert.NoError(t, s.MarkSeen(ctx, review))
+	assert.Len(t, seenReviews.docs, 1, "marking a review again renews its record")
+
+	seen, err = s.HasSeen(ctx, models.Review{ID: "rescraped", Source: "G2", SourceID: "1"})
+	assert.NoError(t, err)
+	assert.True(t, seen)
+
+	seen, _ = s.HasSeen(ctx, models.Review{ID: "g2-1", Source: "Trustpilot", SourceID: "1"})
+	assert.False(t, seen, "the same source ID from another source is a different review")
+
+	now = now.Add(25 * time.Hour)
+	seen, _ = s.HasSeen(ctx, review)
+	assert.False(t, seen, "a review seen longer ago than the TTL is forgotten")
+}
+
+func TestMongoStorePurgesExpiredReviews(t *testing.T) {
+	ctx := context.Background()
+	now := testNow
+	s, seenReviews, _, _ := newFakeMongoStore(&now)
+
+	assert.NoError(t, s.MarkSeen(ctx, models.Review{ID: "old", Source: "G2"}))
+	now = now.Add(23 * time.Hour)
+	assert.NoError(t, s.MarkSeen(ctx, models.Review{ID: "recent", Source: "G2"}))
+	now = now.Add(2 * time.Hour)
+
+	removed, err := s.PurgeExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+	if assert.Len(t, seenReviews.docs, 1) {
+		assert.Equal(t, "recent", seenReviews.docs[0]["sourceId"])
+	}
+}
+
+func TestMongoStoreSavesReviews(t *testing.T) {
+	ctx := context.Background()
+	now := testNow
+	s, _, reviews, _ := newFakeMongoStore(&now)
+	review := models.Review{ID: "g2-1", Source: "G2", SourceID: "1", Content: "Slow", CreatedAt: testNow}
+
+	assert.NoError(t, s.SaveReview(ctx, review))
+	review.Content = "Slow, edited"
+	assert.NoError(t, s.SaveReview(ctx, review))
+
+	if assert.Len(t, reviews.docs, 1, "saving a review again replaces it") {
+		doc := reviews.docs[0]
+		assert.Equal(t, "G2", doc["source"])
+		assert.Equal(t, "1", doc["sourceId"])
+		assert.Equal(t, bson.NewDateTimeFromTime(testNow), doc["createdAt"])
+
+		var stored reviewDocument
+		data, _ := bson.Marshal(doc)
+		assert.NoError(t, bson.Unmarshal(data, &stored))
+		assert.Equal(t, "Slow, edited", stored.Review.Content)
+	}
+}
+
+func TestMongoStoreSavesNotifications(t *testing.T) {
+	ctx := context.Background()
+	now := testNow
+	s, _, _, notifications := newFakeMongoStore(&now)
+	notification := models.Notification{
+		ID:     "n1",
+		Review: models.Review{ID: "g2-1", Source: "G2", SourceID: "1"},
+		SentAt: testNow,
+		Status: "sent",
+	}
+
+	assert.NoError(t, s.SaveNotification(ctx, notification))
+	notification.Status = "acknowledged"
+	assert.NoError(t, s.SaveNotification(ctx, notification))
+	assert.NoError(t, s.SaveNotification(ctx, models.Notification{ID: "n2", Review: notification.Review, SentAt: testNow}))
+
+	if assert.Len(t, notifications.docs, 2) {
+		doc := notifications.docs[0]
+		assert.Equal(t, "n1", doc["_id"])
+		assert.Equal(t, "acknowledged", doc["status"])
+		assert.Equal(t, "G2", doc["source"])
+		assert.Equal(t, "1", doc["sourceId"])
+		assert.Equal(t, bson.NewDateTimeFromTime(testNow), doc["createdAt"])
+	}
+
+	found, err := s.HasNotification(ctx, notification.Review, "acknowledged")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	found, err = s.HasNotification(ctx, notification.Review, "actioned")
+	assert.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestMongoStoreReportsErrors(t *testing.T) {
+	ctx := context.Background()
+	now := testNow
+	s, seenReviews, reviews, notifications := newFakeMongoStore(&now)
+	failure := errors.New("server selection timeout")
+	seenReviews.err, reviews.err, notifications.err = failure, failure, failure
+	review := models.Review{ID: "g2-1", Source: "G2"}
+
+	seen, err := s.HasSeen(ctx, review)
+	assert.ErrorIs(t, err, failure)
+	assert.False(t, seen)
+	assert.ErrorIs(t, s.MarkSeen(ctx, review), failure)
+	_, err = s.PurgeExpired(ctx)
+	assert.ErrorIs(t, err, failure)
+	assert.ErrorIs(t, s.SaveReview(ctx, review), failure)
+	assert.ErrorIs(t, s.SaveNotification(ctx, models.Notification{ID: "n1", Review: review}), failure)
+}
+
+func TestMongoIndexes(t *testing.T) {
+	keys := func(collection string) []bson.D {
+		var all []bson.D
+		for _, index := range mongoIndexes[collection] {
+			all = append(all, index.Keys.(bson.D))
+		}
+		return all
+	}
+	createdAt := bson.D{{Key: "createdAt", Value: 1}}
+
+	assert.Equal(t, []bson.D{sourceKeys, createdAt}, keys(reviewsCollection))
+	assert.Equal(t, []bson.D{sourceKeys, createdAt}, keys(notificationsCollection))
+	assert.Equal(t, []bson.D{sourceKeys, {{Key: "seenAt", Value: 1}}}, keys(seenReviewsCollection))
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// schema creates the store's tables and indexes if they do not exist yet.
+// Notifications are kept in review_notifications, apart from the table the
+// notifier itself writes to.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS seen_reviews (
+	source TEXT NOT NULL,
+	source_id TEXT NOT NULL,
+	seen_at TIMESTAMPTZ NOT NULL,
+	PRIMARY KEY (source, source_id)
+)`,
+	`CREATE TABLE IF NOT EXISTS reviews (
+	source TEXT NOT NULL,
+	source_id TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	payload JSONB NOT NULL,
+	PRIMARY KEY (source, source_id)
+)`,
+	`CREATE INDEX IF NOT EXISTS reviews_created_at ON reviews (created_at)`,
+	`CREATE TABLE IF NOT EXISTS review_notifications (
+	id TEXT PRIMARY KEY,
+	source TEXT NOT NULL,
+	source_id TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL,
+	status TEXT NOT NULL,
+	payload JSONB NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS review_notifications_source ON review_notifications (source, source_id)`,
+	`CREATE INDEX IF NOT EXISTS review_notifications_created_at ON review_notifications (created_at)`,
+}
+
+// SQLStore keeps seen reviews, reviews and notifications in a postgres database
+type SQLStore struct {
+	db  *sql.DB
+	ttl time.Duration
+	now func() time.Time
+}
+
+// NewSQLStore creates a store on db that forgets reviews after ttl
+func NewSQLStore(db *sql.DB, ttl time.Duration) *SQLStore {
+	return &SQLStore{db: db, ttl: ttl, now: time.Now}
+}
+
+// CreateSchema creates the store's tables if they do not exist yet
+func (s *SQLStore) CreateSchema(ctx context.Context) error {
+	for _, statement := range schema {
+		if _, err := s.db.ExecContext(ctx, statement); err != nil {
+			return fmt.Errorf("failed to create review store schema: %w", err)
+		}
+	}
+	return nil
+}
+
+// HasSeen reports whether review was marked seen within the TTL
+func (s *SQLStore) HasSeen(ctx context.Context, review models.Review) (bool, error) {
+	source, sourceID := reviewKey(review)
+
+	var seen bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM seen_reviews WHERE source = $1 AND source_id = $2 AND seen_at > $3)",
+		source, sourceID, s.now().Add(-s.ttl),
+	).Scan(&seen)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up seen review: %w", err)
+	}
+	return seen, nil
+}
+
+// MarkSeen records that review was notified, renewing an earlier record
+func (s *SQLStore) MarkSeen(ctx context.Context, review models.Review) error {
+	source, sourceID := reviewKey(review)
+
+	_, err := s.db.ExecContext(ctx,
+		"INSERT INTO seen_reviews (source, source_id, seen_at) VALUES ($1, $2, $3) "+
+			"ON CONFLICT (source, source_id) DO UPDATE SET seen_at = EXCLUDED.seen_at",
+		source, sourceID, s.now(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to mark review seen: %w", err)
+	}
+	return nil
+}
+
+// PurgeExpired deletes the reviews marked seen longer ago than the TTL
+func (s *SQLStore) PurgeExpired(ctx context.Context) (int64, error) {
+	result, err := s.db.ExecContext(ctx, "DELETE FROM seen_reviews WHERE seen_at <= $1", s.now().Add(-s.ttl))
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge seen reviews: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// SaveReview stores review, replacing an earlier copy of the same source review
+func (s *SQLStore) SaveReview(ctx context.Context, review models.Review) error {
+	source, sourceID := reviewKey(review)
+	payload, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("failed to encode review: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO reviews (source, source_id, created_at, payload) VALUES ($1, $2, $3, $4) "+
+			"ON CONFLICT (source, source_id) DO UPDATE SET created_at = EXCLUDED.created_at, payload = EXCLUDED.payload",
+		source, sourceID, review.CreatedAt, payload,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save review: %w", err)
+	}
+	return nil
+}
+
+// SaveNotification stores notification, replacing an earlier copy with the same ID
+func (s *SQLStore) SaveNotification(ctx context.Context, notification models.Notification) error {
+	source, sourceID := reviewKey(notification.Review)
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx,
+		"INSERT INTO review_notifications (id, source, source_id, created_at, status, payload) VALUES ($1, $2, $3, $4, $5, $6) "+
+			"ON CONFLICT (id) DO UPDATE SET status = EXCLUDED.status, payload = EXCLUDED.payload",
+		notification.ID, source, sourceID, notification.SentAt, notification.Status, payload,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+	return nil
+}
+
+// HasNotification reports whether a notification about review with status is stored
+func (s *SQLStore) HasNotification(ctx context.Context, review models.Review, status string) (bool, error) {
+	source, sourceID := reviewKey(review)
+
+	var found bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM review_notifications WHERE source = $1 AND source_id = $2 AND status = $3)",
+		source, sourceID, status,
+	).Scan(&found)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up notifications: %w", err)
+	}
+	return found, nil
+}
+
+// Ping checks the database connection
+func (s *SQLStore) Ping(ctx context.Context) error {
+	if err := s.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("failed to reach review store: %w", err)
+	}
+	return nil
+}
+
+// Close closes the database connection
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
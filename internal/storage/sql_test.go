@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testNow = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// newMockStore returns a SQLStore backed by sqlmock with a fixed clock
+func newMockStore(t *testing.T) (*SQLStore, sqlmock.Sqlmock) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+
+	s := NewSQLStore(db, 24*time.Hour)
+	s.now = func() time.Time { return testNow }
+	return s, mock
+}
+
+func TestSQLStoreCreatesSchema(t *testing.T) {
+	s, mock := newMockStore(t)
+	for _, statement := range []string{
+		"CREATE TABLE IF NOT EXISTS seen_reviews",
+		"CREATE TABLE IF NOT EXISTS reviews",
+		"CREATE INDEX IF NOT EXISTS reviews_created_at ON reviews (created_at)",
+		"CREATE TABLE IF NOT EXISTS review_notifications",
+		"CREATE INDEX IF NOT EXISTS review_notifications_source ON review_notifications (source, source_id)",
+		"CREATE INDEX IF NOT EXISTS review_notifications_created_at ON review_notifications (created_at)",
+	} {
+		mock.ExpectExec(regexp.QuoteMeta(statement)).WillReturnResult(sqlmock.NewResult(0, 0))
+	}
+
+	assert.NoError(t, s.CreateSchema(context.Background()))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStoreMarkAndCheckSeen(t *testing.T) {
+	s, mock := newMockStore(t)
+	review := models.Review{ID: "g2-1", Source: "G2", SourceID: "1"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO seen_reviews (source, source_id, seen_at) VALUES ($1, $2, $3) ON CONFLICT")).
+		WithArgs("G2", "1", testNow).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM seen_reviews WHERE source = $1 AND source_id = $2 AND seen_at > $3)")).
+		WithArgs("G2", "1", testNow.Add(-24*time.Hour)).
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	assert.NoError(t, s.MarkSeen(context.Background(), review))
+	seen, err := s.HasSeen(context.Background(), review)
+	assert.NoError(t, err)
+	assert.True(t, seen)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStoreHasSeenReportsErrors(t *testing.T) {
+	s, mock := newMockStore(t)
+	mock.ExpectQuery("SELECT EXISTS").WillReturnError(errors.New("connection reset"))
+
+	seen, err := s.HasSeen(context.Background(), models.Review{ID: "g2-1", Source: "G2"})
+	assert.ErrorContains(t, err, "connection reset")
+	assert.False(t, seen)
+}
+
+func TestSQLStorePurgesExpiredReviews(t *testing.T) {
+	s, mock := newMockStore(t)
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM seen_reviews WHERE seen_at <= $1")).
+		WithArgs(testNow.Add(-24 * time.Hour)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+
+	removed, err := s.PurgeExpired(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), removed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStoreSavesReviewsAndNotifications(t *testing.T) {
+	s, mock := newMockStore(t)
+	review := models.Review{ID: "g2-1", Source: "G2", SourceID: "1", CreatedAt: testNow}
+	notification := models.Notification{ID: "n1", Review: review, SentAt: testNow, Status: "sent"}
+
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO reviews (source, source_id, created_at, payload) VALUES ($1, $2, $3, $4) ON CONFLICT (source, source_id) DO UPDATE")).
+		WithArgs("G2", "1", testNow, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO review_notifications (id, source, source_id, created_at, status, payload) VALUES ($1, $2, $3, $4, $5, $6) ON CONFLICT (id) DO UPDATE")).
+		WithArgs("n1", "G2", "1", testNow, "sent", sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, s.SaveReview(context.Background(), review))
+	assert.NoError(t, s.SaveNotification(context.Background(), notification))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSQLStoreHasNotification(t *testing.T) {
+	s, mock := newMockStore(t)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT EXISTS (SELECT 1 FROM review_notifications WHERE source = $1 AND source_id = $2 AND status = $3)")).
+		WithArgs("G2", "1", "actioned").
+		WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+
+	found, err := s.HasNotification(context.Background(), models.Review{ID: "g2-1", Source: "G2", SourceID: "1"}, "actioned")
+	assert.NoError(t, err)
+	assert.True(t, found)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
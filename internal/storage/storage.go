@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// defaultSeenTTL is how long a notified review is remembered when the config
+// leaves SeenTTL unset
+const defaultSeenTTL = 30 * 24 * time.Hour
+
+// Store persists scraped reviews and their notifications, and remembers which
+// reviews have been notified, keyed by source and source ID, so a review
+// scraped again after a restart is not notified twice
+type Store interface {
+	// HasSeen reports whether review was marked seen and has not expired
+	HasSeen(ctx context.Context, review models.Review) (bool, error)
+
+	// MarkSeen records that review was notified
+	MarkSeen(ctx context.Context, review models.Review) error
+
+	// PurgeExpired forgets the reviews marked seen longer ago than the TTL,
+	// returning how many were removed
+	PurgeExpired(ctx context.Context) (int64, error)
+
+	// SaveReview stores review, replacing an earlier copy of the same source review
+	SaveReview(ctx context.Context, review models.Review) error
+
+	// SaveNotification stores notification, replacing an earlier copy with
+	// the same ID
+	SaveNotification(ctx context.Context, notification models.Notification) error
+
+	// HasNotification reports whether a notification about review with
+	// status is stored
+	HasNotification(ctx context.Context, review models.Review, status string) (bool, error)
+
+	// Ping checks that the store is reachable
+	Ping(ctx context.Context) error
+
+	// Close releases the store's resources
+	Close() error
+}
+
+// New opens the configured store, or a MemoryStore when no database is enabled
+func New(ctx context.Context, cfg config.StorageConfig) (Store, error) {
+	ttl := seenTTL(cfg)
+	if !cfg.Database.Enabled {
+		return NewMemoryStore(ttl), nil
+	}
+
+	switch cfg.Database.Type {
+	case "postgres":
+		db, err := sql.Open("postgres", cfg.Database.PostgresDSN())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open review store: %w", err)
+		}
+		s := NewSQLStore(db, ttl)
+		if err := s.CreateSchema(ctx); err != nil {
+			db.Close()
+			return nil, err
+		}
+		return s, nil
+	case "mongodb":
+		return OpenMongoStore(ctx, cfg.Database, ttl)
+	default:
+		return nil, fmt.Errorf("unsupported review store database type %q", cfg.Database.Type)
+	}
+}
+
+// seenTTL returns the configured TTL of seen reviews, or the default
+func seenTTL(cfg config.StorageConfig) time.Duration {
+	if cfg.SeenTTL > 0 {
+		return cfg.SeenTTL.Duration()
+	}
+	return defaultSeenTTL
+}
+
+// reviewKey identifies a review across scraper runs by its source and the ID
+// the source gave it, falling back to the review ID
+func reviewKey(review models.Review) (source, sourceID string) {
+	sourceID = review.SourceID
+	if sourceID == "" {
+		sourceID = review.ID
+	}
+	return review.Source, sourceID
+}
+
+// MemoryStore keeps seen reviews in memory, so they are forgotten when the
+// process exits. It does not keep reviews or notifications, which the API
+// server and notifier already hold in memory. It is safe for concurrent use.
+type MemoryStore struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu   sync.Mutex
+	seen map[[2]string]time.Time
+}
+
+// NewMemoryStore creates an empty in-memory store that forgets reviews after ttl
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	return &MemoryStore{ttl: ttl, now: time.Now, seen: make(map[[2]string]time.Time)}
+}
+
+// HasSeen reports whether review was marked seen within the TTL
+func (s *MemoryStore) HasSeen(ctx context.Context, review models.Review) (bool, error) {
+	source, sourceID := reviewKey(review)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seenAt, ok := s.seen[[2]string{source, sourceID}]
+	return ok && s.now().Sub(seenAt) < s.ttl, nil
+}
+
+// MarkSeen records that review was notified
+func (s *MemoryStore) MarkSeen(ctx context.Context, review models.Review) error {
+	source, sourceID := reviewKey(review)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seen[[2]string{source, sourceID}] = s.now()
+	return nil
+}
+
+// PurgeExpired forgets the reviews marked seen longer ago than the TTL
+func (s *MemoryStore) PurgeExpired(ctx context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed int64
+	for key, seenAt := range s.seen {
+		if s.now().Sub(seenAt) >= s.ttl {
+			delete(s.seen, key)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+// SaveReview does nothing
+func (s *MemoryStore) SaveReview(ctx context.Context, review models.Review) error {
+	return nil
+}
+
+// SaveNotification does nothing
+func (s *MemoryStore) SaveNotification(ctx context.Context, notification models.Notification) error {
+	return nil
+}
+
+// HasNotification always reports false, since notifications are not kept
+func (s *MemoryStore) HasNotification(ctx context.Context, review models.Review, status string) (bool, error) {
+	return false, nil
+}
+
+// Ping always succeeds
+func (s *MemoryStore) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close does nothing
+func (s *MemoryStore) Close() error {
+	return nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStoreMarksReviewsSeen(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore(time.Hour)
+	review := models.Review{ID: "g2-1", Source: "G2", SourceID: "1"}
+
+	seen, err := s.HasSeen(ctx, review)
+	assert.NoError(t, err)
+	assert.False(t, seen)
+
+	assert.NoError(t, s.MarkSeen(ctx, review))
+
+	// The same source review is recognized under a new review ID
+	seen, err = s.HasSeen(ctx, models.Review{ID: "rescraped", Source: "G2", SourceID: "1"})
+	assert.NoError(t, err)
+	assert.True(t, seen)
+
+	seen, _ = s.HasSeen(ctx, models.Review{ID: "g2-1", Source: "Trustpilot", SourceID: "1"})
+	assert.False(t, seen, "the same source ID from another source is a different review")
+}
+
+func TestMemoryStorePurgesExpiredReviews(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryStore(time.Hour)
+	s.now = func() time.Time { return now }
+
+	old := models.Review{ID: "old", Source: "G2"}
+	recent := models.Review{ID: "recent", Source: "G2"}
+	assert.NoError(t, s.MarkSeen(ctx, old))
+	now = now.Add(30 * time.Minute)
+	assert.NoError(t, s.MarkSeen(ctx, recent))
+	now = now.Add(45 * time.Minute)
+
+	seen, _ := s.HasSeen(ctx, old)
+	assert.False(t, seen, "expired reviews are no longer seen")
+
+	removed, err := s.PurgeExpired(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), removed)
+	seen, _ = s.HasSeen(ctx, recent)
+	assert.True(t, seen)
+}
+
+func TestNewWithoutDatabaseUsesMemory(t *testing.T) {
+	s, err := New(context.Background(), config.StorageConfig{})
+
+	assert.NoError(t, err)
+	if assert.IsType(t, &MemoryStore{}, s) {
+		assert.Equal(t, defaultSeenTTL, s.(*MemoryStore).ttl)
+	}
+}
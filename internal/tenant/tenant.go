@@ -0,0 +1,107 @@
+package tenant
+
+import (
+	"strings"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/Infoblox-CTO/review-scraper/internal/router"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+)
+
+// DefaultTenant is the name of the pipeline built from the top-level configuration
+const DefaultTenant = "default"
+
+// Pipeline holds the components used to process a tenant's reviews
+type Pipeline struct {
+	Name     string
+	Analyzer *analyzer.Analyzer
+	Router   *router.Router
+	Notifier *notifier.Notifier
+}
+
+// tenantPipeline pairs a pipeline with the rules that select it
+type tenantPipeline struct {
+	pipeline *Pipeline
+	sources  map[string]bool
+	keywords []string
+}
+
+// Resolver assigns reviews to tenants and returns the pipeline to process them with
+type Resolver struct {
+	tenants  []tenantPipeline
+	fallback *Pipeline
+}
+
+// NewResolver creates a resolver with one pipeline per configured tenant, falling
+// back to the provided default pipeline for reviews that match no tenant
+func NewResolver(tenants []config.TenantConfig, fallback *Pipeline) *Resolver {
+	r := &Resolver{fallback: fallback}
+
+	for _, cfg := range tenants {
+		sources := make(map[string]bool, len(cfg.Sources))
+		for _, source := range cfg.Sources {
+			sources[strings.ToLower(source)] = true
+		}
+
+		keywords := make([]string, 0, len(cfg.Keywords))
+		for _, keyword := range cfg.Keywords {
+			keywords = append(keywords, strings.ToLower(keyword))
+		}
+
+		r.tenants = append(r.tenants, tenantPipeline{
+			pipeline: &Pipeline{
+				Name:     cfg.Name,
+				Analyzer: analyzer.New(cfg.Analyzer),
+				Router:   router.New(cfg.Router),
+				Notifier: notifier.New(cfg.Notifier),
+			},
+			sources:  sources,
+			keywords: keywords,
+		})
+	}
+
+	return r
+}
+
+// Resolve returns the pipeline for a review. Tenants are checked in configuration
+// order, matching on source first and then on keywords in the title or content.
+func (r *Resolver) Resolve(review models.Review) *Pipeline {
+	source := strings.ToLower(review.Source)
+	for _, t := range r.tenants {
+		if t.sources[source] {
+			return t.pipeline
+		}
+	}
+
+	text := strings.ToLower(review.Title + " " + review.Content)
+	for _, t := range r.tenants {
+		for _, keyword := range t.keywords {
+			if strings.Contains(text, keyword) {
+				return t.pipeline
+			}
+		}
+	}
+
+	return r.fallback
+}
+
+// Tag resolves a review's tenant and records it in the review metadata
+func (r *Resolver) Tag(review *models.Review) *Pipeline {
+	pipeline := r.Resolve(*review)
+	if review.Metadata == nil {
+		review.Metadata = make(map[string]interface{})
+	}
+	review.Metadata["tenant"] = pipeline.Name
+	return pipeline
+}
+
+// Pipelines returns every pipeline the resolver can return, default first
+func (r *Resolver) Pipelines() []*Pipeline {
+	pipelines := []*Pipeline{r.fallback}
+	for _, t := range r.tenants {
+		pipelines = append(pipelines, t.pipeline)
+	}
+	return pipelines
+}
@@ -0,0 +1,89 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/analyzer"
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/internal/notifier"
+	"github.com/Infoblox-CTO/review-scraper/internal/router"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestResolver builds a resolver with a DDI tenant matched by source and a
+// security tenant matched by keyword, each with its own lexicon and routing
+func newTestResolver() *Resolver {
+	tenants := []config.TenantConfig{
+		{
+			Name:    "ddi",
+			Sources: []string{"G2"},
+			Analyzer: config.AnalyzerConfig{
+				Mode:     "local",
+				Keywords: []string{"zonefile"},
+			},
+			Router: config.RouterConfig{
+				Mappings: []config.DepartmentMapping{{Category: "general_complaint", Department: "engineering", Priority: 1}},
+			},
+		},
+		{
+			Name:     "security",
+			Keywords: []string{"threat defense"},
+			Analyzer: config.AnalyzerConfig{
+				Mode:     "local",
+				Keywords: []string{"sandbox"},
+			},
+			Router: config.RouterConfig{
+				Mappings: []config.DepartmentMapping{{Category: "general_complaint", Department: "security", Priority: 1}},
+			},
+		},
+	}
+
+	fallback := &Pipeline{
+		Name:     DefaultTenant,
+		Analyzer: analyzer.New(config.AnalyzerConfig{Mode: "local"}),
+		Router:   router.New(config.RouterConfig{}),
+		Notifier: notifier.New(config.NotifierConfig{}),
+	}
+
+	return NewResolver(tenants, fallback)
+}
+
+func TestResolveMatchesSourceThenKeywords(t *testing.T) {
+	r := newTestResolver()
+
+	assert.Equal(t, "ddi", r.Resolve(models.Review{Source: "g2", Content: "threat defense is fine"}).Name,
+		"source matches take precedence over keyword matches")
+	assert.Equal(t, "security", r.Resolve(models.Review{Source: "twitter", Title: "Threat Defense outage"}).Name)
+	assert.Equal(t, DefaultTenant, r.Resolve(models.Review{Source: "twitter", Content: "nothing relevant"}).Name)
+	assert.Len(t, r.Pipelines(), 3)
+}
+
+func TestTenantReviewsUseTenantConfig(t *testing.T) {
+	r := newTestResolver()
+	ctx := context.Background()
+
+	ddiReview := models.Review{ID: "r1", Source: "g2", Content: "The zonefile sandbox import is terrible"}
+	securityReview := models.Review{ID: "r2", Source: "twitter", Content: "threat defense sandbox zonefile handling is terrible"}
+
+	ddi := r.Tag(&ddiReview)
+	security := r.Tag(&securityReview)
+	assert.Equal(t, "ddi", ddiReview.Metadata["tenant"])
+	assert.Equal(t, "security", securityReview.Metadata["tenant"])
+
+	// Each review is analyzed with its own tenant's lexicon
+	ddiResult, err := ddi.Analyzer.Analyze(ctx, ddiReview)
+	assert.NoError(t, err)
+	assert.Contains(t, ddiResult.Keywords, "zonefile")
+	assert.NotContains(t, ddiResult.Keywords, "sandbox")
+
+	securityResult, err := security.Analyzer.Analyze(ctx, securityReview)
+	assert.NoError(t, err)
+	assert.Contains(t, securityResult.Keywords, "sandbox")
+	assert.NotContains(t, securityResult.Keywords, "zonefile")
+
+	// And routed with its own tenant's mappings
+	assert.Equal(t, "engineering", ddi.Router.Route(ddiResult).ID)
+	assert.Equal(t, "security", security.Router.Route(securityResult).ID)
+}
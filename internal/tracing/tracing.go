@@ -0,0 +1,83 @@
+// Package tracing instruments the review pipeline with OpenTelemetry spans.
+// Spans are no-ops until Setup installs an exporter, so instrumented code
+// costs next to nothing when tracing is disabled.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies this service's tracer
+const instrumentationName = "github.com/Infoblox-CTO/review-scraper"
+
+// defaultServiceName is reported when TracingConfig.ServiceName is empty
+const defaultServiceName = "review-scraper"
+
+// Span attribute keys
+const (
+	KeyReviewID     = attribute.Key("review.id")
+	KeyReviewSource = attribute.Key("review.source")
+	KeyScraper      = attribute.Key("scraper.name")
+	KeyDepartment   = attribute.Key("department.id")
+)
+
+// Start starts a span named name, a child of any span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// ReviewAttributes returns the span attributes identifying review
+func ReviewAttributes(review models.Review) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		KeyReviewID.String(review.ID),
+		KeyReviewSource.String(review.Source),
+	}
+}
+
+// End marks span as failed when err is set, then ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Setup installs a tracer provider exporting spans to the OTLP/HTTP endpoint
+// in cfg, returning a function that flushes buffered spans and stops it. When
+// tracing is disabled nothing is installed and the returned function does
+// nothing.
+func Setup(ctx context.Context, cfg config.TracingConfig) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(cfg.Endpoint))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Infoblox-CTO/review-scraper/internal/config"
+	"github.com/Infoblox-CTO/review-scraper/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory
+// for the rest of the test
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return exporter
+}
+
+func TestSetupDisabledIsNoop(t *testing.T) {
+	previous := otel.GetTracerProvider()
+
+	shutdown, err := Setup(context.Background(), config.TracingConfig{Endpoint: "http://localhost:4318"})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+	assert.Equal(t, previous, otel.GetTracerProvider())
+}
+
+func TestStartNestsSpansThroughContext(t *testing.T) {
+	exporter := recordSpans(t)
+
+	review := models.Review{ID: "g2-1", Source: "G2"}
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child", ReviewAttributes(review)...)
+	End(child, errors.New("boom"))
+	End(parent, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	childSpan, parentSpan := spans[0], spans[1]
+	assert.Equal(t, "child", childSpan.Name)
+	assert.Equal(t, parentSpan.SpanContext.SpanID(), childSpan.Parent.SpanID())
+	assert.Contains(t, childSpan.Attributes, KeyReviewID.String("g2-1"))
+	assert.Contains(t, childSpan.Attributes, KeyReviewSource.String("G2"))
+	assert.Equal(t, codes.Error, childSpan.Status.Code)
+	assert.Equal(t, "boom", childSpan.Status.Description)
+	assert.Equal(t, codes.Unset, parentSpan.Status.Code)
+}
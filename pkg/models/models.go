@@ -7,16 +7,37 @@ import (
 // Review represents a user review or comment from any source
 type Review struct {
 	ID          string                 `json:"id"`
-	Source      string                 `json:"source"`      // e.g., "twitter", "reddit", "app_store"
-	SourceID    string                 `json:"sourceId"`    // Original ID from the source
-	Content     string                 `json:"content"`     // The review text
-	Title       string                 `json:"title"`       // The title or headline of the review
-	Author      string                 `json:"author"`      // Username or identifier of the reviewer
-	Rating      *float64               `json:"rating"`      // Star rating if available (1-5)
-	URL         string                 `json:"url"`         // Link to the original review
-	CreatedAt   time.Time              `json:"createdAt"`   // When the review was posted
-	RetrievedAt time.Time              `json:"retrievedAt"` // When we scraped the review
-	Metadata    map[string]interface{} `json:"metadata"`    // Additional platform-specific data
+	Source      string                 `json:"source"`            // e.g., "twitter", "reddit", "app_store"
+	SourceID    string                 `json:"sourceId"`          // Original ID from the source
+	Content     string                 `json:"content"`           // The review text
+	Title       string                 `json:"title"`             // The title or headline of the review
+	Author      string                 `json:"author"`            // Username or identifier of the reviewer
+	Rating      *float64               `json:"rating"`            // Star rating if available (1-5)
+	URL         string                 `json:"url"`               // Link to the original review
+	CreatedAt   time.Time              `json:"createdAt"`         // When the review was posted
+	RetrievedAt time.Time              `json:"retrievedAt"`       // When we scraped the review
+	Language    string                 `json:"language"`          // ISO 639-1 code when known, e.g. "de"
+	Tags        []string               `json:"tags"`              // Source tags plus any derived by the analyzer
+	Replies     []Reply                `json:"replies,omitempty"` // Responses to the review, such as the vendor's
+	Metadata    map[string]interface{} `json:"metadata"`          // Additional platform-specific data
+}
+
+// Reply is a response posted to a review
+type Reply struct {
+	Author    string    `json:"author,omitempty"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"createdAt,omitempty"`
+	IsVendor  bool      `json:"isVendor"` // True if the reply is from the reviewed product's vendor
+}
+
+// HasVendorReply reports whether the vendor has replied to the review
+func (r Review) HasVendorReply() bool {
+	for _, reply := range r.Replies {
+		if reply.IsVendor {
+			return true
+		}
+	}
+	return false
 }
 
 // Tweet represents a tweet from Twitter/X platform
@@ -39,10 +60,35 @@ type AnalysisResult struct {
 	IsNegative     bool               `json:"isNegative"`     // True if the sentiment is negative
 	IsRelevant     bool               `json:"isRelevant"`     // True if the review is relevant to our product
 	IntentCategory string             `json:"intentCategory"` // e.g., "bug_report", "feature_request"
+	Language       string             `json:"language"`       // ISO 639-1 code of the review content, if known
 	Confidence     float64            `json:"confidence"`     // Confidence level of the analysis
 	Keywords       []string           `json:"keywords"`       // Extracted keywords
 	Entities       []Entity           `json:"entities"`       // Extracted entities
 	CategoryScores map[string]float64 `json:"categoryScores"` // Scores for each intent category
+	NeedsAction    bool               `json:"needsAction"`    // True if the review should be followed up
+	Severity       string             `json:"severity"`       // One of SeverityLow, SeverityMedium or SeverityHigh
+}
+
+// Severity levels assigned to analyzed reviews
+const (
+	SeverityLow    = "low"
+	SeverityMedium = "medium"
+	SeverityHigh   = "high"
+)
+
+// SeverityRank orders severities from lowest to highest. Unknown severities
+// rank below all others.
+func SeverityRank(severity string) int {
+	switch severity {
+	case SeverityLow:
+		return 1
+	case SeverityMedium:
+		return 2
+	case SeverityHigh:
+		return 3
+	default:
+		return 0
+	}
 }
 
 // Entity represents a named entity extracted from the review
@@ -67,7 +113,7 @@ type Notification struct {
 	Analysis     AnalysisResult `json:"analysis"`
 	Department   Department     `json:"department"`
 	SentAt       time.Time      `json:"sentAt"`
-	Status       string         `json:"status"`                 // "sent", "delivered", "read", "actioned"
+	Status       string         `json:"status"`                 // "sent", "delivered", "read", "acknowledged", "actioned"
 	ResponseInfo string         `json:"responseInfo,omitempty"` // Action taken by the department
 }
 
@@ -82,6 +128,21 @@ type ScraperStats struct {
 	ErrorDetails     []string  `json:"errorDetails,omitempty"`
 }
 
+// AnalyzedReview is a review together with the outcome of processing it
+type AnalyzedReview struct {
+	Review     Review          `json:"review"`
+	Analysis   *AnalysisResult `json:"analysis,omitempty"`   // Nil when analysis failed
+	Department string          `json:"department,omitempty"` // Department notified, if the review was routed
+}
+
+// SimilarReview is a stored review ranked by how much its analysis overlaps
+// another review's
+type SimilarReview struct {
+	AnalyzedReview
+	Similarity float64  `json:"similarity"` // Cosine similarity of the two reviews' terms, 0 to 1
+	Shared     []string `json:"shared"`     // Keywords, entities and category the reviews have in common
+}
+
 // DashboardMetrics represents aggregated metrics for dashboard display
 type DashboardMetrics struct {
 	TotalReviews        int                       `json:"totalReviews"`
@@ -102,6 +163,23 @@ type DailyMetric struct {
 	AverageSentiment float64   `json:"averageSentiment"`
 }
 
+// ProductSentiment summarizes the analyzed reviews mentioning a product
+type ProductSentiment struct {
+	Product          string  `json:"product"`
+	ReviewCount      int     `json:"reviewCount"`
+	NegativeCount    int     `json:"negativeCount"`
+	AverageSentiment float64 `json:"averageSentiment"`
+	NegativeShare    float64 `json:"negativeShare"` // Fraction of the product's reviews judged negative
+}
+
+// DependencyStatus is the outcome of checking that a dependency is reachable
+type DependencyStatus struct {
+	Name      string `json:"name"`
+	Healthy   bool   `json:"healthy"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latencyMs"`
+}
+
 // ResponseMetric contains response statistics for a department
 type ResponseMetric struct {
 	TotalReceived    int     `json:"totalReceived"`
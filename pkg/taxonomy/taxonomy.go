@@ -0,0 +1,126 @@
+// Package taxonomy holds the Infoblox knowledge base shared by the review
+// enricher, the analyzer and the dashboard metrics: which keywords signal which
+// intent categories, and which keywords identify each product.
+package taxonomy
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultCategories maps keywords to the intent categories detected out of the box
+var DefaultCategories = map[string]string{
+	"bug":        "bug_report",
+	"crash":      "bug_report",
+	"error":      "bug_report",
+	"broken":     "bug_report",
+	"freeze":     "bug_report",
+	"slow":       "performance",
+	"laggy":      "performance",
+	"hang":       "performance",
+	"feature":    "feature_request",
+	"missing":    "feature_request",
+	"wish":       "feature_request",
+	"delivery":   "logistics",
+	"shipping":   "logistics",
+	"payment":    "billing",
+	"charge":     "billing",
+	"refund":     "billing",
+	"support":    "customer_service",
+	"service":    "customer_service",
+	"rude":       "customer_service",
+	"interface":  "ui_ux",
+	"confusing":  "ui_ux",
+	"difficult":  "ui_ux",
+	"dns":        "network_services",
+	"dhcp":       "network_services",
+	"ipam":       "network_services",
+	"ddi":        "network_services",
+	"nios":       "nios_platform",
+	"bloxone":    "bloxone_platform",
+	"threat":     "security",
+	"secure":     "security",
+	"protection": "security",
+	"ddos":       "security",
+	"cloud":      "cloud_services",
+	"automation": "network_automation",
+	"netmri":     "network_automation",
+}
+
+// CategoryForKeyword returns the default intent category for keyword
+func CategoryForKeyword(keyword string) (string, bool) {
+	category, found := DefaultCategories[strings.ToLower(keyword)]
+	return category, found
+}
+
+// ProductKeywords maps product categories to the keywords that identify them
+var ProductKeywords = map[string][]string{
+	"bloxone": {
+		"bloxone", "cloud ddi", "cloud-native", "saas", "branch", "distributed",
+	},
+	"nios": {
+		"nios", "grid", "on-premise", "on-prem", "appliance", "virtual appliance", "vm",
+	},
+	"threat_defense": {
+		"threat defense", "security", "protect", "threat", "malware", "ransomware",
+		"exfiltration", "dns security", "secure dns", "threat intelligence",
+	},
+	"dns": {
+		"dns", "domain", "lookup", "zone", "record", "cname", "mx", "soa", "ns", "ptr",
+		"a record", "aaaa", "bind", "recursive", "resolver",
+	},
+	"dhcp": {
+		"dhcp", "lease", "ip assignment", "dynamic", "scope", "ip address allocation",
+	},
+	"ipam": {
+		"ipam", "ip address management", "subnet", "allocation", "address space",
+		"network management", "ip management",
+	},
+}
+
+// productPatterns matches each product keyword as a whole word or phrase,
+// ignoring case and accepting a plural "s"
+var productPatterns = compileProductPatterns()
+
+// compileProductPatterns builds the matchers for ProductKeywords
+func compileProductPatterns() map[string][]*regexp.Regexp {
+	patterns := make(map[string][]*regexp.Regexp, len(ProductKeywords))
+	for product, keywords := range ProductKeywords {
+		for _, keyword := range keywords {
+			pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `s?\b`)
+			patterns[product] = append(patterns[product], pattern)
+		}
+	}
+	return patterns
+}
+
+// ProductMentions counts how often each product's keywords occur in text.
+// Keywords match whole words only, so "ns" is not found in "questions".
+// Products with no mentions are left out.
+func ProductMentions(text string) map[string]int {
+	mentions := make(map[string]int)
+	for product, patterns := range productPatterns {
+		for _, pattern := range patterns {
+			if count := len(pattern.FindAllStringIndex(text, -1)); count > 0 {
+				mentions[product] += count
+			}
+		}
+	}
+	return mentions
+}
+
+// ProductForText returns the product category mentioned most often in text,
+// breaking ties by name, or false when no product is mentioned
+func ProductForText(text string) (string, bool) {
+	var (
+		topProduct string
+		topCount   int
+	)
+	for product, count := range ProductMentions(text) {
+		if count > topCount || (count == topCount && product < topProduct) {
+			topProduct = product
+			topCount = count
+		}
+	}
+	return topProduct, topCount > 0
+}
@@ -0,0 +1,48 @@
+package taxonomy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCategoryForKeyword(t *testing.T) {
+	category, found := CategoryForKeyword("crash")
+	assert.True(t, found)
+	assert.Equal(t, "bug_report", category)
+
+	category, found = CategoryForKeyword("NIOS")
+	assert.True(t, found)
+	assert.Equal(t, "nios_platform", category)
+
+	_, found = CategoryForKeyword("banana")
+	assert.False(t, found)
+}
+
+func TestProductMentions(t *testing.T) {
+	mentions := ProductMentions("DHCP leases expire early and the DHCP scope is full")
+
+	assert.Equal(t, 4, mentions["dhcp"]) // dhcp twice, lease and scope once each
+	assert.NotContains(t, mentions, "nios")
+}
+
+func TestProductMentionsMatchWholeWords(t *testing.T) {
+	mentions := ProductMentions("Answers to our questions came quickly, and the VMware migration was dynamic")
+
+	assert.NotContains(t, mentions, "dns", `"ns" must not match inside words`)
+	assert.NotContains(t, mentions, "nios", `"vm" must not match inside "VMware"`)
+	assert.Equal(t, 1, mentions["dhcp"])
+}
+
+func TestProductForText(t *testing.T) {
+	product, found := ProductForText("The NIOS grid appliance rebooted")
+	assert.True(t, found)
+	assert.Equal(t, "nios", product)
+
+	product, found = ProductForText("Ransomware and malware protection saved us")
+	assert.True(t, found)
+	assert.Equal(t, "threat_defense", product)
+
+	_, found = ProductForText("Nothing relevant here")
+	assert.False(t, found)
+}